@@ -22,6 +22,8 @@ require (
 	github.com/provideplatform/provide-go v0.0.0-20230402033044-2ea8560d1e46
 	github.com/rivo/uniseg v0.2.0 // indirect
 	github.com/spf13/cobra v1.1.3
+	github.com/spf13/pflag v1.0.5
 	github.com/spf13/viper v1.7.1
 	golang.org/x/crypto v0.0.0-20220315160706-3147a52a75dd
+	gopkg.in/yaml.v2 v2.4.0
 )
@@ -17,8 +17,6 @@
 package accounts
 
 import (
-	"os"
-
 	"github.com/provideplatform/provide-cli/prvd/common"
 	"github.com/spf13/cobra"
 )
@@ -41,7 +39,7 @@ looking for hierarchical deterministic support, check out the wallets API.`,
 
 		defer func() {
 			if r := recover(); r != nil {
-				os.Exit(1)
+				common.Exit(1)
 			}
 		}()
 	},
@@ -50,6 +48,7 @@ looking for hierarchical deterministic support, check out the wallets API.`,
 func init() {
 	AccountsCmd.AddCommand(accountsListCmd)
 	AccountsCmd.AddCommand(accountsInitCmd)
+	AccountsCmd.AddCommand(accountsDetailsCmd)
 	AccountsCmd.Flags().BoolVarP(&optional, "optional", "", false, "List all the optional flags")
 	AccountsCmd.Flags().BoolVarP(&paginate, "paginate", "", false, "List pagination flags")
 }
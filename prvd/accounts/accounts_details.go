@@ -0,0 +1,63 @@
+/*
+ * Copyright 2017-2022 Provide Technologies Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package accounts
+
+import (
+	"fmt"
+	"log"
+
+	"github.com/provideplatform/provide-cli/prvd/common"
+	provide "github.com/provideplatform/provide-go/api/nchain"
+
+	"github.com/spf13/cobra"
+)
+
+var openExplorer bool
+
+var accountsDetailsCmd = &cobra.Command{
+	Use:   "details",
+	Short: "Retrieve a specific signing identity",
+	Long:  `Retrieve details for a specific signing identity (account) by identifier, scoped to the authorized API token`,
+	Run:   fetchAccountDetails,
+}
+
+func fetchAccountDetails(cmd *cobra.Command, args []string) {
+	ctx := common.ContextFromCommand(cmd)
+	token := common.RequireAPIToken(ctx)
+	account, err := provide.GetAccountDetails(token, ctx.AccountID, map[string]interface{}{})
+	if err != nil {
+		log.Printf("Failed to retrieve details for account with id: %s; %s", ctx.AccountID, err.Error())
+		common.Exit(1)
+	}
+	result := fmt.Sprintf("%s\t%s\n", account.ID.String(), account.Address)
+	fmt.Print(result)
+
+	if account.NetworkID == nil {
+		return
+	}
+
+	common.ViewOnExplorer(token, account.NetworkID.String(), func(explorer *common.Explorer) string {
+		return explorer.AddressURL(account.Address)
+	}, openExplorer)
+}
+
+func init() {
+	accountsDetailsCmd.Flags().String("account", "", "id of the account")
+	accountsDetailsCmd.MarkFlagRequired("account")
+
+	accountsDetailsCmd.Flags().BoolVar(&openExplorer, "open", false, "open the account in the block explorer for its network")
+}
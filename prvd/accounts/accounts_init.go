@@ -20,7 +20,6 @@ import (
 	"encoding/hex"
 	"fmt"
 	"log"
-	"os"
 
 	"github.com/provideplatform/provide-cli/prvd/common"
 	provide "github.com/provideplatform/provide-go/api/nchain"
@@ -55,42 +54,43 @@ func createNonCustodialAccount() {
 	publicKey, privateKey, err := providecrypto.EVMGenerateKeyPair()
 	if err != nil {
 		log.Printf("Failed to genereate non-custodial keypair; %s", err.Error())
-		os.Exit(1)
+		common.Exit(1)
 	}
 	secret := hex.EncodeToString(providecrypto.FromECDSA(privateKey))
 	keypairJSON, err := providecrypto.EVMMarshalEncryptedKey(providecrypto.HexToAddress(*publicKey), privateKey, secret)
 	if err != nil {
 		log.Printf("Failed to genereate non-custodial keypair; %s", err.Error())
-		os.Exit(1)
+		common.Exit(1)
 	}
 	result := fmt.Sprintf("%s\t%s\n", *publicKey, string(keypairJSON))
 	fmt.Print(result)
 }
 
 func createManagedAccount(cmd *cobra.Command, args []string) {
-	token := common.RequireAPIToken()
+	ctx := common.ContextFromCommand(cmd)
+	token := common.RequireAPIToken(ctx)
 	params := map[string]interface{}{
-		"network_id": common.NetworkID,
+		"network_id": ctx.NetworkID,
 	}
 	if accountName != "" {
 		params["name"] = accountName
 	}
-	if common.ApplicationID != "" {
-		params["application_id"] = common.ApplicationID
+	if ctx.ApplicationID != "" {
+		params["application_id"] = ctx.ApplicationID
 	}
-	if common.OrganizationID != "" {
-		params["organization_id"] = common.OrganizationID
+	if ctx.OrganizationID != "" {
+		params["organization_id"] = ctx.OrganizationID
 	}
 	account, err := provide.CreateAccount(token, params)
 	if err != nil {
 		log.Printf("Failed to genereate keypair; %s", err.Error())
-		os.Exit(1)
+		common.Exit(1)
 	}
 
-	common.AccountID = account.ID.String()
+	ctx.AccountID = account.ID.String()
 	result := fmt.Sprintf("Account %s\t%s\n", account.ID.String(), account.Address)
 	// FIXME-- when account.Name exists... result = fmt.Sprintf("Account %s\t%s - %s\n", *account.Name, account.ID.String(), *account.Address)
-	appAccountKey := common.BuildConfigKeyWithID(common.AccountConfigKeyPartial, common.ApplicationID)
+	appAccountKey := common.BuildConfigKeyWithID(common.AccountConfigKeyPartial, ctx.ApplicationID)
 	if !viper.IsSet(appAccountKey) {
 		viper.Set(appAccountKey, account.ID.String())
 		viper.WriteConfig()
@@ -102,10 +102,10 @@ func init() {
 	accountsInitCmd.Flags().BoolVarP(&nonCustodial, "non-custodial", "", false, "if the generated keypair is non-custodial")
 	accountsInitCmd.Flags().StringVarP(&accountName, "name", "n", "", "human-readable name to associate with the generated keypair")
 
-	accountsInitCmd.Flags().StringVar(&common.NetworkID, "network", "", "network id")
+	accountsInitCmd.Flags().String("network", "", "network id")
 	accountsInitCmd.MarkFlagRequired("network")
 
-	accountsInitCmd.Flags().StringVar(&common.ApplicationID, "application", "", "application id")
-	accountsInitCmd.Flags().StringVar(&common.OrganizationID, "organization", "", "organization id")
+	accountsInitCmd.Flags().String("application", "", "application id")
+	accountsInitCmd.Flags().String("organization", "", "organization id")
 	accountsInitCmd.Flags().BoolVarP(&optional, "optional", "", false, "List all the optional flags")
 }
@@ -19,7 +19,6 @@ package accounts
 import (
 	"fmt"
 	"log"
-	"os"
 
 	"github.com/provideplatform/provide-cli/prvd/common"
 	provide "github.com/provideplatform/provide-go/api/nchain"
@@ -37,33 +36,39 @@ var accountsListCmd = &cobra.Command{
 }
 
 func listAccounts(cmd *cobra.Command, args []string) {
-	token := common.RequireAPIToken()
+	ctx := common.ContextFromCommand(cmd)
+	token := common.RequireAPIToken(ctx)
 	params := map[string]interface{}{
 		"page": fmt.Sprintf("%d", page),
 		"rpp":  fmt.Sprintf("%d", rpp),
 	}
-	if common.ApplicationID != "" {
-		params["application_id"] = common.ApplicationID
+	if ctx.ApplicationID != "" {
+		params["application_id"] = ctx.ApplicationID
 	}
 	resp, err := provide.ListAccounts(token, params)
 	if err != nil {
 		log.Printf("Failed to retrieve accounts list; %s", err.Error())
-		os.Exit(1)
+		common.Exit(1)
 	}
 	// if status != 200 {
 	// 	log.Printf("Failed to retrieve accounts list; received status: %d", status)
-	// 	os.Exit(1)
+	// 	common.Exit(1)
 	// }
 	for i := range resp {
 		account := resp[i]
-		result := fmt.Sprintf("%s\t%s\n", account.ID.String(), account.Address)
-		// TODO-- when account.Name exists... result = fmt.Sprintf("%s\t%s - %s\n", name, account, *account.Address)
-		fmt.Print(result)
+		result := fmt.Sprintf("%s\t%s", account.ID.String(), account.Address)
+		// TODO-- when account.Name exists... result = fmt.Sprintf("%s\t%s - %s", name, account, *account.Address)
+		if account.NetworkID != nil {
+			if explorer := common.ResolveExplorer(token, account.NetworkID.String()); explorer != nil {
+				result = fmt.Sprintf("%s\t%s", result, explorer.AddressURL(account.Address))
+			}
+		}
+		fmt.Println(result)
 	}
 }
 
 func init() {
-	accountsListCmd.Flags().StringVar(&common.ApplicationID, "application", "", "application identifier to filter accounts")
+	accountsListCmd.Flags().String("application", "", "application identifier to filter accounts")
 	accountsListCmd.Flags().BoolVarP(&optional, "optional", "", false, "List all the optional flags")
 	accountsListCmd.Flags().BoolVarP(&paginate, "paginate", "", false, "List pagination flags")
 	accountsListCmd.Flags().Uint64Var(&page, "page", common.DefaultPage, "page number to retrieve")
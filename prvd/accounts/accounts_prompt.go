@@ -38,6 +38,7 @@ var custodyPromptLabel = "Would you like your wallet to be non-custodial?"
 
 // General Endpoints
 func generalPrompt(cmd *cobra.Command, args []string, currentStep string) {
+	ctx := common.ContextFromCommand(cmd)
 	switch step := currentStep; step {
 	case promptStepInit:
 		common.SelectInput(accountTypePromptArgs, accountTypeLabel)
@@ -51,18 +52,18 @@ func generalPrompt(cmd *cobra.Command, args []string, currentStep string) {
 			if accountName == "" {
 				accountName = common.FreeInput("Account Name", "", common.NoValidation)
 			}
-			if common.ApplicationID == "" {
-				common.RequireApplication()
+			if ctx.ApplicationID == "" {
+				common.RequireApplication(ctx)
 			}
-			if common.OrganizationID == "" {
-				common.RequireOrganization()
+			if ctx.OrganizationID == "" {
+				common.RequireOrganization(ctx)
 			}
 		}
 		CreateAccount(cmd, args)
 	case promptStepList:
 		if optional {
 			fmt.Println("Optional Flags:")
-			common.RequireApplication()
+			common.RequireApplication(ctx)
 		}
 		page, rpp = common.PromptPagination(paginate, page, rpp)
 		listAccounts(cmd, args)
@@ -17,8 +17,6 @@
 package api_tokens
 
 import (
-	"os"
-
 	"github.com/provideplatform/provide-cli/prvd/common"
 	"github.com/spf13/cobra"
 )
@@ -34,12 +32,19 @@ var APITokensCmd = &cobra.Command{
 
 		defer func() {
 			if r := recover(); r != nil {
-				os.Exit(1)
+				common.Exit(1)
 			}
 		}()
 	},
 }
 
+// ResetState restores the package-level state assigned by prompts, which is not bound to a flag;
+// the shell calls this prior to each in-process invocation
+func ResetState() {
+	jwtKeypairs = nil
+	scope = ""
+}
+
 func init() {
 	APITokensCmd.AddCommand(apiTokensListCmd)
 	APITokensCmd.AddCommand(apiTokensInitCmd)
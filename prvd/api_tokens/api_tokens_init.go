@@ -20,7 +20,6 @@ import (
 	"errors"
 	"fmt"
 	"log"
-	"os"
 	"strings"
 
 	"github.com/dgrijalva/jwt-go"
@@ -53,9 +52,10 @@ var apiTokensInitCmd = &cobra.Command{
 
 // createAPIToken triggers the generation of an API token for the given network.
 func createAPIToken(cmd *cobra.Command, args []string) {
+	ctx := common.ContextFromCommand(cmd)
 	RequirePublicJWTVerifiers()
 
-	userToken := common.RequireUserAccessToken()
+	userToken := common.RequireUserAccessToken(ctx)
 	params := map[string]interface{}{}
 
 	if scope != "" {
@@ -70,22 +70,22 @@ func createAPIToken(cmd *cobra.Command, args []string) {
 		params["grant_type"] = "refresh_token"
 	}
 
-	if common.ApplicationID != "" {
-		token, err := provide.CreateApplicationToken(userToken, common.ApplicationID, params)
+	if ctx.ApplicationID != "" {
+		token, err := provide.CreateApplicationToken(userToken, ctx.ApplicationID, params)
 		if err != nil {
-			log.Printf("Failed to authorize API token on behalf of application %s; %s", common.ApplicationID, err.Error())
-			os.Exit(1)
+			log.Printf("Failed to authorize API token on behalf of application %s; %s", ctx.ApplicationID, err.Error())
+			common.Exit(1)
 		}
 
-		appAPITokenKey := common.BuildConfigKeyWithID(common.AccessTokenConfigKey, common.ApplicationID)
-		appAPIRefreshTokenKey := common.BuildConfigKeyWithID(common.RefreshTokenConfigKey, common.ApplicationID)
+		appAPITokenKey := common.BuildConfigKeyWithID(common.AccessTokenConfigKey, ctx.ApplicationID)
+		appAPIRefreshTokenKey := common.BuildConfigKeyWithID(common.RefreshTokenConfigKey, ctx.ApplicationID)
 		var tkn string
 
 		if token.Token != nil {
-			fmt.Printf("API token authorized for application: %s\t%s\n", common.ApplicationID, *token.Token)
+			fmt.Printf("API token authorized for application: %s\t%s\n", ctx.ApplicationID, *token.Token)
 			tkn = *token.Token
 		} else if token.AccessToken != nil {
-			fmt.Printf("Access token authorized for application: %s\t%s\n", common.ApplicationID, *token.AccessToken)
+			fmt.Printf("Access token authorized for application: %s\t%s\n", ctx.ApplicationID, *token.AccessToken)
 			tkn = *token.AccessToken
 		}
 
@@ -96,53 +96,53 @@ func createAPIToken(cmd *cobra.Command, args []string) {
 			}
 
 			if token.RefreshToken != nil {
-				fmt.Printf("Refresh token authorized for application: %s\t%s\n", common.ApplicationID, *token.RefreshToken)
+				fmt.Printf("Refresh token authorized for application: %s\t%s\n", ctx.ApplicationID, *token.RefreshToken)
 				if !viper.IsSet(appAPIRefreshTokenKey) {
 					viper.Set(appAPIRefreshTokenKey, *token.RefreshToken)
 					viper.WriteConfig()
 				}
 			}
 		}
-	} else if common.OrganizationID != "" {
-		params["organization_id"] = common.OrganizationID
+	} else if ctx.OrganizationID != "" {
+		params["organization_id"] = ctx.OrganizationID
 		token, err := provide.CreateToken(userToken, params)
 		if err != nil {
-			log.Printf("failed to authorize API access token on behalf of organization %s; %s", common.OrganizationID, err.Error())
-			os.Exit(1)
+			log.Printf("failed to authorize API access token on behalf of organization %s; %s", ctx.OrganizationID, err.Error())
+			common.Exit(1)
 		}
 
-		orgAPIAccessTokenKey := common.BuildConfigKeyWithID(common.AccessTokenConfigKey, common.OrganizationID)
-		orgAPIRefreshTokenKey := common.BuildConfigKeyWithID(common.RefreshTokenConfigKey, common.OrganizationID)
+		orgAPIAccessTokenKey := common.BuildConfigKeyWithID(common.AccessTokenConfigKey, ctx.OrganizationID)
+		orgAPIRefreshTokenKey := common.BuildConfigKeyWithID(common.RefreshTokenConfigKey, ctx.OrganizationID)
 
 		if token.AccessToken != nil {
-			fmt.Printf("Access token authorized for organization: %s\t%s\n", common.OrganizationID, *token.AccessToken)
+			fmt.Printf("Access token authorized for organization: %s\t%s\n", ctx.OrganizationID, *token.AccessToken)
 			if !viper.IsSet(orgAPIAccessTokenKey) {
 				viper.Set(orgAPIAccessTokenKey, *token.AccessToken)
 				viper.WriteConfig()
 			}
 			if token.RefreshToken != nil {
-				fmt.Printf("Refresh token authorized for organization: %s\t%s\n", common.OrganizationID, *token.RefreshToken)
+				fmt.Printf("Refresh token authorized for organization: %s\t%s\n", ctx.OrganizationID, *token.RefreshToken)
 				if !viper.IsSet(orgAPIRefreshTokenKey) {
 					viper.Set(orgAPIRefreshTokenKey, *token.RefreshToken)
 					viper.WriteConfig()
 				}
 			}
 		} else {
-			log.Printf("Failed to authorize API token on behalf of organization %s; no access/refresh pair returned", common.OrganizationID)
-			os.Exit(1)
+			log.Printf("Failed to authorize API token on behalf of organization %s; no access/refresh pair returned", ctx.OrganizationID)
+			common.Exit(1)
 		}
 	} else {
 		// user token...
 		token, err := provide.CreateToken(userToken, params)
 		if err != nil {
 			log.Printf("failed to authorize API access token on behalf of authorized user; %s", err.Error())
-			os.Exit(1)
+			common.Exit(1)
 		}
 
 		tkn, err := ParseJWT(userToken)
 		if err != nil {
 			log.Printf("failed to parse JWT token on behalf of authorized user; %s", err.Error())
-			os.Exit(1)
+			common.Exit(1)
 		}
 		claims, _ := tkn.Claims.(jwt.MapClaims)
 
@@ -151,13 +151,13 @@ func createAPIToken(cmd *cobra.Command, args []string) {
 		userAPIRefreshTokenKey := common.BuildConfigKeyWithID(common.RefreshTokenConfigKey, userID)
 
 		if token.AccessToken != nil {
-			fmt.Printf("Access token authorized for user: %s\t%s\n", common.OrganizationID, *token.AccessToken)
+			fmt.Printf("Access token authorized for user: %s\t%s\n", ctx.OrganizationID, *token.AccessToken)
 			if !viper.IsSet(userAPIAccessTokenKey) {
 				viper.Set(userAPIAccessTokenKey, *token.AccessToken)
 				viper.WriteConfig()
 			}
 			if token.RefreshToken != nil {
-				fmt.Printf("Refresh token authorized for user: %s\t%s\n", common.OrganizationID, *token.RefreshToken)
+				fmt.Printf("Refresh token authorized for user: %s\t%s\n", ctx.OrganizationID, *token.RefreshToken)
 				if !viper.IsSet(userAPIRefreshTokenKey) {
 					viper.Set(userAPIRefreshTokenKey, *token.RefreshToken)
 					viper.WriteConfig()
@@ -165,7 +165,7 @@ func createAPIToken(cmd *cobra.Command, args []string) {
 			}
 		} else {
 			log.Printf("Failed to authorize API token on behalf of authorized user %s; no access/refresh pair returned", userID)
-			os.Exit(1)
+			common.Exit(1)
 		}
 	}
 }
@@ -233,8 +233,8 @@ func ParseJWT(token string) (*jwt.Token, error) {
 }
 
 func init() {
-	apiTokensInitCmd.Flags().StringVar(&common.ApplicationID, "application", "", "application id")
-	apiTokensInitCmd.Flags().StringVar(&common.OrganizationID, "organization", "", "organization id")
+	apiTokensInitCmd.Flags().String("application", "", "application id")
+	apiTokensInitCmd.Flags().String("organization", "", "organization id")
 
 	apiTokensInitCmd.Flags().BoolVar(&offlineAccess, "offline-access", false, "offline access")
 	apiTokensInitCmd.Flags().BoolVar(&refreshToken, "refresh-token", false, "refresh token")
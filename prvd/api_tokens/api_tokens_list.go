@@ -19,7 +19,6 @@ package api_tokens
 import (
 	"fmt"
 	"log"
-	"os"
 
 	"github.com/provideplatform/provide-cli/prvd/common"
 	provide "github.com/provideplatform/provide-go/api/ident"
@@ -38,22 +37,23 @@ var apiTokensListCmd = &cobra.Command{
 }
 
 func listAPITokens(cmd *cobra.Command, args []string) {
-	token := common.RequireAPIToken()
+	ctx := common.ContextFromCommand(cmd)
+	token := common.RequireAPIToken(ctx)
 	params := map[string]interface{}{
 		"page": fmt.Sprintf("%d", page),
 		"rpp":  fmt.Sprintf("%d", rpp),
 	}
-	if common.ApplicationID != "" {
-		params["application_id"] = common.ApplicationID
+	if ctx.ApplicationID != "" {
+		params["application_id"] = ctx.ApplicationID
 	}
 	resp, err := provide.ListTokens(token, params)
 	if err != nil {
 		log.Printf("Failed to retrieve API tokens list; %s", err.Error())
-		os.Exit(1)
+		common.Exit(1)
 	}
 	// if status != 200 {
 	// 	log.Printf("Failed to retrieve API tokens list; received status: %d", status)
-	// 	os.Exit(1)
+	// 	common.Exit(1)
 	// }
 	for i := range resp {
 		apiToken := resp[i]
@@ -63,7 +63,7 @@ func listAPITokens(cmd *cobra.Command, args []string) {
 }
 
 func init() {
-	apiTokensListCmd.Flags().StringVar(&common.ApplicationID, "application", "", "application identifier to filter API tokens")
+	apiTokensListCmd.Flags().String("application", "", "application identifier to filter API tokens")
 	apiTokensListCmd.Flags().BoolVarP(&optional, "optional", "", false, "List all the optional flags")
 	apiTokensListCmd.Flags().BoolVarP(&paginate, "paginate", "", false, "List pagination flags")
 	apiTokensListCmd.Flags().Uint64Var(&page, "page", common.DefaultPage, "page number to retrieve")
@@ -37,14 +37,15 @@ var offlinePromptLabel = "Would you like to set offline access"
 
 // General Endpoints
 func generalPrompt(cmd *cobra.Command, args []string, currentStep string) {
+	ctx := common.ContextFromCommand(cmd)
 	switch step := currentStep; step {
 	case promptStepInit:
 		if optional {
-			if common.ApplicationID == "" {
-				common.RequireApplication()
+			if ctx.ApplicationID == "" {
+				common.RequireApplication(ctx)
 			}
-			if common.OrganizationID == "" {
-				common.RequireOrganization()
+			if ctx.OrganizationID == "" {
+				common.RequireOrganization(ctx)
 			}
 			if !refreshToken {
 				result := common.SelectInput(refresTokenPromptArgs, refresTokenPromptLabel)
@@ -61,7 +62,7 @@ func generalPrompt(cmd *cobra.Command, args []string, currentStep string) {
 		createAPIToken(cmd, args)
 	case promptStepList:
 		if optional {
-			common.RequireApplication()
+			common.RequireApplication(ctx)
 		}
 		page, rpp = common.PromptPagination(paginate, page, rpp)
 		listAPITokens(cmd, args)
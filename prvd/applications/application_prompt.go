@@ -41,13 +41,14 @@ var walletPromptLabel = "Would you like to set up a wallet"
 
 // General Endpoints
 func generalPrompt(cmd *cobra.Command, args []string, step string) {
+	ctx := common.ContextFromCommand(cmd)
 	switch step {
 	case promptStepInit:
 		if applicationName == "" {
 			applicationName = common.FreeInput("Application Name", "", common.MandatoryValidation)
 		}
-		if common.NetworkID == "" {
-			common.RequireNetwork()
+		if ctx.NetworkID == "" {
+			common.RequireNetwork(ctx)
 		}
 		if optional {
 			fmt.Println("Optional Flags:")
@@ -69,7 +70,7 @@ func generalPrompt(cmd *cobra.Command, args []string, step string) {
 		}
 		createApplication(cmd, args)
 	case promptStepDetails:
-		common.RequireApplication()
+		common.RequireApplication(ctx)
 		fetchApplicationDetails(cmd, args)
 	case promptStepList:
 		page, rpp = common.PromptPagination(paginate, page, rpp)
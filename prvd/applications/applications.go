@@ -17,8 +17,6 @@
 package applications
 
 import (
-	"os"
-
 	"github.com/provideplatform/provide-cli/prvd/common"
 	"github.com/spf13/cobra"
 )
@@ -48,7 +46,7 @@ var ApplicationsCmd = &cobra.Command{
 
 		defer func() {
 			if r := recover(); r != nil {
-				os.Exit(1)
+				common.Exit(1)
 			}
 		}()
 	},
@@ -19,7 +19,6 @@ package applications
 import (
 	"fmt"
 	"log"
-	"os"
 
 	"github.com/provideplatform/provide-cli/prvd/common"
 	provide "github.com/provideplatform/provide-go/api/ident"
@@ -35,18 +34,19 @@ var applicationsDetailsCmd = &cobra.Command{
 }
 
 func fetchApplicationDetails(cmd *cobra.Command, args []string) {
-	token := common.RequireAPIToken()
+	ctx := common.ContextFromCommand(cmd)
+	token := common.RequireAPIToken(ctx)
 	params := map[string]interface{}{}
-	application, err := provide.GetApplicationDetails(token, common.ApplicationID, params)
+	application, err := provide.GetApplicationDetails(token, ctx.ApplicationID, params)
 	if err != nil {
-		log.Printf("Failed to retrieve details for application with id: %s; %s", common.ApplicationID, err.Error())
-		os.Exit(1)
+		log.Printf("Failed to retrieve details for application with id: %s; %s", ctx.ApplicationID, err.Error())
+		common.Exit(1)
 	}
 	result := fmt.Sprintf("%s\t%s\n", application.ID.String(), *application.Name)
 	fmt.Print(result)
 }
 
 func init() {
-	applicationsDetailsCmd.Flags().StringVar(&common.ApplicationID, "application", "", "id of the application")
+	applicationsDetailsCmd.Flags().String("application", "", "id of the application")
 	// applicationsDetailsCmd.MarkFlagRequired("application")
 }
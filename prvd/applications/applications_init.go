@@ -19,7 +19,6 @@ package applications
 import (
 	"fmt"
 	"log"
-	"os"
 
 	"github.com/provideplatform/provide-cli/prvd/accounts"
 	"github.com/provideplatform/provide-cli/prvd/common"
@@ -45,9 +44,9 @@ var applicationsInitCmd = &cobra.Command{
 	Run:   createApplication,
 }
 
-func applicationConfigFactory() map[string]interface{} {
+func applicationConfigFactory(ctx *common.Context) map[string]interface{} {
 	cfg := map[string]interface{}{
-		"network_id": common.NetworkID,
+		"network_id": ctx.NetworkID,
 	}
 
 	if applicationType != "" {
@@ -58,12 +57,13 @@ func applicationConfigFactory() map[string]interface{} {
 }
 
 func createApplication(cmd *cobra.Command, args []string) {
+	ctx := common.ContextFromCommand(cmd)
 	if withoutAPIToken && !withoutWallet {
 		fmt.Println("Cannot create an application that has a wallet but no API token.")
-		os.Exit(1)
+		common.Exit(1)
 	}
-	token := common.RequireAPIToken()
-	cfg := applicationConfigFactory()
+	token := common.RequireAPIToken(ctx)
+	cfg := applicationConfigFactory(ctx)
 	if axiom {
 		cfg["axiom"] = true
 	}
@@ -76,7 +76,7 @@ func createApplication(cmd *cobra.Command, args []string) {
 	application, err := provide.CreateApplication(token, params)
 	if err != nil {
 		log.Printf("Failed to initialize application; %s", err.Error())
-		os.Exit(1)
+		common.Exit(1)
 	}
 
 	// // FIXME-- authorize app token...
@@ -107,7 +107,7 @@ func init() {
 
 	applicationsInitCmd.Flags().StringVar(&applicationType, "type", "", "application type")
 
-	applicationsInitCmd.Flags().StringVar(&common.NetworkID, "network", "", "target network id")
+	applicationsInitCmd.Flags().String("network", "", "target network id")
 	// applicationsInitCmd.MarkFlagRequired("network")
 
 	applicationsInitCmd.Flags().BoolVar(&axiom, "axiom", false, "setup a axiom workgroup")
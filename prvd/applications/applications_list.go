@@ -19,7 +19,6 @@ package applications
 import (
 	"fmt"
 	"log"
-	"os"
 
 	"github.com/provideplatform/provide-cli/prvd/common"
 	provide "github.com/provideplatform/provide-go/api/ident"
@@ -38,7 +37,8 @@ var applicationsListCmd = &cobra.Command{
 }
 
 func listApplications(cmd *cobra.Command, args []string) {
-	token := common.RequireAPIToken()
+	ctx := common.ContextFromCommand(cmd)
+	token := common.RequireAPIToken(ctx)
 	params := map[string]interface{}{
 		"page": fmt.Sprintf("%d", page),
 		"rpp":  fmt.Sprintf("%d", rpp),
@@ -46,7 +46,7 @@ func listApplications(cmd *cobra.Command, args []string) {
 	applications, err := provide.ListApplications(token, params)
 	if err != nil {
 		log.Printf("Failed to retrieve applications list; %s", err.Error())
-		os.Exit(1)
+		common.Exit(1)
 	}
 	for i := range applications {
 		application := applications[i]
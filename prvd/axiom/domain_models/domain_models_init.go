@@ -34,12 +34,16 @@ var schemaQuery string
 
 var description string
 
+var edit bool
 var fields string
 var primaryKey string
 
 var Optional bool
 var paginate bool
 
+// fieldsTemplate is the template for editing the model fields using --edit
+const fieldsTemplate = `[{"name": "", "type": "string"}]`
+
 var initBaselineDomainModelCmd = &cobra.Command{
 	Use:   "init",
 	Short: "Initialize axiom domain model",
@@ -52,41 +56,42 @@ func initDomainModel(cmd *cobra.Command, args []string) {
 }
 
 func initDomainModelRun(cmd *cobra.Command, args []string) {
-	if common.OrganizationID == "" {
-		common.RequireOrganization()
+	ctx := common.ContextFromCommand(cmd)
+	if ctx.OrganizationID == "" {
+		common.RequireOrganization(ctx)
 	}
-	if common.WorkgroupID == "" {
-		common.RequireWorkgroup()
+	if ctx.WorkgroupID == "" {
+		common.RequireWorkgroup(ctx)
 	}
 
-	common.AuthorizeOrganizationContext(true)
+	common.AuthorizeOrganizationContext(ctx, true)
 
-	token, err := common.ResolveOrganizationToken()
+	token, err := common.ResolveOrganizationToken(ctx)
 	if err != nil {
 		log.Printf("failed to initialize axiom domain model; %s", err.Error())
-		os.Exit(1)
+		common.Exit(1)
 	}
 
-	hasSystems := len(common.Organization.Metadata.Workgroups[common.Workgroup.ID].SystemSecretIDs) > 0
+	hasSystems := len(ctx.Organization.Metadata.Workgroups[ctx.Workgroup.ID].SystemSecretIDs) > 0
 
 	if hasSystems && !isSchema {
 		isSchemaPrompt()
 	} else if hasSystems && isSchema {
 		fmt.Print("failed to initialize axiom domain model; cannot create a domain model from a schema without systems")
-		os.Exit(1)
+		common.Exit(1)
 	}
 
 	var params map[string]interface{}
 	if isSchema {
 		schemaQueryPrompt()
 
-		vaultID := common.Organization.Metadata.Workgroups[common.Workgroup.ID].VaultID
-		systemIDs := common.Organization.Metadata.Workgroups[common.Workgroup.ID].SystemSecretIDs
+		vaultID := ctx.Organization.Metadata.Workgroups[ctx.Workgroup.ID].VaultID
+		systemIDs := ctx.Organization.Metadata.Workgroups[ctx.Workgroup.ID].SystemSecretIDs
 
-		isOperator := common.Organization.Metadata.Workgroups[common.Workgroup.ID].OperatorSeparationDegree == 0
+		isOperator := ctx.Organization.Metadata.Workgroups[ctx.Workgroup.ID].OperatorSeparationDegree == 0
 		if isOperator {
-			vaultID = common.Workgroup.Config.VaultID
-			systemIDs = common.Workgroup.Config.SystemSecretIDs
+			vaultID = ctx.Workgroup.Config.VaultID
+			systemIDs = ctx.Workgroup.Config.SystemSecretIDs
 		}
 
 		IDs := make([]string, 0)
@@ -94,14 +99,14 @@ func initDomainModelRun(cmd *cobra.Command, args []string) {
 			IDs = append(IDs, ID.String())
 		}
 
-		schemas, err := axiom.ListSchemas(*token.AccessToken, common.WorkgroupID, map[string]interface{}{
+		schemas, err := axiom.ListSchemas(*token.AccessToken, ctx.WorkgroupID, map[string]interface{}{
 			"vault_id":          vaultID.String(),
 			"system_secret_ids": strings.Join(IDs, ","),
 			"q":                 schemaQuery,
 		})
 		if err != nil {
 			log.Printf("failed to initialize axiom domain model; %s", err.Error())
-			os.Exit(1)
+			common.Exit(1)
 		}
 
 		schemaOpts := make([]string, 0)
@@ -117,12 +122,12 @@ func initDomainModelRun(cmd *cobra.Command, args []string) {
 		i, _, err := prompt.Run()
 		if err != nil {
 			fmt.Printf("failed to initialize axiom domain model; %s", err.Error())
-			os.Exit(1)
+			common.Exit(1)
 		}
 
-		ref := common.SHA256(fmt.Sprintf("%s.%s", common.OrganizationID, schemaOpts[i]))
+		ref := common.SHA256(fmt.Sprintf("%s.%s", ctx.OrganizationID, schemaOpts[i]))
 		models, err := axiom.ListMappings(*token.AccessToken, map[string]interface{}{
-			"workgroup_id": common.WorkgroupID,
+			"workgroup_id": ctx.WorkgroupID,
 			"ref":          ref,
 			// "page":         fmt.Sprintf("%d", page),
 			// "rpp":          fmt.Sprintf("%d", rpp),
@@ -130,13 +135,13 @@ func initDomainModelRun(cmd *cobra.Command, args []string) {
 
 		if len(models) > 0 {
 			fmt.Print("failed to initialize axiom domain model; schema mapping exists")
-			os.Exit(1)
+			common.Exit(1)
 		}
 
-		schema, err := axiom.GetSchemaDetails(*token.AccessToken, common.OrganizationID, ref, map[string]interface{}{})
+		schema, err := axiom.GetSchemaDetails(*token.AccessToken, ctx.OrganizationID, ref, map[string]interface{}{})
 		if err != nil {
 			fmt.Printf("failed to initialize axiom domain model; %s", err.Error())
-			os.Exit(1)
+			common.Exit(1)
 		}
 
 		fields := make([]interface{}, 0)
@@ -161,7 +166,7 @@ func initDomainModelRun(cmd *cobra.Command, args []string) {
 			"description":  schema.Description,
 			"type":         schema.Type,
 			"models":       []interface{}{model},
-			"workgroup_id": common.WorkgroupID,
+			"workgroup_id": ctx.WorkgroupID,
 		}
 	} else {
 		if name == "" {
@@ -171,16 +176,43 @@ func initDomainModelRun(cmd *cobra.Command, args []string) {
 			descriptionPrompt()
 		}
 
+		if fields != "" {
+			fields, err = common.ReadJSONInput(fields)
+			if err != nil {
+				log.Printf("failed to initialize axiom domain model; %s", err.Error())
+				common.Exit(1)
+			}
+		}
+
+		if edit {
+			template := fields
+			if template == "" {
+				template = fieldsTemplate
+			}
+			header := fmt.Sprintf("%s model fields; lines beginning with # are ignored and empty fields abort\ntypes are number or string and the primary key is given using --primary-key", name)
+			fields, err = common.EditJSON(header, template, func(input string) error {
+				editedFields := make([]*axiom.MappingField, 0)
+				if err := json.Unmarshal([]byte(input), &editedFields); err != nil {
+					return err
+				}
+				return validateFields(editedFields)
+			})
+			if err != nil {
+				log.Printf("failed to initialize axiom domain model; %s", err.Error())
+				common.Exit(1)
+			}
+		}
+
 		localFields := make([]*axiom.MappingField, 0)
 		if fields != "" {
 			if err := json.Unmarshal([]byte(fields), &localFields); err != nil {
 				log.Printf("failed to initialize axiom domain model; %s", err.Error())
-				os.Exit(1)
+				common.Exit(1)
 			}
 
 			if err := validateFields(localFields); err != nil {
 				log.Printf("failed to initialize axiom domain model; %s", err.Error())
-				os.Exit(1)
+				common.Exit(1)
 			}
 		}
 
@@ -188,7 +220,7 @@ func initDomainModelRun(cmd *cobra.Command, args []string) {
 
 		if err := primaryKeyPrompt(localFields); err != nil {
 			log.Printf("failed to initialize axiom domain model; %s", err.Error())
-			os.Exit(1)
+			common.Exit(1)
 		}
 
 		modelParam := map[string]interface{}{
@@ -207,14 +239,14 @@ func initDomainModelRun(cmd *cobra.Command, args []string) {
 			"models": []interface{}{
 				modelParam,
 			},
-			"workgroup_id": common.WorkgroupID,
+			"workgroup_id": ctx.WorkgroupID,
 		}
 	}
 
 	m, err := axiom.CreateMapping(*token.AccessToken, params)
 	if err != nil {
 		log.Printf("failed to initialize axiom domain model; %s", err.Error())
-		os.Exit(1)
+		common.Exit(1)
 	}
 
 	result, _ := json.MarshalIndent(m, "", "\t")
@@ -235,7 +267,7 @@ func createModelTypePrompt() {
 
 	result, err := prompt.Run()
 	if err != nil {
-		os.Exit(1)
+		common.Exit(1)
 		return
 	}
 
@@ -249,7 +281,7 @@ func descriptionPrompt() {
 
 	result, err := prompt.Run()
 	if err != nil {
-		os.Exit(1)
+		common.Exit(1)
 		return
 	}
 
@@ -283,7 +315,7 @@ func fieldsPrompt(fields *[]*axiom.MappingField) func([]*axiom.MappingField) {
 	result, err := prompt.Run()
 	if err != nil {
 		fmt.Printf("failed to initialize axiom domain model; %s", err.Error())
-		os.Exit(1)
+		common.Exit(1)
 	}
 
 	// FIXME-- can probably do this more simply - export as const from provide-go ??
@@ -299,7 +331,7 @@ func fieldsPrompt(fields *[]*axiom.MappingField) func([]*axiom.MappingField) {
 	i, _, err := selectPrompt.Run()
 	if err != nil {
 		fmt.Printf("failed to initialize axiom domain model; %s", err.Error())
-		os.Exit(1)
+		common.Exit(1)
 	}
 
 	*fields = append(*fields, &axiom.MappingField{
@@ -379,7 +411,7 @@ func schemaQueryPrompt() {
 		result, err := prompt.Run()
 		if err != nil {
 			fmt.Printf("failed to initialize axiom domain model; %s", err.Error())
-			os.Exit(1)
+			common.Exit(1)
 		}
 
 		schemaQuery = result
@@ -387,15 +419,16 @@ func schemaQueryPrompt() {
 }
 
 func init() {
-	initBaselineDomainModelCmd.Flags().StringVar(&common.OrganizationID, "organization", os.Getenv("PROVIDE_ORGANIZATION_ID"), "organization identifier")
-	initBaselineDomainModelCmd.Flags().StringVar(&common.WorkgroupID, "workgroup", "", "workgroup identifier")
+	initBaselineDomainModelCmd.Flags().String("organization", os.Getenv("PROVIDE_ORGANIZATION_ID"), "organization identifier")
+	initBaselineDomainModelCmd.Flags().String("workgroup", "", "workgroup identifier")
 
 	initBaselineDomainModelCmd.Flags().BoolVar(&isSchema, "schema", false, "create a domain model from a schema")
 	initBaselineDomainModelCmd.Flags().StringVar(&schemaQuery, "schema-query", "", "schema query string")
 
 	initBaselineDomainModelCmd.Flags().StringVar(&name, "type", "", "model type")
 	initBaselineDomainModelCmd.Flags().StringVar(&description, "description", "", "model description")
-	initBaselineDomainModelCmd.Flags().StringVar(&fields, "fields", "", "model fields in the '[{\"name\": \"yourmother\", \"type\": \"string\"}, ...]' format")
+	initBaselineDomainModelCmd.Flags().StringVar(&fields, "fields", "", "model fields in the '[{\"name\": \"yourmother\", \"type\": \"string\"}, ...]' format; use @- to read from stdin or @path to read from a file")
+	initBaselineDomainModelCmd.Flags().BoolVar(&edit, common.EditFlag, false, "edit the model fields using $EDITOR")
 	initBaselineDomainModelCmd.Flags().StringVar(&primaryKey, "primary-key", "", "model primary key")
 
 	initBaselineDomainModelCmd.Flags().BoolVarP(&Optional, "optional", "", false, "List all the Optional flags")
@@ -44,11 +44,12 @@ func listDomainModels(cmd *cobra.Command, args []string) {
 }
 
 func listDomainModelsRun(cmd *cobra.Command, args []string) {
-	if common.OrganizationID == "" {
-		common.RequireOrganization()
+	ctx := common.ContextFromCommand(cmd)
+	if ctx.OrganizationID == "" {
+		common.RequireOrganization(ctx)
 	}
 
-	if common.WorkgroupID == "" {
+	if ctx.WorkgroupID == "" {
 		prompt := promptui.Prompt{
 			IsConfirm: true,
 			Label:     "Select workgroup",
@@ -56,7 +57,7 @@ func listDomainModelsRun(cmd *cobra.Command, args []string) {
 
 		_, err := prompt.Run()
 		if err == nil {
-			common.RequireWorkgroup()
+			common.RequireWorkgroup(ctx)
 		}
 	}
 
@@ -71,27 +72,27 @@ func listDomainModelsRun(cmd *cobra.Command, args []string) {
 		if err == nil {
 			listModelsTypePrompt()
 
-			ref = common.SHA256(fmt.Sprintf("%s.%s", common.OrganizationID, name))
+			ref = common.SHA256(fmt.Sprintf("%s.%s", ctx.OrganizationID, name))
 		}
 	}
 
-	common.AuthorizeOrganizationContext(true)
+	common.AuthorizeOrganizationContext(ctx, true)
 
-	token, err := common.ResolveOrganizationToken()
+	token, err := common.ResolveOrganizationToken(ctx)
 	if err != nil {
 		log.Printf("failed to retrieve axiom domain models; %s", err.Error())
-		os.Exit(1)
+		common.Exit(1)
 	}
 
 	models, err := axiom.ListMappings(*token.AccessToken, map[string]interface{}{
-		"workgroup_id": common.WorkgroupID,
+		"workgroup_id": ctx.WorkgroupID,
 		"ref":          ref,
 		"page":         fmt.Sprintf("%d", page),
 		"rpp":          fmt.Sprintf("%d", rpp),
 	})
 	if err != nil {
 		log.Printf("failed to retrieve axiom domain models; %s", err.Error())
-		os.Exit(1)
+		common.Exit(1)
 	}
 
 	if len(models) == 0 {
@@ -117,8 +118,8 @@ func listModelsTypePrompt() {
 }
 
 func init() {
-	listBaselineDomainModelsCmd.Flags().StringVar(&common.OrganizationID, "organization", os.Getenv("PROVIDE_ORGANIZATION_ID"), "organization identifier")
-	listBaselineDomainModelsCmd.Flags().StringVar(&common.WorkgroupID, "workgroup", "", "workgroup identifier")
+	listBaselineDomainModelsCmd.Flags().String("organization", os.Getenv("PROVIDE_ORGANIZATION_ID"), "organization identifier")
+	listBaselineDomainModelsCmd.Flags().String("workgroup", "", "workgroup identifier")
 	listBaselineDomainModelsCmd.Flags().StringVar(&name, "type", "", "domain model type")
 
 	listBaselineDomainModelsCmd.Flags().Uint64Var(&page, "page", common.DefaultPage, "page number to retrieve")
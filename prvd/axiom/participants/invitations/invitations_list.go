@@ -19,7 +19,6 @@ package invitations
 import (
 	"fmt"
 	"log"
-	"os"
 
 	"github.com/provideplatform/provide-cli/prvd/common"
 	"github.com/provideplatform/provide-go/api/ident"
@@ -41,28 +40,29 @@ func listInvitations(cmd *cobra.Command, args []string) {
 }
 
 func listInvitationsRun(cmd *cobra.Command, args []string) {
-	if common.OrganizationID == "" {
-		common.RequireOrganization()
+	ctx := common.ContextFromCommand(cmd)
+	if ctx.OrganizationID == "" {
+		common.RequireOrganization(ctx)
 	}
-	if common.WorkgroupID == "" {
-		common.RequireWorkgroup()
+	if ctx.WorkgroupID == "" {
+		common.RequireWorkgroup(ctx)
 	}
 
-	common.AuthorizeOrganizationContext(false)
+	common.AuthorizeOrganizationContext(ctx, false)
 
-	token, err := common.ResolveOrganizationToken()
+	token, err := common.ResolveOrganizationToken(ctx)
 	if err != nil {
 		log.Printf("failed to fetch axiom workgroup invitations; %s", err.Error())
-		os.Exit(1)
+		common.Exit(1)
 	}
 
-	invitations, err := ident.ListApplicationInvitations(*token.AccessToken, common.WorkgroupID, map[string]interface{}{
+	invitations, err := ident.ListApplicationInvitations(*token.AccessToken, ctx.WorkgroupID, map[string]interface{}{
 		"page": fmt.Sprintf("%d", page),
 		"rpp":  fmt.Sprintf("%d", rpp),
 	})
 	if err != nil {
 		log.Printf("failed to fetch axiom workgroup invitations; %s", err.Error())
-		os.Exit(1)
+		common.Exit(1)
 	}
 
 	if len(invitations) == 0 {
@@ -76,8 +76,8 @@ func listInvitationsRun(cmd *cobra.Command, args []string) {
 }
 
 func init() {
-	listBaselineWorkgroupInvitationsCmd.Flags().StringVar(&common.OrganizationID, "organization", "", "organization identifier")
-	listBaselineWorkgroupInvitationsCmd.Flags().StringVar(&common.WorkgroupID, "workgroup", "", "workgroup identifier")
+	listBaselineWorkgroupInvitationsCmd.Flags().String("organization", "", "organization identifier")
+	listBaselineWorkgroupInvitationsCmd.Flags().String("workgroup", "", "workgroup identifier")
 
 	listBaselineWorkgroupInvitationsCmd.Flags().Uint64Var(&page, "page", common.DefaultPage, "page number to retrieve")
 	listBaselineWorkgroupInvitationsCmd.Flags().Uint64Var(&rpp, "rpp", common.DefaultRpp, "number of participants to retrieve per page")
@@ -21,7 +21,6 @@ import (
 	"encoding/hex"
 	"fmt"
 	"log"
-	"os"
 	"strings"
 	"time"
 
@@ -57,11 +56,12 @@ func inviteOrganization(cmd *cobra.Command, args []string) {
 }
 
 func inviteOrganizationRun(cmd *cobra.Command, args []string) {
-	if common.OrganizationID == "" {
-		common.RequireOrganization()
+	ctx := common.ContextFromCommand(cmd)
+	if ctx.OrganizationID == "" {
+		common.RequireOrganization(ctx)
 	}
-	if common.WorkgroupID == "" {
-		common.RequireWorkgroup()
+	if ctx.WorkgroupID == "" {
+		common.RequireWorkgroup(ctx)
 	}
 	if firstName == "" {
 		firstNamePrompt()
@@ -76,14 +76,14 @@ func inviteOrganizationRun(cmd *cobra.Command, args []string) {
 		orgNamePrompt()
 	}
 
-	common.AuthorizeOrganizationContext(false)
+	common.AuthorizeOrganizationContext(ctx, false)
 
-	token, err := common.ResolveOrganizationToken()
+	token, err := common.ResolveOrganizationToken(ctx)
 
 	vaults, err := vault.ListVaults(*token.AccessToken, map[string]interface{}{})
 	if err != nil {
 		log.Printf("failed to resolve vault for organization; %s", err.Error())
-		os.Exit(1)
+		common.Exit(1)
 	}
 	orgVaultID := vaults[0].ID.String()
 
@@ -92,7 +92,7 @@ func inviteOrganizationRun(cmd *cobra.Command, args []string) {
 	})
 	if err != nil {
 		log.Printf("failed to resolve secp256k1 key for organization; %s", err.Error())
-		os.Exit(1)
+		common.Exit(1)
 	}
 	secp256k1KeyAddress := keys[0].Address
 
@@ -101,65 +101,65 @@ func inviteOrganizationRun(cmd *cobra.Command, args []string) {
 	})
 	if err != nil {
 		log.Printf("failed to resolve contract for organization; %s", err.Error())
-		os.Exit(1)
+		common.Exit(1)
 	}
 	orgRegistryAddress := contracts[0].Address
 
-	if common.SubjectAccountID == "" {
-		common.SubjectAccountID = common.SHA256(fmt.Sprintf("%s.%s", common.OrganizationID, common.WorkgroupID))
+	if ctx.SubjectAccountID == "" {
+		ctx.SubjectAccountID = common.SHA256(fmt.Sprintf("%s.%s", ctx.OrganizationID, ctx.WorkgroupID))
 	}
 
 	jwtParams := map[string]interface{}{
 		"invitor_organization_address": secp256k1KeyAddress,
 		"registry_contract_address":    orgRegistryAddress,
-		"workgroup_id":                 common.WorkgroupID,
-		"invitor_subject_account_id":   common.SubjectAccountID,
+		"workgroup_id":                 ctx.WorkgroupID,
+		"invitor_subject_account_id":   ctx.SubjectAccountID,
 	}
 
-	authorizedBearerToken := vendJWT(orgVaultID, jwtParams)
+	authorizedBearerToken := vendJWT(ctx, orgVaultID, jwtParams)
 
-	wgID, _ := uuid.FromString(common.WorkgroupID)
+	wgID, _ := uuid.FromString(ctx.WorkgroupID)
 
 	inviteParams := map[string]interface{}{
 		"first_name":        firstName,
 		"last_name":         lastName,
 		"email":             email,
 		"organization_name": orgName,
-		"application_id":    common.WorkgroupID, // FIXME-- should be workgroup id
+		"application_id":    ctx.WorkgroupID, // FIXME-- should be workgroup id
 		"params": map[string]interface{}{
 			"verifiable_credential":      authorizedBearerToken,
 			"is_organization_invite":     true,
-			"operator_separation_degree": common.Organization.Metadata.Workgroups[wgID].OperatorSeparationDegree + 1,
-			"workgroup":                  common.Workgroup,
+			"operator_separation_degree": ctx.Organization.Metadata.Workgroups[wgID].OperatorSeparationDegree + 1,
+			"workgroup":                  ctx.Workgroup,
 		},
 	}
 
 	if err := ident.CreateInvitation(*token.AccessToken, inviteParams); err != nil {
 		log.Printf("failed to invite axiom workgroup user; %s", err.Error())
-		os.Exit(1)
+		common.Exit(1)
 	}
 
 	log.Printf("invited axiom workgroup organization: %s\n", orgName)
 }
 
-func vendJWT(vaultID string, params map[string]interface{}) string {
-	keys, err := vault.ListKeys(common.OrganizationAccessToken, vaultID, map[string]interface{}{
+func vendJWT(ctx *common.Context, vaultID string, params map[string]interface{}) string {
+	keys, err := vault.ListKeys(ctx.OrganizationAccessToken, vaultID, map[string]interface{}{
 		"spec": "RSA-4096",
 	})
 	if err != nil {
 		log.Printf("failed to resolve RSA-4096 key for organization; %s", err.Error())
-		os.Exit(1)
+		common.Exit(1)
 	}
 	if len(keys) == 0 {
 		log.Print("failed to resolve RSA-4096 key for organization")
-		os.Exit(1)
+		common.Exit(1)
 	}
 	key := keys[0]
 
-	org, err := ident.GetOrganizationDetails(common.OrganizationAccessToken, common.OrganizationID, map[string]interface{}{})
+	org, err := ident.GetOrganizationDetails(ctx.OrganizationAccessToken, ctx.OrganizationID, map[string]interface{}{})
 	if err != nil {
 		log.Printf("failed to vend JWT; %s", err.Error())
-		os.Exit(1)
+		common.Exit(1)
 	}
 
 	issuedAt := time.Now()
@@ -167,7 +167,7 @@ func vendJWT(vaultID string, params map[string]interface{}) string {
 	claims := map[string]interface{}{
 		"aud":   org.Metadata["messaging_endpoint"],
 		"iat":   issuedAt.Unix(),
-		"iss":   common.OrganizationID,
+		"iss":   ctx.OrganizationID,
 		"sub":   email,
 		"axiom": params,
 	}
@@ -175,7 +175,7 @@ func vendJWT(vaultID string, params map[string]interface{}) string {
 	natsClaims, err := encodeJWTNatsClaims()
 	if err != nil {
 		log.Printf("failed to encode NATS claims in JWT; %s", err.Error())
-		os.Exit(1)
+		common.Exit(1)
 	}
 	if natsClaims != nil {
 		claims["nats"] = natsClaims
@@ -184,13 +184,13 @@ func vendJWT(vaultID string, params map[string]interface{}) string {
 	publicKey, err := pgputil.DecodeRSAPublicKeyFromPEM([]byte(*key.PublicKey))
 	if err != nil {
 		log.Printf("failed to decode RSA public key from PEM; %s", err.Error())
-		os.Exit(1)
+		common.Exit(1)
 	}
 
 	sshPublicKey, err := ssh.NewPublicKey(publicKey)
 	if err != nil {
 		log.Printf("failed to decode SSH public key for fingerprinting; %s", err.Error())
-		os.Exit(1)
+		common.Exit(1)
 	}
 	fingerprint := ssh.FingerprintLegacyMD5(sshPublicKey)
 
@@ -200,7 +200,7 @@ func vendJWT(vaultID string, params map[string]interface{}) string {
 	strToSign, err := jwtToken.SigningString()
 	if err != nil {
 		log.Printf("failed to generate JWT string for signing; %s", err.Error())
-		os.Exit(1)
+		common.Exit(1)
 	}
 
 	opts := map[string]interface{}{}
@@ -209,7 +209,7 @@ func vendJWT(vaultID string, params map[string]interface{}) string {
 	}
 
 	resp, err := vault.SignMessage(
-		common.OrganizationAccessToken,
+		ctx.OrganizationAccessToken,
 		key.VaultID.String(),
 		key.ID.String(),
 		hex.EncodeToString([]byte(strToSign)),
@@ -217,13 +217,13 @@ func vendJWT(vaultID string, params map[string]interface{}) string {
 	)
 	if err != nil {
 		log.Printf("WARNING: failed to sign JWT using vault key: %s; %s", key.ID, err.Error())
-		os.Exit(1)
+		common.Exit(1)
 	}
 
 	sigAsBytes, err := hex.DecodeString(*resp.Signature)
 	if err != nil {
 		log.Printf("failed to decode signature from hex; %s", err.Error())
-		os.Exit(1)
+		common.Exit(1)
 	}
 
 	encodedSignature := strings.TrimRight(base64.URLEncoding.EncodeToString(sigAsBytes), "=")
@@ -314,7 +314,7 @@ func firstNamePrompt() {
 
 	result, err := prompt.Run()
 	if err != nil {
-		os.Exit(1)
+		common.Exit(1)
 		return
 	}
 
@@ -335,7 +335,7 @@ func lastNamePrompt() {
 
 	result, err := prompt.Run()
 	if err != nil {
-		os.Exit(1)
+		common.Exit(1)
 		return
 	}
 
@@ -350,7 +350,7 @@ func emailPrompt() {
 
 	result, err := prompt.Run()
 	if err != nil {
-		os.Exit(1)
+		common.Exit(1)
 		return
 	}
 
@@ -371,7 +371,7 @@ func orgNamePrompt() {
 
 	result, err := prompt.Run()
 	if err != nil {
-		os.Exit(1)
+		common.Exit(1)
 		return
 	}
 
@@ -379,9 +379,9 @@ func orgNamePrompt() {
 }
 
 func init() {
-	inviteBaselineWorkgroupOrganizationCmd.Flags().StringVar(&common.WorkgroupID, "workgroup", "", "workgroup identifier")
-	inviteBaselineWorkgroupOrganizationCmd.Flags().StringVar(&common.OrganizationID, "organization", "", "organization identifier")
-	inviteBaselineWorkgroupOrganizationCmd.Flags().StringVar(&common.SubjectAccountID, "subject-account", "", "subject account identifier")
+	inviteBaselineWorkgroupOrganizationCmd.Flags().String("workgroup", "", "workgroup identifier")
+	inviteBaselineWorkgroupOrganizationCmd.Flags().String("organization", "", "organization identifier")
+	inviteBaselineWorkgroupOrganizationCmd.Flags().String("subject-account", "", "subject account identifier")
 	inviteBaselineWorkgroupOrganizationCmd.Flags().StringVar(&firstName, "first-name", "", "first name of the invited participant")
 	inviteBaselineWorkgroupOrganizationCmd.Flags().StringVar(&lastName, "last-name", "", "last name of the invited participant")
 	inviteBaselineWorkgroupOrganizationCmd.Flags().StringVar(&email, "email", "", "email address of the invited participant")
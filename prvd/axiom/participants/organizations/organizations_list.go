@@ -19,7 +19,6 @@ package organizations
 import (
 	"fmt"
 	"log"
-	"os"
 
 	"github.com/provideplatform/provide-cli/prvd/common"
 	"github.com/provideplatform/provide-go/api/ident"
@@ -41,28 +40,29 @@ func listOrganizations(cmd *cobra.Command, args []string) {
 }
 
 func listOrganizationsRun(cmd *cobra.Command, args []string) {
-	if common.OrganizationID == "" {
-		common.RequireOrganization()
+	ctx := common.ContextFromCommand(cmd)
+	if ctx.OrganizationID == "" {
+		common.RequireOrganization(ctx)
 	}
-	if common.WorkgroupID == "" {
-		common.RequireWorkgroup()
+	if ctx.WorkgroupID == "" {
+		common.RequireWorkgroup(ctx)
 	}
 
-	common.AuthorizeOrganizationContext(false)
+	common.AuthorizeOrganizationContext(ctx, false)
 
-	token, err := common.ResolveOrganizationToken()
+	token, err := common.ResolveOrganizationToken(ctx)
 	if err != nil {
 		log.Printf("failed to fetch axiom workgroup organizations; %s", err.Error())
-		os.Exit(1)
+		common.Exit(1)
 	}
 
-	orgs, err := ident.ListApplicationOrganizations(*token.AccessToken, common.WorkgroupID, map[string]interface{}{
+	orgs, err := ident.ListApplicationOrganizations(*token.AccessToken, ctx.WorkgroupID, map[string]interface{}{
 		"page": fmt.Sprintf("%d", page),
 		"rpp":  fmt.Sprintf("%d", rpp),
 	})
 	if err != nil {
 		log.Printf("failed to fetch axiom workgroup organizations; %s", err.Error())
-		os.Exit(1)
+		common.Exit(1)
 	}
 
 	for _, org := range orgs {
@@ -72,8 +72,8 @@ func listOrganizationsRun(cmd *cobra.Command, args []string) {
 }
 
 func init() {
-	listBaselineWorkgroupOrganizationsCmd.Flags().StringVar(&common.OrganizationID, "organization", "", "organization identifier")
-	listBaselineWorkgroupOrganizationsCmd.Flags().StringVar(&common.WorkgroupID, "workgroup", "", "workgroup identifier")
+	listBaselineWorkgroupOrganizationsCmd.Flags().String("organization", "", "organization identifier")
+	listBaselineWorkgroupOrganizationsCmd.Flags().String("workgroup", "", "workgroup identifier")
 
 	listBaselineWorkgroupOrganizationsCmd.Flags().Uint64Var(&page, "page", common.DefaultPage, "page number to retrieve")
 	listBaselineWorkgroupOrganizationsCmd.Flags().Uint64Var(&rpp, "rpp", common.DefaultRpp, "number of participants to retrieve per page")
@@ -19,7 +19,6 @@ package users
 import (
 	"fmt"
 	"log"
-	"os"
 
 	"github.com/manifoldco/promptui"
 	"github.com/provideplatform/provide-cli/prvd/common"
@@ -50,11 +49,12 @@ func inviteUser(cmd *cobra.Command, args []string) {
 }
 
 func inviteUserRun(cmd *cobra.Command, args []string) {
-	if common.OrganizationID == "" {
-		common.RequireOrganization()
+	ctx := common.ContextFromCommand(cmd)
+	if ctx.OrganizationID == "" {
+		common.RequireOrganization(ctx)
 	}
-	if common.WorkgroupID == "" {
-		common.RequireWorkgroup()
+	if ctx.WorkgroupID == "" {
+		common.RequireWorkgroup(ctx)
 	}
 	if firstName == "" {
 		firstNamePrompt()
@@ -66,29 +66,29 @@ func inviteUserRun(cmd *cobra.Command, args []string) {
 		emailPrompt()
 	}
 
-	common.AuthorizeOrganizationContext(false)
+	common.AuthorizeOrganizationContext(ctx, false)
 
-	token, err := common.ResolveOrganizationToken()
+	token, err := common.ResolveOrganizationToken(ctx)
 	if err != nil {
 		log.Printf("failed to invite axiom workgroup user; %s", err.Error())
-		os.Exit(1)
+		common.Exit(1)
 	}
 
 	inviteParams := map[string]interface{}{
 		"first_name":        firstName,
 		"last_name":         lastName,
 		"email":             email,
-		"organization_name": common.Organization.Name,
-		"application_id":    common.WorkgroupID, // FIXME--
+		"organization_name": ctx.Organization.Name,
+		"application_id":    ctx.WorkgroupID, // FIXME--
 		"params": map[string]interface{}{
-			"workgroup":                   common.Workgroup,
+			"workgroup":                   ctx.Workgroup,
 			"is_organization_user_invite": true,
 		},
 	}
 
 	if err := ident.CreateInvitation(*token.AccessToken, inviteParams); err != nil {
 		log.Printf("failed to invite axiom workgroup user; %s", err.Error())
-		os.Exit(1)
+		common.Exit(1)
 	}
 
 	log.Printf("invited axiom workgroup user: %s\n", email)
@@ -108,7 +108,7 @@ func firstNamePrompt() {
 
 	result, err := prompt.Run()
 	if err != nil {
-		os.Exit(1)
+		common.Exit(1)
 		return
 	}
 
@@ -129,7 +129,7 @@ func lastNamePrompt() {
 
 	result, err := prompt.Run()
 	if err != nil {
-		os.Exit(1)
+		common.Exit(1)
 		return
 	}
 
@@ -144,7 +144,7 @@ func emailPrompt() {
 
 	result, err := prompt.Run()
 	if err != nil {
-		os.Exit(1)
+		common.Exit(1)
 		return
 	}
 
@@ -152,9 +152,9 @@ func emailPrompt() {
 }
 
 func init() {
-	inviteBaselineWorkgroupUserCmd.Flags().StringVar(&common.WorkgroupID, "workgroup", "", "workgroup identifier")
-	inviteBaselineWorkgroupUserCmd.Flags().StringVar(&common.OrganizationID, "organization", "", "organization identifier")
-	inviteBaselineWorkgroupUserCmd.Flags().StringVar(&common.SubjectAccountID, "subject-account", "", "subject account identifier")
+	inviteBaselineWorkgroupUserCmd.Flags().String("workgroup", "", "workgroup identifier")
+	inviteBaselineWorkgroupUserCmd.Flags().String("organization", "", "organization identifier")
+	inviteBaselineWorkgroupUserCmd.Flags().String("subject-account", "", "subject account identifier")
 	inviteBaselineWorkgroupUserCmd.Flags().StringVar(&firstName, "first-name", "", "first name of the invited participant")
 	inviteBaselineWorkgroupUserCmd.Flags().StringVar(&lastName, "last-name", "", "last name of the invited participant")
 	inviteBaselineWorkgroupUserCmd.Flags().StringVar(&email, "email", "", "email address of the invited participant")
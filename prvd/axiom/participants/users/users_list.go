@@ -19,7 +19,6 @@ package users
 import (
 	"fmt"
 	"log"
-	"os"
 
 	"github.com/provideplatform/provide-cli/prvd/common"
 	"github.com/provideplatform/provide-go/api/ident"
@@ -41,21 +40,22 @@ func listUsers(cmd *cobra.Command, args []string) {
 }
 
 func listUsersRun(cmd *cobra.Command, args []string) {
-	if common.OrganizationID == "" {
-		common.RequireOrganization()
+	ctx := common.ContextFromCommand(cmd)
+	if ctx.OrganizationID == "" {
+		common.RequireOrganization(ctx)
 	}
 
-	common.AuthorizeOrganizationContext(false)
+	common.AuthorizeOrganizationContext(ctx, false)
 
-	token, err := common.ResolveOrganizationToken()
+	token, err := common.ResolveOrganizationToken(ctx)
 
-	users, err := ident.ListOrganizationUsers(*token.AccessToken, common.OrganizationID, map[string]interface{}{
+	users, err := ident.ListOrganizationUsers(*token.AccessToken, ctx.OrganizationID, map[string]interface{}{
 		"page": fmt.Sprintf("%d", page),
 		"rpp":  fmt.Sprintf("%d", rpp),
 	})
 	if err != nil {
 		log.Printf("failed to fetch axiom workgroup users; %s", err.Error())
-		os.Exit(1)
+		common.Exit(1)
 	}
 
 	for _, user := range users {
@@ -65,7 +65,7 @@ func listUsersRun(cmd *cobra.Command, args []string) {
 }
 
 func init() {
-	listBaselineWorkgroupUsersCmd.Flags().StringVar(&common.OrganizationID, "organization", "", "organization identifier")
+	listBaselineWorkgroupUsersCmd.Flags().String("organization", "", "organization identifier")
 
 	listBaselineWorkgroupUsersCmd.Flags().Uint64Var(&page, "page", common.DefaultPage, "page number to retrieve")
 	listBaselineWorkgroupUsersCmd.Flags().Uint64Var(&rpp, "rpp", common.DefaultRpp, "number of participants to retrieve per page")
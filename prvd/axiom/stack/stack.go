@@ -17,10 +17,16 @@
 package stack
 
 import (
+	"strconv"
+
 	"github.com/provideplatform/provide-cli/prvd/common"
 	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
 )
 
+// DefaultName is the name of the default local axiom stack instance
+const DefaultName = "axiom-local"
+
 var StackCmd = &cobra.Command{
 	Use:   "stack",
 	Short: "Interact with a local axiom stack",
@@ -32,6 +38,15 @@ var StackCmd = &cobra.Command{
 	},
 }
 
+// ResetState restores the package-level state assigned by prompts, which is not bound to a flag;
+// the shell calls this prior to each in-process invocation
+func ResetState() {
+	dockerNetworkID = ""
+	natsServerName = ""
+	serviceImages = map[string]string{}
+	serviceEnvironment = map[string]map[string]string{}
+}
+
 var runBaselineStackCmd = &cobra.Command{
 	Use:   "run",
 	Short: "See `prvd axiom stack start --help` instead",
@@ -43,10 +58,65 @@ See: prvd axiom stack run --help instead. This command is deprecated and will be
 	},
 }
 
+// hostPortFlags maps the service names of a local axiom stack to the flags configuring their host ports
+var hostPortFlags = map[string]string{
+	"api":           "port",
+	"elasticsearch": "elasticsearch-port",
+	"ident":         "ident-local-port",
+	"nats":          "nats-port",
+	"nats-ws":       "nats-ws-port",
+	"nchain":        "nchain-local-port",
+	"postgres":      "postgres-port",
+	"privacy":       "privacy-local-port",
+	"redis":         "redis-port",
+	"vault":         "vault-local-port",
+}
+
+// DefaultHostPorts returns the default host port of each service in a local axiom stack
+func DefaultHostPorts() map[string]int {
+	return defaultHostPorts(startBaselineStackCmd.Flags())
+}
+
+// HostPorts returns the host port of each service of the named stack, i.e., the ports allocated
+// to the stack or the default host ports when none are allocated
+func HostPorts(stack string) map[string]int {
+	ports := DefaultHostPorts()
+	if allocated, ok := allocatedPorts()[stackPortsKey(stack)]; ok {
+		for svc, port := range allocated {
+			ports[svc] = port
+		}
+	}
+	return ports
+}
+
+func defaultHostPorts(flags *pflag.FlagSet) map[string]int {
+	ports := map[string]int{}
+	for svc, flag := range hostPortFlags {
+		if f := flags.Lookup(flag); f != nil {
+			if port, err := strconv.Atoi(f.DefValue); err == nil {
+				ports[svc] = port
+			}
+		}
+	}
+	return ports
+}
+
 func init() {
+	StackCmd.AddCommand(backupStackCmd)
+	StackCmd.AddCommand(configStackCmd)
+	StackCmd.AddCommand(dashboardStackCmd)
+	StackCmd.AddCommand(execStackCmd)
+	StackCmd.AddCommand(exportStackCmd)
+	StackCmd.AddCommand(listStackCmd)
 	StackCmd.AddCommand(logsBaselineStackCmd)
+	StackCmd.AddCommand(natsStackCmd)
+	StackCmd.AddCommand(preflightStackCmd)
+	StackCmd.AddCommand(psqlStackCmd)
+	StackCmd.AddCommand(redisCLIStackCmd)
+	StackCmd.AddCommand(restoreStackCmd)
 	StackCmd.AddCommand(runBaselineStackCmd)
 	StackCmd.AddCommand(startBaselineStackCmd)
+	StackCmd.AddCommand(statusStackCmd)
 	StackCmd.AddCommand(stopBaselineStackCmd)
 	StackCmd.Flags().BoolVarP(&Optional, "optional", "", false, "List all the optional flags")
 }
@@ -0,0 +1,808 @@
+/*
+ * Copyright 2017-2022 Provide Technologies Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package stack
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"log"
+	"os"
+	"path"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/client"
+	"github.com/docker/docker/pkg/stdcopy"
+	"github.com/provideplatform/provide-cli/prvd/common"
+	"github.com/spf13/cobra"
+)
+
+// backupVersion is the version of the backup archive format
+const backupVersion = 1
+
+const backupManifestFile = "manifest.json"
+const backupPostgresDir = "postgres"
+const backupPostgresRoles = "roles.sql"
+const backupElasticsearchDir = "elasticsearch"
+const backupNATSDir = "nats"
+
+// elasticsearchSnapshotDir is the path.repo of the local elasticsearch container
+const elasticsearchSnapshotDir = "/usr/share/elasticsearch/backup"
+const elasticsearchSnapshotRepository = "prvd-backup"
+
+var backupOutput string
+
+var backupStackCmd = &cobra.Command{
+	Use:   "backup",
+	Short: "Back up the data of the axiom stack",
+	Long: `Back up the data of a running local axiom stack instance into a single archive.
+
+The archive contains the postgres roles and a pg_dump of each database of the stack, i.e., the
+axiom database and those of the local ident, nchain and vault services, a snapshot of the
+elasticsearch indices and the NATS JetStream store, and can be restored using prvd axiom stack restore.`,
+	Run: backupStack,
+}
+
+var restoreStackCmd = &cobra.Command{
+	Use:   "restore <archive>",
+	Short: "Restore the data of the axiom stack from a backup",
+	Long: `Restore a running local axiom stack instance from an archive created by prvd axiom stack backup.
+
+The postgres databases, the elasticsearch indices and the NATS JetStream store of the stack are
+replaced with those of the archive; NATS is restarted to load the restored store.`,
+	Args: cobra.ExactArgs(1),
+	Run:  restoreStack,
+}
+
+// backupManifest describes the contents of a backup archive
+type backupManifest struct {
+	Version   int       `json:"version"`
+	Stack     string    `json:"stack"`
+	CreatedAt time.Time `json:"created_at"`
+	Snapshot  string    `json:"elasticsearch_snapshot"`
+	Databases []string  `json:"postgres_databases"`
+}
+
+func backupStack(cmd *cobra.Command, args []string) {
+	docker, err := client.NewEnvClient()
+	if err != nil {
+		log.Printf("failed to initialize docker; %s", err.Error())
+		common.Exit(1)
+	}
+	defer docker.Close()
+
+	for _, svc := range []string{"elasticsearch", "nats", "postgres"} {
+		if err := requireRunningContainer(docker, stackContainerName(svc)); err != nil {
+			log.Printf("failed to back up stack: %s; %s", name, err.Error())
+			common.Exit(1)
+		}
+	}
+
+	now := time.Now()
+	output := backupOutput
+	if output == "" {
+		output = fmt.Sprintf("%s-%s.tar.gz", strings.ReplaceAll(name, " ", ""), now.Format("20060102150405"))
+	}
+
+	err = writeBackup(docker, output, &backupManifest{
+		Version:   backupVersion,
+		Stack:     name,
+		CreatedAt: now.UTC(),
+		Snapshot:  strings.ToLower(fmt.Sprintf("%s-%d", strings.ReplaceAll(name, " ", ""), now.Unix())),
+	})
+	if err != nil {
+		log.Printf("failed to back up stack: %s; %s", name, err.Error())
+		common.Exit(1)
+	}
+
+	log.Printf("%s local axiom instance backed up to %s", name, output)
+}
+
+// writeBackup writes the backup archive to the given path; the archive is written to a
+// temporary file which replaces the path once the backup is complete
+func writeBackup(docker *client.Client, output string, manifest *backupManifest) error {
+	f, err := ioutil.TempFile(filepath.Dir(output), ".prvd-backup-")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(f.Name())
+	defer f.Close()
+
+	gw := gzip.NewWriter(f)
+	tw := tar.NewWriter(gw)
+
+	log.Printf("dumping postgres databases")
+	if err := backupPostgres(docker, tw, manifest); err != nil {
+		return fmt.Errorf("failed to dump postgres; %s", err.Error())
+	}
+
+	log.Printf("creating elasticsearch snapshot: %s", manifest.Snapshot)
+	if err := backupElasticsearch(docker, tw, manifest.Snapshot); err != nil {
+		return fmt.Errorf("failed to snapshot elasticsearch; %s", err.Error())
+	}
+
+	log.Printf("copying NATS JetStream store; NATS is paused during the copy")
+	if err := backupNATS(docker, tw); err != nil {
+		return fmt.Errorf("failed to copy NATS JetStream store; %s", err.Error())
+	}
+
+	raw, _ := json.MarshalIndent(manifest, "", "  ")
+	if err := writeTarFile(tw, backupManifestFile, raw); err != nil {
+		return err
+	}
+
+	if err := tw.Close(); err != nil {
+		return err
+	}
+	if err := gw.Close(); err != nil {
+		return err
+	}
+	if err := f.Close(); err != nil {
+		return err
+	}
+
+	return os.Rename(f.Name(), output)
+}
+
+// postgresSuperuser returns the superuser of the postgres container of the stack and the
+// environment with which to run postgres clients as the superuser
+func postgresSuperuser(docker *client.Client) (string, []string, error) {
+	env, err := containerEnv(docker, stackContainerName("postgres"))
+	if err != nil {
+		return "", nil, err
+	}
+
+	user := env["POSTGRES_USER"]
+	if user == "" {
+		user = "postgres"
+	}
+	return user, []string{fmt.Sprintf("PGPASSWORD=%s", env["POSTGRES_PASSWORD"])}, nil
+}
+
+// postgresDatabases returns the databases of the postgres container, other than the templates
+// and the maintenance database
+func postgresDatabases(docker *client.Client, user string, env []string) ([]string, error) {
+	var out bytes.Buffer
+	err := execContainer(docker, stackContainerName("postgres"), []string{
+		"psql", "-U", user, "-d", "postgres", "-At",
+		"-c", "SELECT datname FROM pg_database WHERE NOT datistemplate AND datname <> 'postgres' ORDER BY datname",
+	}, env, nil, &out)
+	if err != nil {
+		return nil, err
+	}
+	return strings.Fields(out.String()), nil
+}
+
+// backupPostgres writes the roles and a dump of each database of the stack to the archive
+func backupPostgres(docker *client.Client, tw *tar.Writer, manifest *backupManifest) error {
+	containerName := stackContainerName("postgres")
+	user, env, err := postgresSuperuser(docker)
+	if err != nil {
+		return err
+	}
+
+	manifest.Databases, err = postgresDatabases(docker, user, env)
+	if err != nil {
+		return err
+	}
+
+	dump := func(entry string, cmd []string) error {
+		f, err := ioutil.TempFile("", "prvd-pgdump-")
+		if err != nil {
+			return err
+		}
+		defer os.Remove(f.Name())
+		defer f.Close()
+
+		if err := execContainer(docker, containerName, cmd, env, nil, f); err != nil {
+			return err
+		}
+		return writeTarPath(tw, path.Join(backupPostgresDir, entry), f.Name())
+	}
+
+	if err := dump(backupPostgresRoles, []string{"pg_dumpall", "-U", user, "--roles-only"}); err != nil {
+		return err
+	}
+
+	for _, database := range manifest.Databases {
+		log.Printf("dumping postgres database: %s", database)
+		if err := dump(fmt.Sprintf("%s.dump", database), []string{"pg_dump", "-U", user, "-d", database, "-Fc"}); err != nil {
+			return fmt.Errorf("failed to dump database %s; %s", database, err.Error())
+		}
+	}
+
+	return nil
+}
+
+func backupElasticsearch(docker *client.Client, tw *tar.Writer, snapshot string) error {
+	containerName := stackContainerName("elasticsearch")
+	if err := resetSnapshotRepository(docker, containerName); err != nil {
+		return err
+	}
+	defer cleanupSnapshotRepository(docker, containerName)
+
+	var resp struct {
+		Snapshot struct {
+			State string `json:"state"`
+		} `json:"snapshot"`
+	}
+	err := elasticsearchRequest(docker, containerName, "PUT",
+		fmt.Sprintf("/_snapshot/%s/%s?wait_for_completion=true", elasticsearchSnapshotRepository, snapshot),
+		`{"indices":"*,-.*","include_global_state":false}`,
+		&resp,
+	)
+	if err != nil {
+		return err
+	}
+	if resp.Snapshot.State != "SUCCESS" {
+		return fmt.Errorf("snapshot %s completed with state %s", snapshot, resp.Snapshot.State)
+	}
+
+	return copyFromContainer(docker, tw, containerName, elasticsearchSnapshotDir, backupElasticsearchDir)
+}
+
+func restoreStack(cmd *cobra.Command, args []string) {
+	docker, err := client.NewEnvClient()
+	if err != nil {
+		log.Printf("failed to initialize docker; %s", err.Error())
+		common.Exit(1)
+	}
+	defer docker.Close()
+
+	for _, svc := range []string{"elasticsearch", "nats", "postgres"} {
+		if err := requireRunningContainer(docker, stackContainerName(svc)); err != nil {
+			log.Printf("failed to restore stack: %s; %s", name, err.Error())
+			common.Exit(1)
+		}
+	}
+
+	dir, err := ioutil.TempDir("", "prvd-restore-")
+	if err != nil {
+		log.Printf("failed to restore stack: %s; %s", name, err.Error())
+		common.Exit(1)
+	}
+	defer os.RemoveAll(dir)
+
+	if err := extractArchive(args[0], dir); err != nil {
+		log.Printf("failed to read backup: %s; %s", args[0], err.Error())
+		common.Exit(1)
+	}
+
+	manifest := &backupManifest{}
+	raw, err := ioutil.ReadFile(filepath.Join(dir, backupManifestFile))
+	if err == nil {
+		err = json.Unmarshal(raw, manifest)
+	}
+	if err != nil {
+		log.Printf("failed to read backup manifest: %s; %s", args[0], err.Error())
+		common.Exit(1)
+	}
+	if manifest.Version != backupVersion {
+		log.Printf("failed to restore stack: %s; unsupported backup version %d", name, manifest.Version)
+		common.Exit(1)
+	}
+
+	if err := restoreBackup(docker, dir, manifest); err != nil {
+		log.Printf("failed to restore stack: %s; %s", name, err.Error())
+		common.Exit(1)
+	}
+
+	log.Printf("%s local axiom instance restored from backup of %s created %s", name, manifest.Stack, manifest.CreatedAt.Format(time.RFC3339))
+}
+
+// restoreBackup restores the stack from the extracted backup in the given directory
+func restoreBackup(docker *client.Client, dir string, manifest *backupManifest) error {
+	log.Printf("restoring postgres databases")
+	if err := restorePostgres(docker, filepath.Join(dir, backupPostgresDir), manifest.Databases); err != nil {
+		return fmt.Errorf("failed to restore postgres; %s", err.Error())
+	}
+
+	log.Printf("restoring elasticsearch snapshot: %s", manifest.Snapshot)
+	if err := restoreElasticsearch(docker, filepath.Join(dir, backupElasticsearchDir), manifest.Snapshot); err != nil {
+		return fmt.Errorf("failed to restore elasticsearch; %s", err.Error())
+	}
+
+	log.Printf("restoring NATS JetStream store")
+	if err := restoreNATS(docker, filepath.Join(dir, backupNATSDir)); err != nil {
+		return fmt.Errorf("failed to restore NATS JetStream store; %s", err.Error())
+	}
+
+	return nil
+}
+
+// restorePostgres restores the roles and replaces each database of the backup; databases
+// missing from the stack are created
+func restorePostgres(docker *client.Client, dir string, databases []string) error {
+	containerName := stackContainerName("postgres")
+	user, env, err := postgresSuperuser(docker)
+	if err != nil {
+		return err
+	}
+
+	existing, err := postgresDatabases(docker, user, env)
+	if err != nil {
+		return err
+	}
+
+	roles, err := os.Open(filepath.Join(dir, backupPostgresRoles))
+	if err != nil {
+		return err
+	}
+	defer roles.Close()
+
+	// roles which exist, i.e., the superuser, fail to be created and are updated instead
+	err = execContainer(docker, containerName, []string{"psql", "-q", "-U", user, "-d", "postgres"}, env, roles, ioutil.Discard)
+	if err != nil {
+		return err
+	}
+
+	for _, database := range databases {
+		log.Printf("restoring postgres database: %s", database)
+		dump, err := os.Open(filepath.Join(dir, fmt.Sprintf("%s.dump", database)))
+		if err != nil {
+			return err
+		}
+
+		cmd := []string{"pg_restore", "-U", user, "--clean", "--if-exists", "-d", database}
+		if !containsString(existing, database) {
+			cmd = []string{"pg_restore", "-U", user, "--create", "-d", "postgres"}
+		}
+
+		err = execContainer(docker, containerName, cmd, env, dump, ioutil.Discard)
+		dump.Close()
+		if err != nil {
+			return fmt.Errorf("failed to restore database %s; %s", database, err.Error())
+		}
+	}
+
+	return nil
+}
+
+func containsString(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
+
+func restoreElasticsearch(docker *client.Client, dir, snapshot string) error {
+	containerName := stackContainerName("elasticsearch")
+	err := execContainer(docker, containerName, []string{"rm", "-rf", elasticsearchSnapshotDir}, nil, nil, ioutil.Discard)
+	if err != nil {
+		return err
+	}
+
+	err = copyToContainer(docker, containerName, dir, path.Dir(elasticsearchSnapshotDir), path.Base(elasticsearchSnapshotDir))
+	if err != nil {
+		return err
+	}
+
+	err = elasticsearchRequest(docker, containerName, "PUT", fmt.Sprintf("/_snapshot/%s", elasticsearchSnapshotRepository), snapshotRepositorySettings(), nil)
+	if err != nil {
+		return err
+	}
+	defer cleanupSnapshotRepository(docker, containerName)
+
+	var resp struct {
+		Snapshots []struct {
+			Indices []string `json:"indices"`
+		} `json:"snapshots"`
+	}
+	err = elasticsearchRequest(docker, containerName, "GET", fmt.Sprintf("/_snapshot/%s/%s", elasticsearchSnapshotRepository, snapshot), "", &resp)
+	if err != nil {
+		return err
+	}
+	if len(resp.Snapshots) == 0 {
+		return fmt.Errorf("snapshot %s not found", snapshot)
+	}
+
+	indices := make([]string, 0)
+	for _, index := range resp.Snapshots[0].Indices {
+		if !strings.HasPrefix(index, ".") {
+			indices = append(indices, index)
+		}
+	}
+	if len(indices) == 0 {
+		return nil
+	}
+
+	for _, index := range indices {
+		err := elasticsearchRequest(docker, containerName, "DELETE", fmt.Sprintf("/%s?ignore_unavailable=true", index), "", nil)
+		if err != nil {
+			return err
+		}
+	}
+
+	body, _ := json.Marshal(map[string]interface{}{
+		"indices":              strings.Join(indices, ","),
+		"include_global_state": false,
+	})
+	return elasticsearchRequest(docker, containerName, "POST",
+		fmt.Sprintf("/_snapshot/%s/%s/_restore?wait_for_completion=true", elasticsearchSnapshotRepository, snapshot),
+		string(body),
+		nil,
+	)
+}
+
+// restoreNATS replaces the JetStream store of the NATS container; the store is cleared while
+// NATS is running and copied into the stopped container, which is then restarted
+func restoreNATS(docker *client.Client, dir string) error {
+	containerName := stackContainerName("nats")
+	err := execContainer(docker, containerName, []string{"sh", "-c", fmt.Sprintf("rm -rf %s/*", natsJetStreamStoreDir)}, nil, nil, ioutil.Discard)
+	if err != nil {
+		return err
+	}
+
+	timeout := time.Millisecond * 5000
+	if err := docker.ContainerStop(context.Background(), containerName, &timeout); err != nil {
+		return err
+	}
+
+	if _, err := os.Stat(dir); err == nil {
+		err = copyToContainer(docker, containerName, dir, path.Dir(natsJetStreamStoreDir), path.Base(natsJetStreamStoreDir))
+		if err != nil {
+			return err
+		}
+	}
+
+	return docker.ContainerStart(context.Background(), containerName, types.ContainerStartOptions{})
+}
+
+// stackContainerName returns the name of the container of the given service of the current stack
+func stackContainerName(service string) string {
+	return fmt.Sprintf("%s-%s", strings.ReplaceAll(name, " ", ""), service)
+}
+
+// containerEnv returns the environment of the given container
+func containerEnv(docker *client.Client, containerName string) (map[string]string, error) {
+	inspect, err := docker.ContainerInspect(context.Background(), containerName)
+	if err != nil {
+		return nil, err
+	}
+
+	env := map[string]string{}
+	for _, envvar := range inspect.Config.Env {
+		if i := strings.Index(envvar, "="); i > 0 {
+			env[envvar[:i]] = envvar[i+1:]
+		}
+	}
+	return env, nil
+}
+
+func requireRunningContainer(docker *client.Client, containerName string) error {
+	inspect, err := docker.ContainerInspect(context.Background(), containerName)
+	if err != nil {
+		return err
+	}
+	if inspect.State == nil || !inspect.State.Running {
+		return fmt.Errorf("container %s is not running", containerName)
+	}
+	return nil
+}
+
+// execContainer runs the command in the given container with the additional environment, streaming
+// stdin to the command and its stdout to the given writer; a non-zero exit code is returned as an
+// error with the stderr output
+func execContainer(docker *client.Client, containerName string, cmd, env []string, stdin io.Reader, stdout io.Writer) error {
+	exec, err := docker.ContainerExecCreate(context.Background(), containerName, types.ExecConfig{
+		AttachStdin:  stdin != nil,
+		AttachStdout: true,
+		AttachStderr: true,
+		Env:          env,
+		Cmd:          cmd,
+	})
+	if err != nil {
+		return err
+	}
+
+	resp, err := docker.ContainerExecAttach(context.Background(), exec.ID, types.ExecStartCheck{})
+	if err != nil {
+		return err
+	}
+	defer resp.Close()
+
+	if stdin != nil {
+		go func() {
+			io.Copy(resp.Conn, stdin)
+			resp.CloseWrite()
+		}()
+	}
+
+	stderr := &bytes.Buffer{}
+	if _, err := stdcopy.StdCopy(stdout, stderr, resp.Reader); err != nil {
+		return err
+	}
+
+	inspect, err := docker.ContainerExecInspect(context.Background(), exec.ID)
+	if err != nil {
+		return err
+	}
+	if inspect.ExitCode != 0 {
+		return fmt.Errorf("%s exited with code %d; %s", cmd[0], inspect.ExitCode, strings.TrimSpace(stderr.String()))
+	}
+	return nil
+}
+
+// elasticsearchRequest sends a request to the elasticsearch API using curl within the given
+// elasticsearch container and unmarshals the response into resp, if given
+func elasticsearchRequest(docker *client.Client, containerName, method, uri, body string, resp interface{}) error {
+	env, err := containerEnv(docker, containerName)
+	if err != nil {
+		return err
+	}
+	password := env["ELASTIC_PASSWORD"]
+
+	var out bytes.Buffer
+	for _, scheme := range []string{"http", "https"} {
+		cmd := []string{
+			"curl", "-s", "-k",
+			"-u", fmt.Sprintf("elastic:%s", password),
+			"-X", method,
+			"-H", "Content-Type: application/json",
+			"-w", "\n%{http_code}",
+		}
+		if body != "" {
+			cmd = append(cmd, "-d", body)
+		}
+		cmd = append(cmd, fmt.Sprintf("%s://localhost:%d%s", scheme, defaultElasticContainerPort, uri))
+
+		out.Reset()
+		err = execContainer(docker, containerName, cmd, nil, nil, &out)
+		if err == nil {
+			break
+		}
+	}
+	if err != nil {
+		return err
+	}
+
+	raw := strings.TrimSpace(out.String())
+	i := strings.LastIndex(raw, "\n")
+	status, _ := strconv.Atoi(raw[i+1:])
+	payload := raw[:i+1]
+	if status < 200 || status >= 300 {
+		return fmt.Errorf("%s %s returned status %d; %s", method, uri, status, strings.TrimSpace(payload))
+	}
+
+	if resp != nil {
+		return json.Unmarshal([]byte(payload), resp)
+	}
+	return nil
+}
+
+func snapshotRepositorySettings() string {
+	return fmt.Sprintf(`{"type":"fs","settings":{"location":"%s"}}`, elasticsearchSnapshotDir)
+}
+
+// resetSnapshotRepository registers an empty snapshot repository at elasticsearchSnapshotDir
+func resetSnapshotRepository(docker *client.Client, containerName string) error {
+	cleanupSnapshotRepository(docker, containerName)
+	return elasticsearchRequest(docker, containerName, "PUT", fmt.Sprintf("/_snapshot/%s", elasticsearchSnapshotRepository), snapshotRepositorySettings(), nil)
+}
+
+// cleanupSnapshotRepository unregisters the snapshot repository and removes its files
+func cleanupSnapshotRepository(docker *client.Client, containerName string) {
+	elasticsearchRequest(docker, containerName, "DELETE", fmt.Sprintf("/_snapshot/%s", elasticsearchSnapshotRepository), "", nil)
+	execContainer(docker, containerName, []string{"rm", "-rf", elasticsearchSnapshotDir}, nil, nil, ioutil.Discard)
+}
+
+// backupNATS writes the JetStream store of the NATS container to the archive; the container is
+// paused while the store is copied so the archive holds a consistent view of its streams
+func backupNATS(docker *client.Client, tw *tar.Writer) error {
+	containerName := stackContainerName("nats")
+	if err := docker.ContainerPause(context.Background(), containerName); err != nil {
+		return err
+	}
+	defer func() {
+		if err := docker.ContainerUnpause(context.Background(), containerName); err != nil {
+			log.Printf("WARNING: failed to unpause container: %s; %s", containerName, err.Error())
+		}
+	}()
+
+	return copyFromContainer(docker, tw, containerName, natsJetStreamStoreDir, backupNATSDir)
+}
+
+// copyFromContainer writes the contents of the directory of the given container to the archive under prefix
+func copyFromContainer(docker *client.Client, tw *tar.Writer, containerName, srcPath, prefix string) error {
+	reader, _, err := docker.CopyFromContainer(context.Background(), containerName, srcPath)
+	if err != nil {
+		return err
+	}
+	defer reader.Close()
+
+	tr := tar.NewReader(reader)
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		// entries are relative to the parent of srcPath, i.e., backup/index-0
+		parts := strings.SplitN(hdr.Name, "/", 2)
+		if len(parts) < 2 || parts[1] == "" {
+			continue
+		}
+		hdr.Name = path.Join(prefix, parts[1])
+		if hdr.Typeflag == tar.TypeDir {
+			hdr.Name += "/"
+		}
+
+		if err := tw.WriteHeader(hdr); err != nil {
+			return err
+		}
+		if _, err := io.Copy(tw, tr); err != nil {
+			return err
+		}
+	}
+}
+
+// copyToContainer copies the contents of the local directory to dstPath/base within the given container
+func copyToContainer(docker *client.Client, containerName, dir, dstPath, base string) error {
+	pr, pw := io.Pipe()
+	go func() {
+		tw := tar.NewWriter(pw)
+		err := filepath.Walk(dir, func(p string, info os.FileInfo, err error) error {
+			if err != nil {
+				return err
+			}
+
+			rel, err := filepath.Rel(dir, p)
+			if err != nil {
+				return err
+			}
+
+			hdr, err := tar.FileInfoHeader(info, "")
+			if err != nil {
+				return err
+			}
+			hdr.Name = path.Join(base, filepath.ToSlash(rel))
+			if info.IsDir() {
+				hdr.Name += "/"
+			}
+
+			if err := tw.WriteHeader(hdr); err != nil {
+				return err
+			}
+			if info.Mode().IsRegular() {
+				f, err := os.Open(p)
+				if err != nil {
+					return err
+				}
+				defer f.Close()
+				_, err = io.Copy(tw, f)
+				return err
+			}
+			return nil
+		})
+		if err == nil {
+			err = tw.Close()
+		}
+		pw.CloseWithError(err)
+	}()
+
+	return docker.CopyToContainer(context.Background(), containerName, dstPath, pr, types.CopyToContainerOptions{
+		AllowOverwriteDirWithFile: true,
+		CopyUIDGID:                true,
+	})
+}
+
+func writeTarFile(tw *tar.Writer, name string, raw []byte) error {
+	err := tw.WriteHeader(&tar.Header{
+		Name:    name,
+		Mode:    0644,
+		Size:    int64(len(raw)),
+		ModTime: time.Now(),
+	})
+	if err != nil {
+		return err
+	}
+	_, err = tw.Write(raw)
+	return err
+}
+
+func writeTarPath(tw *tar.Writer, name, src string) error {
+	f, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	info, err := f.Stat()
+	if err != nil {
+		return err
+	}
+
+	hdr, err := tar.FileInfoHeader(info, "")
+	if err != nil {
+		return err
+	}
+	hdr.Name = name
+
+	if err := tw.WriteHeader(hdr); err != nil {
+		return err
+	}
+	_, err = io.Copy(tw, f)
+	return err
+}
+
+// extractArchive extracts the gzipped tar archive to the given directory
+func extractArchive(archive, dir string) error {
+	f, err := os.Open(archive)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	gr, err := gzip.NewReader(f)
+	if err != nil {
+		return err
+	}
+	defer gr.Close()
+
+	tr := tar.NewReader(gr)
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		target := filepath.Join(dir, filepath.FromSlash(path.Clean("/"+hdr.Name)))
+		switch hdr.Typeflag {
+		case tar.TypeDir:
+			if err := os.MkdirAll(target, 0755); err != nil {
+				return err
+			}
+		case tar.TypeReg:
+			if err := os.MkdirAll(filepath.Dir(target), 0755); err != nil {
+				return err
+			}
+			out, err := os.OpenFile(target, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, os.FileMode(hdr.Mode)&0755|0600)
+			if err != nil {
+				return err
+			}
+			_, err = io.Copy(out, tr)
+			out.Close()
+			if err != nil {
+				return err
+			}
+		}
+	}
+}
+
+func init() {
+	backupStackCmd.Flags().StringVar(&name, "name", "axiom-local", "name of the axiom stack instance")
+	backupStackCmd.Flags().StringVarP(&backupOutput, "output", "o", "", "path of the backup archive; defaults to <name>-<timestamp>.tar.gz")
+
+	restoreStackCmd.Flags().StringVar(&name, "name", "axiom-local", "name of the axiom stack instance")
+}
@@ -0,0 +1,543 @@
+/*
+ * Copyright 2017-2022 Provide Technologies Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package stack
+
+import (
+	"fmt"
+	"io/ioutil"
+	"log"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/provideplatform/provide-cli/prvd/common"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+	"gopkg.in/yaml.v2"
+)
+
+// stackConfigVersion is the version of the stack definition file schema
+const stackConfigVersion = 1
+
+const stackConfigKeyVersion = "version"
+const stackConfigKeyImage = "image"
+const stackConfigKeyEnv = "env"
+const stackConfigServicesPrefix = "services."
+const stackConfigRedacted = "[REDACTED]"
+
+// stackFile is the path of the stack definition file given using --file
+var stackFile string
+
+// stackConfigShowSecrets prints the secrets of the effective stack definition when true
+var stackConfigShowSecrets bool
+
+// stackConfigBinding binds a key of the stack definition file to the flag it configures
+type stackConfigBinding struct {
+	key  string
+	flag string
+}
+
+// stackConfigBindings binds the keys of the stack definition file to the flags of
+// `prvd axiom stack start`, in the order in which they are printed
+var stackConfigBindings = []stackConfigBinding{
+	{"name", "name"},
+	{"registry", "docker-registry"},
+	{"log_level", "log-level"},
+	{"database_logging", "database-logging"},
+	{"syslog_endpoint", "syslog-endpoint"},
+	{"upstream_dns", "upstream-dns"},
+	{"autoremove", "autoremove"},
+	{"prune", "prune"},
+	{"jwt_signer_public_key", "jwt-signer-public-key"},
+
+	{"organization.address", "organization-address"},
+	{"organization.registry_contract_address", "registry-contract-address"},
+	{"organization.nchain_network_id", "nchain-network-id"},
+
+	{"services.api.hostname", "hostname"},
+	{"services.api.port", "port"},
+	{"services.api.container_port", "container-port"},
+	{"services.api.local_build", "with-local-axiom-build"},
+	{"services.api.local_build_path", "axiom-dir-path"},
+	{"services.consumer.hostname", "consumer-hostname"},
+
+	{"services.elasticsearch.hostname", "elasticsearch-hostname"},
+	{"services.elasticsearch.port", "elasticsearch-port"},
+	{"services.elasticsearch.container_port", "elasticsearch-container-port"},
+	{"services.elasticsearch.scheme", "elasticsearch-scheme"},
+	{"services.elasticsearch.username", "elasticsearch-username"},
+	{"services.elasticsearch.password", "elasticsearch-password"},
+	{"services.elasticsearch.memory", "elasticsearch-memory"},
+	{"services.elasticsearch.insecure", "elasticsearch-ssl-insecure"},
+
+	{"services.nats.hostname", "nats-hostname"},
+	{"services.nats.port", "nats-port"},
+	{"services.nats.container_port", "nats-container-port"},
+	{"services.nats.websocket_port", "nats-ws-port"},
+	{"services.nats.websocket_container_port", "nats-ws-container-port"},
+	{"services.nats.websocket_tls", "nats-ws-tls"},
+	{"services.nats.auth_token", "nats-auth-token"},
+
+	{"services.postgres.hostname", "postgres-hostname"},
+	{"services.postgres.port", "postgres-port"},
+	{"services.postgres.container_port", "postgres-container-port"},
+	{"services.postgres.database", "postgres-database"},
+	{"services.postgres.user", "postgres-user"},
+	{"services.postgres.password", "postgres-password"},
+
+	{"services.redis.hostname", "redis-hostname"},
+	{"services.redis.port", "redis-port"},
+	{"services.redis.container_port", "redis-container-port"},
+	{"services.redis.hosts", "redis-hosts"},
+
+	{"services.ident.local", "with-local-ident"},
+	{"services.ident.port", "ident-local-port"},
+	{"services.ident.container_port", "ident-container-port"},
+	{"services.ident.host", "ident-host"},
+	{"services.ident.scheme", "ident-scheme"},
+
+	{"services.nchain.local", "with-local-nchain"},
+	{"services.nchain.port", "nchain-local-port"},
+	{"services.nchain.container_port", "nchain-container-port"},
+	{"services.nchain.host", "nchain-host"},
+	{"services.nchain.scheme", "nchain-scheme"},
+
+	{"services.privacy.local", "with-local-privacy"},
+	{"services.privacy.port", "privacy-local-port"},
+	{"services.privacy.container_port", "privacy-container-port"},
+	{"services.privacy.host", "privacy-host"},
+	{"services.privacy.scheme", "privacy-scheme"},
+
+	{"services.vault.local", "with-local-vault"},
+	{"services.vault.port", "vault-local-port"},
+	{"services.vault.container_port", "vault-container-port"},
+	{"services.vault.host", "vault-host"},
+	{"services.vault.scheme", "vault-scheme"},
+	{"services.vault.refresh_token", "vault-refresh-token"},
+	{"services.vault.seal_unseal_key", "vault-seal-unseal-key"},
+
+	{"sor.id", "sor"},
+	{"sor.url", "sor-url"},
+	{"sor.organization_code", "sor-organization-code"},
+	{"sor.sap.host", "sap-api-host"},
+	{"sor.sap.scheme", "sap-api-scheme"},
+	{"sor.sap.path", "sap-api-path"},
+	{"sor.sap.username", "sap-api-username"},
+	{"sor.sap.password", "sap-api-password"},
+	{"sor.servicenow.host", "servicenow-api-host"},
+	{"sor.servicenow.scheme", "servicenow-api-scheme"},
+	{"sor.servicenow.username", "servicenow-api-username"},
+	{"sor.servicenow.password", "servicenow-api-password"},
+	{"sor.salesforce.host", "salesforce-api-host"},
+	{"sor.salesforce.scheme", "salesforce-api-scheme"},
+	{"sor.salesforce.path", "salesforce-api-path"},
+	{"sor.azure_servicebus.connection_string", "azure-servicebus-connection-string"},
+
+	{"tunnels.enabled", "tunnel"},
+	{"tunnels.disabled", "without-tunnels"},
+	{"tunnels.api", "bpi-tunnel"},
+	{"tunnels.messaging", "messaging-tunnel"},
+	{"tunnels.websocket", "websocket-tunnel"},
+	{"tunnels.api_endpoint", "bpi-endpoint"},
+	{"tunnels.messaging_endpoint", "messaging-endpoint"},
+}
+
+// stackConfigSecrets are the keys of the stack definition file whose values are redacted when printed
+var stackConfigSecrets = map[string]bool{
+	"services.elasticsearch.password":        true,
+	"services.nats.auth_token":               true,
+	"services.postgres.password":             true,
+	"services.vault.refresh_token":           true,
+	"services.vault.seal_unseal_key":         true,
+	"sor.sap.password":                       true,
+	"sor.servicenow.password":                true,
+	"sor.azure_servicebus.connection_string": true,
+}
+
+// stackConfigSecretEnvironment are the substrings of environment variable names whose values are
+// redacted when printed
+var stackConfigSecretEnvironment = []string{"PASSWORD", "SECRET", "TOKEN", "KEY"}
+
+// stackServices are the services of the stack definition file and their default container images
+var stackServices = map[string]string{
+	"api":           axiomContainerImage,
+	"consumer":      axiomContainerImage,
+	"elasticsearch": elasticContainerImage,
+	"ident":         identContainerImage,
+	"nats":          natsContainerImage,
+	"nchain":        nchainContainerImage,
+	"postgres":      postgresContainerImage,
+	"privacy":       privacyContainerImage,
+	"redis":         redisContainerImage,
+	"vault":         vaultContainerImage,
+}
+
+// stackContainerServices maps the containers of a local axiom stack, named without the stack
+// name prefix, to the service of the stack definition file which configures them
+var stackContainerServices = map[string]string{
+	"api":                "api",
+	"consumer":           "consumer",
+	"elasticsearch":      "elasticsearch",
+	"ident-api":          "ident",
+	"ident-consumer":     "ident",
+	"nats":               "nats",
+	"nchain-api":         "nchain",
+	"nchain-consumer":    "nchain",
+	"postgres":           "postgres",
+	"privacy-api":        "privacy",
+	"privacy-consumer":   "privacy",
+	"reachabilitydaemon": "nchain",
+	"redis":              "redis",
+	"statsdaemon":        "nchain",
+	"vault-api":          "vault",
+}
+
+// serviceImages are the container image overrides of the stack definition file, by service
+var serviceImages = map[string]string{}
+
+// serviceEnvironment are the environment overrides of the stack definition file, by service
+var serviceEnvironment = map[string]map[string]string{}
+
+var configStackCmd = &cobra.Command{
+	Use:   "config",
+	Short: "Manage the axiom stack definition",
+	Long:  `Inspect the effective configuration of a local axiom stack defined using a stack definition file and flags`,
+	Run: func(cmd *cobra.Command, args []string) {
+		common.CmdExistsOrExit(cmd, args)
+	},
+}
+
+var configPrintStackCmd = &cobra.Command{
+	Use:   "print",
+	Short: "Print the effective axiom stack definition",
+	Long: `Print the effective configuration of a local axiom stack as a stack definition file.
+
+The stack definition file given using --file is merged with the defaults of
+prvd axiom stack start; flags override individual values of the file. Secrets,
+i.e., passwords and tokens, are redacted unless --show-secrets is given.`,
+	Run: printStackConfig,
+}
+
+func printStackConfig(cmd *cobra.Command, args []string) {
+	if err := loadStackConfig(cmd.Flags()); err != nil {
+		log.Printf("failed to load stack definition; %s", err.Error())
+		common.Exit(1)
+	}
+
+	if err := allocatePorts(cmd.Flags(), name, false); err != nil {
+		log.Printf("failed to allocate stack ports; %s", err.Error())
+		common.Exit(1)
+	}
+	normalizeHostnames()
+
+	raw, err := yaml.Marshal(effectiveStackConfig(cmd.Flags(), stackConfigShowSecrets))
+	if err != nil {
+		log.Printf("failed to print stack definition; %s", err.Error())
+		common.Exit(1)
+	}
+	fmt.Print(string(raw))
+}
+
+// loadStackConfig applies the stack definition file given using --file to the given flags;
+// flags which were given explicitly are left as is
+func loadStackConfig(flags *pflag.FlagSet) error {
+	if stackFile == "" {
+		return nil
+	}
+
+	raw, err := ioutil.ReadFile(stackFile)
+	if err != nil {
+		return err
+	}
+
+	var doc map[string]interface{}
+	if err := yaml.Unmarshal(raw, &doc); err != nil {
+		return fmt.Errorf("%s: %s", stackFile, err.Error())
+	}
+
+	version, ok := doc[stackConfigKeyVersion].(int)
+	if !ok {
+		return fmt.Errorf("%s: version is required", stackFile)
+	}
+	if version != stackConfigVersion {
+		return fmt.Errorf("%s: unsupported version %d; expected version %d", stackFile, version, stackConfigVersion)
+	}
+	delete(doc, stackConfigKeyVersion)
+
+	values := map[string]interface{}{}
+	if err := flattenStackConfig("", doc, values); err != nil {
+		return fmt.Errorf("%s: %s", stackFile, err.Error())
+	}
+
+	bindings := map[string]string{}
+	for _, binding := range stackConfigBindings {
+		bindings[binding.key] = binding.flag
+	}
+
+	keys := make([]string, 0)
+	for key := range values {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		value := values[key]
+		if svc, field, envvar, ok := stackServiceKey(key); ok {
+			switch {
+			case field == stackConfigKeyImage && envvar == "":
+				serviceImages[svc] = fmt.Sprintf("%v", value)
+				continue
+			case field == stackConfigKeyEnv && envvar != "":
+				if serviceEnvironment[svc] == nil {
+					serviceEnvironment[svc] = map[string]string{}
+				}
+				serviceEnvironment[svc][envvar] = fmt.Sprintf("%v", value)
+				continue
+			}
+		}
+
+		flag, ok := bindings[key]
+		if !ok {
+			return fmt.Errorf("%s: unknown key %s", stackFile, key)
+		}
+		if f := flags.Lookup(flag); f != nil && !f.Changed {
+			if err := flags.Set(flag, fmt.Sprintf("%v", value)); err != nil {
+				return fmt.Errorf("%s: invalid %s; %s", stackFile, key, err.Error())
+			}
+		}
+	}
+
+	return nil
+}
+
+// flattenStackConfig flattens the given stack definition into dot-delimited keys
+func flattenStackConfig(prefix string, value interface{}, values map[string]interface{}) error {
+	switch v := value.(type) {
+	case map[interface{}]interface{}:
+		for key, val := range v {
+			if err := flattenStackConfig(fmt.Sprintf("%s%v.", prefix, key), val, values); err != nil {
+				return err
+			}
+		}
+	case map[string]interface{}:
+		for key, val := range v {
+			if err := flattenStackConfig(fmt.Sprintf("%s%s.", prefix, key), val, values); err != nil {
+				return err
+			}
+		}
+	case []interface{}:
+		return fmt.Errorf("%s must not be a list", strings.TrimSuffix(prefix, "."))
+	default:
+		values[strings.TrimSuffix(prefix, ".")] = v
+	}
+	return nil
+}
+
+// stackServiceKey splits keys of the form services.<service>.<field>[.<envvar>]
+func stackServiceKey(key string) (svc, field, envvar string, ok bool) {
+	if !strings.HasPrefix(key, stackConfigServicesPrefix) {
+		return "", "", "", false
+	}
+
+	parts := strings.SplitN(strings.TrimPrefix(key, stackConfigServicesPrefix), ".", 3)
+	if len(parts) < 2 {
+		return "", "", "", false
+	}
+	if _, ok := stackServices[parts[0]]; !ok {
+		return "", "", "", false
+	}
+	if len(parts) == 3 {
+		envvar = parts[2]
+	}
+	return parts[0], parts[1], envvar, true
+}
+
+// effectiveStackConfig renders the stack definition file equivalent to the given flags
+// and the images and environment overrides of the loaded stack definition file; secrets
+// are redacted unless showSecrets is true
+func effectiveStackConfig(flags *pflag.FlagSet, showSecrets bool) yaml.MapSlice {
+	doc := yaml.MapSlice{{Key: stackConfigKeyVersion, Value: stackConfigVersion}}
+
+	for _, binding := range stackConfigBindings {
+		f := flags.Lookup(binding.flag)
+		if f == nil {
+			continue
+		}
+		value := typedFlagValue(f)
+		if !showSecrets && stackConfigSecrets[binding.key] && value != "" {
+			value = stackConfigRedacted
+		}
+		doc = setStackConfigValue(doc, strings.Split(binding.key, "."), value)
+	}
+
+	services := make([]string, 0)
+	for svc := range stackServices {
+		services = append(services, svc)
+	}
+	sort.Strings(services)
+
+	for _, svc := range services {
+		doc = setStackConfigValue(doc, []string{"services", svc, stackConfigKeyImage}, stackServiceImage(svc))
+
+		envvars := make([]string, 0)
+		for envvar := range serviceEnvironment[svc] {
+			envvars = append(envvars, envvar)
+		}
+		sort.Strings(envvars)
+		for _, envvar := range envvars {
+			value := serviceEnvironment[svc][envvar]
+			if !showSecrets && isSecretEnvironment(envvar) && value != "" {
+				value = stackConfigRedacted
+			}
+			doc = setStackConfigValue(doc, []string{"services", svc, stackConfigKeyEnv, envvar}, value)
+		}
+	}
+
+	return doc
+}
+
+// isSecretEnvironment returns true if the value of the given environment variable is a secret
+func isSecretEnvironment(envvar string) bool {
+	for _, secret := range stackConfigSecretEnvironment {
+		if strings.Contains(strings.ToUpper(envvar), secret) {
+			return true
+		}
+	}
+	return false
+}
+
+func setStackConfigValue(doc yaml.MapSlice, path []string, value interface{}) yaml.MapSlice {
+	for i, item := range doc {
+		if item.Key != path[0] {
+			continue
+		}
+		if len(path) > 1 {
+			nested, _ := item.Value.(yaml.MapSlice)
+			doc[i].Value = setStackConfigValue(nested, path[1:], value)
+		} else {
+			doc[i].Value = value
+		}
+		return doc
+	}
+
+	if len(path) > 1 {
+		return append(doc, yaml.MapItem{Key: path[0], Value: setStackConfigValue(yaml.MapSlice{}, path[1:], value)})
+	}
+	return append(doc, yaml.MapItem{Key: path[0], Value: value})
+}
+
+func typedFlagValue(f *pflag.Flag) interface{} {
+	val := f.Value.String()
+	switch f.Value.Type() {
+	case "bool":
+		if b, err := strconv.ParseBool(val); err == nil {
+			return b
+		}
+	case "int":
+		if i, err := strconv.Atoi(val); err == nil {
+			return i
+		}
+	}
+	return val
+}
+
+// stackServiceImage returns the container image of the given service
+func stackServiceImage(svc string) string {
+	if image, ok := serviceImages[svc]; ok {
+		return image
+	}
+	return stackServices[svc]
+}
+
+// stackImages returns the given container images to pull with the images of the stack
+// definition file in place of the defaults they override
+func stackImages(images []string) []string {
+	resolved := make([]string, 0)
+	seen := map[string]bool{}
+	add := func(image string) {
+		if !seen[image] {
+			seen[image] = true
+			resolved = append(resolved, image)
+		}
+	}
+
+	for _, image := range images {
+		// the default image is still pulled unless every service using it is overridden
+		required := true
+		for svc, defaultImage := range stackServices {
+			if image != defaultImage && !strings.HasPrefix(image, defaultImage+":") {
+				continue
+			}
+			if _, ok := serviceImages[svc]; !ok {
+				required = true
+				break
+			}
+			required = false
+		}
+		if required {
+			add(image)
+		}
+	}
+
+	for _, image := range serviceImages {
+		add(image)
+	}
+	return resolved
+}
+
+// stackContainerService resolves the service of the stack definition file which configures the named container
+func stackContainerService(containerName string) string {
+	return stackContainerServices[strings.TrimPrefix(containerName, fmt.Sprintf("%s-", strings.ReplaceAll(name, " ", "")))]
+}
+
+// stackContainerImage applies the image override of the stack definition file to the named container
+func stackContainerImage(containerName, image string) string {
+	if override, ok := serviceImages[stackContainerService(containerName)]; ok {
+		return override
+	}
+	return image
+}
+
+// stackContainerEnvironment applies the environment overrides of the stack definition file to the named container
+func stackContainerEnvironment(containerName string, env []string) []string {
+	overrides := serviceEnvironment[stackContainerService(containerName)]
+	if len(overrides) == 0 {
+		return env
+	}
+
+	environment := make([]string, 0)
+	for _, envvar := range env {
+		if _, ok := overrides[strings.SplitN(envvar, "=", 2)[0]]; !ok {
+			environment = append(environment, envvar)
+		}
+	}
+
+	envvars := make([]string, 0)
+	for envvar := range overrides {
+		envvars = append(envvars, envvar)
+	}
+	sort.Strings(envvars)
+	for _, envvar := range envvars {
+		environment = append(environment, fmt.Sprintf("%s=%s", envvar, overrides[envvar]))
+	}
+	return environment
+}
+
+func init() {
+	configStackCmd.AddCommand(configPrintStackCmd)
+	configPrintStackCmd.Flags().BoolVar(&stackConfigShowSecrets, "show-secrets", false, "when true, passwords, tokens and other secrets are printed instead of redacted")
+}
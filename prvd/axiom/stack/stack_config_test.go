@@ -0,0 +1,226 @@
+/*
+ * Copyright 2017-2022 Provide Technologies Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package stack
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/spf13/pflag"
+	"gopkg.in/yaml.v2"
+)
+
+func TestFlattenStackConfig(t *testing.T) {
+	tests := []struct {
+		name    string
+		doc     string
+		want    map[string]interface{}
+		wantErr bool
+	}{
+		{
+			name: "top-level keys",
+			doc:  "name: my-stack\nprune: true\n",
+			want: map[string]interface{}{"name": "my-stack", "prune": true},
+		},
+		{
+			name: "nested keys",
+			doc:  "services:\n  postgres:\n    port: 5433\n  api:\n    env:\n      LOG_LEVEL: DEBUG\n",
+			want: map[string]interface{}{"services.postgres.port": 5433, "services.api.env.LOG_LEVEL": "DEBUG"},
+		},
+		{
+			name: "empty section",
+			doc:  "tunnels:\n",
+			want: map[string]interface{}{"tunnels": nil},
+		},
+		{
+			name:    "list",
+			doc:     "services:\n  redis:\n    hosts:\n      - a\n      - b\n",
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var doc map[string]interface{}
+			if err := yaml.Unmarshal([]byte(tt.doc), &doc); err != nil {
+				t.Fatal(err)
+			}
+
+			values := map[string]interface{}{}
+			err := flattenStackConfig("", doc, values)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("flattenStackConfig() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && !reflect.DeepEqual(values, tt.want) {
+				t.Errorf("flattenStackConfig() = %v, want %v", values, tt.want)
+			}
+		})
+	}
+}
+
+func TestStackServiceKey(t *testing.T) {
+	tests := []struct {
+		key        string
+		wantSvc    string
+		wantField  string
+		wantEnvvar string
+		wantOk     bool
+	}{
+		{key: "services.api.image", wantSvc: "api", wantField: "image", wantOk: true},
+		{key: "services.api.env.LOG_LEVEL", wantSvc: "api", wantField: "env", wantEnvvar: "LOG_LEVEL", wantOk: true},
+		{key: "services.api.env.A.B", wantSvc: "api", wantField: "env", wantEnvvar: "A.B", wantOk: true},
+		{key: "services.unknown.image", wantOk: false},
+		{key: "services.api", wantOk: false},
+		{key: "name", wantOk: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.key, func(t *testing.T) {
+			svc, field, envvar, ok := stackServiceKey(tt.key)
+			if svc != tt.wantSvc || field != tt.wantField || envvar != tt.wantEnvvar || ok != tt.wantOk {
+				t.Errorf("stackServiceKey(%q) = %q, %q, %q, %v; want %q, %q, %q, %v", tt.key, svc, field, envvar, ok, tt.wantSvc, tt.wantField, tt.wantEnvvar, tt.wantOk)
+			}
+		})
+	}
+}
+
+func TestLoadStackConfig(t *testing.T) {
+	tests := []struct {
+		name      string
+		doc       string
+		args      []string
+		wantFlags map[string]string
+		wantImage string
+		wantEnv   map[string]string
+		wantErr   bool
+	}{
+		{
+			name:      "file values",
+			doc:       "version: 1\nname: my-stack\nservices:\n  postgres:\n    port: 5433\n",
+			wantFlags: map[string]string{"name": "my-stack", "postgres-port": "5433", "log-level": "DEBUG"},
+		},
+		{
+			name:      "flags override file values",
+			doc:       "version: 1\nname: my-stack\nlog_level: INFO\n",
+			args:      []string{"--name", "flag-stack"},
+			wantFlags: map[string]string{"name": "flag-stack", "log-level": "INFO"},
+		},
+		{
+			name:      "image and environment overrides",
+			doc:       "version: 1\nservices:\n  api:\n    image: provide/baseline:edge\n    env:\n      LOG_LEVEL: TRACE\n",
+			wantFlags: map[string]string{"name": DefaultName},
+			wantImage: "provide/baseline:edge",
+			wantEnv:   map[string]string{"LOG_LEVEL": "TRACE"},
+		},
+		{name: "missing version", doc: "name: my-stack\n", wantErr: true},
+		{name: "unsupported version", doc: "version: 2\n", wantErr: true},
+		{name: "unknown key", doc: "version: 1\nbogus: true\n", wantErr: true},
+		{name: "invalid value", doc: "version: 1\nservices:\n  postgres:\n    port: five\n", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir, err := ioutil.TempDir("", "prvd-stack")
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer os.RemoveAll(dir)
+
+			stackFile = filepath.Join(dir, "stack.yml")
+			serviceImages = map[string]string{}
+			serviceEnvironment = map[string]map[string]string{}
+			defer func() {
+				stackFile = ""
+				serviceImages = map[string]string{}
+				serviceEnvironment = map[string]map[string]string{}
+			}()
+			if err := ioutil.WriteFile(stackFile, []byte(tt.doc), 0600); err != nil {
+				t.Fatal(err)
+			}
+
+			flags := pflag.NewFlagSet("start", pflag.ContinueOnError)
+			flags.String("name", DefaultName, "")
+			flags.String("log-level", "DEBUG", "")
+			flags.Int("postgres-port", 5432, "")
+			if err := flags.Parse(tt.args); err != nil {
+				t.Fatal(err)
+			}
+
+			err = loadStackConfig(flags)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("loadStackConfig() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+
+			for flag, want := range tt.wantFlags {
+				if got := flags.Lookup(flag).Value.String(); got != want {
+					t.Errorf("--%s = %s, want %s", flag, got, want)
+				}
+			}
+			if tt.wantImage != "" && stackServiceImage("api") != tt.wantImage {
+				t.Errorf("stackServiceImage(api) = %s, want %s", stackServiceImage("api"), tt.wantImage)
+			}
+			if tt.wantEnv != nil && !reflect.DeepEqual(serviceEnvironment["api"], tt.wantEnv) {
+				t.Errorf("serviceEnvironment[api] = %v, want %v", serviceEnvironment["api"], tt.wantEnv)
+			}
+		})
+	}
+}
+
+func TestEffectiveStackConfigSecrets(t *testing.T) {
+	serviceEnvironment = map[string]map[string]string{"api": {"LOG_LEVEL": "DEBUG", "VAULT_API_TOKEN": "tok"}}
+	defer func() { serviceEnvironment = map[string]map[string]string{} }()
+
+	flags := pflag.NewFlagSet("start", pflag.ContinueOnError)
+	flags.String("postgres-user", "axiom", "")
+	flags.String("postgres-password", "prvdp455", "")
+	flags.String("vault-seal-unseal-key", "", "")
+
+	tests := []struct {
+		name        string
+		showSecrets bool
+		path        []string
+		want        interface{}
+	}{
+		{name: "not a secret", path: []string{"services", "postgres", "user"}, want: "axiom"},
+		{name: "secret", path: []string{"services", "postgres", "password"}, want: stackConfigRedacted},
+		{name: "shown secret", showSecrets: true, path: []string{"services", "postgres", "password"}, want: "prvdp455"},
+		{name: "empty secret", path: []string{"services", "vault", "seal_unseal_key"}, want: ""},
+		{name: "environment", path: []string{"services", "api", "env", "LOG_LEVEL"}, want: "DEBUG"},
+		{name: "secret environment", path: []string{"services", "api", "env", "VAULT_API_TOKEN"}, want: stackConfigRedacted},
+		{name: "shown secret environment", showSecrets: true, path: []string{"services", "api", "env", "VAULT_API_TOKEN"}, want: "tok"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got interface{} = effectiveStackConfig(flags, tt.showSecrets)
+			for _, key := range tt.path {
+				doc, _ := got.(yaml.MapSlice)
+				got = nil
+				for _, item := range doc {
+					if item.Key == key {
+						got = item.Value
+					}
+				}
+			}
+			if got != tt.want {
+				t.Errorf("effectiveStackConfig() %v = %v, want %v", tt.path, got, tt.want)
+			}
+		})
+	}
+}
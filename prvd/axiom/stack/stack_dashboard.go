@@ -0,0 +1,447 @@
+/*
+ * Copyright 2017-2022 Provide Technologies Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package stack
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/c-bata/go-prompt"
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/client"
+	"github.com/provideplatform/provide-cli/prvd/common"
+	"github.com/provideplatform/provide-go/api/axiom"
+	"github.com/provideplatform/provide-go/api/ident"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+const dashboardDefaultInterval = time.Second * 2
+const dashboardAPIRefreshInterval = time.Second * 10
+const dashboardInputPollInterval = time.Millisecond * 50
+const dashboardLogsTail = 200
+const dashboardRestartTimeout = time.Second * 10
+
+var dashboardInterval time.Duration
+
+var dashboardStackCmd = &cobra.Command{
+	Use:   "dashboard",
+	Short: "Display a live dashboard for a local axiom stack",
+	Long: `Display a full-screen dashboard for a local axiom stack instance.
+
+The dashboard presents the health of each container, a live log tail for the selected service
+and, for the active (or given) organization and workgroup, the recent protocol message activity,
+workflows with their status and the BPI and messaging endpoints (i.e., tunnels).
+
+Keybindings:
+  up/down, k/j    select a service
+  l, enter        show the logs of the selected service full-screen; press again to return
+  r               restart the selected service
+  q, ctrl-c       exit the dashboard`,
+	Run: stackDashboard,
+}
+
+// dashboardService is a container of the stack as presented by the dashboard
+type dashboardService struct {
+	id        string
+	service   string
+	state     string
+	health    string
+	startedAt *time.Time
+	ports     string
+}
+
+// dashboard is the state of the stack dashboard
+type dashboard struct {
+	docker *client.Client
+	stack  string
+
+	organizationID string
+	workgroupID    string
+	token          string
+	apiErr         error
+
+	mutex     sync.Mutex
+	services  []*dashboardService
+	selected  int
+	focusLogs bool
+	logs      []string
+	activity  []*axiom.ActivityAPIResponseItem
+	workflows []*axiom.Workflow
+	endpoints [][]string
+	status    string
+
+	refreshedAPIAt time.Time
+}
+
+func stackDashboard(cmd *cobra.Command, args []string) {
+	if dashboardInterval <= 0 {
+		dashboardInterval = dashboardDefaultInterval
+	}
+
+	docker, err := client.NewEnvClient()
+	if err != nil {
+		log.Printf("failed to initialize docker; %s", err.Error())
+		common.Exit(1)
+	}
+	defer docker.Close()
+
+	d := &dashboard{
+		docker: docker,
+		stack:  name,
+	}
+	d.resolveWorkgroup(common.ContextFromCommand(cmd))
+
+	if err := d.refreshContainers(); err != nil {
+		log.Printf("failed to list containers for stack: %s; %s", name, err.Error())
+		common.Exit(1)
+	}
+
+	d.run()
+}
+
+// resolveWorkgroup resolves the organization and workgroup for which protocol message activity,
+// workflows and endpoints are presented; no prompts are presented when none is given or active
+func (d *dashboard) resolveWorkgroup(ctx *common.Context) {
+	d.organizationID = ctx.OrganizationID
+	if d.organizationID == "" {
+		d.organizationID, _ = common.ActiveOrganization()
+	}
+
+	d.workgroupID = ctx.WorkgroupID
+	if d.workgroupID == "" && d.organizationID == viper.GetString(common.ActiveWorkgroupOrganizationIDConfigKey) {
+		d.workgroupID, _ = common.ActiveWorkgroup()
+	}
+
+	if d.organizationID == "" {
+		d.apiErr = fmt.Errorf("no organization selected; run prvd use organization")
+		return
+	}
+	if viper.GetString(common.AccessTokenConfigKey) == "" {
+		d.apiErr = fmt.Errorf("not authenticated; run prvd authenticate")
+		return
+	}
+
+	ctx.OrganizationID = d.organizationID
+	token, err := common.ResolveOrganizationToken(ctx)
+	if err != nil {
+		d.apiErr = fmt.Errorf("failed to authorize organization %s; %s", d.organizationID, err.Error())
+		return
+	}
+	d.token = *token.AccessToken
+}
+
+// run presents the dashboard until it is exited; the containers and log tail are refreshed
+// at the configured interval and the API resources less frequently
+func (d *dashboard) run() {
+	parser := prompt.NewStandardInputParser()
+	writer := prompt.NewStdoutWriter()
+
+	if err := parser.Setup(); err != nil {
+		log.Printf("failed to initialize terminal; %s", err.Error())
+		common.Exit(1)
+	}
+
+	writer.WriteRawStr("\033[?1049h")
+	writer.HideCursor()
+	writer.Flush()
+
+	defer func() {
+		writer.ShowCursor()
+		writer.WriteRawStr("\033[?1049l")
+		writer.Flush()
+		parser.TearDown()
+	}()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	go d.refresh(ctx, writer, parser)
+	d.render(writer, parser.GetWinSize())
+
+	for {
+		b, err := parser.Read()
+		if err != nil || len(b) == 0 {
+			time.Sleep(dashboardInputPollInterval)
+			continue
+		}
+
+		if !d.handleInput(b) {
+			return
+		}
+		d.render(writer, parser.GetWinSize())
+	}
+}
+
+// refresh periodically refreshes and renders the dashboard until the given context is canceled
+func (d *dashboard) refresh(ctx context.Context, writer prompt.ConsoleWriter, parser prompt.ConsoleParser) {
+	ticker := time.NewTicker(dashboardInterval)
+	defer ticker.Stop()
+
+	for {
+		if time.Since(d.refreshedAPIAt) >= dashboardAPIRefreshInterval {
+			d.refreshAPI()
+		}
+		d.refreshLogs()
+		d.render(writer, parser.GetWinSize())
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := d.refreshContainers(); err != nil {
+				d.setStatus(fmt.Sprintf("failed to list containers; %s", err.Error()))
+			}
+		}
+	}
+}
+
+// handleInput handles the given keypress; returns false when the dashboard should exit
+func (d *dashboard) handleInput(b []byte) bool {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+
+	key := prompt.GetKey(b)
+	switch {
+	case key == prompt.ControlC || string(b) == "q":
+		return false
+	case key == prompt.Up || string(b) == "k":
+		if d.selected > 0 {
+			d.selected--
+			d.logs = nil
+		}
+	case key == prompt.Down || string(b) == "j":
+		if d.selected < len(d.services)-1 {
+			d.selected++
+			d.logs = nil
+		}
+	case key == prompt.Enter || key == prompt.ControlM || string(b) == "l":
+		d.focusLogs = !d.focusLogs
+	case key == prompt.Escape:
+		d.focusLogs = false
+	case string(b) == "r":
+		if svc := d.selectedService(); svc != nil {
+			d.status = fmt.Sprintf("restarting %s...", svc.service)
+			go d.restart(svc)
+		}
+	}
+
+	return true
+}
+
+// restart restarts the container of the given service
+func (d *dashboard) restart(svc *dashboardService) {
+	timeout := dashboardRestartTimeout
+	err := d.docker.ContainerRestart(context.Background(), svc.id, &timeout)
+	if err != nil {
+		d.setStatus(fmt.Sprintf("failed to restart %s; %s", svc.service, err.Error()))
+		return
+	}
+	d.setStatus(fmt.Sprintf("restarted %s at %s", svc.service, time.Now().Format(time.Kitchen)))
+}
+
+func (d *dashboard) setStatus(status string) {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+	d.status = status
+}
+
+// selectedService returns the selected service; the caller must hold the mutex
+func (d *dashboard) selectedService() *dashboardService {
+	if d.selected < 0 || d.selected >= len(d.services) {
+		return nil
+	}
+	return d.services[d.selected]
+}
+
+// refreshContainers lists and inspects the containers of the stack
+func (d *dashboard) refreshContainers() error {
+	containers, err := d.docker.ContainerList(context.Background(), types.ContainerListOptions{All: true})
+	if err != nil {
+		return err
+	}
+
+	prefix := fmt.Sprintf("/%s-", strings.ReplaceAll(d.stack, " ", ""))
+	services := make([]*dashboardService, 0)
+	for _, container := range containers {
+		svc := &dashboardService{
+			id:     container.ID,
+			state:  container.State,
+			health: "-",
+			ports:  dashboardPorts(container),
+		}
+		for _, name := range container.Names {
+			if strings.HasPrefix(name, prefix) {
+				svc.service = strings.TrimPrefix(name, prefix)
+			}
+		}
+		if svc.service == "" {
+			continue
+		}
+
+		if inspect, err := d.docker.ContainerInspect(context.Background(), container.ID); err == nil && inspect.State != nil {
+			if inspect.State.Health != nil {
+				svc.health = inspect.State.Health.Status
+			}
+			if startedAt, err := time.Parse(time.RFC3339Nano, inspect.State.StartedAt); err == nil && inspect.State.Running {
+				svc.startedAt = &startedAt
+			}
+		}
+
+		services = append(services, svc)
+	}
+
+	sort.Slice(services, func(i, j int) bool {
+		return services[i].service < services[j].service
+	})
+
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+
+	// keep the selected service selected as services come and go
+	if selected := d.selectedService(); selected != nil {
+		for i, svc := range services {
+			if svc.service == selected.service {
+				d.selected = i
+			}
+		}
+	}
+	d.services = services
+	if d.selected >= len(services) {
+		d.selected = len(services) - 1
+	}
+	if d.selected < 0 {
+		d.selected = 0
+	}
+	return nil
+}
+
+// refreshLogs reads the log tail of the selected service
+func (d *dashboard) refreshLogs() {
+	d.mutex.Lock()
+	svc := d.selectedService()
+	d.mutex.Unlock()
+	if svc == nil {
+		return
+	}
+
+	out, err := d.docker.ContainerLogs(context.Background(), svc.id, types.ContainerLogsOptions{
+		ShowStderr: true,
+		ShowStdout: true,
+		Tail:       fmt.Sprintf("%d", dashboardLogsTail),
+	})
+	if err != nil {
+		d.setStatus(fmt.Sprintf("failed to read logs for %s; %s", svc.service, err.Error()))
+		return
+	}
+	defer out.Close()
+
+	lines := demuxLogLines(out)
+
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+	if selected := d.selectedService(); selected != nil && selected.id == svc.id {
+		d.logs = lines
+	}
+}
+
+// refreshAPI fetches the protocol message activity, workflows and endpoints of the workgroup
+func (d *dashboard) refreshAPI() {
+	d.refreshedAPIAt = time.Now()
+	if d.token == "" {
+		return
+	}
+
+	endpoints := make([][]string, 0)
+	org, err := ident.GetOrganizationDetails(d.token, d.organizationID, map[string]interface{}{})
+	if err == nil && org != nil {
+		endpoints = organizationEndpoints(org.Metadata, d.workgroupID)
+	}
+
+	var activity []*axiom.ActivityAPIResponseItem
+	var workflows []*axiom.Workflow
+	var apiErr error
+
+	if d.workgroupID == "" {
+		apiErr = fmt.Errorf("no workgroup selected; run prvd use workgroup")
+	} else {
+		if analytics, err := axiom.FetchWorkgroupAnalytics(d.token, d.workgroupID, map[string]interface{}{}); err == nil && analytics != nil {
+			activity = analytics.Activity
+		} else if err != nil {
+			apiErr = err
+		}
+
+		if wfs, err := axiom.ListWorkflows(d.token, map[string]interface{}{"workgroup_id": d.workgroupID}); err == nil {
+			workflows = wfs
+		} else {
+			apiErr = err
+		}
+	}
+
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+	d.endpoints = endpoints
+	d.activity = activity
+	d.workflows = workflows
+	d.apiErr = apiErr
+}
+
+// organizationEndpoints returns the BPI and messaging endpoints advertised by the organization
+// and, when they differ, those of the given workgroup
+func organizationEndpoints(metadata map[string]interface{}, workgroupID string) [][]string {
+	endpoints := make([][]string, 0)
+	for _, key := range []string{"bpi_endpoint", "messaging_endpoint"} {
+		if endpoint, endpointOk := metadata[key].(string); endpointOk && endpoint != "" {
+			endpoints = append(endpoints, []string{"organization", strings.ReplaceAll(key, "_", " "), endpoint})
+		}
+	}
+
+	workgroups, _ := metadata["workgroups"].(map[string]interface{})
+	if wg, wgOk := workgroups[workgroupID].(map[string]interface{}); wgOk {
+		for _, key := range []string{"bpi_endpoint", "messaging_endpoint"} {
+			if endpoint, endpointOk := wg[key].(string); endpointOk && endpoint != "" && endpoint != metadata[key] {
+				endpoints = append(endpoints, []string{"workgroup", strings.ReplaceAll(key, "_", " "), endpoint})
+			}
+		}
+	}
+
+	return endpoints
+}
+
+// dashboardPorts renders the published ports of the given container, i.e., 8080->8080/tcp
+func dashboardPorts(container types.Container) string {
+	ports := make([]string, 0)
+	for _, port := range container.Ports {
+		if port.PublicPort != 0 {
+			ports = append(ports, fmt.Sprintf("%d->%d/%s", port.PublicPort, port.PrivatePort, port.Type))
+		}
+	}
+	sort.Strings(ports)
+	return strings.Join(ports, ",")
+}
+
+func init() {
+	dashboardStackCmd.Flags().StringVar(&name, "name", "axiom-local", "name of the axiom stack instance")
+	dashboardStackCmd.Flags().String("organization", "", "organization identifier; defaults to the active organization")
+	dashboardStackCmd.Flags().String("workgroup", "", "workgroup identifier; defaults to the active workgroup")
+	dashboardStackCmd.Flags().DurationVar(&dashboardInterval, "interval", dashboardDefaultInterval, "refresh interval for containers and logs, i.e., 5s")
+}
@@ -0,0 +1,266 @@
+/*
+ * Copyright 2017-2022 Provide Technologies Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package stack
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/c-bata/go-prompt"
+	"github.com/docker/docker/pkg/stdcopy"
+)
+
+const dashboardReverseVideo = "\033[7m"
+const dashboardResetAttributes = "\033[0m"
+
+// dashboardMinWidth is the narrowest terminal in which panes are presented side by side
+const dashboardMinWidth = 100
+
+// render draws the dashboard to fit the given terminal size
+func (d *dashboard) render(writer prompt.ConsoleWriter, size *prompt.WinSize) {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+
+	width := int(size.Col)
+	height := int(size.Row)
+	if width < 20 || height < 10 {
+		return
+	}
+
+	title := fmt.Sprintf(" axiom stack: %s  %s", d.stack, time.Now().Format("15:04:05"))
+	keys := "↑/↓ select  l logs  r restart  q quit "
+	lines := []string{dashboardReverseVideo + fit(title, width-utf8.RuneCountInString(keys)) + keys + dashboardResetAttributes}
+
+	var svcName string
+	if svc := d.selectedService(); svc != nil {
+		svcName = svc.service
+	}
+
+	if d.focusLogs {
+		lines = append(lines, box(fmt.Sprintf("logs: %s", svcName), tail(d.logs, height-4), width, height-2, -1)...)
+	} else {
+		containers := box(fmt.Sprintf("containers (%d)", len(d.services)), d.containerLines(), width, len(d.services)+3, d.selected+1)
+		lines = append(lines, containers...)
+
+		remaining := height - 2 - len(containers)
+		apiHeight := remaining / 2
+		if apiHeight > 12 {
+			apiHeight = 12
+		}
+		endpointsHeight := len(d.endpoints) + 2
+		if d.apiErr != nil || len(d.endpoints) == 0 {
+			endpointsHeight = 3
+		}
+		logsHeight := remaining - apiHeight - endpointsHeight
+
+		if width >= dashboardMinWidth {
+			left := box("workflows", d.workflowLines(), width/2, apiHeight, -1)
+			right := box("protocol messages", d.activityLines(), width-width/2, apiHeight, -1)
+			for i := range left {
+				lines = append(lines, left[i]+right[i])
+			}
+		} else {
+			lines = append(lines, box("workflows", d.workflowLines(), width, apiHeight/2, -1)...)
+			lines = append(lines, box("protocol messages", d.activityLines(), width, apiHeight-apiHeight/2, -1)...)
+		}
+
+		lines = append(lines, box("endpoints", d.endpointLines(), width, endpointsHeight, -1)...)
+		if logsHeight >= 3 {
+			lines = append(lines, box(fmt.Sprintf("logs: %s", svcName), tail(d.logs, logsHeight-2), width, logsHeight, -1)...)
+		}
+	}
+
+	for len(lines) < height-1 {
+		lines = append(lines, "")
+	}
+	lines = append(lines[:height-1], fit(d.status, width))
+
+	writer.CursorGoTo(0, 0)
+	for i, line := range lines {
+		writer.WriteRawStr(line)
+		writer.EraseEndOfLine()
+		if i < len(lines)-1 {
+			writer.WriteRawStr("\r\n")
+		}
+	}
+	writer.Flush()
+}
+
+func (d *dashboard) containerLines() []string {
+	lines := []string{fmt.Sprintf("%-24s %-10s %-10s %-10s %s", "SERVICE", "STATE", "HEALTH", "UPTIME", "PORTS")}
+	for _, svc := range d.services {
+		uptime := "-"
+		if svc.startedAt != nil {
+			uptime = time.Since(*svc.startedAt).Round(time.Second).String()
+		}
+		lines = append(lines, fmt.Sprintf("%-24s %-10s %-10s %-10s %s", svc.service, svc.state, svc.health, uptime, svc.ports))
+	}
+	return lines
+}
+
+func (d *dashboard) workflowLines() []string {
+	if d.apiErr != nil {
+		return []string{d.apiErr.Error()}
+	}
+
+	lines := make([]string, 0)
+	for _, workflow := range d.workflows {
+		lines = append(lines, fmt.Sprintf("%-10s %-8s %s", stringOrEmpty(workflow.Status), stringOrEmpty(workflow.Version), stringOrEmpty(workflow.Name)))
+	}
+	if len(lines) == 0 {
+		lines = append(lines, "no workflows")
+	}
+	return lines
+}
+
+func (d *dashboard) activityLines() []string {
+	if d.apiErr != nil {
+		return []string{d.apiErr.Error()}
+	}
+
+	lines := make([]string, 0)
+	for _, item := range d.activity {
+		timestamp := ""
+		if item.Timestamp != nil {
+			timestamp = item.Timestamp.Local().Format("Jan 2 15:04:05")
+		}
+		lines = append(lines, fmt.Sprintf("%s  %s  %s", timestamp, stringOrEmpty(item.Title), stringOrEmpty(item.Subtitle)))
+	}
+	if len(lines) == 0 {
+		lines = append(lines, "no recent protocol messages")
+	}
+	return lines
+}
+
+func (d *dashboard) endpointLines() []string {
+	if d.apiErr != nil && d.token == "" {
+		return []string{d.apiErr.Error()}
+	}
+
+	lines := make([]string, 0)
+	for _, endpoint := range d.endpoints {
+		lines = append(lines, fmt.Sprintf("%-12s %-20s %s", endpoint[0], endpoint[1], endpoint[2]))
+	}
+	if len(lines) == 0 {
+		lines = append(lines, "no endpoints advertised")
+	}
+	return lines
+}
+
+// box renders the given lines within a titled border of the given size; the line at the given
+// index is highlighted
+func box(title string, lines []string, width, height, highlight int) []string {
+	if height < 2 {
+		return nil
+	}
+
+	inner := width - 2
+	top := "┌─ " + title + " "
+	if utf8.RuneCountInString(top) > width-1 {
+		top = fit(top, width-1)
+	}
+	top += strings.Repeat("─", width-1-utf8.RuneCountInString(top)) + "┐"
+
+	rendered := []string{top}
+	for i := 0; i < height-2; i++ {
+		line := ""
+		if i < len(lines) {
+			line = lines[i]
+		}
+		line = fit(line, inner)
+		if i == highlight {
+			line = dashboardReverseVideo + line + dashboardResetAttributes
+		}
+		rendered = append(rendered, "│"+line+"│")
+	}
+	return append(rendered, "└"+strings.Repeat("─", inner)+"┘")
+}
+
+// fit truncates or pads the given string to the given width
+func fit(str string, width int) string {
+	if width <= 0 {
+		return ""
+	}
+
+	str = strings.ReplaceAll(str, "\t", " ")
+	n := utf8.RuneCountInString(str)
+	if n > width {
+		return string([]rune(str)[:width])
+	}
+	return str + strings.Repeat(" ", width-n)
+}
+
+// tail returns the last n of the given lines
+func tail(lines []string, n int) []string {
+	if n < 0 {
+		n = 0
+	}
+	if len(lines) > n {
+		return lines[len(lines)-n:]
+	}
+	return lines
+}
+
+// demuxLogLines reads the lines of the given multiplexed container log stream
+func demuxLogLines(out io.Reader) []string {
+	r, w := io.Pipe()
+	go func() {
+		_, err := stdcopy.StdCopy(w, w, out)
+		w.CloseWithError(err)
+	}()
+
+	lines := make([]string, 0)
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		lines = append(lines, stripControlSequences(scanner.Text()))
+	}
+	io.Copy(ioutil.Discard, r)
+	return lines
+}
+
+// stripControlSequences removes escape sequences and other control characters which would
+// otherwise corrupt the dashboard
+func stripControlSequences(str string) string {
+	var b strings.Builder
+	escaping := false
+	for _, r := range str {
+		switch {
+		case escaping:
+			if r >= '@' && r <= '~' && r != '[' {
+				escaping = false
+			}
+		case r == '\033':
+			escaping = true
+		case r < ' ' && r != '\t':
+		default:
+			b.WriteRune(r)
+		}
+	}
+	return b.String()
+}
+
+func stringOrEmpty(str *string) string {
+	if str == nil {
+		return ""
+	}
+	return *str
+}
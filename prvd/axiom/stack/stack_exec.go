@@ -0,0 +1,343 @@
+/*
+ * Copyright 2017-2022 Provide Technologies Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package stack
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log"
+	"os"
+
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/container"
+	"github.com/docker/docker/api/types/network"
+	"github.com/docker/docker/client"
+	"github.com/docker/docker/pkg/stdcopy"
+	"github.com/provideplatform/provide-cli/prvd/common"
+	"golang.org/x/crypto/ssh/terminal"
+
+	"github.com/spf13/cobra"
+)
+
+// natsBoxContainerImage provides the nats CLI, which the NATS server image does not include
+const natsBoxContainerImage = "natsio/nats-box"
+
+const execDefaultShell = "sh"
+
+var execStackCmd = &cobra.Command{
+	Use:   "exec <service> [cmd...]",
+	Short: "Run a command in a service of the axiom stack",
+	Long: `Run a command in the container of a service of a local axiom stack instance, i.e., postgres,
+with a TTY when attached to a terminal; the command defaults to a shell.
+
+Flags must precede the service, i.e., prvd axiom stack exec --name my-stack api ls -la`,
+	Args:        cobra.MinimumNArgs(1),
+	Run:         stackExec,
+	Annotations: map[string]string{common.InteractiveAnnotation: "true"},
+}
+
+var psqlStackCmd = &cobra.Command{
+	Use:   "psql [args...]",
+	Short: "Run psql against the postgres service of the axiom stack",
+	Long: `Run psql in the postgres container of a local axiom stack instance, connected to the
+configured database using the configured user and password. Arguments are passed to psql;
+separate them with -- when they begin with a flag, i.e., prvd axiom stack psql -- -c 'select 1'`,
+	Run:         stackPsql,
+	Annotations: map[string]string{common.InteractiveAnnotation: "true"},
+}
+
+var redisCLIStackCmd = &cobra.Command{
+	Use:   "redis-cli [args...]",
+	Short: "Run redis-cli against the redis service of the axiom stack",
+	Long: `Run redis-cli in the redis container of a local axiom stack instance. Arguments are passed to
+redis-cli; separate them with -- when they begin with a flag, i.e., prvd axiom stack redis-cli -- --scan`,
+	Run:         stackRedisCLI,
+	Annotations: map[string]string{common.InteractiveAnnotation: "true"},
+}
+
+var natsStackCmd = &cobra.Command{
+	Use:   "nats [args...]",
+	Short: "Run the nats CLI against the NATS service of the axiom stack",
+	Long: `Run the nats CLI against the NATS service of a local axiom stack instance, authorized with
+the configured auth token, using a temporary nats-box container which shares the network of the
+NATS container. Arguments are passed to nats; without arguments, a nats-box shell is opened.
+Separate arguments with -- when they begin with a flag, i.e., prvd axiom stack nats -- --help`,
+	Run:         stackNATS,
+	Annotations: map[string]string{common.InteractiveAnnotation: "true"},
+}
+
+func stackExec(cmd *cobra.Command, args []string) {
+	command := args[1:]
+	if len(command) == 0 {
+		command = []string{execDefaultShell}
+	}
+
+	exitWith(execService(args[0], command, nil))
+}
+
+func stackPsql(cmd *cobra.Command, args []string) {
+	user, password, database := postgresUser, postgresPassword, postgresDatabase
+
+	// the api container is started with the database credentials of the stack
+	docker := requireExecDocker()
+	env, err := containerEnv(docker, stackContainerName("api"))
+	docker.Close()
+	if err == nil && env["DATABASE_USER"] != "" {
+		user, password, database = env["DATABASE_USER"], env["DATABASE_PASSWORD"], env["DATABASE_NAME"]
+	}
+
+	command := append([]string{"psql", "-U", user, "-d", database}, args...)
+	exitWith(execService("postgres", command, []string{fmt.Sprintf("PGPASSWORD=%s", password)}))
+}
+
+func stackRedisCLI(cmd *cobra.Command, args []string) {
+	exitWith(execService("redis", append([]string{"redis-cli"}, args...), nil))
+}
+
+func stackNATS(cmd *cobra.Command, args []string) {
+	docker := requireExecDocker()
+	defer docker.Close()
+
+	containerName := stackContainerName("nats")
+	if err := requireRunningContainer(docker, containerName); err != nil {
+		log.Printf("service nats of stack %s is not running; %s", name, err.Error())
+		common.Exit(1)
+	}
+
+	inspect, err := docker.ContainerInspect(context.Background(), containerName)
+	if err != nil {
+		log.Printf("failed to inspect NATS container; %s", err.Error())
+		common.Exit(1)
+	}
+
+	// the auth token and port are those the NATS server was started with
+	token := natsAuthToken
+	port := fmt.Sprintf("%d", defaultNATSContainerPort)
+	for i, arg := range inspect.Config.Cmd {
+		if i+1 < len(inspect.Config.Cmd) {
+			switch arg {
+			case "--auth":
+				token = inspect.Config.Cmd[i+1]
+			case "--port":
+				port = inspect.Config.Cmd[i+1]
+			}
+		}
+	}
+
+	entrypoint := []string{"nats"}
+	if len(args) == 0 {
+		entrypoint = []string{execDefaultShell}
+	}
+
+	if _, _, err := docker.ImageInspectWithRaw(context.Background(), natsBoxContainerImage); err != nil {
+		if err := pullImage(docker, natsBoxContainerImage); err != nil {
+			log.Printf("failed to pull %s; %s", natsBoxContainerImage, err.Error())
+			common.Exit(1)
+		}
+	}
+
+	tty := isTerminal()
+	code, err := runInteractive(docker, &container.Config{
+		Image:        natsBoxContainerImage,
+		Entrypoint:   entrypoint,
+		Cmd:          args,
+		Env:          []string{fmt.Sprintf("NATS_URL=nats://%s@localhost:%s", token, port)},
+		Tty:          tty,
+		OpenStdin:    true,
+		StdinOnce:    true,
+		AttachStdin:  true,
+		AttachStdout: true,
+		AttachStderr: true,
+	}, &container.HostConfig{
+		AutoRemove:  true,
+		NetworkMode: container.NetworkMode(fmt.Sprintf("container:%s", containerName)),
+	})
+	if err != nil {
+		log.Printf("failed to run nats CLI; %s", err.Error())
+		common.Exit(1)
+	}
+	common.Exit(code)
+}
+
+func requireExecDocker() *client.Client {
+	docker, err := client.NewEnvClient()
+	if err != nil {
+		log.Printf("failed to initialize docker; %s", err.Error())
+		common.Exit(1)
+	}
+	return docker
+}
+
+// execService runs the command interactively in the container of the given service of the
+// stack and returns its exit code
+func execService(service string, command, env []string) int {
+	docker := requireExecDocker()
+	defer docker.Close()
+
+	containerName := stackContainerName(service)
+	if err := requireRunningContainer(docker, containerName); err != nil {
+		log.Printf("service %s of stack %s is not running; %s", service, name, err.Error())
+		common.Exit(1)
+	}
+
+	code, err := execInteractive(docker, containerName, command, env)
+	if err != nil {
+		log.Printf("failed to exec %s in %s; %s", command[0], containerName, err.Error())
+		common.Exit(1)
+	}
+	return code
+}
+
+func exitWith(code int) {
+	if code != 0 {
+		common.Exit(code)
+	}
+}
+
+// isTerminal returns true when both stdin and stdout are attached to a terminal
+func isTerminal() bool {
+	return terminal.IsTerminal(int(os.Stdin.Fd())) && terminal.IsTerminal(int(os.Stdout.Fd()))
+}
+
+// terminalSize returns the height and width of the terminal attached to stdout
+func terminalSize() types.ResizeOptions {
+	width, height, err := terminal.GetSize(int(os.Stdout.Fd()))
+	if err != nil {
+		return types.ResizeOptions{}
+	}
+	return types.ResizeOptions{Height: uint(height), Width: uint(width)}
+}
+
+// execInteractive runs the command in the given container, attached to stdin, stdout and stderr
+// with a TTY when attached to a terminal, and returns the exit code of the command
+func execInteractive(docker *client.Client, containerName string, command, env []string) (int, error) {
+	tty := isTerminal()
+	exec, err := docker.ContainerExecCreate(context.Background(), containerName, types.ExecConfig{
+		AttachStdin:  true,
+		AttachStdout: true,
+		AttachStderr: true,
+		Tty:          tty,
+		Env:          env,
+		Cmd:          command,
+	})
+	if err != nil {
+		return -1, err
+	}
+
+	resp, err := docker.ContainerExecAttach(context.Background(), exec.ID, types.ExecStartCheck{Tty: tty})
+	if err != nil {
+		return -1, err
+	}
+	defer resp.Close()
+
+	err = streamTerminal(resp, tty, func() {
+		docker.ContainerExecResize(context.Background(), exec.ID, terminalSize())
+	})
+	if err != nil {
+		return -1, err
+	}
+
+	inspect, err := docker.ContainerExecInspect(context.Background(), exec.ID)
+	if err != nil {
+		return -1, err
+	}
+	return inspect.ExitCode, nil
+}
+
+// runInteractive runs a temporary container attached to stdin, stdout and stderr and returns
+// the exit code of the container
+func runInteractive(docker *client.Client, config *container.Config, hostConfig *container.HostConfig) (int, error) {
+	created, err := docker.ContainerCreate(context.Background(), config, hostConfig, &network.NetworkingConfig{}, nil, "")
+	if err != nil {
+		return -1, err
+	}
+
+	resp, err := docker.ContainerAttach(context.Background(), created.ID, types.ContainerAttachOptions{
+		Stream: true,
+		Stdin:  true,
+		Stdout: true,
+		Stderr: true,
+	})
+	if err != nil {
+		return -1, err
+	}
+	defer resp.Close()
+
+	statusCh, errCh := docker.ContainerWait(context.Background(), created.ID, container.WaitConditionNextExit)
+	if err := docker.ContainerStart(context.Background(), created.ID, types.ContainerStartOptions{}); err != nil {
+		return -1, err
+	}
+
+	err = streamTerminal(resp, config.Tty, func() {
+		docker.ContainerResize(context.Background(), created.ID, terminalSize())
+	})
+	if err != nil {
+		return -1, err
+	}
+
+	select {
+	case status := <-statusCh:
+		return int(status.StatusCode), nil
+	case err := <-errCh:
+		return -1, err
+	}
+}
+
+// streamTerminal streams stdin to the hijacked connection and its output to stdout and stderr
+// until the output ends; with a TTY, the local terminal is put into raw mode and resized with
+// the remote TTY
+func streamTerminal(resp types.HijackedResponse, tty bool, resize func()) error {
+	if tty {
+		state, err := terminal.MakeRaw(int(os.Stdin.Fd()))
+		if err != nil {
+			return err
+		}
+		defer terminal.Restore(int(os.Stdin.Fd()), state)
+
+		resize()
+		stop := notifyResize(resize)
+		defer stop()
+	}
+
+	go func() {
+		io.Copy(resp.Conn, os.Stdin)
+		resp.CloseWrite()
+	}()
+
+	var err error
+	if tty {
+		_, err = io.Copy(os.Stdout, resp.Reader)
+	} else {
+		_, err = stdcopy.StdCopy(os.Stdout, os.Stderr, resp.Reader)
+	}
+	return err
+}
+
+func init() {
+	execStackCmd.Flags().StringVar(&name, "name", "axiom-local", "name of the axiom stack instance")
+	execStackCmd.Flags().SetInterspersed(false)
+
+	psqlStackCmd.Flags().StringVar(&name, "name", "axiom-local", "name of the axiom stack instance")
+	psqlStackCmd.Flags().SetInterspersed(false)
+
+	redisCLIStackCmd.Flags().StringVar(&name, "name", "axiom-local", "name of the axiom stack instance")
+	redisCLIStackCmd.Flags().SetInterspersed(false)
+
+	natsStackCmd.Flags().StringVar(&name, "name", "axiom-local", "name of the axiom stack instance")
+	natsStackCmd.Flags().SetInterspersed(false)
+}
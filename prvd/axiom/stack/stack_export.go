@@ -0,0 +1,250 @@
+/*
+ * Copyright 2017-2022 Provide Technologies Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package stack
+
+import (
+	"fmt"
+	"io/ioutil"
+	"log"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+
+	"github.com/provideplatform/provide-cli/prvd/common"
+	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v2"
+)
+
+const exportFormatCompose = "compose"
+
+const composeFilename = "docker-compose.yml"
+const composeVersion = "3.8"
+
+var exportFormat string
+var exportOutput string
+
+var exportStackCmd = &cobra.Command{
+	Use:   "export",
+	Short: "Export the axiom stack definition",
+	Long: `Export the containers prvd axiom stack start would create for a local axiom stack.
+
+Supported formats:
+  compose    docker-compose.yml with its supporting files, i.e., the NATS server config
+  k8s        kubernetes.yml with a Deployment, or StatefulSet for postgres, elasticsearch and NATS,
+             and the Services, ConfigMaps and Secrets of each service in the given namespace
+
+Accepts the flags and stack definition file of prvd axiom stack start.`,
+	Run: exportStack,
+}
+
+func exportStack(cmd *cobra.Command, args []string) {
+	if err := loadStackConfig(cmd.Flags()); err != nil {
+		log.Printf("failed to load stack definition; %s", err.Error())
+		common.Exit(1)
+	}
+
+	if err := allocatePorts(cmd.Flags(), name, false); err != nil {
+		log.Printf("failed to allocate stack ports; %s", err.Error())
+		common.Exit(1)
+	}
+	normalizeHostnames()
+
+	if err := os.MkdirAll(exportOutput, 0755); err != nil {
+		log.Printf("failed to export stack; %s", err.Error())
+		common.Exit(1)
+	}
+
+	var files []string
+	var err error
+	switch exportFormat {
+	case exportFormatCompose:
+		files, err = exportCompose(exportOutput)
+	case exportFormatK8s:
+		files, err = exportK8s(exportOutput)
+	default:
+		err = fmt.Errorf("unsupported format: %s", exportFormat)
+	}
+	if err != nil {
+		log.Printf("failed to export stack; %s", err.Error())
+		common.Exit(1)
+	}
+
+	for _, file := range files {
+		fmt.Printf("wrote %s\n", file)
+	}
+}
+
+// stackContainerSpecs returns the specs of the containers prvd axiom stack start runs, in order
+func stackContainerSpecs() []*containerSpec {
+	specs := []*containerSpec{
+		elasticsearchContainer(),
+		natsContainer(),
+		postgresContainer(),
+		redisContainer(),
+	}
+
+	if withLocalIdent {
+		specs = append(specs, identAPIContainer(), identConsumerContainer())
+	}
+	if withLocalNChain {
+		specs = append(specs, nchainAPIContainer(), nchainConsumerContainer(), statsdaemonContainer(), reachabilitydaemonContainer())
+	}
+	if withLocalPrivacy {
+		specs = append(specs, privacyAPIContainer(), privacyConsumerContainer())
+	}
+	if withLocalVault {
+		specs = append(specs, vaultAPIContainer())
+	}
+
+	return append(specs, baselineAPIContainer(), baselineConsumerContainer())
+}
+
+// stackDependencies are the containers on which every other container of the stack depends
+var stackDependencies = []string{"elasticsearch", "nats", "postgres", "redis"}
+
+// containerService returns the name of the given container without the stack name prefix, i.e., api
+func containerService(spec *containerSpec) string {
+	return strings.TrimPrefix(spec.name, fmt.Sprintf("%s-", strings.ReplaceAll(name, " ", "")))
+}
+
+// exportCompose writes the docker-compose.yml of the stack and the files it mounts to the given directory
+func exportCompose(dir string) ([]string, error) {
+	network := strings.ReplaceAll(name, " ", "")
+	files := make([]string, 0)
+
+	services := yaml.MapSlice{}
+	namedVolumes := yaml.MapSlice{}
+	for _, spec := range stackContainerSpecs() {
+		svc := containerService(spec)
+		service := yaml.MapSlice{
+			{Key: "container_name", Value: spec.name},
+			{Key: "hostname", Value: spec.hostname},
+			{Key: "image", Value: spec.resolvedImage()},
+		}
+
+		if spec.entrypoint != nil {
+			service = append(service, yaml.MapItem{Key: "entrypoint", Value: *spec.entrypoint})
+		}
+		if spec.cmd != nil {
+			service = append(service, yaml.MapItem{Key: "command", Value: *spec.cmd})
+		}
+
+		environment := make([]string, 0)
+		for _, envvar := range spec.environment() {
+			// escape $ to prevent interpolation by docker compose
+			environment = append(environment, strings.ReplaceAll(envvar, "$", "$$"))
+		}
+		service = append(service, yaml.MapItem{Key: "environment", Value: environment})
+
+		if spec.healthcheck != nil {
+			service = append(service, yaml.MapItem{Key: "healthcheck", Value: yaml.MapSlice{
+				{Key: "test", Value: *spec.healthcheck},
+				{Key: "interval", Value: "1m"},
+				{Key: "timeout", Value: "1s"},
+				{Key: "retries", Value: 2},
+				{Key: "start_period", Value: "10s"},
+			}})
+		}
+
+		if len(spec.ports) > 0 {
+			ports := make([]string, 0)
+			for _, mapping := range spec.ports {
+				ports = append(ports, fmt.Sprintf("%d:%d", mapping.hostPort, mapping.containerPort))
+			}
+			service = append(service, yaml.MapItem{Key: "ports", Value: ports})
+		}
+
+		if len(spec.mounts) > 0 || len(spec.volumes) > 0 {
+			volumes := make([]string, 0)
+			for source, target := range spec.mounts {
+				raw, err := ioutil.ReadFile(source)
+				if err != nil {
+					return nil, err
+				}
+				path := filepath.Join(dir, filepath.Base(source))
+				if err := ioutil.WriteFile(path, raw, 0644); err != nil {
+					return nil, err
+				}
+				files = append(files, path)
+				volumes = append(volumes, fmt.Sprintf("./%s:%s", filepath.Base(source), target))
+			}
+			for volumeName, target := range spec.volumes {
+				volumes = append(volumes, fmt.Sprintf("%s:%s", volumeName, target))
+				namedVolumes = append(namedVolumes, yaml.MapItem{Key: volumeName, Value: yaml.MapSlice{{Key: "name", Value: volumeName}}})
+			}
+			service = append(service, yaml.MapItem{Key: "volumes", Value: volumes})
+		}
+
+		if !isStackDependency(svc) {
+			service = append(service, yaml.MapItem{Key: "depends_on", Value: stackDependencies})
+		}
+
+		service = append(service, yaml.MapItem{Key: "networks", Value: []string{network}})
+		if !strings.EqualFold(runtime.GOOS, "windows") {
+			service = append(service, yaml.MapItem{Key: "extra_hosts", Value: []string{"host.docker.internal:host-gateway"}})
+		}
+		if upstreamDNS != "" {
+			service = append(service, yaml.MapItem{Key: "dns", Value: strings.Split(upstreamDNS, ",")})
+		}
+		service = append(service, yaml.MapItem{Key: "restart", Value: "unless-stopped"})
+
+		services = append(services, yaml.MapItem{Key: svc, Value: service})
+	}
+
+	networkConfig := yaml.MapSlice{{Key: "name", Value: network}}
+	if !strings.EqualFold(runtime.GOOS, "windows") {
+		networkConfig = append(networkConfig, yaml.MapItem{Key: "driver", Value: dockerNetworkDriverBridge})
+	}
+
+	compose := yaml.MapSlice{
+		{Key: "version", Value: composeVersion},
+		{Key: "services", Value: services},
+		{Key: "networks", Value: yaml.MapSlice{{Key: network, Value: networkConfig}}},
+	}
+	if len(namedVolumes) > 0 {
+		compose = append(compose, yaml.MapItem{Key: "volumes", Value: namedVolumes})
+	}
+
+	raw, err := yaml.Marshal(compose)
+	if err != nil {
+		return nil, err
+	}
+
+	path := filepath.Join(dir, composeFilename)
+	if err := ioutil.WriteFile(path, raw, 0644); err != nil {
+		return nil, err
+	}
+	return append([]string{path}, files...), nil
+}
+
+func isStackDependency(svc string) bool {
+	for _, dependency := range stackDependencies {
+		if svc == dependency {
+			return true
+		}
+	}
+	return false
+}
+
+func init() {
+	exportStackCmd.Flags().StringVar(&exportFormat, "format", exportFormatCompose, "export format; compose or k8s")
+	exportStackCmd.Flags().StringVarP(&exportOutput, "output", "o", ".", "directory to which the exported files are written")
+	exportStackCmd.Flags().StringVar(&exportNamespace, "namespace", "", "kubernetes namespace of the exported manifests; defaults to the stack name")
+	exportStackCmd.Flags().StringVar(&exportStorage, "storage", k8sDefaultStorage, "storage requested by the kubernetes persistent volume claims of postgres, elasticsearch and NATS")
+	common.SetFlagValues(exportStackCmd, "format", exportFormatCompose, exportFormatK8s)
+}
@@ -0,0 +1,318 @@
+/*
+ * Copyright 2017-2022 Provide Technologies Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package stack
+
+import (
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v2"
+)
+
+const exportFormatK8s = "k8s"
+
+const k8sFilename = "kubernetes.yml"
+
+const k8sLabelName = "app.kubernetes.io/name"
+const k8sLabelInstance = "app.kubernetes.io/instance"
+const k8sLabelPartOf = "app.kubernetes.io/part-of"
+const k8sPartOf = "axiom"
+
+// k8sDefaultStorage is the default storage requested by each persistent volume claim
+const k8sDefaultStorage = "10Gi"
+
+const k8sElasticsearchInitImage = "busybox"
+
+var exportNamespace string
+var exportStorage string
+
+// k8sPersistentVolume is the persistent volume of a service run as a StatefulSet
+type k8sPersistentVolume struct {
+	mountPath string
+	subPath   string
+}
+
+// k8sPersistentVolumes are the services run as StatefulSets with the persistent volumes claimed for their data
+var k8sPersistentVolumes = map[string]*k8sPersistentVolume{
+	"elasticsearch": {mountPath: elasticsearchDataDir},
+	"nats":          {mountPath: natsJetStreamStoreDir},
+	"postgres":      {mountPath: postgresDataDir, subPath: "pgdata"},
+}
+
+// k8sSecretEnvironment are the substrings of the environment variables which are exported as Secrets
+var k8sSecretEnvironment = []string{"PASSWORD", "TOKEN", "SECRET", "SEAL_UNSEAL_KEY", "CONNECTION_STRING"}
+
+// exportK8s writes the Kubernetes manifests of the stack to the given directory; each container is
+// exported as a Deployment, or a StatefulSet with a persistent volume claim for postgres,
+// elasticsearch and NATS, with a ConfigMap and Secret for its environment, a ConfigMap for the files it
+// mounts and a Service named after its hostname for its ports
+func exportK8s(dir string) ([]string, error) {
+	namespace := exportNamespace
+	if namespace == "" {
+		namespace = strings.ToLower(strings.ReplaceAll(name, " ", ""))
+	}
+
+	docs := []yaml.MapSlice{{
+		{Key: "apiVersion", Value: "v1"},
+		{Key: "kind", Value: "Namespace"},
+		{Key: "metadata", Value: yaml.MapSlice{{Key: "name", Value: namespace}}},
+	}}
+
+	for _, spec := range stackContainerSpecs() {
+		manifests, err := k8sManifests(spec, namespace)
+		if err != nil {
+			return nil, err
+		}
+		docs = append(docs, manifests...)
+	}
+
+	var buf strings.Builder
+	for i, doc := range docs {
+		raw, err := yaml.Marshal(doc)
+		if err != nil {
+			return nil, err
+		}
+		if i > 0 {
+			buf.WriteString("---\n")
+		}
+		buf.Write(raw)
+	}
+
+	path := filepath.Join(dir, k8sFilename)
+	if err := ioutil.WriteFile(path, []byte(buf.String()), 0644); err != nil {
+		return nil, err
+	}
+	return []string{path}, nil
+}
+
+// k8sManifests returns the manifests of the given container
+func k8sManifests(spec *containerSpec, namespace string) ([]yaml.MapSlice, error) {
+	svc := containerService(spec)
+	labels := yaml.MapSlice{
+		{Key: k8sLabelName, Value: svc},
+		{Key: k8sLabelInstance, Value: strings.ReplaceAll(name, " ", "")},
+		{Key: k8sLabelPartOf, Value: k8sPartOf},
+	}
+	selector := labels[:2]
+
+	metadata := func(name string) yaml.MapSlice {
+		return yaml.MapSlice{
+			{Key: "name", Value: name},
+			{Key: "namespace", Value: namespace},
+			{Key: "labels", Value: labels},
+		}
+	}
+
+	docs := make([]yaml.MapSlice, 0)
+
+	config := yaml.MapSlice{}
+	secrets := yaml.MapSlice{}
+	for _, envvar := range spec.environment() {
+		kv := strings.SplitN(envvar, "=", 2)
+		if len(kv) != 2 {
+			continue
+		}
+		if isK8sSecret(kv[0]) {
+			secrets = append(secrets, yaml.MapItem{Key: kv[0], Value: kv[1]})
+		} else {
+			config = append(config, yaml.MapItem{Key: kv[0], Value: kv[1]})
+		}
+	}
+
+	envFrom := make([]yaml.MapSlice, 0)
+	if len(config) > 0 {
+		docs = append(docs, yaml.MapSlice{
+			{Key: "apiVersion", Value: "v1"},
+			{Key: "kind", Value: "ConfigMap"},
+			{Key: "metadata", Value: metadata(fmt.Sprintf("%s-env", spec.name))},
+			{Key: "data", Value: config},
+		})
+		envFrom = append(envFrom, yaml.MapSlice{{Key: "configMapRef", Value: yaml.MapSlice{{Key: "name", Value: fmt.Sprintf("%s-env", spec.name)}}}})
+	}
+	if len(secrets) > 0 {
+		docs = append(docs, yaml.MapSlice{
+			{Key: "apiVersion", Value: "v1"},
+			{Key: "kind", Value: "Secret"},
+			{Key: "metadata", Value: metadata(fmt.Sprintf("%s-env", spec.name))},
+			{Key: "type", Value: "Opaque"},
+			{Key: "stringData", Value: secrets},
+		})
+		envFrom = append(envFrom, yaml.MapSlice{{Key: "secretRef", Value: yaml.MapSlice{{Key: "name", Value: fmt.Sprintf("%s-env", spec.name)}}}})
+	}
+
+	container := yaml.MapSlice{
+		{Key: "name", Value: svc},
+		{Key: "image", Value: spec.resolvedImage()},
+	}
+	if spec.entrypoint != nil {
+		container = append(container, yaml.MapItem{Key: "command", Value: *spec.entrypoint})
+	}
+	if spec.cmd != nil {
+		container = append(container, yaml.MapItem{Key: "args", Value: *spec.cmd})
+	}
+	if len(envFrom) > 0 {
+		container = append(container, yaml.MapItem{Key: "envFrom", Value: envFrom})
+	}
+
+	if len(spec.ports) > 0 {
+		ports := make([]yaml.MapSlice, 0)
+		servicePorts := make([]yaml.MapSlice, 0)
+		for _, mapping := range spec.ports {
+			ports = append(ports, yaml.MapSlice{{Key: "containerPort", Value: mapping.containerPort}})
+			servicePorts = append(servicePorts, yaml.MapSlice{
+				{Key: "name", Value: fmt.Sprintf("tcp-%d", mapping.containerPort)},
+				{Key: "port", Value: mapping.containerPort},
+				{Key: "targetPort", Value: mapping.containerPort},
+			})
+		}
+		container = append(container, yaml.MapItem{Key: "ports", Value: ports})
+
+		// other containers of the stack address the service using its hostname
+		docs = append(docs, yaml.MapSlice{
+			{Key: "apiVersion", Value: "v1"},
+			{Key: "kind", Value: "Service"},
+			{Key: "metadata", Value: metadata(spec.hostname)},
+			{Key: "spec", Value: yaml.MapSlice{
+				{Key: "selector", Value: selector},
+				{Key: "ports", Value: servicePorts},
+			}},
+		})
+	}
+
+	if spec.healthcheck != nil && len(*spec.healthcheck) > 1 {
+		container = append(container, yaml.MapItem{Key: "livenessProbe", Value: yaml.MapSlice{
+			{Key: "exec", Value: yaml.MapSlice{{Key: "command", Value: (*spec.healthcheck)[1:]}}},
+			{Key: "initialDelaySeconds", Value: 10},
+			{Key: "periodSeconds", Value: 60},
+			{Key: "timeoutSeconds", Value: 1},
+			{Key: "failureThreshold", Value: 2},
+		}})
+	}
+
+	volumes := make([]yaml.MapSlice, 0)
+	volumeMounts := make([]yaml.MapSlice, 0)
+	if len(spec.mounts) > 0 {
+		files := yaml.MapSlice{}
+		sources := make([]string, 0)
+		for source := range spec.mounts {
+			sources = append(sources, source)
+		}
+		sort.Strings(sources)
+
+		for _, source := range sources {
+			raw, err := ioutil.ReadFile(source)
+			if err != nil {
+				return nil, err
+			}
+			key := filepath.Base(source)
+			files = append(files, yaml.MapItem{Key: key, Value: string(raw)})
+			volumeMounts = append(volumeMounts, yaml.MapSlice{
+				{Key: "name", Value: "files"},
+				{Key: "mountPath", Value: spec.mounts[source]},
+				{Key: "subPath", Value: key},
+			})
+		}
+
+		docs = append(docs, yaml.MapSlice{
+			{Key: "apiVersion", Value: "v1"},
+			{Key: "kind", Value: "ConfigMap"},
+			{Key: "metadata", Value: metadata(fmt.Sprintf("%s-files", spec.name))},
+			{Key: "data", Value: files},
+		})
+		volumes = append(volumes, yaml.MapSlice{
+			{Key: "name", Value: "files"},
+			{Key: "configMap", Value: yaml.MapSlice{{Key: "name", Value: fmt.Sprintf("%s-files", spec.name)}}},
+		})
+	}
+
+	persistentVolume := k8sPersistentVolumes[svc]
+	if persistentVolume != nil {
+		volumeMount := yaml.MapSlice{
+			{Key: "name", Value: "data"},
+			{Key: "mountPath", Value: persistentVolume.mountPath},
+		}
+		if persistentVolume.subPath != "" {
+			volumeMount = append(volumeMount, yaml.MapItem{Key: "subPath", Value: persistentVolume.subPath})
+		}
+		volumeMounts = append(volumeMounts, volumeMount)
+	}
+
+	if len(volumeMounts) > 0 {
+		container = append(container, yaml.MapItem{Key: "volumeMounts", Value: volumeMounts})
+	}
+
+	podSpec := yaml.MapSlice{}
+	if svc == "elasticsearch" {
+		// elasticsearch requires vm.max_map_count of at least 262144 on the node
+		podSpec = append(podSpec, yaml.MapItem{Key: "initContainers", Value: []yaml.MapSlice{{
+			{Key: "name", Value: "sysctl"},
+			{Key: "image", Value: k8sElasticsearchInitImage},
+			{Key: "command", Value: []string{"sysctl", "-w", fmt.Sprintf("vm.max_map_count=%d", preflightMinMaxMapCount)}},
+			{Key: "securityContext", Value: yaml.MapSlice{{Key: "privileged", Value: true}}},
+		}}})
+	}
+	podSpec = append(podSpec, yaml.MapItem{Key: "hostname", Value: spec.hostname})
+	podSpec = append(podSpec, yaml.MapItem{Key: "containers", Value: []yaml.MapSlice{container}})
+	if len(volumes) > 0 {
+		podSpec = append(podSpec, yaml.MapItem{Key: "volumes", Value: volumes})
+	}
+
+	workloadSpec := yaml.MapSlice{
+		{Key: "replicas", Value: 1},
+		{Key: "selector", Value: yaml.MapSlice{{Key: "matchLabels", Value: selector}}},
+	}
+
+	kind := "Deployment"
+	if persistentVolume != nil {
+		kind = "StatefulSet"
+		workloadSpec = append(workloadSpec, yaml.MapItem{Key: "serviceName", Value: spec.hostname})
+	}
+
+	workloadSpec = append(workloadSpec, yaml.MapItem{Key: "template", Value: yaml.MapSlice{
+		{Key: "metadata", Value: yaml.MapSlice{{Key: "labels", Value: labels}}},
+		{Key: "spec", Value: podSpec},
+	}})
+
+	if persistentVolume != nil {
+		workloadSpec = append(workloadSpec, yaml.MapItem{Key: "volumeClaimTemplates", Value: []yaml.MapSlice{{
+			{Key: "metadata", Value: yaml.MapSlice{{Key: "name", Value: "data"}}},
+			{Key: "spec", Value: yaml.MapSlice{
+				{Key: "accessModes", Value: []string{"ReadWriteOnce"}},
+				{Key: "resources", Value: yaml.MapSlice{{Key: "requests", Value: yaml.MapSlice{{Key: "storage", Value: exportStorage}}}}},
+			}},
+		}}})
+	}
+
+	return append(docs, yaml.MapSlice{
+		{Key: "apiVersion", Value: "apps/v1"},
+		{Key: "kind", Value: kind},
+		{Key: "metadata", Value: metadata(spec.name)},
+		{Key: "spec", Value: workloadSpec},
+	}), nil
+}
+
+func isK8sSecret(envvar string) bool {
+	for _, secret := range k8sSecretEnvironment {
+		if strings.Contains(envvar, secret) {
+			return true
+		}
+	}
+	return false
+}
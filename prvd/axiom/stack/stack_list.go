@@ -0,0 +1,104 @@
+/*
+ * Copyright 2017-2022 Provide Technologies Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package stack
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"sort"
+	"strings"
+
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/client"
+	"github.com/provideplatform/provide-cli/prvd/common"
+	"github.com/spf13/cobra"
+)
+
+// stackDiscoveryService is the service whose container identifies a local axiom stack
+const stackDiscoveryService = "nats"
+
+var listStackCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List local axiom stacks",
+	Long:  `List each local axiom stack with its state and the host ports allocated to it`,
+	Run:   listStacks,
+}
+
+func listStacks(cmd *cobra.Command, args []string) {
+	allocations := allocatedPorts()
+
+	stacks := map[string]string{}
+	for stack := range allocations {
+		stacks[stack] = stack
+	}
+
+	docker, err := client.NewEnvClient()
+	if err != nil {
+		log.Printf("failed to initialize docker; %s", err.Error())
+		common.Exit(1)
+	}
+	defer docker.Close()
+
+	containers, err := docker.ContainerList(context.Background(), types.ContainerListOptions{All: true})
+	if err != nil {
+		log.Printf("WARNING: failed to list containers; %s", err.Error())
+		docker = nil
+	}
+	for _, container := range containers {
+		for _, name := range container.Names {
+			suffix := fmt.Sprintf("-%s", stackDiscoveryService)
+			if strings.HasSuffix(name, suffix) {
+				stack := strings.TrimSuffix(strings.TrimPrefix(name, "/"), suffix)
+				stacks[stackPortsKey(stack)] = stack
+			}
+		}
+	}
+
+	keys := make([]string, 0)
+	for key := range stacks {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	fmt.Printf("%-24s %-12s %s\n", "NAME", "STATE", "PORTS")
+	for _, key := range keys {
+		stack := stacks[key]
+
+		state := "unknown"
+		if docker != nil {
+			statuses, err := ServiceStatuses(docker, stack)
+			if err == nil {
+				switch stackStatusCode(statuses) {
+				case stackStatusHealthy:
+					state = "running"
+				case stackStatusDegraded:
+					state = "degraded"
+				case stackStatusStopped:
+					state = "stopped"
+				}
+			}
+		}
+
+		ports := "-"
+		if allocation, ok := allocations[key]; ok {
+			ports = formatPorts(allocation)
+		}
+
+		fmt.Printf("%-24s %-12s %s\n", stack, state, ports)
+	}
+}
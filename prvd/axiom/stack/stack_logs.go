@@ -17,21 +17,91 @@
 package stack
 
 import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
 	"log"
 	"os"
+	"regexp"
+	"sort"
+	"strings"
 	"sync"
+	"time"
 
+	"github.com/docker/docker/api/types"
 	"github.com/docker/docker/client"
+	"github.com/docker/docker/pkg/stdcopy"
 	"github.com/provideplatform/provide-cli/prvd/common"
+	"golang.org/x/crypto/ssh/terminal"
 
 	"github.com/spf13/cobra"
 )
 
+const logsTimestampFormat = "2006-01-02 15:04:05.000"
+const logsColorReset = "\033[0m"
+
+// logsColors are the colors of the service name prefixes, assigned in order of the service names
+var logsColors = []string{"\033[36m", "\033[33m", "\033[32m", "\033[35m", "\033[34m", "\033[31m", "\033[96m", "\033[93m", "\033[92m", "\033[95m"}
+
+var logsFollow bool
+var logsTail string
+var logsSince string
+var logsServices []string
+var logsGrep string
+var logsTimestamps bool
+var logsNoColor bool
+
 var logsBaselineStackCmd = &cobra.Command{
 	Use:   "logs",
 	Short: "Print axiom stack logs",
-	Long:  `Print the logs from each container in a local axiom stack instance`,
-	Run:   stackLogs,
+	Long: `Print the logs from each container in a local axiom stack instance.
+
+Each line is prefixed with the name of its service and its timestamp. Without --follow, the lines
+of all services are merged in timestamp order; with --follow, lines are printed as they arrive.
+Lines written to stderr by a container are printed to stderr.`,
+	Run: stackLogs,
+}
+
+// logSource is a service container of the stack from which logs are read
+type logSource struct {
+	service string
+	id      string
+	tty     bool
+	prefix  string
+}
+
+// logLine is a line of a container log
+type logLine struct {
+	timestamp time.Time
+	stderr    bool
+	text      string
+	source    *logSource
+}
+
+// logPrinter prints the log lines of the stack, either as they are written or, when collecting,
+// once all logs have been read
+type logPrinter struct {
+	mutex      sync.Mutex
+	collect    bool
+	lines      []*logLine
+	grep       *regexp.Regexp
+	timestamps bool
+	stdout     io.Writer
+	stderr     io.Writer
+}
+
+// LogsOptions configures the logs printed by PrintLogs
+type LogsOptions struct {
+	Services   []string
+	Follow     bool
+	Tail       string
+	Since      string
+	Grep       string
+	Timestamps bool
+	Color      bool
+	Stdout     io.Writer
+	Stderr     io.Writer
 }
 
 func stackLogs(cmd *cobra.Command, args []string) {
@@ -42,14 +112,248 @@ func stackLogsRun(cmd *cobra.Command, args []string) {
 	docker, err := client.NewEnvClient()
 	if err != nil {
 		log.Printf("failed to initialize docker; %s", err.Error())
-		os.Exit(1)
+		common.Exit(1)
+	}
+
+	err = PrintLogs(context.Background(), docker, name, &LogsOptions{
+		Services:   logsServices,
+		Follow:     logsFollow,
+		Tail:       logsTail,
+		Since:      logsSince,
+		Grep:       logsGrep,
+		Timestamps: logsTimestamps,
+		Color:      !logsNoColor && terminal.IsTerminal(int(os.Stdout.Fd())),
+		Stdout:     os.Stdout,
+		Stderr:     os.Stderr,
+	})
+	if err != nil {
+		log.Printf("failed to read logs of stack: %s; %s", name, err.Error())
+		common.Exit(1)
+	}
+}
+
+// PrintLogs prints the logs of the services of the named stack, each line prefixed with the name
+// of its service; when following, lines are printed as they are written until the context is
+// canceled, otherwise the lines of all services are printed in timestamp order
+func PrintLogs(ctx context.Context, docker *client.Client, stack string, opts *LogsOptions) error {
+	printer := &logPrinter{
+		collect:    !opts.Follow,
+		timestamps: opts.Timestamps,
+		stdout:     opts.Stdout,
+		stderr:     opts.Stderr,
+	}
+
+	if opts.Grep != "" {
+		grep, err := regexp.Compile(opts.Grep)
+		if err != nil {
+			return fmt.Errorf("invalid grep pattern; %s", err.Error())
+		}
+		printer.grep = grep
+	}
+
+	sources, err := logSources(docker, stack, opts.Services)
+	if err != nil {
+		return err
+	}
+
+	width := 0
+	for _, source := range sources {
+		if len(source.service) > width {
+			width = len(source.service)
+		}
+	}
+	for i, source := range sources {
+		source.prefix = fmt.Sprintf("%-*s |", width, source.service)
+		if opts.Color {
+			source.prefix = fmt.Sprintf("%s%s%s", logsColors[i%len(logsColors)], source.prefix, logsColorReset)
+		}
 	}
 
 	wg := sync.WaitGroup{}
-	common.LogContainers(docker, &wg, name)
+	for _, source := range sources {
+		wg.Add(1)
+		go func(source *logSource) {
+			defer wg.Done()
+			if err := printer.read(ctx, docker, source, opts); err != nil && ctx.Err() == nil {
+				log.Printf("WARNING: failed to read logs of %s; %s", source.service, err.Error())
+			}
+		}(source)
+	}
 	wg.Wait()
+
+	printer.flush()
+	return nil
+}
+
+// logSources returns the containers of the stack, optionally limited to the given services
+func logSources(docker *client.Client, stack string, services []string) ([]*logSource, error) {
+	containers, err := Containers(docker, stack)
+	if err != nil {
+		return nil, err
+	}
+	if len(containers) == 0 {
+		return nil, fmt.Errorf("no containers found")
+	}
+
+	available := map[string]*logSource{}
+	names := make([]string, 0)
+	for _, container := range containers {
+		tty := false
+		if inspect, err := docker.ContainerInspect(context.Background(), container.ID); err == nil && inspect.Config != nil {
+			tty = inspect.Config.Tty
+		}
+
+		available[container.Service] = &logSource{
+			service: container.Service,
+			id:      container.ID,
+			tty:     tty,
+		}
+		names = append(names, container.Service)
+	}
+
+	sources := make([]*logSource, 0)
+	if len(services) == 0 {
+		for _, service := range names {
+			sources = append(sources, available[service])
+		}
+		return sources, nil
+	}
+
+	for _, service := range services {
+		source, ok := available[strings.TrimSpace(service)]
+		if !ok {
+			return nil, fmt.Errorf("unknown service %s; available services: %s", service, strings.Join(names, ", "))
+		}
+		sources = append(sources, source)
+	}
+	return sources, nil
+}
+
+// read reads the logs of the given container, demultiplexing stdout and stderr into lines
+func (p *logPrinter) read(ctx context.Context, docker *client.Client, source *logSource, opts *LogsOptions) error {
+	out, err := docker.ContainerLogs(ctx, source.id, types.ContainerLogsOptions{
+		ShowStdout: true,
+		ShowStderr: true,
+		Follow:     opts.Follow,
+		Tail:       opts.Tail,
+		Since:      opts.Since,
+		Timestamps: true,
+	})
+	if err != nil {
+		return err
+	}
+	defer out.Close()
+
+	stdout := &logLineWriter{printer: p, source: source}
+	stderr := &logLineWriter{printer: p, source: source, stderr: true}
+	defer stdout.Close()
+	defer stderr.Close()
+
+	if source.tty {
+		_, err = io.Copy(stdout, out)
+	} else {
+		_, err = stdcopy.StdCopy(stdout, stderr, out)
+	}
+	return err
+}
+
+// add prints the line unless it does not match --grep; lines are held until flush when collecting
+func (p *logPrinter) add(line *logLine) {
+	if p.grep != nil && !p.grep.MatchString(line.text) {
+		return
+	}
+
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	if p.collect {
+		p.lines = append(p.lines, line)
+		return
+	}
+	p.print(line)
+}
+
+// flush prints the collected lines in timestamp order
+func (p *logPrinter) flush() {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	sort.SliceStable(p.lines, func(i, j int) bool {
+		return p.lines[i].timestamp.Before(p.lines[j].timestamp)
+	})
+	for _, line := range p.lines {
+		p.print(line)
+	}
+	p.lines = nil
+}
+
+func (p *logPrinter) print(line *logLine) {
+	w := p.stdout
+	if line.stderr {
+		w = p.stderr
+	}
+
+	if p.timestamps && !line.timestamp.IsZero() {
+		fmt.Fprintf(w, "%s %s %s\n", line.source.prefix, line.timestamp.Local().Format(logsTimestampFormat), line.text)
+	} else {
+		fmt.Fprintf(w, "%s %s\n", line.source.prefix, line.text)
+	}
+}
+
+// logLineWriter splits the demultiplexed output of a container into lines
+type logLineWriter struct {
+	printer *logPrinter
+	source  *logSource
+	stderr  bool
+	buf     bytes.Buffer
+}
+
+func (w *logLineWriter) Write(b []byte) (int, error) {
+	w.buf.Write(b)
+	for {
+		i := bytes.IndexByte(w.buf.Bytes(), '\n')
+		if i < 0 {
+			return len(b), nil
+		}
+		w.emit(string(w.buf.Next(i + 1)))
+	}
+}
+
+// Close emits the trailing line which is not terminated by a newline, if any
+func (w *logLineWriter) Close() error {
+	if w.buf.Len() > 0 {
+		w.emit(w.buf.String())
+		w.buf.Reset()
+	}
+	return nil
+}
+
+// emit parses the timestamp docker prepends to each line, i.e., 2022-11-01T12:00:00.000000000Z
+func (w *logLineWriter) emit(raw string) {
+	raw = strings.TrimRight(raw, "\r\n")
+
+	line := &logLine{
+		stderr: w.stderr,
+		text:   raw,
+		source: w.source,
+	}
+	if i := strings.IndexByte(raw, ' '); i > 0 {
+		if timestamp, err := time.Parse(time.RFC3339Nano, raw[:i]); err == nil {
+			line.timestamp = timestamp
+			line.text = raw[i+1:]
+		}
+	}
+
+	w.printer.add(line)
 }
 
 func init() {
 	logsBaselineStackCmd.Flags().StringVar(&name, "name", "axiom-local", "name of the axiom stack instance")
+	logsBaselineStackCmd.Flags().BoolVarP(&logsFollow, "follow", "f", false, "when true, the logs are streamed as they are written")
+	logsBaselineStackCmd.Flags().StringVar(&logsTail, "tail", "all", "number of lines to print from the end of the logs of each service")
+	logsBaselineStackCmd.Flags().StringVar(&logsSince, "since", "", "print logs since a timestamp, i.e., 2022-11-01T12:00:00Z, or relative duration, i.e., 10m")
+	logsBaselineStackCmd.Flags().StringSliceVar(&logsServices, "service", []string{}, "comma-separated services of which to print logs, i.e., api,consumer")
+	logsBaselineStackCmd.Flags().StringVar(&logsGrep, "grep", "", "regular expression which printed lines must match")
+	logsBaselineStackCmd.Flags().BoolVar(&logsTimestamps, "timestamps", true, "when true, each line is printed with its timestamp")
+	logsBaselineStackCmd.Flags().BoolVar(&logsNoColor, "no-color", false, "when true, service name prefixes are not colored")
 }
@@ -0,0 +1,114 @@
+/*
+ * Copyright 2017-2022 Provide Technologies Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package stack
+
+import (
+	"bytes"
+	"regexp"
+	"testing"
+	"time"
+)
+
+func TestLogLineWriter(t *testing.T) {
+	tests := []struct {
+		name          string
+		writes        []string
+		wantText      []string
+		wantTimestamp []time.Time
+	}{
+		{
+			name:          "timestamped line",
+			writes:        []string{"2022-11-01T12:00:00.123456789Z listening on :8080\n"},
+			wantText:      []string{"listening on :8080"},
+			wantTimestamp: []time.Time{time.Date(2022, 11, 1, 12, 0, 0, 123456789, time.UTC)},
+		},
+		{
+			name:          "timezone offset",
+			writes:        []string{"2022-11-01T14:00:00+02:00 ready\n"},
+			wantText:      []string{"ready"},
+			wantTimestamp: []time.Time{time.Date(2022, 11, 1, 12, 0, 0, 0, time.UTC)},
+		},
+		{
+			name:          "line without timestamp",
+			writes:        []string{"panic: runtime error\n"},
+			wantText:      []string{"panic: runtime error"},
+			wantTimestamp: []time.Time{{}},
+		},
+		{
+			name:          "line split across writes",
+			writes:        []string{"2022-11-01T12:00:00Z con", "nected\r\n2022-11-01T12:00:01Z done"},
+			wantText:      []string{"connected", "done"},
+			wantTimestamp: []time.Time{time.Date(2022, 11, 1, 12, 0, 0, 0, time.UTC), time.Date(2022, 11, 1, 12, 0, 1, 0, time.UTC)},
+		},
+		{
+			name:          "empty line",
+			writes:        []string{"2022-11-01T12:00:00Z \n"},
+			wantText:      []string{""},
+			wantTimestamp: []time.Time{time.Date(2022, 11, 1, 12, 0, 0, 0, time.UTC)},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			printer := &logPrinter{collect: true}
+			w := &logLineWriter{printer: printer, source: &logSource{service: "api"}}
+			for _, write := range tt.writes {
+				w.Write([]byte(write))
+			}
+			w.Close()
+
+			if len(printer.lines) != len(tt.wantText) {
+				t.Fatalf("emitted %d lines, want %d", len(printer.lines), len(tt.wantText))
+			}
+			for i, line := range printer.lines {
+				if line.text != tt.wantText[i] {
+					t.Errorf("line %d text = %q, want %q", i, line.text, tt.wantText[i])
+				}
+				if !line.timestamp.Equal(tt.wantTimestamp[i]) {
+					t.Errorf("line %d timestamp = %s, want %s", i, line.timestamp, tt.wantTimestamp[i])
+				}
+			}
+		})
+	}
+}
+
+func TestLogPrinterFlush(t *testing.T) {
+	stdout := &bytes.Buffer{}
+	stderr := &bytes.Buffer{}
+	printer := &logPrinter{
+		collect: true,
+		grep:    regexp.MustCompile("ready|error"),
+		stdout:  stdout,
+		stderr:  stderr,
+	}
+
+	api := &logSource{service: "api", prefix: "api  |"}
+	nats := &logSource{service: "nats", prefix: "nats |"}
+	at := func(sec int) time.Time { return time.Date(2022, 11, 1, 12, 0, sec, 0, time.UTC) }
+
+	printer.add(&logLine{timestamp: at(2), text: "api ready", source: api})
+	printer.add(&logLine{timestamp: at(1), text: "nats ready", source: nats})
+	printer.add(&logLine{timestamp: at(0), text: "starting", source: api})
+	printer.add(&logLine{timestamp: at(3), text: "api error", source: api, stderr: true})
+	printer.flush()
+
+	if want := "nats | nats ready\napi  | api ready\n"; stdout.String() != want {
+		t.Errorf("stdout = %q, want %q", stdout.String(), want)
+	}
+	if want := "api  | api error\n"; stderr.String() != want {
+		t.Errorf("stderr = %q, want %q", stderr.String(), want)
+	}
+}
@@ -0,0 +1,202 @@
+/*
+ * Copyright 2017-2022 Provide Technologies Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package stack
+
+import (
+	"fmt"
+	"net"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/docker/docker/client"
+	"github.com/spf13/pflag"
+	"github.com/spf13/viper"
+)
+
+// stackPortsConfigKey is the config key of the host ports allocated to each named stack
+const stackPortsConfigKey = "stack-ports"
+
+// portBlockStride is the distance between the port blocks allocated to named stacks; the
+// block at offset n allocates each default host port + n * portBlockStride
+const portBlockStride = 10
+
+// maxPortBlocks is the number of port blocks considered by the port allocator
+const maxPortBlocks = 100
+
+// optionalServiceFlags maps the optional local services of a stack to the flags which enable them
+var optionalServiceFlags = map[string]string{
+	"ident":   "with-local-ident",
+	"nchain":  "with-local-nchain",
+	"privacy": "with-local-privacy",
+	"vault":   "with-local-vault",
+}
+
+// allocatedPorts returns the host ports persisted for each named stack
+func allocatedPorts() map[string]map[string]int {
+	allocations := map[string]map[string]int{}
+	stacks, _ := viper.Get(stackPortsConfigKey).(map[string]interface{})
+	for stack, val := range stacks {
+		ports := map[string]int{}
+		svcs, _ := val.(map[string]interface{})
+		for svc, port := range svcs {
+			if p, err := strconv.Atoi(fmt.Sprintf("%v", port)); err == nil {
+				ports[svc] = p
+			}
+		}
+		allocations[stack] = ports
+	}
+	return allocations
+}
+
+// persistAllocatedPorts persists the host ports of the named stack; nil ports release its allocation
+func persistAllocatedPorts(stack string, ports map[string]int) error {
+	allocations := allocatedPorts()
+	if ports == nil {
+		delete(allocations, stackPortsKey(stack))
+	} else {
+		allocations[stackPortsKey(stack)] = ports
+	}
+
+	stacks := map[string]interface{}{}
+	for s, p := range allocations {
+		svcs := map[string]interface{}{}
+		for svc, port := range p {
+			svcs[svc] = port
+		}
+		stacks[s] = svcs
+	}
+
+	viper.Set(stackPortsConfigKey, stacks)
+	return viper.WriteConfig()
+}
+
+// allocatePorts configures the host port flags of the named stack which were not given explicitly,
+// or by the stack definition file, using the ports persisted for the stack or otherwise the first
+// block of ports which is neither allocated to another stack nor in use; the allocated ports are
+// persisted for the stack when persist is true, whereas explicit ports only apply to this run
+func allocatePorts(flags *pflag.FlagSet, stack string, persist bool) error {
+	allocations := allocatedPorts()
+	ports, ok := allocations[stackPortsKey(stack)]
+	if !ok {
+		var err error
+		owned := map[int]bool{}
+		if docker, err := client.NewEnvClient(); err == nil {
+			owned = stackPublishedPorts(docker, stack)
+			docker.Close()
+		}
+
+		ports, err = allocatePortBlock(flags, stack, allocations, owned)
+		if err != nil {
+			return err
+		}
+	}
+
+	for svc, flag := range hostPortFlags {
+		f := flags.Lookup(flag)
+		if f == nil {
+			continue
+		}
+		if f.Changed {
+			continue
+		}
+		if port, ok := ports[svc]; ok {
+			if err := flags.Set(flag, strconv.Itoa(port)); err != nil {
+				return err
+			}
+			f.Changed = false
+		}
+	}
+
+	if persist {
+		return persistAllocatedPorts(stack, ports)
+	}
+	return nil
+}
+
+// allocatePortBlock returns the first block of default host ports which is neither allocated to
+// another stack nor in use on the host; ports owned by the running containers of the stack are
+// not in use, and only the ports of enabled services are required to be free
+func allocatePortBlock(flags *pflag.FlagSet, stack string, allocations map[string]map[string]int, owned map[int]bool) (map[string]int, error) {
+	allocated := map[int]bool{}
+	for s, ports := range allocations {
+		if s == stackPortsKey(stack) {
+			continue
+		}
+		for _, port := range ports {
+			allocated[port] = true
+		}
+	}
+
+	defaults := defaultHostPorts(flags)
+	for offset := 0; offset < maxPortBlocks; offset++ {
+		ports := map[string]int{}
+		available := true
+		for svc, port := range defaults {
+			p := port + offset*portBlockStride
+			if allocated[p] || (serviceEnabled(flags, svc) && !owned[p] && !isPortFree(p)) {
+				available = false
+				break
+			}
+			ports[svc] = p
+		}
+		if available {
+			return ports, nil
+		}
+	}
+
+	return nil, fmt.Errorf("failed to allocate host ports for stack: %s; no free block of ports", stack)
+}
+
+// serviceEnabled returns true unless the service is an optional local service which is not enabled
+func serviceEnabled(flags *pflag.FlagSet, svc string) bool {
+	flag, ok := optionalServiceFlags[svc]
+	if !ok {
+		return true
+	}
+	f := flags.Lookup(flag)
+	return f != nil && f.Value.String() == "true"
+}
+
+func isPortFree(port int) bool {
+	listener, err := net.Listen("tcp", net.JoinHostPort("0.0.0.0", strconv.Itoa(port)))
+	if err != nil {
+		return false
+	}
+	listener.Close()
+	return true
+}
+
+// stackPortsKey returns the key of the named stack within the persisted allocations; config keys are case-insensitive
+func stackPortsKey(stack string) string {
+	return strings.ToLower(strings.ReplaceAll(stack, " ", ""))
+}
+
+// formatPorts renders the given host ports, i.e., api=8080 nats=4222
+func formatPorts(ports map[string]int) string {
+	svcs := make([]string, 0)
+	for svc := range ports {
+		svcs = append(svcs, svc)
+	}
+	sort.Strings(svcs)
+
+	rendered := make([]string, 0)
+	for _, svc := range svcs {
+		rendered = append(rendered, fmt.Sprintf("%s=%d", svc, ports[svc]))
+	}
+	return strings.Join(rendered, " ")
+}
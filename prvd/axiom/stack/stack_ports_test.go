@@ -0,0 +1,98 @@
+/*
+ * Copyright 2017-2022 Provide Technologies Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package stack
+
+import (
+	"net"
+	"testing"
+
+	"github.com/spf13/pflag"
+)
+
+func TestAllocatePortBlock(t *testing.T) {
+	tests := []struct {
+		name          string
+		identEnabled  bool
+		busy          []string
+		owned         []string
+		allocated     []string
+		ownAllocation bool
+		wantOffset    int
+	}{
+		{name: "free ports", wantOffset: 0},
+		{name: "port in use", busy: []string{"api"}, wantOffset: 1},
+		{name: "port owned by the stack", busy: []string{"api"}, owned: []string{"api"}, wantOffset: 0},
+		{name: "port of disabled service in use", busy: []string{"ident"}, wantOffset: 0},
+		{name: "port of enabled service in use", identEnabled: true, busy: []string{"ident"}, wantOffset: 1},
+		{name: "port allocated to another stack", allocated: []string{"api"}, wantOffset: 1},
+		{name: "port of disabled service allocated to another stack", allocated: []string{"ident"}, wantOffset: 1},
+		{name: "ports allocated to the stack itself", allocated: []string{"api", "ident"}, ownAllocation: true, wantOffset: 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			listeners := map[string]net.Listener{}
+			ports := map[string]int{}
+			for _, svc := range []string{"api", "ident"} {
+				listener, err := net.Listen("tcp", "0.0.0.0:0")
+				if err != nil {
+					t.Fatal(err)
+				}
+				defer listener.Close()
+				listeners[svc] = listener
+				ports[svc] = listener.Addr().(*net.TCPAddr).Port
+			}
+			for svc, listener := range listeners {
+				if !containsString(tt.busy, svc) {
+					listener.Close()
+				}
+			}
+
+			flags := pflag.NewFlagSet("start", pflag.ContinueOnError)
+			flags.Int("port", ports["api"], "")
+			flags.Int("ident-local-port", ports["ident"], "")
+			flags.Bool("with-local-ident", tt.identEnabled, "")
+
+			owned := map[int]bool{}
+			for _, svc := range tt.owned {
+				owned[ports[svc]] = true
+			}
+
+			stack := "axiom-test"
+			allocations := map[string]map[string]int{}
+			for _, svc := range tt.allocated {
+				key := "other-stack"
+				if tt.ownAllocation {
+					key = stackPortsKey(stack)
+				}
+				if allocations[key] == nil {
+					allocations[key] = map[string]int{}
+				}
+				allocations[key][svc] = ports[svc]
+			}
+
+			got, err := allocatePortBlock(flags, stack, allocations, owned)
+			if err != nil {
+				t.Fatalf("allocatePortBlock() error = %v", err)
+			}
+			for svc, port := range ports {
+				if want := port + tt.wantOffset*portBlockStride; got[svc] != want {
+					t.Errorf("allocatePortBlock()[%s] = %d, want %d", svc, got[svc], want)
+				}
+			}
+		})
+	}
+}
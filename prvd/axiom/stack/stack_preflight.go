@@ -0,0 +1,345 @@
+/*
+ * Copyright 2017-2022 Provide Technologies Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package stack
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"log"
+	"net"
+	"os"
+	"runtime"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/blang/semver/v4"
+	"github.com/docker/docker/client"
+	"github.com/provideplatform/provide-cli/prvd/common"
+	"github.com/spf13/cobra"
+)
+
+// preflightMinDockerVersion is the minimum version of the docker engine supported by the stack
+const preflightMinDockerVersion = "20.10.0"
+
+// preflightMinMemory is the minimum memory of the docker host, in bytes
+const preflightMinMemory = 4 * 1024 * 1024 * 1024
+
+// preflightElasticsearchMemoryHeadroom is the memory required by the docker host in addition to
+// the memory allocated to elasticsearch using --elasticsearch-memory, in bytes
+const preflightElasticsearchMemoryHeadroom = 2 * 1024 * 1024 * 1024
+
+// preflightMinDiskSpace is the minimum free disk space of the docker root directory, in bytes
+const preflightMinDiskSpace = 10 * 1024 * 1024 * 1024
+
+// preflightMinMaxMapCount is the minimum vm.max_map_count required by elasticsearch
+const preflightMinMaxMapCount = 262144
+
+const preflightMaxMapCountPath = "/proc/sys/vm/max_map_count"
+
+var skipPreflight bool
+
+var preflightStackCmd = &cobra.Command{
+	Use:   "preflight",
+	Short: "Check the host before starting the axiom stack",
+	Long: `Run the pre-flight checks of prvd axiom stack start without starting the stack.
+
+Verifies the docker daemon is available and supported, the host port of every configured
+service is free, vm.max_map_count and memory suffice for elasticsearch, the docker root
+directory has free disk space and every container image is available; all problems are
+reported at once. Accepts the flags and stack definition file of prvd axiom stack start.`,
+	Run: stackPreflight,
+}
+
+// preflightResult is the outcome of a pre-flight check; a result with an error is a problem
+type preflightResult struct {
+	check  string
+	detail string
+	err    error
+}
+
+func stackPreflight(cmd *cobra.Command, args []string) {
+	if err := loadStackConfig(cmd.Flags()); err != nil {
+		log.Printf("failed to load stack definition; %s", err.Error())
+		common.Exit(1)
+	}
+
+	if err := allocatePorts(cmd.Flags(), name, false); err != nil {
+		log.Printf("failed to allocate stack ports; %s", err.Error())
+		common.Exit(1)
+	}
+
+	docker, err := client.NewEnvClient()
+	if err != nil {
+		log.Printf("failed to initialize docker; %s", err.Error())
+		common.Exit(1)
+	}
+	defer docker.Close()
+
+	results := preflight(docker)
+	problems := 0
+	for _, result := range results {
+		if result.err != nil {
+			problems++
+			fmt.Printf("%-6s %-16s %s\n", "FAIL", result.check, result.err.Error())
+		} else {
+			fmt.Printf("%-6s %-16s %s\n", "ok", result.check, result.detail)
+		}
+	}
+
+	if problems > 0 {
+		fmt.Printf("\n%d pre-flight check(s) failed\n", problems)
+		common.Exit(1)
+	}
+}
+
+// requirePreflight runs the pre-flight checks and exits reporting every problem found
+func requirePreflight(docker *client.Client) {
+	problems := make([]string, 0)
+	for _, result := range preflight(docker) {
+		if result.err != nil {
+			problems = append(problems, fmt.Sprintf("%s: %s", result.check, result.err.Error()))
+		}
+	}
+
+	if len(problems) > 0 {
+		log.Printf("failed to start local BPI instance; %d pre-flight check(s) failed:\n\t%s", len(problems), strings.Join(problems, "\n\t"))
+		common.Exit(1)
+	}
+}
+
+// preflight checks the docker daemon and the host against the configured stack
+func preflight(docker *client.Client) []*preflightResult {
+	results := make([]*preflightResult, 0)
+
+	version, err := docker.ServerVersion(context.Background())
+	available := err == nil
+	if available {
+		results = append(results, preflightDockerVersion(version.Version))
+	} else {
+		results = append(results, &preflightResult{check: "docker", err: fmt.Errorf("docker daemon is unavailable; %s", err.Error())})
+	}
+
+	results = append(results, preflightPorts(docker, available)...)
+	results = append(results, preflightMaxMapCount())
+	if !available {
+		return results
+	}
+
+	info, err := docker.Info(context.Background())
+	if err != nil {
+		results = append(results, &preflightResult{check: "docker", err: fmt.Errorf("failed to inspect docker daemon; %s", err.Error())})
+	} else {
+		results = append(results, preflightMemory(info.MemTotal))
+		results = append(results, preflightDiskSpace(info.DockerRootDir))
+	}
+
+	return append(results, preflightImages(docker)...)
+}
+
+func preflightDockerVersion(version string) *preflightResult {
+	result := &preflightResult{check: "docker", detail: fmt.Sprintf("docker %s", version)}
+
+	v, err := semver.ParseTolerant(version)
+	if err != nil {
+		result.detail = fmt.Sprintf("docker %s; unable to verify version", version)
+		return result
+	}
+	if v.LT(semver.MustParse(preflightMinDockerVersion)) {
+		result.err = fmt.Errorf("docker %s is not supported; docker %s or later is required", version, preflightMinDockerVersion)
+	}
+	return result
+}
+
+// preflightPorts verifies the host port of each configured service is unique and free; ports
+// published by the containers of the stack itself are considered free, as starting the stack
+// stops or removes its previous containers
+func preflightPorts(docker *client.Client, dockerAvailable bool) []*preflightResult {
+	ports := map[string]int{
+		"api":           port,
+		"elasticsearch": elasticPort,
+		"nats":          natsPort,
+		"nats-ws":       natsWebsocketPort,
+		"postgres":      postgresPort,
+		"redis":         redisPort,
+	}
+	if withLocalIdent {
+		ports["ident"] = identPort
+	}
+	if withLocalNChain {
+		ports["nchain"] = nchainPort
+	}
+	if withLocalPrivacy {
+		ports["privacy"] = privacyPort
+	}
+	if withLocalVault {
+		ports["vault"] = vaultPort
+	}
+
+	published := map[int]bool{}
+	if dockerAvailable {
+		published = stackPublishedPorts(docker, name)
+	}
+
+	services := make([]string, 0)
+	for svc := range ports {
+		services = append(services, svc)
+	}
+	sort.Strings(services)
+
+	results := make([]*preflightResult, 0)
+	allocated := map[int]string{}
+	for _, svc := range services {
+		p := ports[svc]
+		result := &preflightResult{check: fmt.Sprintf("port %d", p), detail: fmt.Sprintf("%s port is free", svc)}
+		if other, ok := allocated[p]; ok {
+			result.err = fmt.Errorf("%s port is also configured for %s", svc, other)
+		} else if !published[p] {
+			if listener, err := net.Listen("tcp", net.JoinHostPort("0.0.0.0", strconv.Itoa(p))); err != nil {
+				result.err = fmt.Errorf("%s port is already in use; stop the process using it or configure another port", svc)
+			} else {
+				listener.Close()
+			}
+		}
+		allocated[p] = svc
+		results = append(results, result)
+	}
+	return results
+}
+
+// stackPublishedPorts returns the host ports published by the containers of the given stack
+func stackPublishedPorts(docker *client.Client, stack string) map[int]bool {
+	published := map[int]bool{}
+	containers, err := common.ListContainers(docker, stack)
+	if err != nil {
+		return published
+	}
+
+	for _, container := range containers {
+		if len(container.Names) == 0 || !isStackContainer(container.Names[0], stack) {
+			continue // the name filter matches substrings, i.e., the containers of axiom-local2 for axiom-local
+		}
+		for _, p := range container.Ports {
+			if p.PublicPort != 0 {
+				published[int(p.PublicPort)] = true
+			}
+		}
+	}
+	return published
+}
+
+// isStackContainer returns true if the container of the given name, i.e., /axiom-local-api, is a
+// container of the given stack
+func isStackContainer(containerName, stack string) bool {
+	prefix := fmt.Sprintf("%s-", strings.ReplaceAll(stack, " ", ""))
+	containerName = strings.TrimPrefix(containerName, "/")
+	if !strings.HasPrefix(containerName, prefix) {
+		return false
+	}
+	_, ok := stackContainerServices[strings.TrimPrefix(containerName, prefix)]
+	return ok
+}
+
+// preflightMaxMapCount verifies vm.max_map_count suffices for elasticsearch on linux hosts
+func preflightMaxMapCount() *preflightResult {
+	result := &preflightResult{check: "vm.max_map_count"}
+	if runtime.GOOS != "linux" {
+		result.detail = fmt.Sprintf("not applicable on %s", runtime.GOOS)
+		return result
+	}
+
+	raw, err := ioutil.ReadFile(preflightMaxMapCountPath)
+	if err != nil {
+		result.detail = fmt.Sprintf("unable to read %s", preflightMaxMapCountPath)
+		return result
+	}
+
+	count, err := strconv.Atoi(strings.TrimSpace(string(raw)))
+	if err != nil {
+		result.detail = fmt.Sprintf("unable to parse %s", preflightMaxMapCountPath)
+		return result
+	}
+
+	result.detail = strconv.Itoa(count)
+	if count < preflightMinMaxMapCount {
+		result.err = fmt.Errorf("%d is too low for elasticsearch; run sysctl -w vm.max_map_count=%d", count, preflightMinMaxMapCount)
+	}
+	return result
+}
+
+// preflightMemory verifies the memory of the docker host suffices for the stack and elasticsearch
+func preflightMemory(memTotal int64) *preflightResult {
+	required := int64(preflightMinMemory)
+	if elasticMemory != "" {
+		gb, err := strconv.ParseInt(strings.Replace(elasticMemory, "GB", "", -1), 10, 64)
+		if err != nil {
+			return &preflightResult{check: "memory", err: fmt.Errorf("invalid elasticsearch memory allocation: %s", elasticMemory)}
+		}
+		if esRequired := gb*1024*1024*1024 + preflightElasticsearchMemoryHeadroom; esRequired > required {
+			required = esRequired
+		}
+	}
+
+	result := &preflightResult{check: "memory", detail: fmt.Sprintf("%s available to docker", common.FormatBytes(uint64(memTotal)))}
+	if memTotal < required {
+		result.err = fmt.Errorf("%s available to docker; at least %s is required", common.FormatBytes(uint64(memTotal)), common.FormatBytes(uint64(required)))
+	}
+	return result
+}
+
+// preflightDiskSpace verifies the free disk space of the docker root directory, when local
+func preflightDiskSpace(dockerRootDir string) *preflightResult {
+	result := &preflightResult{check: "disk"}
+	if _, err := os.Stat(dockerRootDir); dockerRootDir == "" || err != nil {
+		result.detail = "docker root directory is not local; unable to verify free disk space"
+		return result
+	}
+
+	free, err := diskFree(dockerRootDir)
+	if err != nil {
+		result.detail = fmt.Sprintf("unable to verify free disk space; %s", err.Error())
+		return result
+	}
+
+	result.detail = fmt.Sprintf("%s free in %s", common.FormatBytes(free), dockerRootDir)
+	if free < preflightMinDiskSpace {
+		result.err = fmt.Errorf("%s free in %s; at least %s is required", common.FormatBytes(free), dockerRootDir, common.FormatBytes(preflightMinDiskSpace))
+	}
+	return result
+}
+
+// preflightImages verifies each required container image is present locally or available from its registry
+func preflightImages(docker *client.Client) []*preflightResult {
+	results := make([]*preflightResult, 0)
+	for _, image := range requiredImages() {
+		canonicalImage := image
+		if dockerRegistry != "" {
+			canonicalImage = fmt.Sprintf("%s/%s", dockerRegistry, image)
+		}
+
+		result := &preflightResult{check: "image", detail: fmt.Sprintf("%s is present", canonicalImage)}
+		if _, _, err := docker.ImageInspectWithRaw(context.Background(), canonicalImage); err != nil {
+			result.detail = fmt.Sprintf("%s is available", canonicalImage)
+			if _, err := docker.DistributionInspect(context.Background(), canonicalImage, ""); err != nil {
+				result.err = fmt.Errorf("%s is unavailable; %s", canonicalImage, err.Error())
+			}
+		}
+		results = append(results, result)
+	}
+	return results
+}
@@ -48,9 +48,10 @@ var SoRPromptLabel = "Select a Sor"
 
 // General Endpoints
 func generalPrompt(cmd *cobra.Command, args []string, currentStep string) {
+	ctx := common.ContextFromCommand(cmd)
 	switch step := currentStep; step {
 	case promptStepStart:
-		common.RequireOrganization()
+		common.RequireOrganization(ctx)
 		if Optional {
 			if name == "" {
 				name = common.FreeInput("Name", "", common.NoValidation)
@@ -166,7 +167,7 @@ func generalPrompt(cmd *cobra.Command, args []string, currentStep string) {
 			if axiomRegistryContractAddress == "0x" {
 				axiomOrganizationAddress = common.FreeInput("Baseline Registry Contract Address", "0x", common.HexValidation)
 			}
-			if common.WorkgroupID == "" {
+			if ctx.WorkgroupID == "" {
 				axiomOrganizationAddress = common.FreeInput("Baseline Workgroup ID", "", common.HexValidation)
 			}
 			if nchainBaselineNetworkID == "0x" {
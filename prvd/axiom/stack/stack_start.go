@@ -63,12 +63,7 @@ const postgresContainerImage = "postgres"
 const natsContainerImage = "provide/nats-server:2.7.2-PRVD"
 const redisContainerImage = "redis"
 const defaultContainerReachabilityTimeout = time.Millisecond * 2500
-const defaultBPIStackName = "axiom-local"
-const defaultElasticReachabilityTimeout = time.Second * 5
 const defaultNatsServerName = "prvd"
-const defaultNatsReachabilityTimeout = time.Second * 5
-const defaultPostgresReachabilityTimeout = time.Second * 5
-const defaultRedisReachabilityTimeout = time.Second * 5
 
 const defaultJWTSignerPublicKey = `-----BEGIN PUBLIC KEY-----
 MIICIjANBgkqhkiG9w0BAQEFAAOCAg8AMIICCgKCAgEAullT/WoZnxecxKwQFlwE
@@ -103,6 +98,43 @@ type portMapping struct {
 	containerPort int
 }
 
+// containerSpec describes a container of the local stack; mounts are mapped source => target
+// and named volumes are mapped volume => target
+type containerSpec struct {
+	name        string
+	hostname    string
+	image       string
+	entrypoint  *[]string
+	cmd         *[]string
+	healthcheck *[]string
+	env         *[]string
+	mounts      map[string]string
+	volumes     map[string]string
+	ports       []portMapping
+}
+
+// resolvedImage returns the image of the container, as overridden by the stack definition file
+func (c *containerSpec) resolvedImage() string {
+	return stackContainerImage(c.name, c.image)
+}
+
+// environment returns the environment of the container; containers without an environment
+// of their own use the common environment of the stack, with the port to listen on when
+// exactly one port is mapped
+func (c *containerSpec) environment() []string {
+	var environment []string
+	if c.env != nil {
+		environment = *c.env
+	} else {
+		var listenPort *int
+		if len(c.ports) == 1 {
+			listenPort = &c.ports[0].containerPort
+		}
+		environment = containerEnvironmentFactory(listenPort)
+	}
+	return stackContainerEnvironment(c.name, environment)
+}
+
 var dockerNetworkID string
 var Optional bool
 var name string
@@ -229,24 +261,46 @@ var axiomDirPath string
 var startBaselineStackCmd = &cobra.Command{
 	Use:   "start",
 	Short: "Start the axiom stack",
-	Long:  `Start a local BPI stack instance and connect to internal systems of record`,
-	Run:   startStack,
+	Long: `Start a local BPI stack instance and connect to internal systems of record.
+
+Unless given explicitly, the host ports of a named stack are allocated from the first block of
+ports not allocated to another stack nor in use, and are kept for the stack until it is pruned.
+
+The stack may be defined using a versioned stack definition file given using --file, which
+configures its services, images, ports, environment, system of record and tunnels; flags
+override individual values of the file. See prvd axiom stack config print.`,
+	Run: startStack,
 }
 
 func startStack(cmd *cobra.Command, args []string) {
+	if err := loadStackConfig(cmd.Flags()); err != nil {
+		log.Printf("failed to load stack definition; %s", err.Error())
+		common.Exit(1)
+	}
+
+	if err := allocatePorts(cmd.Flags(), name, true); err != nil {
+		log.Printf("failed to allocate stack ports; %s", err.Error())
+		common.Exit(1)
+	}
+
 	generalPrompt(cmd, args, promptStepStart)
 }
 
 func runStackStart(cmd *cobra.Command, args []string) {
+	ctx := common.ContextFromCommand(cmd)
 	docker, err := client.NewEnvClient()
 	if err != nil {
 		log.Printf("failed to initialize docker; %s", err.Error())
-		os.Exit(1)
+		common.Exit(1)
+	}
+
+	if !skipPreflight {
+		requirePreflight(docker)
 	}
 
 	go common.PurgeContainers(docker, name, prune)
 
-	authorizeContext()
+	authorizeContext(ctx)
 	normalizeHostnames()
 	sorPrompt()
 	tunnelAPIPrompt()
@@ -254,6 +308,98 @@ func runStackStart(cmd *cobra.Command, args []string) {
 
 	wg := &sync.WaitGroup{}
 
+	if common.IsReleaseContext() {
+		if !withLocalIdent {
+			requireAPIServiceCompatibility("ident", identAPIScheme, identAPIHost)
+		}
+		if !withLocalNChain {
+			requireAPIServiceCompatibility("nchain", nchainAPIScheme, nchainAPIHost)
+		}
+		if !withLocalPrivacy {
+			requireAPIServiceCompatibility("privacy", privacyAPIScheme, privacyAPIHost)
+		}
+		if !withLocalVault {
+			requireAPIServiceCompatibility("vault", vaultAPIScheme, vaultAPIHost)
+		}
+	}
+
+	// pull failures are reported once all pulls complete, rather than exiting from the goroutines
+	pullErrs := make([]error, 0)
+	pullErrsMutex := &sync.Mutex{}
+	for _, image := range requiredImages() {
+		img := image
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			canonicalImage := img
+			if dockerRegistry != "" {
+				canonicalImage = fmt.Sprintf("%s/%s", dockerRegistry, img)
+			}
+			err := pullImage(docker, canonicalImage)
+			if err != nil {
+				pullErrsMutex.Lock()
+				pullErrs = append(pullErrs, fmt.Errorf("failed to pull local BPI container image: %s; %s", img, err.Error()))
+				pullErrsMutex.Unlock()
+			}
+		}()
+	}
+
+	configureNetwork(docker)
+	common.RequireOrganizationEndpoints(ctx,
+		func() {
+			applyFlags(ctx)
+
+			wg.Wait()
+			if len(pullErrs) > 0 {
+				for _, err := range pullErrs {
+					log.Printf(err.Error())
+				}
+				common.Exit(1)
+			}
+
+			if withLocalBaselineBuild {
+				useLocalBaselineBuild(docker)
+			}
+
+			// run local deps, optional local containers and the BPI in dependency order
+			err := startServices(docker, startupServices())
+			if err != nil {
+				log.Printf("failed to start local BPI instance %s; %s", name, err.Error())
+				common.Exit(1)
+			}
+
+			log.Printf("%s local BPI instance started", name)
+
+			if !withoutRequireOrganizationKeys {
+				requireOrganizationKeys(ctx)
+			}
+
+			if !withoutRequireSubjectAccount {
+				requireBPISubjectAccount(ctx)
+			}
+		},
+		func(reason *string) {
+			if reason != nil {
+				log.Printf(*reason)
+
+				if !prune {
+					common.StopContainers(docker, name)
+				} else {
+					common.PurgeContainers(docker, name, true)
+					common.PurgeNetwork(docker, name)
+				}
+			}
+		},
+		port,
+		natsPort,
+		natsWebsocketPort,
+	)
+}
+
+// requiredImages returns the container images required by the configured stack, including
+// the images of the stack definition file in place of the defaults they override
+func requiredImages() []string {
 	images := make([]string, 0)
 	images = append(
 		images,
@@ -270,14 +416,12 @@ func runStackStart(cmd *cobra.Command, args []string) {
 			version, err := common.Manifest.GetImageVersion(identContainerImage)
 			if err != nil {
 				log.Printf("failed to resolve version for pinned container image: %s; %s", identContainerImage, err.Error())
-				os.Exit(1)
+				common.Exit(1)
 			}
 			identVersion = *version
 		}
 		identImage := fmt.Sprintf("%s:%s", identContainerImage, identVersion)
 		images = append(images, identImage)
-	} else if common.IsReleaseContext() {
-		// TODO: enforce target API major version level using status endpoint at configured identBaseURL/status
 	}
 
 	if withLocalNChain {
@@ -286,14 +430,12 @@ func runStackStart(cmd *cobra.Command, args []string) {
 			version, err := common.Manifest.GetImageVersion(nchainContainerImage)
 			if err != nil {
 				log.Printf("failed to resolve version for pinned container image: %s; %s", nchainContainerImage, err.Error())
-				os.Exit(1)
+				common.Exit(1)
 			}
 			nchainVersion = *version
 		}
 		nchainImage := fmt.Sprintf("%s:%s", nchainContainerImage, nchainVersion)
 		images = append(images, nchainImage)
-	} else if common.IsReleaseContext() {
-		// TODO: enforce target API major version level using status endpoint at configured nchainBaseURL/status
 	}
 
 	if withLocalPrivacy {
@@ -302,14 +444,12 @@ func runStackStart(cmd *cobra.Command, args []string) {
 			version, err := common.Manifest.GetImageVersion(privacyContainerImage)
 			if err != nil {
 				log.Printf("failed to resolve version for pinned container image: %s; %s", privacyContainerImage, err.Error())
-				os.Exit(1)
+				common.Exit(1)
 			}
 			privacyVersion = *version
 		}
 		privacyImage := fmt.Sprintf("%s:%s", privacyContainerImage, privacyVersion)
 		images = append(images, privacyImage)
-	} else if common.IsReleaseContext() {
-		// TODO: enforce target API major version level using status endpoint at configured privacyBaseURL/status
 	}
 
 	if withLocalVault {
@@ -318,175 +458,29 @@ func runStackStart(cmd *cobra.Command, args []string) {
 			version, err := common.Manifest.GetImageVersion(vaultContainerImage)
 			if err != nil {
 				log.Printf("failed to resolve version for pinned container image: %s; %s", vaultContainerImage, err.Error())
-				os.Exit(1)
+				common.Exit(1)
 			}
 			vaultVersion = *version
 		}
 		vaultImage := fmt.Sprintf("%s:%s", vaultContainerImage, vaultVersion)
 		images = append(images, vaultImage)
-	} else if common.IsReleaseContext() {
-		// TODO: enforce target API major version level using status endpoint at configured vaultBaseURL/status
 	}
 
 	if withLocalIdent || withLocalNChain || withLocalPrivacy || withLocalVault {
 		images = append(images, postgresContainerImage)
 	}
 
-	for _, image := range images {
-		img := image
-		wg.Add(1)
-		go func() {
-			canonicalImage := img
-			if dockerRegistry != "" {
-				canonicalImage = fmt.Sprintf("%s/%s", dockerRegistry, img)
-			}
-			err := pullImage(docker, canonicalImage)
-			if err != nil {
-				log.Printf("failed to pull local BPI container image: %s; %s", img, err.Error())
-				os.Exit(1)
-			}
-			wg.Done()
-		}()
-	}
-
-	configureNetwork(docker)
-	common.RequireOrganizationEndpoints(
-		func() {
-			applyFlags()
-
-			wg.Wait()
-
-			// run local deps
-			wg.Add(1)
-			go runElasticsearch(docker, wg)
-
-			// FIXME-- DRY this up...
-			elasticReachable := false
-			for !elasticReachable {
-				host := fmt.Sprintf("localhost:%v", elasticPort)
-				conn, err := net.DialTimeout("tcp", host, defaultElasticReachabilityTimeout)
-				if err == nil {
-					conn.Close()
-					elasticReachable = true
-				}
-			}
-
-			wg.Add(1)
-			go runNATS(docker, wg)
-
-			// FIXME-- DRY this up...
-			natsReachable := false
-			for !natsReachable {
-				host := fmt.Sprintf("localhost:%v", natsPort)
-				conn, err := net.DialTimeout("tcp", host, defaultNatsReachabilityTimeout)
-				if err == nil {
-					conn.Close()
-					natsReachable = true
-				}
-			}
-
-			wg.Add(1)
-			go runPostgres(docker, wg)
-
-			// FIXME-- DRY this up...
-			postgresReachable := false
-			for !postgresReachable {
-				host := fmt.Sprintf("localhost:%v", postgresPort)
-				conn, err := net.DialTimeout("tcp", host, defaultPostgresReachabilityTimeout)
-				if err == nil {
-					conn.Close()
-					postgresReachable = true
-				}
-			}
-
-			wg.Add(1)
-			go runRedis(docker, wg)
-
-			// FIXME-- DRY this up...
-			redisReachable := false
-			for !redisReachable {
-				host := fmt.Sprintf("localhost:%v", redisPort)
-				conn, err := net.DialTimeout("tcp", host, defaultRedisReachabilityTimeout)
-				if err == nil {
-					conn.Close()
-					redisReachable = true
-				}
-			}
-
-			// run optional local containers
-			if withLocalIdent {
-				wg.Add(1)
-				go runIdentAPI(docker, wg)
-
-				wg.Add(1)
-				go runIdentConsumer(docker, wg)
-			}
-
-			if withLocalNChain {
-				wg.Add(1)
-				go runNChainAPI(docker, wg)
-
-				wg.Add(1)
-				go runNChainConsumer(docker, wg)
-
-				wg.Add(1)
-				go runStatsdaemon(docker, wg)
-
-				wg.Add(1)
-				go runReachabilitydaemon(docker, wg)
-			}
-
-			if withLocalPrivacy {
-				wg.Add(1)
-				go runPrivacyAPI(docker, wg)
-
-				wg.Add(1)
-				go runPrivacyConsumer(docker, wg)
-			}
-
-			if withLocalVault {
-				wg.Add(1)
-				go runVaultAPI(docker, wg)
-			}
-
-			if withLocalBaselineBuild {
-				useLocalBaselineBuild(docker)
-			}
-
-			// run BPI
-			wg.Add(1)
-			go runBaselineAPI(docker, wg)
-
-			wg.Add(1)
-			go runBaselineConsumer(docker, wg)
-
-			wg.Wait()
-			log.Printf("%s local BPI instance started", name)
-
-			if !withoutRequireOrganizationKeys {
-				requireOrganizationKeys()
-			}
-
-			if !withoutRequireSubjectAccount {
-				requireBPISubjectAccount()
-			}
-		},
-		func(reason *string) {
-			if reason != nil {
-				log.Printf(*reason)
+	return stackImages(images)
+}
 
-				if !prune {
-					common.StopContainers(docker, name)
-				} else {
-					common.PurgeContainers(docker, name, true)
-					common.PurgeNetwork(docker, name)
-				}
-			}
-		},
-		port,
-		natsPort,
-		natsWebsocketPort,
-	)
+// requireAPIServiceCompatibility enforces the target API major version level of a hosted
+// service using its status endpoint; the target version is pinned by the release manifest
+func requireAPIServiceCompatibility(svc, scheme, host string) {
+	err := common.RequireAPIServiceCompatibility(svc, scheme, host)
+	if err != nil {
+		log.Printf("failed to enforce target API major version for %s service; %s", svc, err.Error())
+		common.Exit(1)
+	}
 }
 
 func sorConfigFactory() map[string]interface{} {
@@ -562,7 +556,7 @@ func sorConfigFactory() map[string]interface{} {
 	return sor
 }
 
-func requireBPISubjectAccount() error {
+func requireBPISubjectAccount(ctx *common.Context) error {
 	log.Printf("waiting for BPI to become available...")
 	for axiom.Status() != nil {
 		time.Sleep(time.Second * 1)
@@ -571,39 +565,39 @@ func requireBPISubjectAccount() error {
 
 	token, err := ident.CreateToken(organizationRefreshToken, map[string]interface{}{
 		"grant_type":      "refresh_token",
-		"organization_id": common.OrganizationID,
+		"organization_id": ctx.OrganizationID,
 	})
 	if err != nil {
-		log.Printf("failed to authorize access token on behalf of organization %s; %s", common.OrganizationID, err.Error())
-		os.Exit(1)
+		log.Printf("failed to authorize access token on behalf of organization %s; %s", ctx.OrganizationID, err.Error())
+		common.Exit(1)
 	}
 
 	var sacct *axiom.SubjectAccount
-	subjectAccountID := axiom.SubjectAccountIDFactory(common.OrganizationID, common.WorkgroupID)
+	subjectAccountID := axiom.SubjectAccountIDFactory(ctx.OrganizationID, ctx.WorkgroupID)
 
-	sacct, err = axiom.GetSubjectAccountDetails(*token.AccessToken, common.OrganizationID, subjectAccountID, map[string]interface{}{})
+	sacct, err = axiom.GetSubjectAccountDetails(*token.AccessToken, ctx.OrganizationID, subjectAccountID, map[string]interface{}{})
 	if err == nil && sacct != nil && sacct.ID != nil {
 		log.Printf("BPI subject account resolved: %s", *sacct.ID)
 		// TODO-- update if needed...
 		return nil
 	}
 
-	sacct, err = axiom.CreateSubjectAccount(*token.AccessToken, common.OrganizationID, map[string]interface{}{
+	sacct, err = axiom.CreateSubjectAccount(*token.AccessToken, ctx.OrganizationID, map[string]interface{}{
 		"metadata": &axiom.SubjectAccountMetadata{
 			// Counterparties []*Participant `sql:"-" json:"counterparties,omitempty"`
 			NetworkID:           &nchainBaselineNetworkID,
 			OrganizationAddress: &axiomOrganizationAddress,
 			// OrganizationDomain *string `json:"organization_domain,omitempty"`
-			OrganizationID:                &common.OrganizationID,
+			OrganizationID:                &ctx.OrganizationID,
 			OrganizationMessagingEndpoint: &common.MessagingEndpoint,
 			OrganizationProxyEndpoint:     &common.BPIEndpoint,
 			OrganizationRefreshToken:      &organizationRefreshToken,
 			// OrganizationWebsocketEndpoint *string `json:"organization_websocket_endpoint,omitempty"`
 			RegistryContractAddress: &axiomRegistryContractAddress,
 			SOR:                     sorConfigFactory(),
-			WorkgroupID:             &common.WorkgroupID,
+			WorkgroupID:             &ctx.WorkgroupID,
 		},
-		"subject_id": common.OrganizationID,
+		"subject_id": ctx.OrganizationID,
 	})
 
 	if err != nil {
@@ -615,25 +609,25 @@ func requireBPISubjectAccount() error {
 	return nil
 }
 
-func requireOrganizationKeys() {
+func requireOrganizationKeys(ctx *common.Context) {
 	var err error
 
-	_, err = common.RequireOrganizationKeypair("babyJubJub")
+	_, err = common.RequireOrganizationKeypair(ctx, "babyJubJub")
 	if err != nil {
 		log.Printf("WARNING: failed to require organization keypair; %s", err.Error())
 	}
 
-	_, err = common.RequireOrganizationKeypair("secp256k1")
+	_, err = common.RequireOrganizationKeypair(ctx, "secp256k1")
 	if err != nil {
 		log.Printf("WARNING: failed to require organization keypair; %s", err.Error())
 	}
 
-	_, err = common.RequireOrganizationKeypair("BIP39")
+	_, err = common.RequireOrganizationKeypair(ctx, "BIP39")
 	if err != nil {
 		log.Printf("WARNING: failed to require organization keypair; %s", err.Error())
 	}
 
-	_, err = common.RequireOrganizationKeypair("RSA-4096")
+	_, err = common.RequireOrganizationKeypair(ctx, "RSA-4096")
 	if err != nil {
 		log.Printf("WARNING: failed to require organization keypair; %s", err.Error())
 	}
@@ -667,7 +661,7 @@ func configureNetwork(docker *client.Client) {
 
 	if err != nil {
 		log.Printf("failed to setup docker network; %s", err.Error())
-		os.Exit(1)
+		common.Exit(1)
 	}
 
 	dockerNetworkID = network.ID
@@ -675,32 +669,32 @@ func configureNetwork(docker *client.Client) {
 }
 
 func normalizeHostnames() {
-	apiHostname = strings.Replace(apiHostname, defaultBPIStackName, name, -1)
-	consumerHostname = strings.Replace(consumerHostname, defaultBPIStackName, name, -1)
-	elasticHostname = strings.Replace(elasticHostname, defaultBPIStackName, name, -1)
-	identHostname = strings.Replace(identHostname, defaultBPIStackName, name, -1)
-	identConsumerHostname = strings.Replace(identConsumerHostname, defaultBPIStackName, name, -1)
-	natsHostname = strings.Replace(natsHostname, defaultBPIStackName, name, -1)
-	nchainHostname = strings.Replace(nchainHostname, defaultBPIStackName, name, -1)
-	nchainConsumerHostname = strings.Replace(nchainConsumerHostname, defaultBPIStackName, name, -1)
-	postgresHostname = strings.Replace(postgresHostname, defaultBPIStackName, name, -1)
-	privacyHostname = strings.Replace(privacyHostname, defaultBPIStackName, name, -1)
-	privacyConsumerHostname = strings.Replace(privacyConsumerHostname, defaultBPIStackName, name, -1)
-	redisHostname = strings.Replace(redisHostname, defaultBPIStackName, name, -1)
-	vaultHostname = strings.Replace(vaultHostname, defaultBPIStackName, name, -1)
+	apiHostname = strings.Replace(apiHostname, DefaultName, name, -1)
+	consumerHostname = strings.Replace(consumerHostname, DefaultName, name, -1)
+	elasticHostname = strings.Replace(elasticHostname, DefaultName, name, -1)
+	identHostname = strings.Replace(identHostname, DefaultName, name, -1)
+	identConsumerHostname = strings.Replace(identConsumerHostname, DefaultName, name, -1)
+	natsHostname = strings.Replace(natsHostname, DefaultName, name, -1)
+	nchainHostname = strings.Replace(nchainHostname, DefaultName, name, -1)
+	nchainConsumerHostname = strings.Replace(nchainConsumerHostname, DefaultName, name, -1)
+	postgresHostname = strings.Replace(postgresHostname, DefaultName, name, -1)
+	privacyHostname = strings.Replace(privacyHostname, DefaultName, name, -1)
+	privacyConsumerHostname = strings.Replace(privacyConsumerHostname, DefaultName, name, -1)
+	redisHostname = strings.Replace(redisHostname, DefaultName, name, -1)
+	vaultHostname = strings.Replace(vaultHostname, DefaultName, name, -1)
 }
 
-func authorizeContext() {
+func authorizeContext(ctx *common.Context) {
 	if !withoutRequireWorkgroup {
 		// log.Printf("authorizing workgroup context")
-		authorizeWorkgroupContext()
+		authorizeWorkgroupContext(ctx)
 	}
 
 	// log.Printf("authorizing organization context")
-	common.AuthorizeOrganizationContext(false)
+	common.AuthorizeOrganizationContext(ctx, false)
 
 	if organizationRefreshToken == "" {
-		refreshTokenKey := common.BuildConfigKeyWithID(common.RefreshTokenConfigKey, common.OrganizationID)
+		refreshTokenKey := common.BuildConfigKeyWithID(common.RefreshTokenConfigKey, ctx.OrganizationID)
 		if viper.IsSet(refreshTokenKey) {
 			// log.Printf("using cached API refresh token for organization: %s\n", common.OrganizationID)
 			organizationRefreshToken = viper.GetString(refreshTokenKey)
@@ -708,15 +702,15 @@ func authorizeContext() {
 				vaultRefreshToken = organizationRefreshToken
 			}
 		} else {
-			organizationAuthPrompt()
-			if common.OrganizationRefreshToken != "" {
-				organizationRefreshToken = common.OrganizationRefreshToken
+			organizationAuthPrompt(ctx)
+			if ctx.OrganizationRefreshToken != "" {
+				organizationRefreshToken = ctx.OrganizationRefreshToken
 				if vaultRefreshToken == "" {
 					vaultRefreshToken = organizationRefreshToken
 				}
 			} else {
-				log.Printf("failed to resolve refresh token for organization: %s\n", common.OrganizationID)
-				os.Exit(1)
+				log.Printf("failed to resolve refresh token for organization: %s\n", ctx.OrganizationID)
+				common.Exit(1)
 			}
 		}
 	} else if organizationRefreshToken != "" && vaultRefreshToken == "" {
@@ -724,27 +718,27 @@ func authorizeContext() {
 	}
 }
 
-func authorizeWorkgroupContext() {
-	if common.WorkgroupID == "" {
-		err := common.RequireWorkgroup()
+func authorizeWorkgroupContext(ctx *common.Context) {
+	if ctx.WorkgroupID == "" {
+		err := common.RequireWorkgroup(ctx)
 		if err != nil {
 			log.Printf("failed to require workgroup; %s", err.Error())
-			os.Exit(1)
+			common.Exit(1)
 		}
 	}
 
 	var contracts []*nchain.Contract
 
-	token, err := common.ResolveOrganizationToken()
+	token, err := common.ResolveOrganizationToken(ctx)
 	if err != nil {
-		log.Printf("failed to resolve workgroup: %s; %s", common.WorkgroupID, err.Error())
-		os.Exit(1)
+		log.Printf("failed to resolve workgroup: %s; %s", ctx.WorkgroupID, err.Error())
+		common.Exit(1)
 	}
 
-	workgroup, err := ident.GetApplicationDetails(*token.AccessToken, common.WorkgroupID, map[string]interface{}{})
+	workgroup, err := ident.GetApplicationDetails(*token.AccessToken, ctx.WorkgroupID, map[string]interface{}{})
 	if err != nil {
-		log.Printf("failed to resolve workgroup: %s; %s", common.WorkgroupID, err.Error())
-		os.Exit(1)
+		log.Printf("failed to resolve workgroup: %s; %s", ctx.WorkgroupID, err.Error())
+		common.Exit(1)
 	}
 
 	contracts, err = nchain.ListContracts(*token.AccessToken, map[string]interface{}{
@@ -752,17 +746,17 @@ func authorizeWorkgroupContext() {
 	})
 	if err != nil {
 		log.Printf("failed to resolve global organization registry contract; %s", err.Error())
-		os.Exit(1)
+		common.Exit(1)
 	} else if len(contracts) == 0 {
-		common.AuthorizeOrganizationContext(true)
+		common.AuthorizeOrganizationContext(ctx, true)
 
 		token, err := ident.CreateToken(*token.AccessToken, map[string]interface{}{
 			"scope":           "offline_access",
-			"organization_id": common.OrganizationID,
+			"organization_id": ctx.OrganizationID,
 		})
 		if err != nil {
-			log.Printf("failed to authorize API access token on behalf of workgroup %s; %s", common.WorkgroupID, err.Error())
-			os.Exit(1)
+			log.Printf("failed to authorize API access token on behalf of workgroup %s; %s", ctx.WorkgroupID, err.Error())
+			common.Exit(1)
 		}
 
 		contracts, err = nchain.ListContracts(*token.AccessToken, map[string]interface{}{
@@ -770,10 +764,10 @@ func authorizeWorkgroupContext() {
 		})
 		if err != nil {
 			log.Printf("failed to resolve global organization registry contract; %s", err.Error())
-			os.Exit(1)
+			common.Exit(1)
 		} else if len(contracts) == 0 {
 			log.Printf("failed to resolve global organization registry contract")
-			os.Exit(1)
+			common.Exit(1)
 		}
 	}
 
@@ -781,27 +775,27 @@ func authorizeWorkgroupContext() {
 		if workgroup.NetworkID != uuid.Nil {
 			nchainBaselineNetworkID = workgroup.NetworkID.String()
 		} else {
-			err := common.RequireL1Network()
+			err := common.RequireL1Network(ctx)
 			if err != nil {
 				log.Printf("failed to require network id; %s", err.Error())
-				os.Exit(1)
+				common.Exit(1)
 			}
-			nchainBaselineNetworkID = common.NetworkID
+			nchainBaselineNetworkID = ctx.NetworkID
 		}
 	}
 
 	orgRegistryContract := contracts[0]
 	if orgRegistryContract.Address == nil || *orgRegistryContract.Address == "0x" {
 		log.Printf("failed to resolve global organization registry contract; %s", err.Error())
-		os.Exit(1)
+		common.Exit(1)
 	}
 	axiomRegistryContractAddress = *contracts[0].Address
 }
 
-func applyFlags() {
-	if (axiomOrganizationAddress == "" || axiomOrganizationAddress == "0x") && common.ResolvedBaselineOrgAddress != "" {
+func applyFlags(ctx *common.Context) {
+	if (axiomOrganizationAddress == "" || axiomOrganizationAddress == "0x") && ctx.ResolvedBaselineOrgAddress != "" {
 		// FIXME-- this belongs somewhere better
-		axiomOrganizationAddress = common.ResolvedBaselineOrgAddress
+		axiomOrganizationAddress = ctx.ResolvedBaselineOrgAddress
 	}
 
 	// HACK
@@ -877,7 +871,7 @@ func applyFlags() {
 
 	// HACK
 	if jwtSignerPublicKey == "" {
-		keys, err := vault.ListKeys(common.OrganizationAccessToken, common.VaultID, map[string]interface{}{
+		keys, err := vault.ListKeys(ctx.OrganizationAccessToken, ctx.VaultID, map[string]interface{}{
 			"spec": "RSA-4096",
 		})
 		if err != nil {
@@ -946,296 +940,257 @@ func containerEnvironmentFactory(listenPort *int) []string {
 	return env
 }
 
-func runBaselineAPI(docker *client.Client, wg *sync.WaitGroup) {
+// baselineAPIContainer returns the spec of the local BPI API container
+func baselineAPIContainer() *containerSpec {
 	image := axiomContainerImage
 	if withLocalBaselineBuild {
 		image = localBaselineContainerImage
 	}
 
-	err := runContainer(
-		docker,
-		fmt.Sprintf("%s-api", strings.ReplaceAll(name, " ", "")),
-		apiHostname,
-		image,
-		&[]string{"./ops/run_api.sh"},
-		nil,
-		&[]string{"CMD", "curl", "-f", fmt.Sprintf("http://%s:%d/status", apiHostname, containerPort)},
-		nil,
-		map[string]string{},
-		[]portMapping{{
+	return &containerSpec{
+		name:        fmt.Sprintf("%s-api", strings.ReplaceAll(name, " ", "")),
+		hostname:    apiHostname,
+		image:       image,
+		entrypoint:  &[]string{"./ops/run_api.sh"},
+		healthcheck: &[]string{"CMD", "curl", "-f", fmt.Sprintf("http://%s:%d/status", apiHostname, containerPort)},
+		ports: []portMapping{{
 			hostPort:      port,
 			containerPort: containerPort,
-		}}...,
-	)
+		}},
+	}
+}
 
+func runBaselineAPI(docker *client.Client) error {
+	err := runContainer(docker, baselineAPIContainer())
 	if err != nil {
-		log.Printf("failed to create local BPI container; %s", err.Error())
-		os.Exit(1)
+		return fmt.Errorf("failed to create local BPI container; %s", err.Error())
 	}
 
 	os.Setenv("AXIOM_API_HOST", fmt.Sprintf("localhost:%d", port))
 	os.Setenv("AXIOM_API_SCHEME", "http")
 
-	if wg != nil {
-		wg.Done()
-	}
+	return nil
 }
 
-func runBaselineConsumer(docker *client.Client, wg *sync.WaitGroup) {
+// baselineConsumerContainer returns the spec of the local BPI consumer container
+func baselineConsumerContainer() *containerSpec {
 	image := axiomContainerImage
 	if withLocalBaselineBuild {
 		image = localBaselineContainerImage
 	}
 
-	err := runContainer(
-		docker,
-		fmt.Sprintf("%s-consumer", strings.ReplaceAll(name, " ", "")),
-		consumerHostname,
-		image,
-		&[]string{"./ops/run_consumer.sh"},
-		nil,
-		&[]string{"CMD", "curl", "-f", fmt.Sprintf("http://%s:%d/status", apiHostname, port)},
-		nil,
-		map[string]string{},
-		[]portMapping{}...,
-	)
+	return &containerSpec{
+		name:        fmt.Sprintf("%s-consumer", strings.ReplaceAll(name, " ", "")),
+		hostname:    consumerHostname,
+		image:       image,
+		entrypoint:  &[]string{"./ops/run_consumer.sh"},
+		healthcheck: &[]string{"CMD", "curl", "-f", fmt.Sprintf("http://%s:%d/status", apiHostname, port)},
+	}
+}
 
+func runBaselineConsumer(docker *client.Client) error {
+	err := runContainer(docker, baselineConsumerContainer())
 	if err != nil {
-		log.Printf("failed to create local BPI consumer container; %s", err.Error())
-		os.Exit(1)
+		return fmt.Errorf("failed to create local BPI consumer container; %s", err.Error())
 	}
 
-	if wg != nil {
-		wg.Done()
-	}
+	return nil
 }
 
-func runIdentAPI(docker *client.Client, wg *sync.WaitGroup) {
-	err := runContainer(
-		docker,
-		fmt.Sprintf("%s-ident-api", strings.ReplaceAll(name, " ", "")),
-		identHostname,
-		identContainerImage,
-		&[]string{"./ops/run_api.sh"},
-		nil,
-		&[]string{"CMD", "curl", "-f", fmt.Sprintf("http://%s:%d/status", identHostname, identContainerPort)},
-		nil,
-		map[string]string{},
-		[]portMapping{{
+// identAPIContainer returns the spec of the local ident API container
+func identAPIContainer() *containerSpec {
+	return &containerSpec{
+		name:        fmt.Sprintf("%s-ident-api", strings.ReplaceAll(name, " ", "")),
+		hostname:    identHostname,
+		image:       identContainerImage,
+		entrypoint:  &[]string{"./ops/run_api.sh"},
+		healthcheck: &[]string{"CMD", "curl", "-f", fmt.Sprintf("http://%s:%d/status", identHostname, identContainerPort)},
+		ports: []portMapping{{
 			hostPort:      identPort,
 			containerPort: identContainerPort,
-		}}...,
-	)
+		}},
+	}
+}
 
+func runIdentAPI(docker *client.Client) error {
+	err := runContainer(docker, identAPIContainer())
 	if err != nil {
-		log.Printf("failed to create local ident API container; %s", err.Error())
-		os.Exit(1)
+		return fmt.Errorf("failed to create local ident API container; %s", err.Error())
 	}
 
-	if wg != nil {
-		wg.Done()
-	}
+	return nil
 }
 
-func runIdentConsumer(docker *client.Client, wg *sync.WaitGroup) {
-	err := runContainer(
-		docker,
-		fmt.Sprintf("%s-ident-consumer", strings.ReplaceAll(name, " ", "")),
-		identConsumerHostname,
-		identContainerImage,
-		&[]string{"./ops/run_consumer.sh"},
-		nil,
-		&[]string{"CMD", "curl", "-f", fmt.Sprintf("http://%s:%d/status", identHostname, identContainerPort)},
-		nil,
-		map[string]string{},
-		[]portMapping{}...,
-	)
+// identConsumerContainer returns the spec of the local ident consumer container
+func identConsumerContainer() *containerSpec {
+	return &containerSpec{
+		name:        fmt.Sprintf("%s-ident-consumer", strings.ReplaceAll(name, " ", "")),
+		hostname:    identConsumerHostname,
+		image:       identContainerImage,
+		entrypoint:  &[]string{"./ops/run_consumer.sh"},
+		healthcheck: &[]string{"CMD", "curl", "-f", fmt.Sprintf("http://%s:%d/status", identHostname, identContainerPort)},
+	}
+}
 
+func runIdentConsumer(docker *client.Client) error {
+	err := runContainer(docker, identConsumerContainer())
 	if err != nil {
-		log.Printf("failed to create local ident consumer container; %s", err.Error())
-		os.Exit(1)
+		return fmt.Errorf("failed to create local ident consumer container; %s", err.Error())
 	}
 
-	if wg != nil {
-		wg.Done()
-	}
+	return nil
 }
 
-func runNChainAPI(docker *client.Client, wg *sync.WaitGroup) {
-	err := runContainer(
-		docker,
-		fmt.Sprintf("%s-nchain-api", strings.ReplaceAll(name, " ", "")),
-		nchainHostname,
-		nchainContainerImage,
-		&[]string{"./ops/run_api.sh"},
-		nil,
-		&[]string{"CMD", "curl", "-f", fmt.Sprintf("http://%s:%d/status", nchainHostname, nchainContainerPort)},
-		nil,
-		map[string]string{},
-		[]portMapping{{
+// nchainAPIContainer returns the spec of the local nchain API container
+func nchainAPIContainer() *containerSpec {
+	return &containerSpec{
+		name:        fmt.Sprintf("%s-nchain-api", strings.ReplaceAll(name, " ", "")),
+		hostname:    nchainHostname,
+		image:       nchainContainerImage,
+		entrypoint:  &[]string{"./ops/run_api.sh"},
+		healthcheck: &[]string{"CMD", "curl", "-f", fmt.Sprintf("http://%s:%d/status", nchainHostname, nchainContainerPort)},
+		ports: []portMapping{{
 			hostPort:      nchainPort,
 			containerPort: nchainContainerPort,
-		}}...,
-	)
+		}},
+	}
+}
 
+func runNChainAPI(docker *client.Client) error {
+	err := runContainer(docker, nchainAPIContainer())
 	if err != nil {
-		log.Printf("failed to create local nchain API container; %s", err.Error())
-		os.Exit(1)
+		return fmt.Errorf("failed to create local nchain API container; %s", err.Error())
 	}
 
-	if wg != nil {
-		wg.Done()
-	}
+	return nil
 }
 
-func runNChainConsumer(docker *client.Client, wg *sync.WaitGroup) {
-	err := runContainer(
-		docker,
-		fmt.Sprintf("%s-nchain-consumer", strings.ReplaceAll(name, " ", "")),
-		nchainConsumerHostname,
-		nchainContainerImage,
-		&[]string{"./ops/run_consumer.sh"},
-		nil,
-		&[]string{"CMD", "curl", "-f", fmt.Sprintf("http://%s:%d/status", nchainHostname, nchainContainerPort)},
-		nil,
-		map[string]string{},
-		[]portMapping{}...,
-	)
+// nchainConsumerContainer returns the spec of the local nchain consumer container
+func nchainConsumerContainer() *containerSpec {
+	return &containerSpec{
+		name:        fmt.Sprintf("%s-nchain-consumer", strings.ReplaceAll(name, " ", "")),
+		hostname:    nchainConsumerHostname,
+		image:       nchainContainerImage,
+		entrypoint:  &[]string{"./ops/run_consumer.sh"},
+		healthcheck: &[]string{"CMD", "curl", "-f", fmt.Sprintf("http://%s:%d/status", nchainHostname, nchainContainerPort)},
+	}
+}
 
+func runNChainConsumer(docker *client.Client) error {
+	err := runContainer(docker, nchainConsumerContainer())
 	if err != nil {
-		log.Printf("failed to create local nchain consumer container; %s", err.Error())
-		os.Exit(1)
+		return fmt.Errorf("failed to create local nchain consumer container; %s", err.Error())
 	}
 
-	if wg != nil {
-		wg.Done()
-	}
+	return nil
 }
 
-func runStatsdaemon(docker *client.Client, wg *sync.WaitGroup) {
-	err := runContainer(
-		docker,
-		fmt.Sprintf("%s-statsdaemon", strings.ReplaceAll(name, " ", "")),
-		nchainStatsdaemonHostname,
-		nchainContainerImage,
-		&[]string{"./ops/run_statsdaemon.sh"},
-		nil,
-		&[]string{"CMD", "curl", "-f", fmt.Sprintf("http://%s:%d/status", nchainHostname, nchainContainerPort)},
-		nil,
-		map[string]string{},
-		[]portMapping{}...,
-	)
+// statsdaemonContainer returns the spec of the local nchain statsdaemon container
+func statsdaemonContainer() *containerSpec {
+	return &containerSpec{
+		name:        fmt.Sprintf("%s-statsdaemon", strings.ReplaceAll(name, " ", "")),
+		hostname:    nchainStatsdaemonHostname,
+		image:       nchainContainerImage,
+		entrypoint:  &[]string{"./ops/run_statsdaemon.sh"},
+		healthcheck: &[]string{"CMD", "curl", "-f", fmt.Sprintf("http://%s:%d/status", nchainHostname, nchainContainerPort)},
+	}
+}
 
+func runStatsdaemon(docker *client.Client) error {
+	err := runContainer(docker, statsdaemonContainer())
 	if err != nil {
-		log.Printf("failed to create local statsdaemon container; %s", err.Error())
-		os.Exit(1)
+		return fmt.Errorf("failed to create local statsdaemon container; %s", err.Error())
 	}
 
-	if wg != nil {
-		wg.Done()
-	}
+	return nil
 }
 
-func runReachabilitydaemon(docker *client.Client, wg *sync.WaitGroup) {
-	err := runContainer(
-		docker,
-		fmt.Sprintf("%s-reachabilitydaemon", strings.ReplaceAll(name, " ", "")),
-		nchainReachabilitydaemonHostname,
-		nchainContainerImage,
-		&[]string{"./ops/run_reachabilitydaemon.sh"},
-		nil,
-		&[]string{"CMD", "curl", "-f", fmt.Sprintf("http://%s:%d/status", nchainHostname, nchainContainerPort)},
-		nil,
-		map[string]string{},
-		[]portMapping{}...,
-	)
+// reachabilitydaemonContainer returns the spec of the local nchain reachabilitydaemon container
+func reachabilitydaemonContainer() *containerSpec {
+	return &containerSpec{
+		name:        fmt.Sprintf("%s-reachabilitydaemon", strings.ReplaceAll(name, " ", "")),
+		hostname:    nchainReachabilitydaemonHostname,
+		image:       nchainContainerImage,
+		entrypoint:  &[]string{"./ops/run_reachabilitydaemon.sh"},
+		healthcheck: &[]string{"CMD", "curl", "-f", fmt.Sprintf("http://%s:%d/status", nchainHostname, nchainContainerPort)},
+	}
+}
 
+func runReachabilitydaemon(docker *client.Client) error {
+	err := runContainer(docker, reachabilitydaemonContainer())
 	if err != nil {
-		log.Printf("failed to create local reachabilitydaemon container; %s", err.Error())
-		os.Exit(1)
+		return fmt.Errorf("failed to create local reachabilitydaemon container; %s", err.Error())
 	}
 
-	if wg != nil {
-		wg.Done()
-	}
+	return nil
 }
 
-func runPrivacyAPI(docker *client.Client, wg *sync.WaitGroup) {
-	err := runContainer(
-		docker,
-		fmt.Sprintf("%s-privacy-api", strings.ReplaceAll(name, " ", "")),
-		privacyHostname,
-		privacyContainerImage,
-		&[]string{"./ops/run_api.sh"},
-		nil,
-		&[]string{"CMD", "curl", "-f", fmt.Sprintf("http://%s:%d/status", privacyHostname, privacyContainerPort)},
-		nil,
-		map[string]string{},
-		[]portMapping{{
+// privacyAPIContainer returns the spec of the local privacy API container
+func privacyAPIContainer() *containerSpec {
+	return &containerSpec{
+		name:        fmt.Sprintf("%s-privacy-api", strings.ReplaceAll(name, " ", "")),
+		hostname:    privacyHostname,
+		image:       privacyContainerImage,
+		entrypoint:  &[]string{"./ops/run_api.sh"},
+		healthcheck: &[]string{"CMD", "curl", "-f", fmt.Sprintf("http://%s:%d/status", privacyHostname, privacyContainerPort)},
+		ports: []portMapping{{
 			hostPort:      privacyPort,
 			containerPort: privacyContainerPort,
-		}}...,
-	)
+		}},
+	}
+}
 
+func runPrivacyAPI(docker *client.Client) error {
+	err := runContainer(docker, privacyAPIContainer())
 	if err != nil {
-		log.Printf("failed to create local privacy API container; %s", err.Error())
-		os.Exit(1)
+		return fmt.Errorf("failed to create local privacy API container; %s", err.Error())
 	}
 
-	if wg != nil {
-		wg.Done()
-	}
+	return nil
 }
 
-func runPrivacyConsumer(docker *client.Client, wg *sync.WaitGroup) {
-	err := runContainer(
-		docker,
-		fmt.Sprintf("%s-privacy-consumer", strings.ReplaceAll(name, " ", "")),
-		privacyConsumerHostname,
-		privacyContainerImage,
-		&[]string{"./ops/run_consumer.sh"},
-		nil,
-		&[]string{"CMD", "curl", "-f", fmt.Sprintf("http://%s:%d/status", privacyHostname, privacyContainerPort)},
-		nil,
-		map[string]string{},
-		[]portMapping{}...,
-	)
+// privacyConsumerContainer returns the spec of the local privacy consumer container
+func privacyConsumerContainer() *containerSpec {
+	return &containerSpec{
+		name:        fmt.Sprintf("%s-privacy-consumer", strings.ReplaceAll(name, " ", "")),
+		hostname:    privacyConsumerHostname,
+		image:       privacyContainerImage,
+		entrypoint:  &[]string{"./ops/run_consumer.sh"},
+		healthcheck: &[]string{"CMD", "curl", "-f", fmt.Sprintf("http://%s:%d/status", privacyHostname, privacyContainerPort)},
+	}
+}
 
+func runPrivacyConsumer(docker *client.Client) error {
+	err := runContainer(docker, privacyConsumerContainer())
 	if err != nil {
-		log.Printf("failed to create local privacy consumer container; %s", err.Error())
-		os.Exit(1)
+		return fmt.Errorf("failed to create local privacy consumer container; %s", err.Error())
 	}
 
-	if wg != nil {
-		wg.Done()
-	}
+	return nil
 }
 
-func runVaultAPI(docker *client.Client, wg *sync.WaitGroup) {
-	err := runContainer(
-		docker,
-		fmt.Sprintf("%s-vault-api", strings.ReplaceAll(name, " ", "")),
-		vaultHostname,
-		vaultContainerImage,
-		&[]string{"./ops/run_api.sh"},
-		nil,
-		&[]string{"CMD", "curl", "-f", fmt.Sprintf("http://%s:%d/status", vaultHostname, vaultContainerPort)},
-		nil,
-		map[string]string{},
-		[]portMapping{{
+// vaultAPIContainer returns the spec of the local vault API container
+func vaultAPIContainer() *containerSpec {
+	return &containerSpec{
+		name:        fmt.Sprintf("%s-vault-api", strings.ReplaceAll(name, " ", "")),
+		hostname:    vaultHostname,
+		image:       vaultContainerImage,
+		entrypoint:  &[]string{"./ops/run_api.sh"},
+		healthcheck: &[]string{"CMD", "curl", "-f", fmt.Sprintf("http://%s:%d/status", vaultHostname, vaultContainerPort)},
+		ports: []portMapping{{
 			hostPort:      vaultPort,
 			containerPort: vaultContainerPort,
-		}}...,
-	)
+		}},
+	}
+}
 
+func runVaultAPI(docker *client.Client) error {
+	err := runContainer(docker, vaultAPIContainer())
 	if err != nil {
-		log.Printf("failed to create local vault API container; %s", err.Error())
-		os.Exit(1)
+		return fmt.Errorf("failed to create local vault API container; %s", err.Error())
 	}
 
-	if wg != nil {
-		wg.Done()
-	}
+	return nil
 }
 
 func writeNATSConfig() *string {
@@ -1248,7 +1203,7 @@ func writeNATSConfig() *string {
 	err := ioutil.WriteFile(pathstr, cfg, 0644)
 	if err != nil {
 		log.Printf("failed to write local nats-server.conf; %s", err.Error())
-		os.Exit(1)
+		common.Exit(1)
 	}
 
 	if pathstr == "" {
@@ -1257,7 +1212,8 @@ func writeNATSConfig() *string {
 	return &pathstr
 }
 
-func runElasticsearch(docker *client.Client, wg *sync.WaitGroup) {
+// elasticsearchContainer returns the spec of the local elasticsearch container
+func elasticsearchContainer() *containerSpec {
 	// cfgPath := writeElasticsearchConfig()
 	mountPoints := map[string]string{}
 
@@ -1269,6 +1225,7 @@ func runElasticsearch(docker *client.Client, wg *sync.WaitGroup) {
 		fmt.Sprintf("ELASTIC_PASSWORD=%s", elasticPassword),
 		"discovery.type=single-node",
 		"bootstrap.memory_lock=true",
+		fmt.Sprintf("path.repo=%s", elasticsearchSnapshotDir),
 		// "-p", fmt.Sprintf("%d", elasticContainerPort),
 		// "--ulimit", "nofile=65535:65535",
 		// "http.host=0.0.0.0",
@@ -1281,40 +1238,39 @@ func runElasticsearch(docker *client.Client, wg *sync.WaitGroup) {
 		_elasticMemory, err := strconv.ParseUint(strings.Replace(elasticMemory, "GB", "", -1), 10, 64)
 		if err != nil {
 			log.Printf("failed to parse provided elasticsearch memory allocation; %s", err.Error())
-			os.Exit(1)
+			common.Exit(1)
 		}
 		env = append(env, fmt.Sprintf("ES_JAVA_OPTS=-Xms%dm -Xmx%dm", _elasticMemory*1024, _elasticMemory*1024))
 	}
 
-	err := runContainer(
-		docker,
-		fmt.Sprintf("%s-elasticsearch", strings.ReplaceAll(name, " ", "")),
-		elasticHostname,
-		elasticContainerImage,
-		nil,
-		nil,
-		&[]string{"CMD", "nc", "-zv", "localhost", fmt.Sprintf("%d", elasticPort)},
-		&env,
-		mountPoints,
-		[]portMapping{
+	return &containerSpec{
+		name:        fmt.Sprintf("%s-elasticsearch", strings.ReplaceAll(name, " ", "")),
+		hostname:    elasticHostname,
+		image:       elasticContainerImage,
+		healthcheck: &[]string{"CMD", "nc", "-zv", "localhost", fmt.Sprintf("%d", elasticPort)},
+		env:         &env,
+		mounts:      mountPoints,
+		volumes:     stackVolumes("elasticsearch"),
+		ports: []portMapping{
 			{
 				hostPort:      elasticPort,
 				containerPort: elasticContainerPort,
 			},
-		}...,
-	)
+		},
+	}
+}
 
+func runElasticsearch(docker *client.Client) error {
+	err := runContainer(docker, elasticsearchContainer())
 	if err != nil {
-		log.Printf("failed to create local BPI elasticsearch container; %s", err.Error())
-		os.Exit(1)
+		return fmt.Errorf("failed to create local BPI elasticsearch container; %s", err.Error())
 	}
 
-	if wg != nil {
-		wg.Done()
-	}
+	return nil
 }
 
-func runNATS(docker *client.Client, wg *sync.WaitGroup) {
+// natsContainer returns the spec of the local NATS container
+func natsContainer() *containerSpec {
 	cfgPath := writeNATSConfig()
 	mountPoints := map[string]string{}
 
@@ -1326,24 +1282,24 @@ func runNATS(docker *client.Client, wg *sync.WaitGroup) {
 		fmt.Sprintf("JWT_SIGNER_PUBLIC_KEY=%s", strings.ReplaceAll(jwtSignerPublicKey, "\\n", "\n")),
 	}
 
-	err := runContainer(
-		docker,
-		fmt.Sprintf("%s-nats", strings.ReplaceAll(name, " ", "")),
-		natsHostname,
-		natsContainerImage,
-		nil,
-		&[]string{
+	return &containerSpec{
+		name:     fmt.Sprintf("%s-nats", strings.ReplaceAll(name, " ", "")),
+		hostname: natsHostname,
+		image:    natsContainerImage,
+		cmd: &[]string{
 			"--js",
 			"--server_name", natsServerName,
 			"--auth", natsAuthToken,
 			"--config", "/etc/nats-server.conf",
 			"--port", fmt.Sprintf("%d", natsContainerPort),
+			"--store_dir", natsJetStreamStoreDir,
 			"-DVV",
 		},
-		&[]string{"CMD", "nc", "-zv", "localhost", fmt.Sprintf("%d", natsContainerPort)},
-		&env,
-		mountPoints,
-		[]portMapping{
+		healthcheck: &[]string{"CMD", "nc", "-zv", "localhost", fmt.Sprintf("%d", natsContainerPort)},
+		env:         &env,
+		mounts:      mountPoints,
+		volumes:     stackVolumes("nats"),
+		ports: []portMapping{
 			{
 				hostPort:      natsPort,
 				containerPort: natsContainerPort,
@@ -1352,76 +1308,70 @@ func runNATS(docker *client.Client, wg *sync.WaitGroup) {
 				hostPort:      natsWebsocketPort,
 				containerPort: natsWebsocketContainerPort,
 			},
-		}...,
-	)
+		},
+	}
+}
 
+func runNATS(docker *client.Client) error {
+	err := runContainer(docker, natsContainer())
 	if err != nil {
-		log.Printf("failed to create local BPI NATS container; %s", err.Error())
-		os.Exit(1)
+		return fmt.Errorf("failed to create local BPI NATS container; %s", err.Error())
 	}
 
-	if wg != nil {
-		wg.Done()
-	}
+	return nil
 }
 
-func runPostgres(docker *client.Client, wg *sync.WaitGroup) {
-	err := runContainer(
-		docker,
-		fmt.Sprintf("%s-postgres", strings.ReplaceAll(name, " ", "")),
-		postgresHostname,
-		postgresContainerImage,
-		nil,
-		nil,
-		&[]string{"CMD", "pg_isready", "-U", "prvd", "-d", "prvd"},
-		&[]string{
+// postgresContainer returns the spec of the local postgres container
+func postgresContainer() *containerSpec {
+	return &containerSpec{
+		name:        fmt.Sprintf("%s-postgres", strings.ReplaceAll(name, " ", "")),
+		hostname:    postgresHostname,
+		image:       postgresContainerImage,
+		healthcheck: &[]string{"CMD", "pg_isready", "-U", "prvd", "-d", "prvd"},
+		env: &[]string{
 			// FIXME -- allow user to set these....
 			"POSTGRES_DB=prvd",
 			"POSTGRES_USER=prvd",
 			"POSTGRES_PASSWORD=prvdp455",
 		},
-		map[string]string{},
-		[]portMapping{{
+		volumes: stackVolumes("postgres"),
+		ports: []portMapping{{
 			hostPort:      postgresPort,
 			containerPort: postgresContainerPort,
-		}}...,
-	)
+		}},
+	}
+}
 
+func runPostgres(docker *client.Client) error {
+	err := runContainer(docker, postgresContainer())
 	if err != nil {
-		log.Printf("failed to create local postgres container; %s", err.Error())
-		os.Exit(1)
+		return fmt.Errorf("failed to create local postgres container; %s", err.Error())
 	}
 
-	if wg != nil {
-		wg.Done()
-	}
+	return nil
 }
 
-func runRedis(docker *client.Client, wg *sync.WaitGroup) {
-	err := runContainer(
-		docker,
-		fmt.Sprintf("%s-redis", strings.ReplaceAll(name, " ", "")),
-		redisHostname,
-		redisContainerImage,
-		nil,
-		nil,
-		&[]string{"CMD", "redis-cli", "ping"},
-		nil,
-		map[string]string{},
-		[]portMapping{{
+// redisContainer returns the spec of the local redis container
+func redisContainer() *containerSpec {
+	return &containerSpec{
+		name:        fmt.Sprintf("%s-redis", strings.ReplaceAll(name, " ", "")),
+		hostname:    redisHostname,
+		image:       redisContainerImage,
+		healthcheck: &[]string{"CMD", "redis-cli", "ping"},
+		ports: []portMapping{{
 			hostPort:      redisPort,
 			containerPort: redisContainerPort,
-		}}...,
-	)
+		}},
+	}
+}
 
+func runRedis(docker *client.Client) error {
+	err := runContainer(docker, redisContainer())
 	if err != nil {
-		log.Printf("failed to create local BPI redis container; %s", err.Error())
-		os.Exit(1)
+		return fmt.Errorf("failed to create local BPI redis container; %s", err.Error())
 	}
 
-	if wg != nil {
-		wg.Done()
-	}
+	return nil
 }
 
 func pullImage(docker *client.Client, image string) error {
@@ -1443,17 +1393,12 @@ func pullImage(docker *client.Client, image string) error {
 	return nil
 }
 
-func runContainer(
-	docker *client.Client,
-	name, hostname, image string,
-	entrypoint, cmd, healthcheck, env *[]string,
-	mounts map[string]string,
-	ports ...portMapping,
-) error {
+func runContainer(docker *client.Client, spec *containerSpec) error {
+	image := spec.resolvedImage()
 	log.Printf("running local BPI container image: %s", image)
 
 	isReachable := func(host string, port int) bool {
-		addr := fmt.Sprintf("%s:%d", host, port)
+		addr := net.JoinHostPort(host, strconv.Itoa(port))
 		conn, err := net.DialTimeout("tcp", addr, defaultContainerReachabilityTimeout)
 		if err == nil {
 			defer conn.Close()
@@ -1462,10 +1407,9 @@ func runContainer(
 	}
 
 	portBinding := nat.PortMap{}
-	for _, mapping := range ports {
+	for _, mapping := range spec.ports {
 		if isReachable("0.0.0.0", mapping.hostPort) {
-			log.Printf("failed to run local BPI container image: %s; bind for 0.0.0.0:%d failed; port is already allocated", image, mapping.hostPort)
-			os.Exit(1)
+			return fmt.Errorf("failed to run local BPI container image: %s; bind for 0.0.0.0:%d failed; port is already allocated", image, mapping.hostPort)
 		}
 
 		port, _ := nat.NewPort("tcp", strconv.Itoa(mapping.containerPort))
@@ -1475,54 +1419,59 @@ func runContainer(
 		}}
 	}
 
-	var listenPort *int
-	if len(ports) == 1 {
-		listenPort = &ports[0].containerPort
-	}
-
-	var environment []string
-	if env != nil {
-		environment = *env
-	} else {
-		environment = containerEnvironmentFactory(listenPort)
-	}
-
 	containerConfig := &container.Config{
-		Env:      environment,
-		Hostname: hostname,
+		Env:      spec.environment(),
+		Hostname: spec.hostname,
 		Image:    image,
 	}
 
-	if cmd != nil {
-		containerConfig.Cmd = *cmd
+	if spec.cmd != nil {
+		containerConfig.Cmd = *spec.cmd
 	}
 
-	if entrypoint != nil {
-		containerConfig.Entrypoint = *entrypoint
+	if spec.entrypoint != nil {
+		containerConfig.Entrypoint = *spec.entrypoint
 	}
 
-	if healthcheck != nil {
+	if spec.healthcheck != nil {
 		containerConfig.Healthcheck = &container.HealthConfig{
 			Interval:    time.Minute * 1,
 			Retries:     2,
 			StartPeriod: time.Second * 10,
-			Test:        *healthcheck,
+			Test:        *spec.healthcheck,
 			Timeout:     time.Second * 1,
 		}
 	}
 
 	mountedVolumes := make([]mount.Mount, 0)
-	for source := range mounts { // mounts are mapped source => target...
+	for source := range spec.mounts { // mounts are mapped source => target...
 		mountedVolumes = append(mountedVolumes, mount.Mount{
 			Type:   mount.TypeBind,
 			Source: source,
-			Target: mounts[source],
+			Target: spec.mounts[source],
 		})
 	}
 
+	for volumeName, target := range spec.volumes {
+		if err := requireVolume(docker, volumeName); err != nil {
+			return err
+		}
+
+		mountedVolumes = append(mountedVolumes, mount.Mount{
+			Type:   mount.TypeVolume,
+			Source: volumeName,
+			Target: target,
+		})
+	}
+
+	containers, err := common.ListContainers(docker, "")
+	if err != nil {
+		return err
+	}
+
 	var containerID string
-	for _, container := range common.ListContainers(docker, "") {
-		if strings.ReplaceAll(container.Names[0], "/", "") == name {
+	for _, container := range containers {
+		if strings.ReplaceAll(container.Names[0], "/", "") == spec.name {
 			containerID = container.ID
 		}
 	}
@@ -1554,7 +1503,7 @@ func runContainer(
 			hostConfig,
 			&network.NetworkingConfig{},
 			nil,
-			strings.ReplaceAll(name, " ", ""),
+			strings.ReplaceAll(spec.name, " ", ""),
 		)
 
 		if err != nil {
@@ -1564,7 +1513,7 @@ func runContainer(
 		containerID = container.ID
 	}
 
-	err := docker.ContainerStart(context.Background(), containerID, types.ContainerStartOptions{})
+	err = docker.ContainerStart(context.Background(), containerID, types.ContainerStartOptions{})
 	if err != nil {
 		return err
 	}
@@ -1607,20 +1556,20 @@ func useLocalBaselineBuild(docker *client.Client) {
 	}
 }
 
-func organizationAuthPrompt() {
+func organizationAuthPrompt(ctx *common.Context) {
 	prompt := promptui.Prompt{
 		IsConfirm: true,
-		Label:     fmt.Sprintf("Authorize access/refresh token for %s", *common.Organization.Name),
+		Label:     fmt.Sprintf("Authorize access/refresh token for %s", *ctx.Organization.Name),
 	}
 
 	result, err := prompt.Run()
 	if err != nil {
-		os.Exit(1)
+		common.Exit(1)
 		return
 	}
 
 	if strings.ToLower(result) == "y" {
-		common.AuthorizeOrganizationContext(true)
+		common.AuthorizeOrganizationContext(ctx, true)
 	}
 }
 
@@ -1636,7 +1585,7 @@ func tunnelAPIPrompt() {
 
 	result, err := prompt.Run()
 	if err != nil {
-		os.Exit(1)
+		common.Exit(1)
 		return
 	}
 
@@ -1657,7 +1606,7 @@ func tunnelMessagingPrompt() {
 
 	result, err := prompt.Run()
 	if err != nil {
-		os.Exit(1)
+		common.Exit(1)
 		return
 	}
 
@@ -1713,7 +1662,7 @@ func sorURLPrompt() {
 
 	result, err := prompt.Run()
 	if err != nil {
-		os.Exit(1)
+		common.Exit(1)
 		return
 	}
 
@@ -1721,9 +1670,10 @@ func sorURLPrompt() {
 }
 
 func init() {
-	startBaselineStackCmd.Flags().StringVar(&name, "name", defaultBPIStackName, "name of the axiom stack instance")
+	startBaselineStackCmd.Flags().StringVarP(&stackFile, "file", "f", "", "path to a stack definition file; flags override its values")
+	startBaselineStackCmd.Flags().StringVar(&name, "name", DefaultName, "name of the axiom stack instance")
 
-	startBaselineStackCmd.Flags().StringVar(&common.OrganizationID, "organization", os.Getenv("PROVIDE_ORGANIZATION_ID"), "organization identifier")
+	startBaselineStackCmd.Flags().String("organization", os.Getenv("PROVIDE_ORGANIZATION_ID"), "organization identifier")
 	// runBaselineStackCmd.MarkFlagRequired("organization")
 
 	startBaselineStackCmd.Flags().StringVar(&common.BPIEndpoint, "bpi-endpoint", "", "local BPI endpoint for use by one or more authorized systems of record")
@@ -1814,7 +1764,7 @@ func init() {
 	startBaselineStackCmd.Flags().BoolVar(&withLocalVault, "with-local-vault", false, "when true, vault service is run locally")
 	startBaselineStackCmd.Flags().IntVar(&vaultPort, "vault-local-port", 8084, "port for the local vault service")
 
-	startBaselineStackCmd.Flags().StringVar(&common.UserAccessToken, "access-token", "", "access token for the user; useful for running non-interactively")
+	startBaselineStackCmd.Flags().String("access-token", "", "access token for the user; useful for running non-interactively")
 	startBaselineStackCmd.Flags().StringVar(&organizationRefreshToken, "organization-refresh-token", os.Getenv("PROVIDE_ORGANIZATION_REFRESH_TOKEN"), "refresh token to vend access tokens for use with the local organization")
 
 	startBaselineStackCmd.Flags().BoolVar(&withoutRequireOrganizationKeys, "without-require-organization-keys", false, "when true, no initial keys are required for the organization upon starting the stack")
@@ -1841,12 +1791,18 @@ func init() {
 
 	startBaselineStackCmd.Flags().StringVar(&axiomOrganizationAddress, "organization-address", defaultBaselineOrganizationAddress, "public axiom regsitry address of the organization")
 	startBaselineStackCmd.Flags().StringVar(&axiomRegistryContractAddress, "registry-contract-address", defaultBaselineRegistryContractAddress, "public axiom regsitry contract address")
-	startBaselineStackCmd.Flags().StringVar(&common.WorkgroupID, "workgroup", "", "axiom workgroup identifier")
+	startBaselineStackCmd.Flags().String("workgroup", "", "axiom workgroup identifier")
 
 	startBaselineStackCmd.Flags().StringVar(&nchainBaselineNetworkID, "nchain-network-id", "", "nchain network id of the axiom mainnet")
 	startBaselineStackCmd.Flags().BoolVarP(&Optional, "prompt-all", "", false, "when true, prompts for all optional flags")
+	startBaselineStackCmd.Flags().BoolVar(&skipPreflight, "skip-preflight", false, "when true, the pre-flight checks of the host are skipped")
+	startBaselineStackCmd.Flags().DurationVar(&startupTimeout, "startup-timeout", 0, "maximum time to wait for each service to become ready; defaults to a per-service timeout")
 
 	initSORFlags()
+
+	configPrintStackCmd.Flags().AddFlagSet(startBaselineStackCmd.Flags())
+	preflightStackCmd.Flags().AddFlagSet(startBaselineStackCmd.Flags())
+	exportStackCmd.Flags().AddFlagSet(startBaselineStackCmd.Flags())
 }
 
 func initSORFlags() {
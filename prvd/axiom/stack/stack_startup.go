@@ -0,0 +1,525 @@
+/*
+ * Copyright 2017-2022 Provide Technologies Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package stack
+
+import (
+	"bufio"
+	"context"
+	"crypto/tls"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/docker/docker/client"
+)
+
+// startupProbeInterval is the interval between readiness probes of a starting service
+const startupProbeInterval = time.Second * 1
+
+// startupProbeTimeout bounds a single readiness probe
+const startupProbeTimeout = time.Second * 3
+
+const defaultElasticStartupTimeout = time.Minute * 3
+const defaultNATSStartupTimeout = time.Second * 30
+const defaultPostgresStartupTimeout = time.Minute * 1
+const defaultRedisStartupTimeout = time.Second * 30
+const defaultAPIStartupTimeout = time.Minute * 2
+
+// postgresErrCannotConnectNow is the SQLSTATE returned while postgres is starting up or shutting down
+const postgresErrCannotConnectNow = "57P03"
+
+// postgresProtocolVersion is version 3.0 of the postgres wire protocol
+const postgresProtocolVersion = 196608
+
+var startupTimeout time.Duration
+
+// startupService is a node of the dependency graph of the local stack
+type startupService struct {
+	name      string
+	dependsOn []string
+	container func() *containerSpec
+	run       func(docker *client.Client) error
+	probe     func() error
+	timeout   time.Duration
+}
+
+// startupError reports the service which failed to become ready and why
+type startupError struct {
+	service    string
+	dependency string
+	err        error
+}
+
+func (e *startupError) Error() string {
+	if e.dependency != "" {
+		return fmt.Sprintf("%s not started; dependency %s failed", e.service, e.dependency)
+	}
+	return fmt.Sprintf("%s failed to become ready; %s", e.service, e.err.Error())
+}
+
+// startupServices returns the dependency graph of the services to start, based on the configured stack
+func startupServices() []*startupService {
+	dependencies := []string{"elasticsearch", "nats", "postgres", "redis"}
+	local := []string{"nats", "postgres", "redis"}
+
+	services := []*startupService{
+		{
+			name:      "elasticsearch",
+			container: elasticsearchContainer,
+			run:       runElasticsearch,
+			probe:     func() error { return probeElasticsearch(elasticPort) },
+			timeout:   defaultElasticStartupTimeout,
+		},
+		{
+			name:      "nats",
+			container: natsContainer,
+			run:       runNATS,
+			probe:     func() error { return probeNATS(natsPort) },
+			timeout:   defaultNATSStartupTimeout,
+		},
+		{
+			name:      "postgres",
+			container: postgresContainer,
+			run:       runPostgres,
+			probe:     func() error { return probePostgres(postgresPort, postgresUser, postgresDatabase) },
+			timeout:   defaultPostgresStartupTimeout,
+		},
+		{
+			name:      "redis",
+			container: redisContainer,
+			run:       runRedis,
+			probe:     func() error { return probeRedis(redisPort) },
+			timeout:   defaultRedisStartupTimeout,
+		},
+	}
+
+	if withLocalIdent {
+		dependencies = append(dependencies, "ident-api")
+		services = append(services,
+			&startupService{
+				name:      "ident-api",
+				dependsOn: local,
+				container: identAPIContainer,
+				run:       runIdentAPI,
+				probe:     func() error { return probeHTTPStatus(identPort) },
+				timeout:   defaultAPIStartupTimeout,
+			},
+			&startupService{
+				name:      "ident-consumer",
+				dependsOn: local,
+				container: identConsumerContainer,
+				run:       runIdentConsumer,
+			},
+		)
+	}
+
+	if withLocalNChain {
+		dependencies = append(dependencies, "nchain-api")
+		services = append(services,
+			&startupService{
+				name:      "nchain-api",
+				dependsOn: local,
+				container: nchainAPIContainer,
+				run:       runNChainAPI,
+				probe:     func() error { return probeHTTPStatus(nchainPort) },
+				timeout:   defaultAPIStartupTimeout,
+			},
+			&startupService{
+				name:      "nchain-consumer",
+				dependsOn: local,
+				container: nchainConsumerContainer,
+				run:       runNChainConsumer,
+			},
+			&startupService{
+				name:      "statsdaemon",
+				dependsOn: local,
+				container: statsdaemonContainer,
+				run:       runStatsdaemon,
+			},
+			&startupService{
+				name:      "reachabilitydaemon",
+				dependsOn: local,
+				container: reachabilitydaemonContainer,
+				run:       runReachabilitydaemon,
+			},
+		)
+	}
+
+	if withLocalPrivacy {
+		dependencies = append(dependencies, "privacy-api")
+		services = append(services,
+			&startupService{
+				name:      "privacy-api",
+				dependsOn: local,
+				container: privacyAPIContainer,
+				run:       runPrivacyAPI,
+				probe:     func() error { return probeHTTPStatus(privacyPort) },
+				timeout:   defaultAPIStartupTimeout,
+			},
+			&startupService{
+				name:      "privacy-consumer",
+				dependsOn: local,
+				container: privacyConsumerContainer,
+				run:       runPrivacyConsumer,
+			},
+		)
+	}
+
+	if withLocalVault {
+		dependencies = append(dependencies, "vault-api")
+		services = append(services, &startupService{
+			name:      "vault-api",
+			dependsOn: local,
+			container: vaultAPIContainer,
+			run:       runVaultAPI,
+			probe:     func() error { return probeHTTPStatus(vaultPort) },
+			timeout:   defaultAPIStartupTimeout,
+		})
+	}
+
+	services = append(services,
+		&startupService{
+			name:      "api",
+			dependsOn: dependencies,
+			container: baselineAPIContainer,
+			run:       runBaselineAPI,
+			probe:     func() error { return probeHTTPStatus(port) },
+			timeout:   defaultAPIStartupTimeout,
+		},
+		&startupService{
+			name:      "consumer",
+			dependsOn: dependencies,
+			container: baselineConsumerContainer,
+			run:       runBaselineConsumer,
+		},
+	)
+
+	return services
+}
+
+// startServices starts each service once all of its dependencies are ready and waits
+// for each service to pass its readiness probe; the first failure is returned
+func startServices(docker *client.Client, services []*startupService) error {
+	ready := map[string]chan struct{}{}
+	for _, svc := range services {
+		ready[svc.name] = make(chan struct{})
+	}
+
+	for _, svc := range services {
+		for _, dep := range svc.dependsOn {
+			if _, ok := ready[dep]; !ok {
+				return fmt.Errorf("%s depends on unknown service %s", svc.name, dep)
+			}
+		}
+	}
+
+	var mutex sync.Mutex
+	errs := map[string]error{}
+	failed := func(name string) error {
+		mutex.Lock()
+		defer mutex.Unlock()
+		return errs[name]
+	}
+
+	wg := &sync.WaitGroup{}
+	for _, svc := range services {
+		wg.Add(1)
+		go func(svc *startupService) {
+			defer wg.Done()
+			defer close(ready[svc.name])
+
+			var err error
+			for _, dep := range svc.dependsOn {
+				<-ready[dep]
+				if failed(dep) != nil {
+					err = &startupError{service: svc.name, dependency: dep}
+					break
+				}
+			}
+
+			if err == nil {
+				if err = svc.run(docker); err != nil {
+					err = &startupError{service: svc.name, err: err}
+				} else if err = awaitReadiness(docker, svc); err == nil {
+					log.Printf("%s ready", svc.container().name)
+				}
+			}
+
+			if err != nil {
+				mutex.Lock()
+				errs[svc.name] = err
+				mutex.Unlock()
+			}
+		}(svc)
+	}
+	wg.Wait()
+
+	// report the root cause in preference to the dependents which were not started
+	var dependentErr error
+	for _, svc := range services {
+		if err, ok := errs[svc.name]; ok {
+			var serr *startupError
+			if errors.As(err, &serr) && serr.dependency != "" {
+				if dependentErr == nil {
+					dependentErr = err
+				}
+				continue
+			}
+			return err
+		}
+	}
+
+	return dependentErr
+}
+
+// awaitReadiness polls the readiness probe and docker health of the given service until
+// either reports the service ready, its container exits or the startup timeout elapses
+func awaitReadiness(docker *client.Client, svc *startupService) error {
+	timeout := svc.timeout
+	if startupTimeout > 0 {
+		timeout = startupTimeout
+	}
+
+	containerName := svc.container().name
+	deadline := time.Now().Add(timeout)
+	lastErr := errors.New("no readiness probe completed")
+
+	for {
+		inspect, err := docker.ContainerInspect(context.Background(), containerName)
+		if err == nil && inspect.State != nil {
+			if !inspect.State.Running && inspect.State.Status != "created" {
+				return &startupError{
+					service: svc.name,
+					err:     fmt.Errorf("container %s %s with exit code %d", containerName, inspect.State.Status, inspect.State.ExitCode),
+				}
+			}
+
+			if inspect.State.Health != nil && inspect.State.Health.Status == containerHealthHealthy {
+				return nil
+			}
+		}
+
+		if svc.probe == nil {
+			if err == nil && inspect.State != nil && inspect.State.Running {
+				return nil
+			}
+		} else if lastErr = svc.probe(); lastErr == nil {
+			return nil
+		}
+
+		if time.Now().After(deadline) {
+			if svc.probe == nil && err != nil {
+				lastErr = err
+			}
+			return &startupError{
+				service: svc.name,
+				err:     fmt.Errorf("not ready after %s; %s", timeout, lastErr.Error()),
+			}
+		}
+
+		time.Sleep(startupProbeInterval)
+	}
+}
+
+func probeAddress(port int) string {
+	return net.JoinHostPort("localhost", strconv.Itoa(port))
+}
+
+// probeElasticsearch requires the elasticsearch cluster health to be yellow or green
+func probeElasticsearch(port int) error {
+	schemes := []string{elasticAPIScheme}
+	if elasticAPIScheme == "https" {
+		schemes = append(schemes, "http") // the local elasticsearch container does not enable tls by default
+	}
+
+	httpClient := &http.Client{
+		Timeout: startupProbeTimeout,
+		Transport: &http.Transport{
+			TLSClientConfig: &tls.Config{InsecureSkipVerify: true},
+		},
+	}
+
+	var err error
+	for _, scheme := range schemes {
+		err = func() error {
+			req, err := http.NewRequest(http.MethodGet, fmt.Sprintf("%s://%s/_cluster/health", scheme, probeAddress(port)), nil)
+			if err != nil {
+				return err
+			}
+			req.SetBasicAuth(elasticUsername, elasticPassword)
+
+			resp, err := httpClient.Do(req)
+			if err != nil {
+				return err
+			}
+			defer resp.Body.Close()
+
+			if resp.StatusCode != http.StatusOK {
+				return fmt.Errorf("cluster health returned status %d", resp.StatusCode)
+			}
+
+			var health struct {
+				Status string `json:"status"`
+			}
+			if err := json.NewDecoder(resp.Body).Decode(&health); err != nil {
+				return fmt.Errorf("failed to parse cluster health; %s", err.Error())
+			}
+
+			if health.Status != "green" && health.Status != "yellow" {
+				return fmt.Errorf("cluster health is %s", health.Status)
+			}
+			return nil
+		}()
+
+		if err == nil {
+			return nil
+		}
+	}
+
+	return err
+}
+
+// probeNATS requires the NATS server to send its INFO greeting
+func probeNATS(port int) error {
+	conn, err := net.DialTimeout("tcp", probeAddress(port), startupProbeTimeout)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+	conn.SetDeadline(time.Now().Add(startupProbeTimeout))
+
+	line, err := bufio.NewReader(conn).ReadString('\n')
+	if err != nil {
+		return fmt.Errorf("failed to read INFO; %s", err.Error())
+	}
+
+	if !strings.HasPrefix(line, "INFO ") {
+		return fmt.Errorf("unexpected greeting %q", strings.TrimSpace(line))
+	}
+	return nil
+}
+
+// probeRedis requires redis to answer PING; an authentication error also implies readiness
+func probeRedis(port int) error {
+	conn, err := net.DialTimeout("tcp", probeAddress(port), startupProbeTimeout)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+	conn.SetDeadline(time.Now().Add(startupProbeTimeout))
+
+	if _, err := conn.Write([]byte("PING\r\n")); err != nil {
+		return err
+	}
+
+	line, err := bufio.NewReader(conn).ReadString('\n')
+	if err != nil {
+		return fmt.Errorf("failed to read PING reply; %s", err.Error())
+	}
+
+	line = strings.TrimSpace(line)
+	if line == "+PONG" || strings.HasPrefix(line, "-NOAUTH") {
+		return nil
+	}
+	return fmt.Errorf("unexpected PING reply %q", line)
+}
+
+// probePostgres requires postgres to accept connections, like pg_isready; the startup message
+// is answered with an authentication request once the server is ready and with the
+// cannot_connect_now error while it is still starting
+func probePostgres(port int, user, database string) error {
+	conn, err := net.DialTimeout("tcp", probeAddress(port), startupProbeTimeout)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+	conn.SetDeadline(time.Now().Add(startupProbeTimeout))
+
+	params := fmt.Sprintf("user\x00%s\x00database\x00%s\x00\x00", user, database)
+	msg := make([]byte, 8, 8+len(params))
+	binary.BigEndian.PutUint32(msg[0:4], uint32(8+len(params)))
+	binary.BigEndian.PutUint32(msg[4:8], postgresProtocolVersion)
+	msg = append(msg, params...)
+
+	if _, err := conn.Write(msg); err != nil {
+		return err
+	}
+
+	header := make([]byte, 5)
+	if _, err := io.ReadFull(conn, header); err != nil {
+		return fmt.Errorf("failed to read startup reply; %s", err.Error())
+	}
+
+	switch header[0] {
+	case 'R':
+		return nil
+	case 'E':
+		length := binary.BigEndian.Uint32(header[1:5])
+		if length < 4 || length > 8192 {
+			return fmt.Errorf("invalid error response length %d", length)
+		}
+
+		body := make([]byte, length-4)
+		if _, err := io.ReadFull(conn, body); err != nil {
+			return fmt.Errorf("failed to read error response; %s", err.Error())
+		}
+
+		var code, message string
+		for _, field := range strings.Split(string(body), "\x00") {
+			if len(field) < 2 {
+				continue
+			}
+			switch field[0] {
+			case 'C':
+				code = field[1:]
+			case 'M':
+				message = field[1:]
+			}
+		}
+
+		if code == postgresErrCannotConnectNow {
+			return errors.New(message)
+		}
+		return nil // the server is accepting connections, even if it rejected this one
+	default:
+		return fmt.Errorf("unexpected startup reply %q", header[0])
+	}
+}
+
+// probeHTTPStatus requires the /status endpoint of a local service to return 2xx
+func probeHTTPStatus(port int) error {
+	httpClient := &http.Client{Timeout: startupProbeTimeout}
+	resp, err := httpClient.Get(fmt.Sprintf("http://%s/status", probeAddress(port)))
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("status returned %d", resp.StatusCode)
+	}
+	return nil
+}
@@ -0,0 +1,213 @@
+/*
+ * Copyright 2017-2022 Provide Technologies Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package stack
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/client"
+	"github.com/provideplatform/provide-cli/prvd/common"
+	"github.com/spf13/cobra"
+)
+
+const stackStatusHealthy = 0
+const stackStatusDegraded = 1
+const stackStatusStopped = 2
+
+const containerStateMissing = "missing"
+const containerStateRunning = "running"
+const containerHealthHealthy = "healthy"
+const containerHealthNone = "-"
+
+// stackRequiredServices are the containers every local axiom stack runs
+var stackRequiredServices = []string{"api", "consumer", "elasticsearch", "nats", "postgres", "redis"}
+
+var statusStackCmd = &cobra.Command{
+	Use:   "status",
+	Short: "Print the status of the axiom stack",
+	Long: `Print the state, health, uptime, ports and image of each service container in a
+local axiom stack instance, including the optional local ident, nchain, privacy and vault services.
+
+Exits with status 0 when every service is running and healthy, 1 when a service is missing,
+stopped or unhealthy or docker is unavailable, and 2 when the stack is not running.`,
+	Run: stackStatus,
+}
+
+// Container is a container of a service of the stack
+type Container struct {
+	types.Container
+	Service string
+}
+
+// ServiceStatus is the status of a service container of the stack
+type ServiceStatus struct {
+	Service string
+	State   string
+	Health  string
+	Uptime  string
+	Ports   string
+	Image   string
+}
+
+func stackStatus(cmd *cobra.Command, args []string) {
+	docker, err := client.NewEnvClient()
+	if err != nil {
+		log.Printf("failed to initialize docker; %s", err.Error())
+		common.Exit(1)
+	}
+	defer docker.Close()
+
+	statuses, err := ServiceStatuses(docker, name)
+	if err != nil {
+		log.Printf("failed to resolve status of stack: %s; %s", name, err.Error())
+		common.Exit(1)
+	}
+
+	fmt.Printf("%-24s %-10s %-10s %-12s %-32s %s\n", "SERVICE", "STATE", "HEALTH", "UPTIME", "PORTS", "IMAGE")
+	for _, status := range statuses {
+		fmt.Printf("%-24s %-10s %-10s %-12s %-32s %s\n", status.Service, status.State, status.Health, status.Uptime, status.Ports, status.Image)
+	}
+
+	code := stackStatusCode(statuses)
+	switch code {
+	case stackStatusHealthy:
+		fmt.Printf("\n%s is running\n", name)
+	case stackStatusDegraded:
+		fmt.Printf("\n%s is degraded\n", name)
+	case stackStatusStopped:
+		fmt.Printf("\n%s is not running\n", name)
+	}
+	common.Exit(code)
+}
+
+// Containers returns the service containers of the named stack, sorted by service
+func Containers(docker *client.Client, stack string) ([]*Container, error) {
+	containers, err := common.ListContainers(docker, stack)
+	if err != nil {
+		return nil, err
+	}
+
+	prefix := fmt.Sprintf("/%s-", strings.ReplaceAll(stack, " ", ""))
+	results := make([]*Container, 0)
+	for _, container := range containers {
+		service := ""
+		for _, name := range container.Names {
+			if strings.HasPrefix(name, prefix) {
+				service = strings.TrimPrefix(name, prefix)
+				break
+			}
+		}
+		if service == "" {
+			continue
+		}
+		results = append(results, &Container{Container: container, Service: service})
+	}
+
+	sort.Slice(results, func(i, j int) bool {
+		return results[i].Service < results[j].Service
+	})
+	return results, nil
+}
+
+// ServiceStatuses resolves the status of each service container of the named stack;
+// required services without a container are reported as missing
+func ServiceStatuses(docker *client.Client, stack string) ([]*ServiceStatus, error) {
+	containers, err := Containers(docker, stack)
+	if err != nil {
+		return nil, err
+	}
+
+	statuses := make([]*ServiceStatus, 0)
+	services := map[string]bool{}
+	for _, container := range containers {
+		status := &ServiceStatus{
+			Service: container.Service,
+			State:   container.State,
+			Health:  containerHealthNone,
+			Uptime:  "-",
+			Ports:   dashboardPorts(container.Container),
+			Image:   container.Image,
+		}
+		if status.Ports == "" {
+			status.Ports = "-"
+		}
+
+		if inspect, err := docker.ContainerInspect(context.Background(), container.ID); err == nil && inspect.State != nil {
+			if inspect.State.Health != nil {
+				status.Health = inspect.State.Health.Status
+			}
+			if startedAt, err := time.Parse(time.RFC3339Nano, inspect.State.StartedAt); err == nil && inspect.State.Running {
+				status.Uptime = time.Since(startedAt).Round(time.Second).String()
+			}
+		}
+
+		services[status.Service] = true
+		statuses = append(statuses, status)
+	}
+
+	for _, svc := range stackRequiredServices {
+		if !services[svc] {
+			statuses = append(statuses, &ServiceStatus{
+				Service: svc,
+				State:   containerStateMissing,
+				Health:  containerHealthNone,
+				Uptime:  "-",
+				Ports:   "-",
+				Image:   "-",
+			})
+		}
+	}
+
+	sort.Slice(statuses, func(i, j int) bool {
+		return statuses[i].Service < statuses[j].Service
+	})
+	return statuses, nil
+}
+
+// stackStatusCode returns the exit code for the given service statuses
+func stackStatusCode(statuses []*ServiceStatus) int {
+	running := 0
+	healthy := true
+	for _, status := range statuses {
+		if status.State == containerStateRunning {
+			running++
+		} else {
+			healthy = false
+		}
+		if status.Health != containerHealthNone && status.Health != containerHealthHealthy {
+			healthy = false
+		}
+	}
+
+	switch {
+	case running == 0:
+		return stackStatusStopped
+	case !healthy:
+		return stackStatusDegraded
+	}
+	return stackStatusHealthy
+}
+
+func init() {
+	statusStackCmd.Flags().StringVar(&name, "name", "axiom-local", "name of the axiom stack instance")
+}
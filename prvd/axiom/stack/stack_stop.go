@@ -18,7 +18,6 @@ package stack
 
 import (
 	"log"
-	"os"
 
 	"github.com/docker/docker/client"
 	"github.com/provideplatform/provide-cli/prvd/common"
@@ -26,11 +25,16 @@ import (
 	"github.com/spf13/cobra"
 )
 
+var purgeVolumes bool
+
 var stopBaselineStackCmd = &cobra.Command{
 	Use:   "stop",
 	Short: "Stop the axiom stack",
-	Long:  `Stop a local axiom stack instance`,
-	Run:   stopStack,
+	Long: `Stop a local axiom stack instance.
+
+The data of postgres, elasticsearch and NATS JetStream is kept in named volumes of the stack,
+which survive --prune unless --volumes is also given.`,
+	Run: stopStack,
 }
 
 func stopStack(cmd *cobra.Command, args []string) {
@@ -41,7 +45,7 @@ func runStackStop(cmd *cobra.Command, args []string) {
 	docker, err := client.NewEnvClient()
 	if err != nil {
 		log.Printf("failed to initialize docker; %s", err.Error())
-		os.Exit(1)
+		common.Exit(1)
 	}
 
 	if !prune {
@@ -49,6 +53,14 @@ func runStackStop(cmd *cobra.Command, args []string) {
 	} else {
 		common.PurgeContainers(docker, name, true)
 		common.PurgeNetwork(docker, name)
+
+		if purgeVolumes {
+			common.PurgeVolumes(docker, name)
+		}
+
+		if err := persistAllocatedPorts(name, nil); err != nil {
+			log.Printf("WARNING: failed to release stack ports; %s", err.Error())
+		}
 	}
 
 	log.Printf("%s local axiom instance stopped", name)
@@ -57,4 +69,5 @@ func runStackStop(cmd *cobra.Command, args []string) {
 func init() {
 	stopBaselineStackCmd.Flags().StringVar(&name, "name", "axiom-local", "name of the axiom stack instance")
 	stopBaselineStackCmd.Flags().BoolVar(&prune, "prune", false, "when true, previously-created docker resources are pruned prior to stack initialization")
+	stopBaselineStackCmd.Flags().BoolVar(&purgeVolumes, "volumes", false, "when true with --prune, the named data volumes of the stack are also removed")
 }
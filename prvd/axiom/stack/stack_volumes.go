@@ -0,0 +1,67 @@
+/*
+ * Copyright 2017-2022 Provide Technologies Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package stack
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	volumetypes "github.com/docker/docker/api/types/volume"
+	"github.com/docker/docker/client"
+	"github.com/provideplatform/provide-cli/prvd/common"
+)
+
+const elasticsearchDataDir = "/usr/share/elasticsearch/data"
+const natsJetStreamStoreDir = "/var/lib/nats"
+const postgresDataDir = "/var/lib/postgresql/data"
+
+// stackVolumeTargets are the stateful services with a named volume, mapped service => data directory
+var stackVolumeTargets = map[string]string{
+	"elasticsearch": elasticsearchDataDir,
+	"nats":          natsJetStreamStoreDir,
+	"postgres":      postgresDataDir,
+}
+
+// stackVolumeName returns the name of the volume of the given service of a stack, i.e., axiom-local-postgres-data
+func stackVolumeName(stack, service string) string {
+	return fmt.Sprintf("%s-%s-data", strings.ReplaceAll(stack, " ", ""), service)
+}
+
+// stackVolumes returns the named volume of the given service of the current stack, mapped volume => target
+func stackVolumes(service string) map[string]string {
+	target, ok := stackVolumeTargets[service]
+	if !ok {
+		return nil
+	}
+	return map[string]string{stackVolumeName(name, service): target}
+}
+
+// requireVolume creates the named volume, labeled with the stack to which it belongs, unless it exists
+func requireVolume(docker *client.Client, volumeName string) error {
+	if _, err := docker.VolumeInspect(context.Background(), volumeName); err == nil {
+		return nil
+	}
+
+	_, err := docker.VolumeCreate(context.Background(), volumetypes.VolumeCreateBody{
+		Name: volumeName,
+		Labels: map[string]string{
+			common.DockerStackLabel: strings.ReplaceAll(name, " ", ""),
+		},
+	})
+	return err
+}
@@ -19,7 +19,6 @@ package subject_accounts
 import (
 	"fmt"
 	"log"
-	"os"
 
 	"github.com/provideplatform/provide-cli/prvd/common"
 	"github.com/provideplatform/provide-go/api/axiom"
@@ -39,24 +38,25 @@ func fetchSubjectAccountDetails(cmd *cobra.Command, args []string) {
 }
 
 func fetchSubjectAccountDetailsRun(cmd *cobra.Command, args []string) {
-	if common.OrganizationID == "" {
-		common.RequireOrganization()
+	ctx := common.ContextFromCommand(cmd)
+	if ctx.OrganizationID == "" {
+		common.RequireOrganization(ctx)
 	}
 
-	if common.WorkgroupID == "" {
-		common.RequireWorkgroup()
+	if ctx.WorkgroupID == "" {
+		common.RequireWorkgroup(ctx)
 	}
 
-	token, err := common.ResolveOrganizationToken()
+	token, err := common.ResolveOrganizationToken(ctx)
 
-	if common.SubjectAccountID == "" {
-		common.SubjectAccountID = common.SHA256(fmt.Sprintf("%s.%s", common.OrganizationID, common.WorkgroupID))
+	if ctx.SubjectAccountID == "" {
+		ctx.SubjectAccountID = common.SHA256(fmt.Sprintf("%s.%s", ctx.OrganizationID, ctx.WorkgroupID))
 	}
 
-	sa, err := axiom.GetSubjectAccountDetails(*token.AccessToken, common.OrganizationID, common.SubjectAccountID, map[string]interface{}{})
+	sa, err := axiom.GetSubjectAccountDetails(*token.AccessToken, ctx.OrganizationID, ctx.SubjectAccountID, map[string]interface{}{})
 	if err != nil {
-		log.Printf("Failed to retrieve details for subject account with id: %s; %s", common.OrganizationID, err.Error())
-		os.Exit(1)
+		log.Printf("Failed to retrieve details for subject account with id: %s; %s", ctx.OrganizationID, err.Error())
+		common.Exit(1)
 	}
 
 	if sa.ID == nil {
@@ -64,12 +64,12 @@ func fetchSubjectAccountDetailsRun(cmd *cobra.Command, args []string) {
 		return
 	}
 
-	result := fmt.Sprintf("%s;\tworkgroup: %s\t%s;\torganization: %s\t%s\n", *sa.ID, common.Workgroup.ID, *common.Workgroup.Name, *common.Organization.ID, *common.Organization.Name)
+	result := fmt.Sprintf("%s;\tworkgroup: %s\t%s;\torganization: %s\t%s\n", *sa.ID, ctx.Workgroup.ID, *ctx.Workgroup.Name, *ctx.Organization.ID, *ctx.Organization.Name)
 	fmt.Print(result)
 }
 
 func init() {
-	subjectAccountDetailsCmd.Flags().StringVar(&common.OrganizationID, "organization", "", "organization identifier")
-	subjectAccountDetailsCmd.Flags().StringVar(&common.WorkgroupID, "workgroup", "", "workgroup identifier")
-	subjectAccountDetailsCmd.Flags().StringVar(&common.SubjectAccountID, "subject-account", "", "subject account identifier")
+	subjectAccountDetailsCmd.Flags().String("organization", "", "organization identifier")
+	subjectAccountDetailsCmd.Flags().String("workgroup", "", "workgroup identifier")
+	subjectAccountDetailsCmd.Flags().String("subject-account", "", "subject account identifier")
 }
@@ -49,71 +49,72 @@ func createSubjectAccount(cmd *cobra.Command, args []string) {
 }
 
 func createSubjectAccountRun(cmd *cobra.Command, args []string) {
-	if common.OrganizationID == "" {
-		common.RequireOrganization()
+	ctx := common.ContextFromCommand(cmd)
+	if ctx.OrganizationID == "" {
+		common.RequireOrganization(ctx)
 	}
 
 	// TODO-- check if user can pass workgroup id of workgroup that is not associated with organization id and handle that
-	if common.WorkgroupID == "" {
-		common.RequireWorkgroup()
+	if ctx.WorkgroupID == "" {
+		common.RequireWorkgroup(ctx)
 	}
 
-	if common.NetworkID == "" {
-		common.RequireL1Network()
+	if ctx.NetworkID == "" {
+		common.RequireL1Network(ctx)
 	}
 
-	if common.Organization.Metadata != nil && common.Organization.Metadata.Domain != "" {
-		orgDomain = common.Organization.Metadata.Domain
+	if ctx.Organization.Metadata != nil && ctx.Organization.Metadata.Domain != "" {
+		orgDomain = ctx.Organization.Metadata.Domain
 	} else if orgDomain == "" {
 		orgDomainPrompt()
 	}
 
-	common.AuthorizeOrganizationContext(true)
+	common.AuthorizeOrganizationContext(ctx, true)
 
-	token, err := common.ResolveOrganizationToken()
+	token, err := common.ResolveOrganizationToken(ctx)
 
 	contracts, err := nchain.ListContracts(*token.AccessToken, map[string]interface{}{
 		"type": "organization-registry",
 	})
 	if err != nil {
 		fmt.Printf("failed to create subject account; %s", err.Error())
-		os.Exit(1)
+		common.Exit(1)
 	}
 
 	if len(contracts) == 0 {
 		fmt.Println("failed to create subject account; failed to resolve organization registry contract")
-		os.Exit(1)
+		common.Exit(1)
 	}
 
 	if len(contracts) > 1 {
 		fmt.Println("failed to create subject account; resolved ambiguous organization registry contracts")
-		os.Exit(1)
+		common.Exit(1)
 	}
 
-	sa, err := axiom.CreateSubjectAccount(*token.AccessToken, common.OrganizationID, map[string]interface{}{
+	sa, err := axiom.CreateSubjectAccount(*token.AccessToken, ctx.OrganizationID, map[string]interface{}{
 		"metadata": map[string]interface{}{
-			"organization_id":            common.OrganizationID,
-			"organization_address":       common.Organization.Metadata.Address,
+			"organization_id":            ctx.OrganizationID,
+			"organization_address":       ctx.Organization.Metadata.Address,
 			"organization_refresh_token": *token.RefreshToken,
-			"workgroup_id":               common.WorkgroupID,
+			"workgroup_id":               ctx.WorkgroupID,
 			"registry_contract_address":  *contracts[0].Address,
-			"network_id":                 common.NetworkID,
+			"network_id":                 ctx.NetworkID,
 			"organization_domain":        orgDomain,
 		},
 	})
 	if err != nil {
 		log.Printf("failed to initialize axiom subject account; %s", err.Error())
-		os.Exit(1)
+		common.Exit(1)
 	}
 
 	// TODO-- make utility function to DRY this up
-	vaultID := common.Organization.Metadata.Workgroups[common.Workgroup.ID].VaultID
-	systemIDs := common.Organization.Metadata.Workgroups[common.Workgroup.ID].SystemSecretIDs
+	vaultID := ctx.Organization.Metadata.Workgroups[ctx.Workgroup.ID].VaultID
+	systemIDs := ctx.Organization.Metadata.Workgroups[ctx.Workgroup.ID].SystemSecretIDs
 
-	isOperator := common.Organization.Metadata.Workgroups[common.Workgroup.ID].OperatorSeparationDegree == 0
+	isOperator := ctx.Organization.Metadata.Workgroups[ctx.Workgroup.ID].OperatorSeparationDegree == 0
 	if isOperator {
-		vaultID = common.Workgroup.Config.VaultID
-		systemIDs = common.Workgroup.Config.SystemSecretIDs
+		vaultID = ctx.Workgroup.Config.VaultID
+		systemIDs = ctx.Workgroup.Config.SystemSecretIDs
 	}
 
 	if len(systemIDs) > 0 && vaultID != nil {
@@ -121,49 +122,49 @@ func createSubjectAccountRun(cmd *cobra.Command, args []string) {
 			secret, err := vault.FetchSecret(*token.AccessToken, vaultID.String(), secretID.String(), map[string]interface{}{})
 			if err != nil {
 				log.Printf("failed to initialize axiom subject account; %s", err.Error())
-				os.Exit(1)
+				common.Exit(1)
 			}
 
 			var systemParams map[string]interface{}
 			err = json.Unmarshal([]byte(*secret.Value), &systemParams)
 			if err != nil {
 				log.Printf("failed to initialize axiom subject account; %s", err.Error())
-				os.Exit(1)
+				common.Exit(1)
 			}
 
-			if _, err := axiom.CreateSystem(*token.AccessToken, common.WorkgroupID, systemParams); err != nil {
+			if _, err := axiom.CreateSystem(*token.AccessToken, ctx.WorkgroupID, systemParams); err != nil {
 				log.Printf("failed to initialize axiom subject account; %s", err.Error())
-				os.Exit(1)
+				common.Exit(1)
 			}
 
 			if err := vault.DeleteSecret(*token.AccessToken, vaultID.String(), secretID.String()); err != nil {
 				log.Printf("failed to initialize axiom subject account; %s", err.Error())
-				os.Exit(1)
+				common.Exit(1)
 			}
 		}
 
-		common.Organization.Metadata.Domain = orgDomain
-		common.Organization.Metadata.Workgroups[common.Workgroup.ID].SystemSecretIDs = make([]*uuid.UUID, 0)
+		ctx.Organization.Metadata.Domain = orgDomain
+		ctx.Organization.Metadata.Workgroups[ctx.Workgroup.ID].SystemSecretIDs = make([]*uuid.UUID, 0)
 
 		var organizationParams map[string]interface{}
-		raw, _ := json.Marshal(common.Organization)
+		raw, _ := json.Marshal(ctx.Organization)
 		json.Unmarshal(raw, &organizationParams)
 
-		if err := ident.UpdateOrganization(*token.AccessToken, common.OrganizationID, organizationParams); err != nil {
+		if err := ident.UpdateOrganization(*token.AccessToken, ctx.OrganizationID, organizationParams); err != nil {
 			log.Printf("failed to initialize axiom subject account; %s", err.Error())
-			os.Exit(1)
+			common.Exit(1)
 		}
 
 		if isOperator {
-			common.Workgroup.Config.SystemSecretIDs = make([]*uuid.UUID, 0)
+			ctx.Workgroup.Config.SystemSecretIDs = make([]*uuid.UUID, 0)
 
 			var workgroupParams map[string]interface{}
-			raw, _ := json.Marshal(common.Workgroup)
+			raw, _ := json.Marshal(ctx.Workgroup)
 			json.Unmarshal(raw, &workgroupParams)
 
-			if err := axiom.UpdateWorkgroup(*token.AccessToken, common.WorkgroupID, workgroupParams); err != nil {
+			if err := axiom.UpdateWorkgroup(*token.AccessToken, ctx.WorkgroupID, workgroupParams); err != nil {
 				log.Printf("failed to initialize axiom subject account; %s", err.Error())
-				os.Exit(1)
+				common.Exit(1)
 			}
 		}
 
@@ -188,7 +189,7 @@ func orgDomainPrompt() {
 
 	result, err := prompt.Run()
 	if err != nil {
-		os.Exit(1)
+		common.Exit(1)
 		return
 	}
 
@@ -196,9 +197,9 @@ func orgDomainPrompt() {
 }
 
 func init() {
-	initBaselineSubjectAccountCmd.Flags().StringVar(&common.OrganizationID, "organization", os.Getenv("PROVIDE_ORGANIZATION_ID"), "organization identifier")
-	initBaselineSubjectAccountCmd.Flags().StringVar(&common.WorkgroupID, "workgroup", "", "workgroup identifier")
-	initBaselineSubjectAccountCmd.Flags().StringVar(&common.NetworkID, "network", "", "nchain network id of the axiom mainnet to use for this workgroup")
+	initBaselineSubjectAccountCmd.Flags().String("organization", os.Getenv("PROVIDE_ORGANIZATION_ID"), "organization identifier")
+	initBaselineSubjectAccountCmd.Flags().String("workgroup", "", "workgroup identifier")
+	initBaselineSubjectAccountCmd.Flags().String("network", "", "nchain network id of the axiom mainnet to use for this workgroup")
 
 	initBaselineSubjectAccountCmd.Flags().StringVar(&orgDomain, "organization-domain", "", "organization domain to use for this subject account, if it is not set on the organization")
 
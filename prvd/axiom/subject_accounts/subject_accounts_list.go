@@ -19,7 +19,6 @@ package subject_accounts
 import (
 	"fmt"
 	"log"
-	"os"
 
 	"github.com/provideplatform/provide-cli/prvd/common"
 	"github.com/provideplatform/provide-go/api/axiom"
@@ -42,38 +41,39 @@ func listSubjectAccounts(cmd *cobra.Command, args []string) {
 }
 
 func listSubjectAccountsRun(cmd *cobra.Command, args []string) {
-	token, err := common.ResolveOrganizationToken()
+	ctx := common.ContextFromCommand(cmd)
+	token, err := common.ResolveOrganizationToken(ctx)
 	if err != nil {
 		log.Printf("failed to retrieve axiom subject accounts; %s", err.Error())
-		os.Exit(1)
+		common.Exit(1)
 	}
 
-	subject_accounts, err := axiom.ListSubjectAccounts(*token.AccessToken, common.OrganizationID, map[string]interface{}{
+	subject_accounts, err := axiom.ListSubjectAccounts(*token.AccessToken, ctx.OrganizationID, map[string]interface{}{
 		"page": fmt.Sprintf("%d", page),
 		"rpp":  fmt.Sprintf("%d", rpp),
 	})
 	if err != nil {
 		log.Printf("failed to retrieve axiom subject accounts; %s", err.Error())
-		os.Exit(1)
+		common.Exit(1)
 	}
 	// fmt.Printf("subject accounts len: %v", len(subject_accounts))
 	for _, subject_account := range subject_accounts {
-		details, err := axiom.GetSubjectAccountDetails(*token.AccessToken, common.OrganizationID, *subject_account.ID, map[string]interface{}{})
+		details, err := axiom.GetSubjectAccountDetails(*token.AccessToken, ctx.OrganizationID, *subject_account.ID, map[string]interface{}{})
 		if err != nil {
 			log.Printf("failed to retrieve axiom subject accounts; %s", err.Error())
-			os.Exit(1)
+			common.Exit(1)
 		}
 
 		subject_account_wg, err := axiom.GetWorkgroupDetails(*token.AccessToken, *details.Metadata.WorkgroupID, map[string]interface{}{})
 		if err != nil {
 			log.Printf("failed to retrieve axiom subject accounts; %s", err.Error())
-			os.Exit(1)
+			common.Exit(1)
 		}
 
 		subject_account_org, err := ident.GetOrganizationDetails(*token.AccessToken, *details.Metadata.OrganizationID, map[string]interface{}{})
 		if err != nil {
 			log.Printf("failed to retrieve axiom subject accounts; %s", err.Error())
-			os.Exit(1)
+			common.Exit(1)
 		}
 
 		result := fmt.Sprintf("%s;\tworkgroup: %s\t%s;\torganization: %s\t%s\n", *subject_account.ID, subject_account_wg.ID, *subject_account_wg.Name, *subject_account_org.ID, *subject_account_org.Name)
@@ -82,7 +82,7 @@ func listSubjectAccountsRun(cmd *cobra.Command, args []string) {
 }
 
 func init() {
-	listBaselineSubjectAccountsCmd.Flags().StringVar(&common.OrganizationID, "organization", "", "organization identifier")
+	listBaselineSubjectAccountsCmd.Flags().String("organization", "", "organization identifier")
 
 	listBaselineSubjectAccountsCmd.Flags().Uint64Var(&page, "page", common.DefaultPage, "page number to retrieve")
 	listBaselineSubjectAccountsCmd.Flags().Uint64Var(&rpp, "rpp", common.DefaultRpp, "number of axiom subject accounts to retrieve per page")
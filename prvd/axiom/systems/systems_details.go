@@ -20,7 +20,6 @@ import (
 	"encoding/json"
 	"fmt"
 	"log"
-	"os"
 
 	"github.com/manifoldco/promptui"
 	"github.com/provideplatform/provide-cli/prvd/common"
@@ -45,45 +44,46 @@ func fetchSystemDetails(cmd *cobra.Command, args []string) {
 }
 
 func fetchSystemDetailsRun(cmd *cobra.Command, args []string) {
-	if err := common.RequireOrganization(); err != nil {
+	ctx := common.ContextFromCommand(cmd)
+	if err := common.RequireOrganization(ctx); err != nil {
 		fmt.Printf("failed to retrive system details; %s", err.Error())
-		os.Exit(1)
+		common.Exit(1)
 	}
 
-	if err := common.RequireWorkgroup(); err != nil {
+	if err := common.RequireWorkgroup(ctx); err != nil {
 		fmt.Printf("failed to retrive system details; %s", err.Error())
-		os.Exit(1)
+		common.Exit(1)
 	}
 
-	common.AuthorizeOrganizationContext(true)
+	common.AuthorizeOrganizationContext(ctx, true)
 
-	token, err := common.ResolveOrganizationToken()
+	token, err := common.ResolveOrganizationToken(ctx)
 	if err != nil {
 		log.Printf("failed to retrieve systems; %s", err.Error())
-		os.Exit(1)
+		common.Exit(1)
 	}
 
-	subjectAccountID := common.SHA256(fmt.Sprintf("%s.%s", common.OrganizationID, common.WorkgroupID))
-	sa, err := axiom.GetSubjectAccountDetails(*token.AccessToken, common.OrganizationID, subjectAccountID, map[string]interface{}{})
+	subjectAccountID := common.SHA256(fmt.Sprintf("%s.%s", ctx.OrganizationID, ctx.WorkgroupID))
+	sa, err := axiom.GetSubjectAccountDetails(*token.AccessToken, ctx.OrganizationID, subjectAccountID, map[string]interface{}{})
 	if err != nil {
 		fmt.Printf("failed to initialize system; %s", err.Error())
-		os.Exit(1)
+		common.Exit(1)
 	}
 
 	isOnboarded := sa.ID != nil
 	if !isOnboarded {
-		localVaultID := common.Organization.Metadata.Workgroups[common.Workgroup.ID].VaultID
-		localSystemIDs := common.Organization.Metadata.Workgroups[common.Workgroup.ID].SystemSecretIDs
+		localVaultID := ctx.Organization.Metadata.Workgroups[ctx.Workgroup.ID].VaultID
+		localSystemIDs := ctx.Organization.Metadata.Workgroups[ctx.Workgroup.ID].SystemSecretIDs
 
-		isOperator := common.Organization.Metadata.Workgroups[common.Workgroup.ID].OperatorSeparationDegree == 0
+		isOperator := ctx.Organization.Metadata.Workgroups[ctx.Workgroup.ID].OperatorSeparationDegree == 0
 		if isOperator {
-			localVaultID = common.Workgroup.Config.VaultID
-			localSystemIDs = common.Workgroup.Config.SystemSecretIDs
+			localVaultID = ctx.Workgroup.Config.VaultID
+			localSystemIDs = ctx.Workgroup.Config.SystemSecretIDs
 		}
 
 		if vaultID != "" && vaultID != localVaultID.String() {
 			fmt.Print("failed to retrieve system details; invalid vault id")
-			os.Exit(1)
+			common.Exit(1)
 		}
 
 		var system vault.Secret
@@ -95,7 +95,7 @@ func fetchSystemDetailsRun(cmd *cobra.Command, args []string) {
 			secret, err := vault.FetchSecret(*token.AccessToken, localVaultID.String(), secretID.String(), map[string]interface{}{})
 			if err != nil {
 				log.Printf("failed to retrieve systems; %s", err.Error())
-				os.Exit(1)
+				common.Exit(1)
 			}
 
 			secrets = append(secrets, secret)
@@ -111,7 +111,7 @@ func fetchSystemDetailsRun(cmd *cobra.Command, args []string) {
 
 			if system.VaultID == nil {
 				fmt.Print("failed to retrieve system details; invalid system id")
-				os.Exit(1)
+				common.Exit(1)
 			}
 		} else {
 			prompt := promptui.Select{
@@ -122,7 +122,7 @@ func fetchSystemDetailsRun(cmd *cobra.Command, args []string) {
 			i, _, err := prompt.Run()
 			if err != nil {
 				fmt.Printf("failed to retrieve system details; %s", err.Error())
-				os.Exit(1)
+				common.Exit(1)
 			}
 
 			system = *secrets[i]
@@ -132,16 +132,16 @@ func fetchSystemDetailsRun(cmd *cobra.Command, args []string) {
 		err = json.Unmarshal([]byte(*system.Value), &value)
 		if err != nil {
 			log.Printf("failed to retrieve system details; %s", err.Error())
-			os.Exit(1)
+			common.Exit(1)
 		}
 
 		result, _ := json.MarshalIndent(value, "", "\t")
 		fmt.Printf("%s\n", string(result))
 	} else {
-		systems, err := axiom.ListSystems(*token.AccessToken, common.WorkgroupID, map[string]interface{}{})
+		systems, err := axiom.ListSystems(*token.AccessToken, ctx.WorkgroupID, map[string]interface{}{})
 		if err != nil {
 			log.Printf("failed to retrieve systems; %s", err.Error())
-			os.Exit(1)
+			common.Exit(1)
 		}
 
 		systemOpts := make([]string, 0)
@@ -160,7 +160,7 @@ func fetchSystemDetailsRun(cmd *cobra.Command, args []string) {
 
 			if system == nil {
 				fmt.Print("failed to retrieve system details; invalid system id")
-				os.Exit(1)
+				common.Exit(1)
 			}
 		} else {
 			prompt := promptui.Select{
@@ -171,7 +171,7 @@ func fetchSystemDetailsRun(cmd *cobra.Command, args []string) {
 			i, _, err := prompt.Run()
 			if err != nil {
 				fmt.Printf("failed to retrieve system details; %s", err.Error())
-				os.Exit(1)
+				common.Exit(1)
 			}
 
 			system = systems[i]
@@ -183,8 +183,8 @@ func fetchSystemDetailsRun(cmd *cobra.Command, args []string) {
 }
 
 func init() {
-	detailBaselineSystemCmd.Flags().StringVar(&common.OrganizationID, "organization", "", "organization identifier")
-	detailBaselineSystemCmd.Flags().StringVar(&common.WorkgroupID, "workgroup", "", "workgroup identifier")
+	detailBaselineSystemCmd.Flags().String("organization", "", "organization identifier")
+	detailBaselineSystemCmd.Flags().String("workgroup", "", "workgroup identifier")
 	detailBaselineSystemCmd.Flags().StringVar(&vaultID, "vault", "", "vault identifier")
 	detailBaselineSystemCmd.Flags().StringVar(&systemID, "system", "", "system identifier")
 }
@@ -90,22 +90,23 @@ func initSystem(cmd *cobra.Command, args []string) {
 }
 
 func initSystemRun(cmd *cobra.Command, args []string) {
-	if common.OrganizationID == "" {
-		common.RequireOrganization()
+	ctx := common.ContextFromCommand(cmd)
+	if ctx.OrganizationID == "" {
+		common.RequireOrganization(ctx)
 	}
-	if common.WorkgroupID == "" {
-		common.RequireWorkgroup()
+	if ctx.WorkgroupID == "" {
+		common.RequireWorkgroup(ctx)
 	}
 	if systemType == "" {
 		systemTypePrompt()
 	}
 
-	common.AuthorizeOrganizationContext(true)
+	common.AuthorizeOrganizationContext(ctx, true)
 
-	token, err := common.ResolveOrganizationToken()
+	token, err := common.ResolveOrganizationToken(ctx)
 	if err != nil {
 		fmt.Printf("failed to initialize system; %s", err.Error())
-		os.Exit(1)
+		common.Exit(1)
 	}
 
 	var params map[string]interface{}
@@ -114,19 +115,19 @@ func initSystemRun(cmd *cobra.Command, args []string) {
 	vaults, err := vault.ListVaults(*token.AccessToken, map[string]interface{}{})
 	if err != nil {
 		fmt.Printf("failed to initialize system; %s", err.Error())
-		os.Exit(1)
+		common.Exit(1)
 	}
 
 	if len(vaults) == 0 {
 		fmt.Printf("failed to initialize system; workgroup must have a vault")
-		os.Exit(1)
+		common.Exit(1)
 	}
 
-	subjectAccountID := common.SHA256(fmt.Sprintf("%s.%s", common.OrganizationID, common.WorkgroupID))
-	sa, err := axiom.GetSubjectAccountDetails(*token.AccessToken, common.OrganizationID, subjectAccountID, map[string]interface{}{})
+	subjectAccountID := common.SHA256(fmt.Sprintf("%s.%s", ctx.OrganizationID, ctx.WorkgroupID))
+	sa, err := axiom.GetSubjectAccountDetails(*token.AccessToken, ctx.OrganizationID, subjectAccountID, map[string]interface{}{})
 	if err != nil {
 		fmt.Printf("failed to initialize system; %s", err.Error())
-		os.Exit(1)
+		common.Exit(1)
 	}
 
 	isOnboarded := sa.ID != nil
@@ -142,46 +143,46 @@ func initSystemRun(cmd *cobra.Command, args []string) {
 		secret, err := vault.CreateSecret(*token.AccessToken, vaults[0].ID.String(), secretParams)
 		if err != nil {
 			fmt.Printf("failed to initialize system; %s", err.Error())
-			os.Exit(1)
+			common.Exit(1)
 		}
 
-		isOperator := common.Organization.Metadata.Workgroups[common.Workgroup.ID].OperatorSeparationDegree == 0
+		isOperator := ctx.Organization.Metadata.Workgroups[ctx.Workgroup.ID].OperatorSeparationDegree == 0
 		if isOperator {
-			if common.Workgroup.Config.SystemSecretIDs == nil {
-				common.Workgroup.Config.SystemSecretIDs = make([]*uuid.UUID, 0)
+			if ctx.Workgroup.Config.SystemSecretIDs == nil {
+				ctx.Workgroup.Config.SystemSecretIDs = make([]*uuid.UUID, 0)
 			}
 
-			common.Workgroup.Config.SystemSecretIDs = append(common.Workgroup.Config.SystemSecretIDs, &secret.ID)
+			ctx.Workgroup.Config.SystemSecretIDs = append(ctx.Workgroup.Config.SystemSecretIDs, &secret.ID)
 
 			var wgInterface map[string]interface{}
-			raw, _ := json.Marshal(common.Workgroup)
+			raw, _ := json.Marshal(ctx.Workgroup)
 			json.Unmarshal(raw, &wgInterface)
 
-			if err := axiom.UpdateWorkgroup(*token.AccessToken, common.Workgroup.ID.String(), wgInterface); err != nil {
+			if err := axiom.UpdateWorkgroup(*token.AccessToken, ctx.Workgroup.ID.String(), wgInterface); err != nil {
 				fmt.Printf("failed to initialize system; %s", err.Error())
-				os.Exit(1)
+				common.Exit(1)
 			}
 		}
 
-		common.Organization.Metadata.Workgroups[common.Workgroup.ID].SystemSecretIDs = append(common.Organization.Metadata.Workgroups[common.Workgroup.ID].SystemSecretIDs, &secret.ID)
+		ctx.Organization.Metadata.Workgroups[ctx.Workgroup.ID].SystemSecretIDs = append(ctx.Organization.Metadata.Workgroups[ctx.Workgroup.ID].SystemSecretIDs, &secret.ID)
 
 		var orgInterface map[string]interface{}
-		raw, _ = json.Marshal(common.Organization)
+		raw, _ = json.Marshal(ctx.Organization)
 		json.Unmarshal(raw, &orgInterface)
 
-		if err := ident.UpdateOrganization(*token.AccessToken, *common.Organization.ID, orgInterface); err != nil {
+		if err := ident.UpdateOrganization(*token.AccessToken, *ctx.Organization.ID, orgInterface); err != nil {
 			fmt.Printf("failed to initialize system; %s", err.Error())
-			os.Exit(1)
+			common.Exit(1)
 		}
 
 		result, _ := json.MarshalIndent(secret, "", "\t")
 		fmt.Printf("system secret: %s\n", string(result))
 	} else {
 		params["vault_id"] = vaults[0].ID
-		system, err := axiom.CreateSystem(*token.AccessToken, common.WorkgroupID, params)
+		system, err := axiom.CreateSystem(*token.AccessToken, ctx.WorkgroupID, params)
 		if err != nil {
 			fmt.Printf("failed to initialize system; %s", err.Error())
-			os.Exit(1)
+			common.Exit(1)
 		}
 
 		result, _ := json.MarshalIndent(system, "", "\t")
@@ -202,7 +203,7 @@ func systemTypePrompt() {
 	i, _, err := prompt.Run()
 	if err != nil {
 		fmt.Printf("failed to initialize system; %s", err.Error())
-		os.Exit(1)
+		common.Exit(1)
 	}
 
 	systemType = systemTypes[i]
@@ -224,7 +225,7 @@ func systemPrompt(params *map[string]interface{}, token string) {
 		i, _, err := prompt.Run()
 		if err != nil {
 			fmt.Printf("failed to initialize system; %s", err.Error())
-			os.Exit(1)
+			common.Exit(1)
 		}
 
 		systemMiddlewareType = middlewareTypes[i]
@@ -263,7 +264,7 @@ func systemPrompt(params *map[string]interface{}, token string) {
 
 		if err := axiom.SystemReachability(token, reachabilityParams); err != nil {
 			fmt.Printf("failed to initialize system; %s", err.Error())
-			os.Exit(1)
+			common.Exit(1)
 		}
 
 		system := map[string]interface{}{
@@ -306,7 +307,7 @@ func systemPrompt(params *map[string]interface{}, token string) {
 
 		if err := axiom.SystemReachability(token, reachabilityParams); err != nil {
 			fmt.Printf("failed to initialize system; %s", err.Error())
-			os.Exit(1)
+			common.Exit(1)
 		}
 
 		system := map[string]interface{}{
@@ -354,7 +355,7 @@ func systemPrompt(params *map[string]interface{}, token string) {
 
 		if err := axiom.SystemReachability(token, reachabilityParams); err != nil {
 			fmt.Printf("failed to initialize system; %s", err.Error())
-			os.Exit(1)
+			common.Exit(1)
 		}
 
 		system := map[string]interface{}{
@@ -402,7 +403,7 @@ func systemPrompt(params *map[string]interface{}, token string) {
 
 		if err := axiom.SystemReachability(token, reachabilityParams); err != nil {
 			fmt.Printf("failed to initialize system; %s", err.Error())
-			os.Exit(1)
+			common.Exit(1)
 		}
 
 		systemOutboundOnlyPrompt()
@@ -432,7 +433,7 @@ func systemPrompt(params *map[string]interface{}, token string) {
 
 		if err := axiom.SystemReachability(token, reachabilityParams); err != nil {
 			fmt.Printf("failed to initialize system; %s", err.Error())
-			os.Exit(1)
+			common.Exit(1)
 		}
 
 		system := map[string]interface{}{
@@ -471,7 +472,7 @@ func systemNamePrompt() {
 
 		result, err := prompt.Run()
 		if err != nil {
-			os.Exit(1)
+			common.Exit(1)
 			return
 		}
 
@@ -487,7 +488,7 @@ func systemDescriptionPrompt() {
 
 		result, err := prompt.Run()
 		if err != nil {
-			os.Exit(1)
+			common.Exit(1)
 			return
 		}
 
@@ -515,7 +516,7 @@ func systemNoMiddlewarePrompt() {
 		result, err := prompt.Run()
 		if err != nil {
 			fmt.Printf("failed to initialize axiom domain model; %s", err.Error())
-			os.Exit(1)
+			common.Exit(1)
 		}
 
 		systemEndpointURL = result
@@ -536,7 +537,7 @@ func systemInboundOnlyPrompt() {
 		i, _, err := prompt.Run()
 		if err != nil {
 			fmt.Printf("failed to initialize system; %s", err.Error())
-			os.Exit(1)
+			common.Exit(1)
 		}
 
 		systemInboundMiddleware = middlewareOpts[i]
@@ -550,7 +551,7 @@ func systemInboundOnlyPrompt() {
 
 		if !isValid {
 			fmt.Print("failed to initialize system; invalid system inbound middleware type")
-			os.Exit(1)
+			common.Exit(1)
 		}
 	}
 
@@ -573,14 +574,14 @@ func systemInboundOnlyPrompt() {
 		result, err := prompt.Run()
 		if err != nil {
 			fmt.Printf("failed to initialize system; %s", err.Error())
-			os.Exit(1)
+			common.Exit(1)
 		}
 
 		systemInboundEndpointURL = result
 	} else {
 		if _, err := url.ParseRequestURI(systemInboundEndpointURL); err != nil {
 			fmt.Printf("failed to initialize system; %s", err.Error())
-			os.Exit(1)
+			common.Exit(1)
 		}
 	}
 }
@@ -599,7 +600,7 @@ func systemOutboundOnlyPrompt() {
 		i, _, err := prompt.Run()
 		if err != nil {
 			fmt.Printf("failed to initialize system; %s", err.Error())
-			os.Exit(1)
+			common.Exit(1)
 		}
 
 		systemOutboundMiddleware = middlewareOpts[i]
@@ -613,7 +614,7 @@ func systemOutboundOnlyPrompt() {
 
 		if !isValid {
 			fmt.Print("failed to initialize system; invalid system outbound middleware type")
-			os.Exit(1)
+			common.Exit(1)
 		}
 	}
 
@@ -636,14 +637,14 @@ func systemOutboundOnlyPrompt() {
 		result, err := prompt.Run()
 		if err != nil {
 			fmt.Printf("failed to initialize system; %s", err.Error())
-			os.Exit(1)
+			common.Exit(1)
 		}
 
 		systemOutboundEndpointURL = result
 	} else {
 		if _, err := url.ParseRequestURI(systemOutboundEndpointURL); err != nil {
 			fmt.Printf("failed to initialize system; %s", err.Error())
-			os.Exit(1)
+			common.Exit(1)
 		}
 	}
 }
@@ -661,7 +662,7 @@ func systemAuthMethodsPrompt(method, username, password *string) {
 		i, _, err := prompt.Run()
 		if err != nil {
 			fmt.Printf("failed to initialize system; %s", err.Error())
-			os.Exit(1)
+			common.Exit(1)
 		}
 
 		*method = authMethods[i]
@@ -675,7 +676,7 @@ func systemAuthMethodsPrompt(method, username, password *string) {
 
 		if !isValid {
 			fmt.Print("failed to initialize system; invalid system authentication method")
-			os.Exit(1)
+			common.Exit(1)
 		}
 	}
 
@@ -688,7 +689,7 @@ func systemAuthMethodsPrompt(method, username, password *string) {
 		result, err := prompt.Run()
 		if err != nil {
 			fmt.Printf("failed to initialize system; %s", err.Error())
-			os.Exit(1)
+			common.Exit(1)
 		}
 
 		*username = result
@@ -704,7 +705,7 @@ func systemAuthMethodsPrompt(method, username, password *string) {
 		result, err := prompt.Run()
 		if err != nil {
 			fmt.Printf("failed to initialize system; %s", err.Error())
-			os.Exit(1)
+			common.Exit(1)
 		}
 
 		*password = result
@@ -735,7 +736,7 @@ func systemClientCredentialsPrompt(requireCredentials *bool, clientID, clientSec
 		result, err := prompt.Run()
 		if err != nil {
 			fmt.Printf("failed to initialize system; %s", err.Error())
-			os.Exit(1)
+			common.Exit(1)
 		}
 
 		*clientID = result
@@ -751,7 +752,7 @@ func systemClientCredentialsPrompt(requireCredentials *bool, clientID, clientSec
 		result, err := prompt.Run()
 		if err != nil {
 			fmt.Printf("failed to initialize system; %s", err.Error())
-			os.Exit(1)
+			common.Exit(1)
 		}
 
 		*clientSecret = result
@@ -759,9 +760,10 @@ func systemClientCredentialsPrompt(requireCredentials *bool, clientID, clientSec
 }
 
 func init() {
-	initBaselineSystemCmd.Flags().StringVar(&common.OrganizationID, "organization", os.Getenv("PROVIDE_ORGANIZATION_ID"), "organization identifier")
-	initBaselineSystemCmd.Flags().StringVar(&common.WorkgroupID, "workgroup", "", "workgroup identifier")
+	initBaselineSystemCmd.Flags().String("organization", os.Getenv("PROVIDE_ORGANIZATION_ID"), "organization identifier")
+	initBaselineSystemCmd.Flags().String("workgroup", "", "workgroup identifier")
 	initBaselineSystemCmd.Flags().StringVar(&systemType, "system-type", "", "system type")
+	common.SetFlagValues(initBaselineSystemCmd, "system-type", sapSystemIdentifier, servicenowSystemIdentifier)
 
 	initBaselineSystemCmd.Flags().StringVar(&systemName, "name", "", "name")
 	initBaselineSystemCmd.Flags().StringVar(&systemDescription, "description", "", "description")
@@ -20,7 +20,6 @@ import (
 	"encoding/json"
 	"fmt"
 	"log"
-	"os"
 
 	"github.com/provideplatform/provide-cli/prvd/common"
 	"github.com/provideplatform/provide-go/api/axiom"
@@ -43,37 +42,38 @@ func listSystems(cmd *cobra.Command, args []string) {
 }
 
 func listSystemsRun(cmd *cobra.Command, args []string) {
-	if common.OrganizationID == "" {
-		common.RequireOrganization()
+	ctx := common.ContextFromCommand(cmd)
+	if ctx.OrganizationID == "" {
+		common.RequireOrganization(ctx)
 	}
-	if common.WorkgroupID == "" {
-		common.RequireWorkgroup()
+	if ctx.WorkgroupID == "" {
+		common.RequireWorkgroup(ctx)
 	}
 
-	common.AuthorizeOrganizationContext(true)
+	common.AuthorizeOrganizationContext(ctx, true)
 
-	token, err := common.ResolveOrganizationToken()
+	token, err := common.ResolveOrganizationToken(ctx)
 	if err != nil {
 		fmt.Printf("failed to initialize system; %s", err.Error())
-		os.Exit(1)
+		common.Exit(1)
 	}
 
-	subjectAccountID := common.SHA256(fmt.Sprintf("%s.%s", common.OrganizationID, common.WorkgroupID))
-	sa, err := axiom.GetSubjectAccountDetails(*token.AccessToken, common.OrganizationID, subjectAccountID, map[string]interface{}{})
+	subjectAccountID := common.SHA256(fmt.Sprintf("%s.%s", ctx.OrganizationID, ctx.WorkgroupID))
+	sa, err := axiom.GetSubjectAccountDetails(*token.AccessToken, ctx.OrganizationID, subjectAccountID, map[string]interface{}{})
 	if err != nil {
 		fmt.Printf("failed to initialize system; %s", err.Error())
-		os.Exit(1)
+		common.Exit(1)
 	}
 
 	isOnboarded := sa.ID != nil
 	if !isOnboarded {
-		vaultID := common.Organization.Metadata.Workgroups[common.Workgroup.ID].VaultID
-		systemIDs := common.Organization.Metadata.Workgroups[common.Workgroup.ID].SystemSecretIDs
+		vaultID := ctx.Organization.Metadata.Workgroups[ctx.Workgroup.ID].VaultID
+		systemIDs := ctx.Organization.Metadata.Workgroups[ctx.Workgroup.ID].SystemSecretIDs
 
-		isOperator := common.Organization.Metadata.Workgroups[common.Workgroup.ID].OperatorSeparationDegree == 0
+		isOperator := ctx.Organization.Metadata.Workgroups[ctx.Workgroup.ID].OperatorSeparationDegree == 0
 		if isOperator {
-			vaultID = common.Workgroup.Config.VaultID
-			systemIDs = common.Workgroup.Config.SystemSecretIDs
+			vaultID = ctx.Workgroup.Config.VaultID
+			systemIDs = ctx.Workgroup.Config.SystemSecretIDs
 		}
 
 		secrets := make([]*vault.Secret, 0)
@@ -82,7 +82,7 @@ func listSystemsRun(cmd *cobra.Command, args []string) {
 			secret, err := vault.FetchSecret(*token.AccessToken, vaultID.String(), secretID.String(), map[string]interface{}{})
 			if err != nil {
 				log.Printf("failed to retrieve systems; %s", err.Error())
-				os.Exit(1)
+				common.Exit(1)
 			}
 			secrets = append(secrets, secret)
 		}
@@ -97,17 +97,17 @@ func listSystemsRun(cmd *cobra.Command, args []string) {
 			err := json.Unmarshal([]byte(*secret.Value), &value)
 			if err != nil {
 				log.Printf("failed to retrieve systems; %s", err.Error())
-				os.Exit(1)
+				common.Exit(1)
 			}
 
 			result, _ := json.MarshalIndent(value, "", "\t")
 			fmt.Printf("%s\n", string(result))
 		}
 	} else {
-		systems, err := axiom.ListSystems(*token.AccessToken, common.WorkgroupID, map[string]interface{}{})
+		systems, err := axiom.ListSystems(*token.AccessToken, ctx.WorkgroupID, map[string]interface{}{})
 		if err != nil {
 			log.Printf("failed to retrieve systems; %s", err.Error())
-			os.Exit(1)
+			common.Exit(1)
 		}
 
 		for _, system := range systems {
@@ -118,8 +118,8 @@ func listSystemsRun(cmd *cobra.Command, args []string) {
 }
 
 func init() {
-	listBaselineSystemsCmd.Flags().StringVar(&common.OrganizationID, "organization", "", "organization identifier")
-	listBaselineSystemsCmd.Flags().StringVar(&common.WorkgroupID, "workgroup", "", "workgroup identifier")
+	listBaselineSystemsCmd.Flags().String("organization", "", "organization identifier")
+	listBaselineSystemsCmd.Flags().String("workgroup", "", "workgroup identifier")
 
 	listBaselineSystemsCmd.Flags().Uint64Var(&page, "page", common.DefaultPage, "page number to retrieve")
 	listBaselineSystemsCmd.Flags().Uint64Var(&rpp, "rpp", common.DefaultRpp, "number of axiom workgroups to retrieve per page")
@@ -18,8 +18,8 @@ package messages
 
 import (
 	"log"
-	"os"
 
+	"github.com/provideplatform/provide-cli/prvd/common"
 	"github.com/spf13/cobra"
 )
 
@@ -32,7 +32,7 @@ var listBaselineMessagesCmd = &cobra.Command{
 
 func listMessages(cmd *cobra.Command, args []string) {
 	log.Printf("not implemented")
-	os.Exit(1)
+	common.Exit(1)
 }
 
 func init() {
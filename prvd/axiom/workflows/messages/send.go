@@ -18,8 +18,8 @@ package messages
 
 import (
 	"encoding/json"
+	"fmt"
 	"log"
-	"os"
 	"strings"
 
 	"github.com/provideplatform/provide-cli/prvd/common"
@@ -31,9 +31,11 @@ import (
 var axiomAPIEndpoint string
 var axiomID string
 var data string
+var edit bool
 var id string
 var messageType string
 var recipients string
+var workflowID string
 var Optional bool
 
 var sendBaselineMessageCmd = &cobra.Command{
@@ -48,11 +50,12 @@ func sendMessage(cmd *cobra.Command, args []string) {
 }
 
 func sendMessageRun(cmd *cobra.Command, args []string) {
-	if common.OrganizationID == "" {
-		common.RequireOrganization()
+	ctx := common.ContextFromCommand(cmd)
+	if ctx.OrganizationID == "" {
+		common.RequireOrganization(ctx)
 	}
-	if common.WorkgroupID == "" {
-		common.RequireWorkgroup()
+	if ctx.WorkgroupID == "" {
+		common.RequireWorkgroup(ctx)
 	}
 	if messageType == "" {
 		opts := make([]string, 0)
@@ -68,23 +71,52 @@ func sendMessageRun(cmd *cobra.Command, args []string) {
 	if axiomID == "" {
 		axiomID = common.FreeInput("Baseline ID", "", common.NoValidation)
 	}
-	if data == "" {
-		data = common.FreeInput("Data", "", common.JSONValidation)
+
+	var err error
+	if data != "" {
+		data, err = common.ReadJSONInput(data)
+		if err != nil {
+			log.Printf("WARNING: failed to send axiom message; %s", err.Error())
+			common.Exit(1)
+		}
 	}
 
-	common.AuthorizeOrganizationContext(true)
+	common.AuthorizeOrganizationContext(ctx, true)
 
-	token, err := common.ResolveOrganizationToken()
+	token, err := common.ResolveOrganizationToken(ctx)
 	if err != nil {
 		log.Printf("WARNING: failed to send axiom message; %s", err.Error())
-		os.Exit(1)
+		common.Exit(1)
+	}
+
+	if edit {
+		template := data
+		if template == "" {
+			if workflowID == "" {
+				workflowID = common.FreeInput("Workflow ID", "", common.MandatoryValidation)
+			}
+			template = payloadTemplate(*token.AccessToken, workflowID)
+		}
+		header := fmt.Sprintf("%s message %s payload; lines beginning with # are ignored and an empty payload aborts", messageType, id)
+		data, err = common.EditJSON(header, template, common.JSONValidation)
+		if err != nil {
+			log.Printf("WARNING: failed to send axiom message; %s", err.Error())
+			common.Exit(1)
+		}
+	} else if data == "" {
+		data = common.FreeInput("Data", "", common.JSONValidation)
+	}
+
+	if err := common.JSONValidation(data); err != nil {
+		log.Printf("WARNING: failed to send axiom message; %s", err.Error())
+		common.Exit(1)
 	}
 
 	var payload map[string]interface{}
 	err = json.Unmarshal([]byte(data), &payload)
 	if err != nil {
 		log.Printf("WARNING: failed to send axiom message; %s", err.Error())
-		os.Exit(1)
+		common.Exit(1)
 	}
 
 	params := map[string]interface{}{
@@ -98,12 +130,12 @@ func sendMessageRun(cmd *cobra.Command, args []string) {
 	if recipients != "" {
 		_recipients := make([]*axiom.Participant, 0)
 		for _, id := range strings.Split(recipients, ",") {
-			orgs, err := ident.ListApplicationOrganizations(*token.AccessToken, common.ApplicationID, map[string]interface{}{
+			orgs, err := ident.ListApplicationOrganizations(*token.AccessToken, ctx.WorkgroupID, map[string]interface{}{
 				"organization_id": id,
 			})
 			if err != nil {
 				log.Printf("WARNING: failed to send message message data as JSON; %s", err.Error())
-				os.Exit(1)
+				common.Exit(1)
 			}
 			for _, org := range orgs {
 				if addr, addrOk := org.Metadata["address"].(string); addrOk {
@@ -120,11 +152,11 @@ func sendMessageRun(cmd *cobra.Command, args []string) {
 	axiomdRecord, err := axiom.SendProtocolMessage(*token.AccessToken, params)
 	if err != nil {
 		log.Printf("WARNING: failed to axiom %d-byte payload; %s", len(data), err.Error())
-		os.Exit(1)
+		common.Exit(1)
 	}
 
 	log.Printf("axiomd record: %v", axiomdRecord.(map[string]interface{})["axiom_id"].(string))
-	if common.Verbose {
+	if ctx.Verbose {
 		raw, _ := json.MarshalIndent(axiomdRecord, "", "  ")
 		log.Printf(string(raw))
 	}
@@ -135,7 +167,9 @@ func init() {
 
 	sendBaselineMessageCmd.Flags().StringVar(&axiomID, "axiom-id", "", "the globally-unique axiom identifier for the record")
 
-	sendBaselineMessageCmd.Flags().StringVar(&data, "data", "", "content of the message")
+	sendBaselineMessageCmd.Flags().StringVar(&data, "data", "", "content of the message; use @- to read from stdin or @path to read from a file")
+	sendBaselineMessageCmd.Flags().BoolVar(&edit, common.EditFlag, false, "edit the content of the message using $EDITOR")
+	sendBaselineMessageCmd.Flags().StringVar(&workflowID, "workflow", "", "identifier of the workflow whose domain model templates the content of the message when editing")
 	// sendBaselineMessageCmd.MarkFlagRequired("data")
 
 	sendBaselineMessageCmd.Flags().StringVar(&id, "id", "", "identifier of the associated payload in the internal system of record")
@@ -144,9 +178,69 @@ func init() {
 	sendBaselineMessageCmd.Flags().StringVar(&messageType, "type", "", "type of the payload to be axiomd")
 	// sendBaselineMessageCmd.Flags().StringVar(&recipients, "recipients", "", "comma-delimited list of recipient organization ids")
 
-	sendBaselineMessageCmd.Flags().StringVar(&common.OrganizationID, "organization", "", "organization identifier")
+	sendBaselineMessageCmd.Flags().String("organization", "", "organization identifier")
 	// sendBaselineMessageCmd.MarkFlagRequired("organization")
 
-	sendBaselineMessageCmd.Flags().StringVar(&common.ApplicationID, "workgroup", "", "workgroup identifier")
+	sendBaselineMessageCmd.Flags().String("workgroup", "", "workgroup identifier")
 	//sendBaselineMessageCmd.MarkFlagRequired("workgroup")
 }
+
+// payloadTemplate returns a JSON payload template derived from the fields of the domain model of
+// the given workflow; the model is referenced by the "model" workflow metadata, or else is the
+// model of the workflow's workgroup matching the message type
+func payloadTemplate(token, workflowID string) string {
+	workflow, err := axiom.GetWorkflowDetails(token, workflowID, map[string]interface{}{})
+	if err != nil {
+		log.Printf("WARNING: failed to resolve workflow %s; %s", workflowID, err.Error())
+		return "{}"
+	}
+	if workflow.WorkgroupID == nil {
+		log.Printf("WARNING: workflow %s is not associated with a workgroup", workflowID)
+		return "{}"
+	}
+
+	ref := messageType
+	if workflow.Metadata != nil {
+		var metadata map[string]interface{}
+		json.Unmarshal(*workflow.Metadata, &metadata)
+		if model, ok := metadata["model"].(string); ok && model != "" {
+			ref = model
+		}
+	}
+
+	mappings, err := axiom.ListMappings(token, map[string]interface{}{
+		"workgroup_id": workflow.WorkgroupID.String(),
+	})
+	if err != nil {
+		log.Printf("WARNING: failed to resolve domain models; %s", err.Error())
+		return "{}"
+	}
+
+	var model *axiom.MappingModel
+	for _, mapping := range mappings {
+		for _, m := range mapping.Models {
+			if m.ID.String() == ref || (m.Type != nil && *m.Type == ref) {
+				model = m
+			}
+		}
+	}
+	if model == nil {
+		log.Printf("WARNING: domain model %s of workflow %s not found", ref, workflowID)
+		return "{}"
+	}
+
+	payload := map[string]interface{}{}
+	for _, field := range model.Fields {
+		switch {
+		case field.DefaultValue != nil:
+			payload[field.Name] = field.DefaultValue
+		case strings.EqualFold(field.Type, "number"):
+			payload[field.Name] = 0
+		default:
+			payload[field.Name] = ""
+		}
+	}
+
+	raw, _ := json.Marshal(payload)
+	return string(raw)
+}
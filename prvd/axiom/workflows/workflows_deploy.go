@@ -39,40 +39,41 @@ func deployWorkflow(cmd *cobra.Command, args []string) {
 }
 
 func deployWorkflowRun(cmd *cobra.Command, args []string) {
-	if common.OrganizationID == "" {
-		common.RequireOrganization()
+	ctx := common.ContextFromCommand(cmd)
+	if ctx.OrganizationID == "" {
+		common.RequireOrganization(ctx)
 	}
-	if common.WorkgroupID == "" {
-		common.RequireWorkgroup()
+	if ctx.WorkgroupID == "" {
+		common.RequireWorkgroup(ctx)
 	}
 
-	token, err := common.ResolveOrganizationToken()
+	token, err := common.ResolveOrganizationToken(ctx)
 	if err != nil {
 		log.Printf("failed to deploy workflow; %s", err.Error())
-		os.Exit(1)
+		common.Exit(1)
 	}
 	if workflowID == "" {
-		workflowPrompt(*token.AccessToken)
+		workflowPrompt(ctx, *token.AccessToken)
 	}
 
 	w, err := axiom.GetWorkflowDetails(*token.AccessToken, workflowID, map[string]interface{}{})
 	if err != nil {
 		log.Printf("failed to deploy workflow; %s", err.Error())
-		os.Exit(1)
+		common.Exit(1)
 	}
 	if w.WorkflowID != nil {
 		log.Print("failed to deploy workflow; cannot deploy a workflow instance")
-		os.Exit(1)
+		common.Exit(1)
 	}
 	if *w.Status != "draft" {
 		log.Print("failed to deploy workflow; cannot deploy a non-draft instance")
-		os.Exit(1)
+		common.Exit(1)
 	}
 
 	ws, err := axiom.ListWorksteps(*token.AccessToken, workflowID, map[string]interface{}{})
 	if err != nil {
 		log.Printf("failed to deploy workflow; %s", err.Error())
-		os.Exit(1)
+		common.Exit(1)
 	}
 
 	hasFinality := false
@@ -83,7 +84,7 @@ func deployWorkflowRun(cmd *cobra.Command, args []string) {
 
 		if metadata["prover"] == nil {
 			log.Printf("failed to deploy workflow; all worksteps must have a prover")
-			os.Exit(1)
+			common.Exit(1)
 		}
 
 		if workstep.RequireFinality {
@@ -93,13 +94,13 @@ func deployWorkflowRun(cmd *cobra.Command, args []string) {
 
 	if !hasFinality {
 		log.Printf("failed to deploy workflow; at least 1 workstep must require finality")
-		os.Exit(1)
+		common.Exit(1)
 	}
 
 	deployed, err := axiom.DeployWorkflow(*token.AccessToken, workflowID, map[string]interface{}{})
 	if err != nil {
 		fmt.Printf("failed to deploy workflow; %s", err.Error())
-		os.Exit(1)
+		common.Exit(1)
 	}
 
 	// wait til status is deployed ?
@@ -109,8 +110,8 @@ func deployWorkflowRun(cmd *cobra.Command, args []string) {
 }
 
 func init() {
-	deployBaselineWorkflowCmd.Flags().StringVar(&common.OrganizationID, "organization", os.Getenv("PROVIDE_ORGANIZATION_ID"), "organization identifier")
-	deployBaselineWorkflowCmd.Flags().StringVar(&common.WorkgroupID, "workgroup", "", "workgroup identifier")
+	deployBaselineWorkflowCmd.Flags().String("organization", os.Getenv("PROVIDE_ORGANIZATION_ID"), "organization identifier")
+	deployBaselineWorkflowCmd.Flags().String("workgroup", "", "workgroup identifier")
 	deployBaselineWorkflowCmd.Flags().StringVar(&workflowID, "workflow", "", "workflow identifier")
 
 	deployBaselineWorkflowCmd.Flags().BoolVarP(&Optional, "optional", "", false, "List all the Optional flags")
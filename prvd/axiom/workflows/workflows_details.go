@@ -20,7 +20,6 @@ import (
 	"encoding/json"
 	"fmt"
 	"log"
-	"os"
 
 	"github.com/manifoldco/promptui"
 	"github.com/provideplatform/provide-cli/prvd/common"
@@ -43,48 +42,49 @@ func fetchWorkflowDetails(cmd *cobra.Command, args []string) {
 }
 
 func fetchWorkflowDetailsRun(cmd *cobra.Command, args []string) {
-	if err := common.RequireOrganization(); err != nil {
+	ctx := common.ContextFromCommand(cmd)
+	if err := common.RequireOrganization(ctx); err != nil {
 		fmt.Printf("failed to retrive workflow details; %s", err.Error())
-		os.Exit(1)
+		common.Exit(1)
 	}
 
-	if err := common.RequireWorkgroup(); err != nil {
+	if err := common.RequireWorkgroup(ctx); err != nil {
 		fmt.Printf("failed to retrive workflow details; %s", err.Error())
-		os.Exit(1)
+		common.Exit(1)
 	}
 
-	token, err := common.ResolveOrganizationToken()
+	token, err := common.ResolveOrganizationToken(ctx)
 	if err != nil {
 		log.Printf("failed to retrieve workflow details; %s", err.Error())
-		os.Exit(1)
+		common.Exit(1)
 	}
 
 	if workflowID == "" {
-		workflowPrompt(*token.AccessToken)
+		workflowPrompt(ctx, *token.AccessToken)
 	}
 
 	w, err := axiom.GetWorkflowDetails(*token.AccessToken, workflowID, map[string]interface{}{})
 	if err != nil {
 		log.Printf("failed to retrieve workflow details; %s", err.Error())
-		os.Exit(1)
+		common.Exit(1)
 	}
 
 	result, _ := json.MarshalIndent(w, "", "\t")
 	fmt.Printf("%s\n", string(result))
 }
 
-func workflowPrompt(token string) {
+func workflowPrompt(ctx *common.Context, token string) {
 	workflows, err := axiom.ListWorkflows(token, map[string]interface{}{
-		"workgroup_id": common.WorkgroupID,
+		"workgroup_id": ctx.WorkgroupID,
 	})
 	if err != nil {
 		fmt.Printf("failed to retrieve workflow details; %s", err.Error())
-		os.Exit(1)
+		common.Exit(1)
 	}
 
 	if len(workflows) == 0 {
 		fmt.Print("No workflows found\n")
-		os.Exit(1)
+		common.Exit(1)
 	}
 
 	opts := make([]string, 0)
@@ -101,14 +101,14 @@ func workflowPrompt(token string) {
 	i, _, err := prompt.Run()
 	if err != nil {
 		fmt.Printf("failed to retrieve workflow details; %s", err.Error())
-		os.Exit(1)
+		common.Exit(1)
 	}
 
 	workflowID = workflows[i].ID.String()
 }
 
 func init() {
-	detailBaselineWorkflowCmd.Flags().StringVar(&common.OrganizationID, "organization", "", "organization identifier")
-	detailBaselineWorkflowCmd.Flags().StringVar(&common.WorkgroupID, "workgroup", "", "workgroup identifier")
+	detailBaselineWorkflowCmd.Flags().String("organization", "", "organization identifier")
+	detailBaselineWorkflowCmd.Flags().String("workgroup", "", "workgroup identifier")
 	detailBaselineWorkflowCmd.Flags().StringVar(&workflowID, "workflow", "", "workflow identifier")
 }
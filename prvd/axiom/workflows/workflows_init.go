@@ -46,11 +46,12 @@ func initWorkflow(cmd *cobra.Command, args []string) {
 }
 
 func initWorkflowRun(cmd *cobra.Command, args []string) {
-	if common.OrganizationID == "" {
-		common.RequireOrganization()
+	ctx := common.ContextFromCommand(cmd)
+	if ctx.OrganizationID == "" {
+		common.RequireOrganization(ctx)
 	}
-	if common.WorkgroupID == "" {
-		common.RequireWorkgroup()
+	if ctx.WorkgroupID == "" {
+		common.RequireWorkgroup(ctx)
 	}
 	if name == "" {
 		namePrompt()
@@ -63,20 +64,20 @@ func initWorkflowRun(cmd *cobra.Command, args []string) {
 	} else {
 		if _, err := semver.Make(version); err != nil {
 			fmt.Printf("failed to initialize workflow; %s", err.Error())
-			os.Exit(1)
+			common.Exit(1)
 		}
 	}
 
-	common.AuthorizeOrganizationContext(true)
+	common.AuthorizeOrganizationContext(ctx, true)
 
-	token, err := common.ResolveOrganizationToken()
+	token, err := common.ResolveOrganizationToken(ctx)
 	if err != nil {
 		fmt.Printf("failed to initialize workflow; %s", err.Error())
-		os.Exit(1)
+		common.Exit(1)
 	}
 
 	params := map[string]interface{}{
-		"workgroup_id": common.WorkgroupID,
+		"workgroup_id": ctx.WorkgroupID,
 		"name":         name,
 		"version":      version,
 	}
@@ -88,7 +89,7 @@ func initWorkflowRun(cmd *cobra.Command, args []string) {
 	w, err := axiom.CreateWorkflow(*token.AccessToken, params)
 	if err != nil {
 		fmt.Printf("failed to initialize workflow; %s", err.Error())
-		os.Exit(1)
+		common.Exit(1)
 	}
 
 	result, _ := json.MarshalIndent(w, "", "\t")
@@ -109,7 +110,7 @@ func namePrompt() {
 
 	result, err := prompt.Run()
 	if err != nil {
-		os.Exit(1)
+		common.Exit(1)
 		return
 	}
 
@@ -123,7 +124,7 @@ func descriptionPrompt() {
 
 	result, err := prompt.Run()
 	if err != nil {
-		os.Exit(1)
+		common.Exit(1)
 		return
 	}
 
@@ -149,7 +150,7 @@ func versionPrompt() {
 
 	result, err := prompt.Run()
 	if err != nil {
-		os.Exit(1)
+		common.Exit(1)
 		return
 	}
 
@@ -157,8 +158,8 @@ func versionPrompt() {
 }
 
 func init() {
-	initBaselineWorkflowCmd.Flags().StringVar(&common.OrganizationID, "organization", os.Getenv("PROVIDE_ORGANIZATION_ID"), "organization identifier")
-	initBaselineWorkflowCmd.Flags().StringVar(&common.WorkgroupID, "workgroup", "", "workgroup identifier")
+	initBaselineWorkflowCmd.Flags().String("organization", os.Getenv("PROVIDE_ORGANIZATION_ID"), "organization identifier")
+	initBaselineWorkflowCmd.Flags().String("workgroup", "", "workgroup identifier")
 
 	initBaselineWorkflowCmd.Flags().StringVar(&name, "name", "", "name of the axiom workflow")
 	initBaselineWorkflowCmd.Flags().StringVar(&description, "description", "", "description of the axiom workflow")
@@ -19,7 +19,6 @@ package workflows
 import (
 	"encoding/json"
 	"fmt"
-	"os"
 
 	"github.com/manifoldco/promptui"
 	"github.com/provideplatform/provide-cli/prvd/common"
@@ -45,11 +44,12 @@ func listWorkflows(cmd *cobra.Command, args []string) {
 }
 
 func listWorkflowsRun(cmd *cobra.Command, args []string) {
-	if common.OrganizationID == "" {
-		common.RequireOrganization()
+	ctx := common.ContextFromCommand(cmd)
+	if ctx.OrganizationID == "" {
+		common.RequireOrganization(ctx)
 	}
-	if common.WorkgroupID == "" {
-		common.RequireWorkgroup()
+	if ctx.WorkgroupID == "" {
+		common.RequireWorkgroup(ctx)
 	}
 	if !filterInstances {
 		prompt := promptui.Prompt{
@@ -72,16 +72,16 @@ func listWorkflowsRun(cmd *cobra.Command, args []string) {
 		}
 	}
 
-	common.AuthorizeOrganizationContext(true)
+	common.AuthorizeOrganizationContext(ctx, true)
 
-	token, err := common.ResolveOrganizationToken()
+	token, err := common.ResolveOrganizationToken(ctx)
 	if err != nil {
 		fmt.Printf("failed to list workflows; %s", err.Error())
-		os.Exit(1)
+		common.Exit(1)
 	}
 
 	params := map[string]interface{}{
-		"workgroup_id": common.WorkgroupID,
+		"workgroup_id": ctx.WorkgroupID,
 	}
 
 	if filterInstances {
@@ -95,7 +95,7 @@ func listWorkflowsRun(cmd *cobra.Command, args []string) {
 	workflows, err := axiom.ListWorkflows(*token.AccessToken, params)
 	if err != nil {
 		fmt.Printf("failed to list workflows; %s", err.Error())
-		os.Exit(1)
+		common.Exit(1)
 	}
 
 	if len(workflows) == 0 {
@@ -110,8 +110,8 @@ func listWorkflowsRun(cmd *cobra.Command, args []string) {
 }
 
 func init() {
-	listBaselineWorkflowsCmd.Flags().StringVar(&common.OrganizationID, "organization", "", "organization identifier")
-	listBaselineWorkflowsCmd.Flags().StringVar(&common.WorkgroupID, "workgroup", "", "workgroup identifier")
+	listBaselineWorkflowsCmd.Flags().String("organization", "", "organization identifier")
+	listBaselineWorkflowsCmd.Flags().String("workgroup", "", "workgroup identifier")
 
 	listBaselineWorkflowsCmd.Flags().BoolVar(&filterInstances, "filter-instances", false, "filter workflow prototypes")
 	listBaselineWorkflowsCmd.Flags().BoolVar(&filterPrototypes, "filter-prototypes", false, "filter workflow instances")
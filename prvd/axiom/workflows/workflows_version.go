@@ -39,33 +39,34 @@ func versionWorkflow(cmd *cobra.Command, args []string) {
 }
 
 func versionWorkflowRun(cmd *cobra.Command, args []string) {
-	if common.OrganizationID == "" {
-		common.RequireOrganization()
+	ctx := common.ContextFromCommand(cmd)
+	if ctx.OrganizationID == "" {
+		common.RequireOrganization(ctx)
 	}
-	if common.WorkgroupID == "" {
-		common.RequireWorkgroup()
+	if ctx.WorkgroupID == "" {
+		common.RequireWorkgroup(ctx)
 	}
 
-	token, err := common.ResolveOrganizationToken()
+	token, err := common.ResolveOrganizationToken(ctx)
 	if err != nil {
 		fmt.Printf("failed to version workflow; %s", err.Error())
-		os.Exit(1)
+		common.Exit(1)
 	}
 	if workflowID == "" {
-		workflowPrompt(*token.AccessToken)
+		workflowPrompt(ctx, *token.AccessToken)
 	}
 	workflow, err := axiom.GetWorkflowDetails(*token.AccessToken, workflowID, map[string]interface{}{})
 	if err != nil {
 		fmt.Printf("failed to version workflow; %s", err.Error())
-		os.Exit(1)
+		common.Exit(1)
 	}
 	if workflow.WorkflowID != nil {
 		fmt.Print("failed to version workflow; cannot version a workflow instance")
-		os.Exit(1)
+		common.Exit(1)
 	}
 	if *workflow.Status != "deployed" {
 		fmt.Print("failed to version workflow; cannot version a non-deployed workflow")
-		os.Exit(1)
+		common.Exit(1)
 	}
 
 	if name == "" {
@@ -79,7 +80,7 @@ func versionWorkflowRun(cmd *cobra.Command, args []string) {
 	} else {
 		if _, err := semver.Make(version); err != nil {
 			fmt.Printf("failed to version workflow; %s", err.Error())
-			os.Exit(1)
+			common.Exit(1)
 		}
 	}
 
@@ -88,7 +89,7 @@ func versionWorkflowRun(cmd *cobra.Command, args []string) {
 
 	if !v2.GT(v1) {
 		fmt.Printf("failed to version workflow; new version must be greater than previous")
-		os.Exit(1)
+		common.Exit(1)
 	}
 
 	params := map[string]interface{}{
@@ -103,7 +104,7 @@ func versionWorkflowRun(cmd *cobra.Command, args []string) {
 	w, err := axiom.VersionWorkflow(*token.AccessToken, workflowID, params)
 	if err != nil {
 		fmt.Printf("failed to version workflow; %s", err.Error())
-		os.Exit(1)
+		common.Exit(1)
 	}
 
 	result, _ := json.MarshalIndent(w, "", "\t")
@@ -111,8 +112,8 @@ func versionWorkflowRun(cmd *cobra.Command, args []string) {
 }
 
 func init() {
-	versionBaselineWorkflowCmd.Flags().StringVar(&common.OrganizationID, "organization", os.Getenv("PROVIDE_ORGANIZATION_ID"), "organization identifier")
-	versionBaselineWorkflowCmd.Flags().StringVar(&common.WorkgroupID, "workgroup", "", "workgroup identifier")
+	versionBaselineWorkflowCmd.Flags().String("organization", os.Getenv("PROVIDE_ORGANIZATION_ID"), "organization identifier")
+	versionBaselineWorkflowCmd.Flags().String("workgroup", "", "workgroup identifier")
 	versionBaselineWorkflowCmd.Flags().StringVar(&workflowID, "workflow", "", "workflow identifier")
 
 	versionBaselineWorkflowCmd.Flags().StringVar(&name, "name", "", "name of the axiom workflow")
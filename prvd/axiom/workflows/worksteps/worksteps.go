@@ -34,6 +34,12 @@ var WorkstepsCmd = &cobra.Command{
 	},
 }
 
+// ResetState restores the package-level state assigned by prompts, which is not bound to a flag;
+// the shell calls this prior to each in-process invocation
+func ResetState() {
+	prover = ""
+}
+
 func init() {
 	WorkstepsCmd.AddCommand(listBaselineWorkstepsCmd)
 	//  WorkstepsCmd.AddCommand(detailBaselineWorkstepCmd)
@@ -53,21 +53,22 @@ func initWorkstep(cmd *cobra.Command, args []string) {
 }
 
 func initWorkstepRun(cmd *cobra.Command, args []string) {
-	if common.OrganizationID == "" {
-		common.RequireOrganization()
+	ctx := common.ContextFromCommand(cmd)
+	if ctx.OrganizationID == "" {
+		common.RequireOrganization(ctx)
 	}
-	if common.WorkgroupID == "" {
-		common.RequireWorkgroup()
+	if ctx.WorkgroupID == "" {
+		common.RequireWorkgroup(ctx)
 	}
 
-	token, err := common.ResolveOrganizationToken()
+	token, err := common.ResolveOrganizationToken(ctx)
 	if err != nil {
 		fmt.Printf("failed to initialize workstep; %s", err.Error())
-		os.Exit(1)
+		common.Exit(1)
 	}
 
 	if workflowID == "" {
-		workflowPrompt(*token.AccessToken)
+		workflowPrompt(ctx, *token.AccessToken)
 	}
 
 	if name == "" {
@@ -99,7 +100,7 @@ func initWorkstepRun(cmd *cobra.Command, args []string) {
 
 		if !isValid {
 			fmt.Print("failed to initialize workstep; invalid prover identifier")
-			os.Exit(1)
+			common.Exit(1)
 		}
 	}
 
@@ -123,21 +124,21 @@ func initWorkstepRun(cmd *cobra.Command, args []string) {
 	ws, err := axiom.CreateWorkstep(*token.AccessToken, workflowID, params)
 	if err != nil {
 		fmt.Printf("failed to initialize workstep; %s", err.Error())
-		os.Exit(1)
+		common.Exit(1)
 	}
 
 	result, _ := json.MarshalIndent(ws, "", "\t")
 	fmt.Printf("%s\n", string(result))
 }
 
-func workflowPrompt(token string) {
+func workflowPrompt(ctx *common.Context, token string) {
 	workflows, err := axiom.ListWorkflows(token, map[string]interface{}{
-		"workgroup_id": common.WorkgroupID,
+		"workgroup_id": ctx.WorkgroupID,
 	})
 
 	if len(workflows) == 0 {
 		fmt.Print("No workflows found\n")
-		os.Exit(1)
+		common.Exit(1)
 	}
 
 	opts := make([]string, 0)
@@ -152,7 +153,7 @@ func workflowPrompt(token string) {
 
 	i, _, err := prompt.Run()
 	if err != nil {
-		os.Exit(1)
+		common.Exit(1)
 	}
 
 	workflowID = workflows[i].ID.String()
@@ -172,7 +173,7 @@ func namePrompt() {
 
 	result, err := prompt.Run()
 	if err != nil {
-		os.Exit(1)
+		common.Exit(1)
 		return
 	}
 
@@ -186,7 +187,7 @@ func descriptionPrompt() {
 
 	result, err := prompt.Run()
 	if err != nil {
-		os.Exit(1)
+		common.Exit(1)
 		return
 	}
 
@@ -214,15 +215,15 @@ func proverPrompt() {
 
 	i, _, err := prompt.Run()
 	if err != nil {
-		os.Exit(1)
+		common.Exit(1)
 	}
 
 	prover = opts[i]
 }
 
 func init() {
-	initBaselineWorkstepCmd.Flags().StringVar(&common.OrganizationID, "organization", os.Getenv("PROVIDE_ORGANIZATION_ID"), "organization identifier")
-	initBaselineWorkstepCmd.Flags().StringVar(&common.WorkgroupID, "workgroup", "", "workgroup identifier")
+	initBaselineWorkstepCmd.Flags().String("organization", os.Getenv("PROVIDE_ORGANIZATION_ID"), "organization identifier")
+	initBaselineWorkstepCmd.Flags().String("workgroup", "", "workgroup identifier")
 	initBaselineWorkstepCmd.Flags().StringVar(&workflowID, "workflow", "", "workflow identifier")
 
 	initBaselineWorkstepCmd.Flags().StringVar(&name, "name", "", "name of the axiom workstep")
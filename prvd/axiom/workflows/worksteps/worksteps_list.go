@@ -19,7 +19,6 @@ package worksteps
 import (
 	"encoding/json"
 	"fmt"
-	"os"
 
 	"github.com/provideplatform/provide-cli/prvd/common"
 	"github.com/provideplatform/provide-go/api/axiom"
@@ -41,27 +40,28 @@ func listWorksteps(cmd *cobra.Command, args []string) {
 }
 
 func listWorkstepsRun(cmd *cobra.Command, args []string) {
-	if common.OrganizationID == "" {
-		common.RequireOrganization()
+	ctx := common.ContextFromCommand(cmd)
+	if ctx.OrganizationID == "" {
+		common.RequireOrganization(ctx)
 	}
-	if common.WorkgroupID == "" {
-		common.RequireWorkgroup()
+	if ctx.WorkgroupID == "" {
+		common.RequireWorkgroup(ctx)
 	}
 
-	token, err := common.ResolveOrganizationToken()
+	token, err := common.ResolveOrganizationToken(ctx)
 	if err != nil {
 		fmt.Printf("failed to list worksteps; %s", err.Error())
-		os.Exit(1)
+		common.Exit(1)
 	}
 
 	if workflowID == "" {
-		workflowPrompt(*token.AccessToken)
+		workflowPrompt(ctx, *token.AccessToken)
 	}
 
 	worksteps, err := axiom.ListWorksteps(*token.AccessToken, workflowID, map[string]interface{}{})
 	if err != nil {
 		fmt.Printf("failed to list worksteps; %s", err.Error())
-		os.Exit(1)
+		common.Exit(1)
 	}
 
 	if len(worksteps) == 0 {
@@ -76,8 +76,8 @@ func listWorkstepsRun(cmd *cobra.Command, args []string) {
 }
 
 func init() {
-	listBaselineWorkstepsCmd.Flags().StringVar(&common.OrganizationID, "organization", "", "organization identifier")
-	listBaselineWorkstepsCmd.Flags().StringVar(&common.WorkgroupID, "workgroup", "", "workgroup identifier")
+	listBaselineWorkstepsCmd.Flags().String("organization", "", "organization identifier")
+	listBaselineWorkstepsCmd.Flags().String("workgroup", "", "workgroup identifier")
 	listBaselineWorkstepsCmd.Flags().StringVar(&workflowID, "workflow", "", "workflow identifier")
 
 	listBaselineWorkstepsCmd.Flags().Uint64Var(&page, "page", common.DefaultPage, "page number to retrieve")
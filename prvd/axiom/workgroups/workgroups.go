@@ -34,6 +34,15 @@ var WorkgroupsCmd = &cobra.Command{
 	},
 }
 
+// ResetState restores the package-level state assigned by prompts, which is not bound to a flag;
+// the shell calls this prior to each in-process invocation
+func ResetState() {
+	babyJubJubKeyID = ""
+	secp256k1KeyID = ""
+	hdwalletID = ""
+	rsa4096KeyID = ""
+}
+
 func init() {
 	WorkgroupsCmd.AddCommand(listBaselineWorkgroupsCmd)
 	WorkgroupsCmd.AddCommand(detailBaselineWorkgroupCmd)
@@ -20,7 +20,6 @@ import (
 	"encoding/json"
 	"fmt"
 	"log"
-	"os"
 
 	"github.com/provideplatform/provide-cli/prvd/common"
 	"github.com/provideplatform/provide-go/api/axiom"
@@ -40,26 +39,27 @@ func fetchWorkgroupDetails(cmd *cobra.Command, args []string) {
 }
 
 func fetchWorkgroupDetailsRun(cmd *cobra.Command, args []string) {
-	if common.OrganizationID == "" {
-		common.RequireOrganization()
+	ctx := common.ContextFromCommand(cmd)
+	if ctx.OrganizationID == "" {
+		common.RequireOrganization(ctx)
 	}
 
-	if common.WorkgroupID == "" {
-		common.RequireWorkgroup()
+	if ctx.WorkgroupID == "" {
+		common.RequireWorkgroup(ctx)
 	}
 
-	common.AuthorizeOrganizationContext(true)
+	common.AuthorizeOrganizationContext(ctx, true)
 
-	token, err := common.ResolveOrganizationToken()
+	token, err := common.ResolveOrganizationToken(ctx)
 	if err != nil {
-		log.Printf("Failed to retrieve details for workgroup with id: %s; %s", common.WorkgroupID, err.Error())
-		os.Exit(1)
+		log.Printf("Failed to retrieve details for workgroup with id: %s; %s", ctx.WorkgroupID, err.Error())
+		common.Exit(1)
 	}
 
-	wg, err := axiom.GetWorkgroupDetails(*token.AccessToken, common.WorkgroupID, map[string]interface{}{})
+	wg, err := axiom.GetWorkgroupDetails(*token.AccessToken, ctx.WorkgroupID, map[string]interface{}{})
 	if err != nil {
-		log.Printf("Failed to retrieve details for workgroup with id: %s; %s", common.WorkgroupID, err.Error())
-		os.Exit(1)
+		log.Printf("Failed to retrieve details for workgroup with id: %s; %s", ctx.WorkgroupID, err.Error())
+		common.Exit(1)
 	}
 
 	result, _ := json.MarshalIndent(wg, "", "\t")
@@ -67,6 +67,6 @@ func fetchWorkgroupDetailsRun(cmd *cobra.Command, args []string) {
 }
 
 func init() {
-	detailBaselineWorkgroupCmd.Flags().StringVar(&common.OrganizationID, "organization", "", "organization identifier")
-	detailBaselineWorkgroupCmd.Flags().StringVar(&common.WorkgroupID, "workgroup", "", "workgroup identifier")
+	detailBaselineWorkgroupCmd.Flags().String("organization", "", "organization identifier")
+	detailBaselineWorkgroupCmd.Flags().String("workgroup", "", "workgroup identifier")
 }
@@ -63,13 +63,13 @@ var initBaselineWorkgroupCmd = &cobra.Command{
 	Run:   initWorkgroup,
 }
 
-func AuthorizeApplicationContext() {
+func AuthorizeApplicationContext(ctx *common.Context) {
 	// common.AuthorizeApplicationContext()
-	if _, err := nchain.CreateWallet(common.ApplicationAccessToken, map[string]interface{}{
+	if _, err := nchain.CreateWallet(ctx.ApplicationAccessToken, map[string]interface{}{
 		"purpose": 44,
 	}); err != nil {
 		log.Printf("failed to initialize HD wallet; %s", err.Error())
-		os.Exit(1)
+		common.Exit(1)
 	}
 }
 func initWorkgroup(cmd *cobra.Command, args []string) {
@@ -77,8 +77,9 @@ func initWorkgroup(cmd *cobra.Command, args []string) {
 }
 
 func initWorkgroupRun(cmd *cobra.Command, args []string) {
-	if common.OrganizationID == "" {
-		common.RequireOrganization()
+	ctx := common.ContextFromCommand(cmd)
+	if ctx.OrganizationID == "" {
+		common.RequireOrganization(ctx)
 	}
 	if name == "" {
 		namePrompt()
@@ -86,57 +87,57 @@ func initWorkgroupRun(cmd *cobra.Command, args []string) {
 	if description == "" {
 		descriptionPrompt()
 	}
-	if common.NetworkID == "" {
-		common.RequireL1Network()
+	if ctx.NetworkID == "" {
+		common.RequireL1Network(ctx)
 	}
-	if common.L2NetworkID == "" {
-		common.RequireL2Network()
+	if ctx.L2NetworkID == "" {
+		common.RequireL2Network(ctx)
 	}
 	if !hasAgreedToTermsOfService {
 		if ok := common.RequireTermsOfServiceAgreement(); !ok {
 			fmt.Print("failed to initialize axiom workgroup; must accept the terms of agreement")
-			os.Exit(1)
+			common.Exit(1)
 		}
 	}
 	if !hasAgreedToPrivacyPolicy {
 		if ok := common.RequirePrivacyPolicyAgreement(); !ok {
 			fmt.Print("failed to initialize axiom workgroup; must accept the privacy policy")
-			os.Exit(1)
+			common.Exit(1)
 		}
 	}
 
-	if common.Organization.Metadata != nil && common.Organization.Metadata.Domain != "" {
-		orgDomain = common.Organization.Metadata.Domain
+	if ctx.Organization.Metadata != nil && ctx.Organization.Metadata.Domain != "" {
+		orgDomain = ctx.Organization.Metadata.Domain
 	} else if orgDomain == "" {
 		orgDomainPrompt()
 	}
 
-	common.AuthorizeOrganizationContext(true)
+	common.AuthorizeOrganizationContext(ctx, true)
 
-	token, err := common.ResolveOrganizationToken()
+	token, err := common.ResolveOrganizationToken(ctx)
 	if err != nil {
 		log.Printf("failed to initialize axiom workgroup; %s", err.Error())
-		os.Exit(1)
+		common.Exit(1)
 	}
 
 	vaults, err := vault.ListVaults(*token.AccessToken, map[string]interface{}{})
 	if err != nil {
 		log.Printf("failed to initialize axiom workgroup; %s", err.Error())
-		os.Exit(1)
+		common.Exit(1)
 	}
 
 	orgVault := vaults[0]
 	if orgVault == nil {
 		log.Print("failed to initialize axiom workgroup; failed to fetch organization vault; no vaults found")
-		os.Exit(1)
+		common.Exit(1)
 	}
 
 	params := map[string]interface{}{
 		"name":       name,
-		"network_id": common.NetworkID,
+		"network_id": ctx.NetworkID,
 		"config": map[string]interface{}{
 			"vault_id":          orgVault.ID.String(),
-			"l2_network_id":     common.L2NetworkID,
+			"l2_network_id":     ctx.L2NetworkID,
 			"system_secret_ids": make([]*uuid.UUID, 0),
 		},
 		"type": "axiom",
@@ -149,23 +150,23 @@ func initWorkgroupRun(cmd *cobra.Command, args []string) {
 	wg, err := axiom.CreateWorkgroup(*token.AccessToken, params)
 	if err != nil {
 		log.Printf("failed to initialize axiom workgroup; %s", err.Error())
-		os.Exit(1)
+		common.Exit(1)
 	}
 
-	if err := common.RequireOrganizationVault(); err != nil {
+	if err := common.RequireOrganizationVault(ctx); err != nil {
 		log.Printf("failed to initialize axiom workgroup; %s", err.Error())
-		os.Exit(1)
+		common.Exit(1)
 	}
 
-	if err := requireOrganizationKeys(); err != nil {
+	if err := requireOrganizationKeys(ctx); err != nil {
 		log.Printf("failed to initialize axiom workgroup; %s", err.Error())
-		os.Exit(1)
+		common.Exit(1)
 	}
 
-	secp256k1Key, err := vault.FetchKey(*token.AccessToken, common.VaultID, secp256k1KeyID)
+	secp256k1Key, err := vault.FetchKey(*token.AccessToken, ctx.VaultID, secp256k1KeyID)
 	if err != nil {
 		fmt.Printf("failed to initialize axiom workgroup: %s", err.Error())
-		os.Exit(1)
+		common.Exit(1)
 	}
 
 	termsNow := time.Now()
@@ -173,10 +174,10 @@ func initWorkgroupRun(cmd *cobra.Command, args []string) {
 	termsTimestampHex := hex.EncodeToString([]byte(termsString))
 	termsTimestampHash := common.SHA256(termsTimestampHex)
 
-	termsSig, err := vault.SignMessage(*token.AccessToken, common.VaultID, secp256k1KeyID, termsTimestampHash, map[string]interface{}{})
+	termsSig, err := vault.SignMessage(*token.AccessToken, ctx.VaultID, secp256k1KeyID, termsTimestampHash, map[string]interface{}{})
 	if err != nil {
 		fmt.Printf("failed to initialize axiom workgroup: %s", err.Error())
-		os.Exit(1)
+		common.Exit(1)
 	}
 
 	privacyNow := time.Now()
@@ -184,23 +185,23 @@ func initWorkgroupRun(cmd *cobra.Command, args []string) {
 	privacyTimestampHex := hex.EncodeToString([]byte(privacyString))
 	privacyTimestampHash := common.SHA256(privacyTimestampHex)
 
-	privacySig, err := vault.SignMessage(*token.AccessToken, common.VaultID, secp256k1KeyID, privacyTimestampHash, map[string]interface{}{})
+	privacySig, err := vault.SignMessage(*token.AccessToken, ctx.VaultID, secp256k1KeyID, privacyTimestampHash, map[string]interface{}{})
 	if err != nil {
 		fmt.Printf("failed to initialize axiom workgroup: %s", err.Error())
-		os.Exit(1)
+		common.Exit(1)
 	}
 
-	if common.Organization.Metadata == nil {
-		common.Organization.Metadata = &common.OrganizationMetadata{
+	if ctx.Organization.Metadata == nil {
+		ctx.Organization.Metadata = &common.OrganizationMetadata{
 			Address:    *secp256k1Key.Address,
 			Workgroups: map[uuid.UUID]*common.OrganizationWorkgroupMetadata{},
 		}
-	} else if common.Organization.Metadata.Workgroups == nil {
-		common.Organization.Metadata.Workgroups = map[uuid.UUID]*common.OrganizationWorkgroupMetadata{}
+	} else if ctx.Organization.Metadata.Workgroups == nil {
+		ctx.Organization.Metadata.Workgroups = map[uuid.UUID]*common.OrganizationWorkgroupMetadata{}
 	}
 
-	common.Organization.Metadata.Domain = orgDomain
-	common.Organization.Metadata.Workgroups[wg.ID] = &common.OrganizationWorkgroupMetadata{
+	ctx.Organization.Metadata.Domain = orgDomain
+	ctx.Organization.Metadata.Workgroups[wg.ID] = &common.OrganizationWorkgroupMetadata{
 		OperatorSeparationDegree: uint32(0),
 		VaultID:                  &orgVault.ID,
 		SystemSecretIDs:          make([]*uuid.UUID, 0),
@@ -215,32 +216,32 @@ func initWorkgroupRun(cmd *cobra.Command, args []string) {
 	}
 
 	var orgInterface map[string]interface{}
-	raw, _ := json.Marshal(common.Organization)
+	raw, _ := json.Marshal(ctx.Organization)
 	json.Unmarshal(raw, &orgInterface)
 
-	if err := ident.UpdateOrganization(*token.AccessToken, common.OrganizationID, orgInterface); err != nil {
+	if err := ident.UpdateOrganization(*token.AccessToken, ctx.OrganizationID, orgInterface); err != nil {
 		log.Printf("failed to initialize axiom workgroup; %s", err.Error())
-		os.Exit(1)
+		common.Exit(1)
 	}
 
-	common.WorkgroupID = wg.ID.String()
+	ctx.WorkgroupID = wg.ID.String()
 
-	registryContract := common.InitWorkgroupContract(axiomRegistryContractAddress)
+	registryContract := common.InitWorkgroupContract(ctx, axiomRegistryContractAddress)
 
-	sa, err := axiom.CreateSubjectAccount(*token.AccessToken, common.OrganizationID, map[string]interface{}{
+	sa, err := axiom.CreateSubjectAccount(*token.AccessToken, ctx.OrganizationID, map[string]interface{}{
 		"metadata": map[string]interface{}{
-			"organization_id":            common.OrganizationID,
+			"organization_id":            ctx.OrganizationID,
 			"organization_address":       *secp256k1Key.Address,
 			"organization_refresh_token": *token.AccessToken,
-			"workgroup_id":               common.WorkgroupID,
+			"workgroup_id":               ctx.WorkgroupID,
 			"registry_contract_address":  *registryContract.Address,
-			"network_id":                 common.NetworkID,
+			"network_id":                 ctx.NetworkID,
 			"organization_domain":        orgDomain,
 		},
 	})
 	if err != nil {
 		fmt.Printf("failed to initialize axiom workgroup; %s", err.Error())
-		os.Exit(1)
+		common.Exit(1)
 	}
 
 	//common.RequireOrganizationEndpoints(nil)
@@ -262,36 +263,36 @@ func orgDomainPrompt() {
 
 	result, err := prompt.Run()
 	if err != nil {
-		os.Exit(1)
+		common.Exit(1)
 		return
 	}
 
 	orgDomain = result
 }
 
-func requireOrganizationKeys() error {
+func requireOrganizationKeys(ctx *common.Context) error {
 	var key *vault.Key
 	var err error
 
-	key, err = common.RequireOrganizationKeypair("babyJubJub")
+	key, err = common.RequireOrganizationKeypair(ctx, "babyJubJub")
 	if err != nil {
 		return err
 	}
 	babyJubJubKeyID = key.ID.String()
 
-	key, err = common.RequireOrganizationKeypair("secp256k1")
+	key, err = common.RequireOrganizationKeypair(ctx, "secp256k1")
 	if err != nil {
 		return err
 	}
 	secp256k1KeyID = key.ID.String()
 
-	key, err = common.RequireOrganizationKeypair("BIP39")
+	key, err = common.RequireOrganizationKeypair(ctx, "BIP39")
 	if err != nil {
 		return err
 	}
 	hdwalletID = key.ID.String()
 
-	key, err = common.RequireOrganizationKeypair("RSA-4096")
+	key, err = common.RequireOrganizationKeypair(ctx, "RSA-4096")
 	if err != nil {
 		return err
 	}
@@ -314,7 +315,7 @@ func namePrompt() {
 
 	result, err := prompt.Run()
 	if err != nil {
-		os.Exit(1)
+		common.Exit(1)
 		return
 	}
 
@@ -328,14 +329,14 @@ func descriptionPrompt() {
 
 	result, err := prompt.Run()
 	if err != nil {
-		os.Exit(1)
+		common.Exit(1)
 		return
 	}
 
 	description = result
 }
 
-func organizationAuthPrompt(target string) {
+func organizationAuthPrompt(ctx *common.Context, target string) {
 	prompt := promptui.Prompt{
 		IsConfirm: true,
 		Label:     fmt.Sprintf("Authorize access/refresh token for %s?", target),
@@ -343,21 +344,21 @@ func organizationAuthPrompt(target string) {
 
 	result, err := prompt.Run()
 	if err != nil {
-		os.Exit(1)
+		common.Exit(1)
 		return
 	}
 
 	if strings.ToLower(result) == "y" {
-		common.AuthorizeOrganizationContext(true)
+		common.AuthorizeOrganizationContext(ctx, true)
 	}
 }
 
 func init() {
-	initBaselineWorkgroupCmd.Flags().StringVar(&common.OrganizationID, "organization", os.Getenv("PROVIDE_ORGANIZATION_ID"), "organization identifier")
+	initBaselineWorkgroupCmd.Flags().String("organization", os.Getenv("PROVIDE_ORGANIZATION_ID"), "organization identifier")
 	initBaselineWorkgroupCmd.Flags().StringVar(&name, "name", "", "name of the axiom workgroup")
 	initBaselineWorkgroupCmd.Flags().StringVar(&description, "description", "", "description of the axiom workgroup")
-	initBaselineWorkgroupCmd.Flags().StringVar(&common.NetworkID, "network", "", "nchain network id of the axiom mainnet to use for this workgroup")
-	initBaselineWorkgroupCmd.Flags().StringVar(&common.L2NetworkID, "l2", "", "nchain l2 network id of the axiom layer 2 to use for this workgroup")
+	initBaselineWorkgroupCmd.Flags().String("network", "", "nchain network id of the axiom mainnet to use for this workgroup")
+	initBaselineWorkgroupCmd.Flags().String("l2", "", "nchain l2 network id of the axiom layer 2 to use for this workgroup")
 	initBaselineWorkgroupCmd.Flags().BoolVarP(&hasAgreedToTermsOfService, "terms", "", false, "accept the terms of service (https://provide.services/terms)")
 	initBaselineWorkgroupCmd.Flags().BoolVarP(&hasAgreedToPrivacyPolicy, "privacy", "", false, "accept the privacy policy (https://provide.services/privacy-policy)")
 	initBaselineWorkgroupCmd.Flags().StringVar(&axiomRegistryContractAddress, "registry-contract-address", "0x", "organization registry contract address")
@@ -21,7 +21,6 @@ import (
 	"encoding/json"
 	"fmt"
 	"log"
-	"os"
 	"time"
 
 	"github.com/dgrijalva/jwt-go"
@@ -60,6 +59,7 @@ func joinWorkgroup(cmd *cobra.Command, args []string) {
 }
 
 func joinWorkgroupRun(cmd *cobra.Command, args []string) {
+	ctx := common.ContextFromCommand(cmd)
 	if inviteJWT == "" {
 		jwtPrompt()
 	}
@@ -83,7 +83,7 @@ func joinWorkgroupRun(cmd *cobra.Command, args []string) {
 		resp, err := ident.Authenticate(email, password)
 		if err != nil {
 			fmt.Printf("failed to accept invite; %s", err.Error())
-			os.Exit(1)
+			common.Exit(1)
 		}
 
 		if resp.Token.AccessToken != nil && resp.Token.RefreshToken != nil {
@@ -106,13 +106,13 @@ func joinWorkgroupRun(cmd *cobra.Command, args []string) {
 
 		if _, err := ident.CreateUser("", createUserParams); err != nil {
 			fmt.Printf("failed to accept invite; %s", err.Error())
-			os.Exit(1)
+			common.Exit(1)
 		}
 
 		resp, err := ident.Authenticate(email, password)
 		if err != nil {
 			fmt.Printf("failed to accept invite; %s", err.Error())
-			os.Exit(1)
+			common.Exit(1)
 		}
 
 		if resp.Token.AccessToken != nil && resp.Token.RefreshToken != nil {
@@ -129,13 +129,13 @@ func joinWorkgroupRun(cmd *cobra.Command, args []string) {
 		if !hasAgreedToTermsOfService {
 			if ok := common.RequireTermsOfServiceAgreement(); !ok {
 				fmt.Print("failed to accept invite; must accept the terms of agreement")
-				os.Exit(1)
+				common.Exit(1)
 			}
 		}
 		if !hasAgreedToPrivacyPolicy {
 			if ok := common.RequirePrivacyPolicyAgreement(); !ok {
 				fmt.Print("failed to accept invite; must accept the privacy policy")
-				os.Exit(1)
+				common.Exit(1)
 			}
 		}
 
@@ -157,42 +157,42 @@ func joinWorkgroupRun(cmd *cobra.Command, args []string) {
 			orgParams["description"] = orgDescription
 		}
 
-		org, err := ident.CreateOrganization(common.RequireUserAccessToken(), orgParams)
+		org, err := ident.CreateOrganization(common.RequireUserAccessToken(ctx), orgParams)
 		if err != nil {
 			fmt.Printf("failed to accept invite; %s", err.Error())
-			os.Exit(1)
+			common.Exit(1)
 		}
 
-		common.OrganizationID = *org.ID
+		ctx.OrganizationID = *org.ID
 
-		common.AuthorizeOrganizationContext(true)
+		common.AuthorizeOrganizationContext(ctx, true)
 
-		token, err := common.ResolveOrganizationToken()
+		token, err := common.ResolveOrganizationToken(ctx)
 		if err != nil {
 			log.Printf("failed to accept invite; %s", err.Error())
-			os.Exit(1)
+			common.Exit(1)
 		}
 
-		common.RequireOrganizationVault()
+		common.RequireOrganizationVault(ctx)
 
 		vaults, err := vault.ListVaults(*token.AccessToken, map[string]interface{}{})
 		if err != nil {
 			log.Printf("failed to accept invite; %s", err.Error())
-			os.Exit(1)
+			common.Exit(1)
 		}
 
 		orgVault := vaults[0]
 		if orgVault == nil {
 			log.Print("failed to accept invite; failed to fetch organization vault; no vaults found")
-			os.Exit(1)
+			common.Exit(1)
 		}
 
-		requireOrganizationKeys()
+		requireOrganizationKeys(ctx)
 
-		secp256k1Key, err := vault.FetchKey(*token.AccessToken, common.VaultID, secp256k1KeyID)
+		secp256k1Key, err := vault.FetchKey(*token.AccessToken, ctx.VaultID, secp256k1KeyID)
 		if err != nil {
 			fmt.Printf("failed to initialize axiom workgroup: %s", err.Error())
-			os.Exit(1)
+			common.Exit(1)
 		}
 
 		termsNow := time.Now()
@@ -200,10 +200,10 @@ func joinWorkgroupRun(cmd *cobra.Command, args []string) {
 		termsTimestampHex := hex.EncodeToString([]byte(termsString))
 		termsTimestampHash := common.SHA256(termsTimestampHex)
 
-		termsSig, err := vault.SignMessage(*token.AccessToken, common.VaultID, secp256k1KeyID, termsTimestampHash, map[string]interface{}{})
+		termsSig, err := vault.SignMessage(*token.AccessToken, ctx.VaultID, secp256k1KeyID, termsTimestampHash, map[string]interface{}{})
 		if err != nil {
 			fmt.Printf("failed to initialize axiom workgroup: %s", err.Error())
-			os.Exit(1)
+			common.Exit(1)
 		}
 
 		privacyNow := time.Now()
@@ -211,16 +211,16 @@ func joinWorkgroupRun(cmd *cobra.Command, args []string) {
 		privacyTimestampHex := hex.EncodeToString([]byte(privacyString))
 		privacyTimestampHash := common.SHA256(privacyTimestampHex)
 
-		privacySig, err := vault.SignMessage(*token.AccessToken, common.VaultID, secp256k1KeyID, privacyTimestampHash, map[string]interface{}{})
+		privacySig, err := vault.SignMessage(*token.AccessToken, ctx.VaultID, secp256k1KeyID, privacyTimestampHash, map[string]interface{}{})
 		if err != nil {
 			fmt.Printf("failed to initialize axiom workgroup: %s", err.Error())
-			os.Exit(1)
+			common.Exit(1)
 		}
 
 		// TODO-- configure system for organization participant
 
-		common.Organization.Metadata.Address = *secp256k1Key.Address
-		common.Organization.Metadata.Workgroups[*decodedTokenData.ApplicationID] = &common.OrganizationWorkgroupMetadata{
+		ctx.Organization.Metadata.Address = *secp256k1Key.Address
+		ctx.Organization.Metadata.Workgroups[*decodedTokenData.ApplicationID] = &common.OrganizationWorkgroupMetadata{
 			OperatorSeparationDegree: decodedTokenData.Params.OperatorSeparationDegree,
 			VaultID:                  &orgVault.ID,
 			SystemSecretIDs:          make([]*uuid.UUID, 0),
@@ -235,17 +235,17 @@ func joinWorkgroupRun(cmd *cobra.Command, args []string) {
 		}
 
 		var orgInterface map[string]interface{}
-		raw, _ := json.Marshal(common.Organization)
+		raw, _ := json.Marshal(ctx.Organization)
 		json.Unmarshal(raw, &orgInterface)
 
-		if err := ident.UpdateOrganization(*token.AccessToken, common.OrganizationID, orgInterface); err != nil {
+		if err := ident.UpdateOrganization(*token.AccessToken, ctx.OrganizationID, orgInterface); err != nil {
 			log.Printf("failed to accept invite; %s", err.Error())
-			os.Exit(1)
+			common.Exit(1)
 		}
 
 		subjectAccountParams := map[string]interface{}{
 			"metadata": map[string]interface{}{
-				"organization_id":            common.OrganizationID,
+				"organization_id":            ctx.OrganizationID,
 				"organization_address":       *secp256k1Key.Address,
 				"organization_refresh_token": *token.RefreshToken,
 				"workgroup_id":               *decodedTokenData.ApplicationID,
@@ -259,7 +259,7 @@ func joinWorkgroupRun(cmd *cobra.Command, args []string) {
 			"token":                  *decodedTokenData.Params.AuthorizedBearerToken,
 		}); err != nil {
 			log.Printf("failed to accept invite; %s", err.Error())
-			os.Exit(1)
+			common.Exit(1)
 		}
 	}
 
@@ -273,7 +273,7 @@ func jwtPrompt() {
 
 	result, err := prompt.Run()
 	if err != nil {
-		os.Exit(1)
+		common.Exit(1)
 		return
 	}
 
@@ -316,7 +316,7 @@ func modePrompt() {
 	i, _, err := selectPrompt.Run()
 	if err != nil {
 		fmt.Printf("failed to accept invitation; %s", err.Error())
-		os.Exit(1)
+		common.Exit(1)
 	}
 
 	mode = acceptInviteModes[i]
@@ -332,7 +332,7 @@ func loginPrompt(defaultEmail string) {
 
 		result, err := prompt.Run()
 		if err != nil {
-			os.Exit(1)
+			common.Exit(1)
 			return
 		}
 
@@ -354,7 +354,7 @@ func signupPrompt(defaultFirst, defaultLast, defaultEmail string) {
 
 		result, err := prompt.Run()
 		if err != nil {
-			os.Exit(1)
+			common.Exit(1)
 			return
 		}
 
@@ -370,7 +370,7 @@ func signupPrompt(defaultFirst, defaultLast, defaultEmail string) {
 
 		result, err := prompt.Run()
 		if err != nil {
-			os.Exit(1)
+			common.Exit(1)
 			return
 		}
 
@@ -386,7 +386,7 @@ func signupPrompt(defaultFirst, defaultLast, defaultEmail string) {
 
 		result, err := prompt.Run()
 		if err != nil {
-			os.Exit(1)
+			common.Exit(1)
 			return
 		}
 
@@ -408,7 +408,7 @@ func orgPrompt(defaultName string) {
 
 		result, err := prompt.Run()
 		if err != nil {
-			os.Exit(1)
+			common.Exit(1)
 			return
 		}
 
@@ -422,7 +422,7 @@ func orgPrompt(defaultName string) {
 
 		result, err := prompt.Run()
 		if err != nil {
-			os.Exit(1)
+			common.Exit(1)
 			return
 		}
 
@@ -19,7 +19,6 @@ package workgroups
 import (
 	"fmt"
 	"log"
-	"os"
 
 	"github.com/provideplatform/provide-cli/prvd/common"
 	"github.com/provideplatform/provide-go/api/axiom"
@@ -41,10 +40,11 @@ func listWorkgroups(cmd *cobra.Command, args []string) {
 }
 
 func listWorkgroupsRun(cmd *cobra.Command, args []string) {
-	token, err := common.ResolveOrganizationToken()
+	ctx := common.ContextFromCommand(cmd)
+	token, err := common.ResolveOrganizationToken(ctx)
 	if err != nil {
 		log.Printf("failed to retrieve axiom workgroups; %s", err.Error())
-		os.Exit(1)
+		common.Exit(1)
 	}
 
 	workgroups, err := axiom.ListWorkgroups(*token.AccessToken, map[string]interface{}{
@@ -53,7 +53,7 @@ func listWorkgroupsRun(cmd *cobra.Command, args []string) {
 	})
 	if err != nil {
 		log.Printf("failed to retrieve axiom workgroups; %s", err.Error())
-		os.Exit(1)
+		common.Exit(1)
 	}
 	for _, workgroup := range workgroups {
 		result := fmt.Sprintf("%s\t%s\n", workgroup.ID, *workgroup.Name)
@@ -62,7 +62,7 @@ func listWorkgroupsRun(cmd *cobra.Command, args []string) {
 }
 
 func init() {
-	listBaselineWorkgroupsCmd.Flags().StringVar(&common.OrganizationID, "organization", "", "organization identifier")
+	listBaselineWorkgroupsCmd.Flags().String("organization", "", "organization identifier")
 
 	listBaselineWorkgroupsCmd.Flags().Uint64Var(&page, "page", common.DefaultPage, "page number to retrieve")
 	listBaselineWorkgroupsCmd.Flags().Uint64Var(&rpp, "rpp", common.DefaultRpp, "number of axiom workgroups to retrieve per page")
@@ -35,6 +35,7 @@ var emptyPromptLabel = "What would you like to do"
 
 // General Endpoints
 func generalPrompt(cmd *cobra.Command, args []string, step string) {
+	ctx := common.ContextFromCommand(cmd)
 	switch step {
 	case promptStepList:
 		page, rpp = common.PromptPagination(paginate, page, rpp)
@@ -44,11 +45,11 @@ func generalPrompt(cmd *cobra.Command, args []string, step string) {
 	case promptStepInit:
 		if Optional {
 			fmt.Println("Optional Flags:")
-			if common.NetworkID == "" {
-				common.RequireL1Network()
+			if ctx.NetworkID == "" {
+				common.RequireL1Network(ctx)
 			}
-			if common.OrganizationID == "" {
-				common.RequireOrganization()
+			if ctx.OrganizationID == "" {
+				common.RequireOrganization(ctx)
 			}
 			if common.MessagingEndpoint == "" {
 				common.MessagingEndpoint = common.FreeInput("Messaging Endpoint", "", common.NoValidation)
@@ -63,8 +64,8 @@ func generalPrompt(cmd *cobra.Command, args []string, step string) {
 	case promptStepJoin:
 		if Optional {
 			fmt.Println("Optional Flags:")
-			if common.OrganizationID == "" {
-				common.RequireOrganization()
+			if ctx.OrganizationID == "" {
+				common.RequireOrganization(ctx)
 			}
 			if inviteJWT == "" {
 				inviteJWT = common.FreeInput("JWT Invite", "", common.NoValidation)
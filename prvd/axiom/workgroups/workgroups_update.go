@@ -19,7 +19,6 @@ package workgroups
 import (
 	"encoding/json"
 	"fmt"
-	"os"
 
 	uuid "github.com/kthomas/go.uuid"
 	"github.com/manifoldco/promptui"
@@ -40,46 +39,47 @@ func updateWorkgroup(cmd *cobra.Command, args []string) {
 }
 
 func updateWorkgroupRun(cmd *cobra.Command, args []string) {
-	if common.OrganizationID == "" {
-		common.RequireOrganization()
+	ctx := common.ContextFromCommand(cmd)
+	if ctx.OrganizationID == "" {
+		common.RequireOrganization(ctx)
 	}
-	if common.WorkgroupID == "" {
-		common.RequireWorkgroup()
+	if ctx.WorkgroupID == "" {
+		common.RequireWorkgroup(ctx)
 	}
 
-	wgID, err := uuid.FromString(common.WorkgroupID)
+	wgID, err := uuid.FromString(ctx.WorkgroupID)
 	if err != nil {
 		fmt.Printf("failed to update axiom workgroup: %s", err.Error())
-		os.Exit(1)
+		common.Exit(1)
 	}
 
-	isOperator := common.Organization.Metadata.Workgroups[wgID].OperatorSeparationDegree == 0
+	isOperator := ctx.Organization.Metadata.Workgroups[wgID].OperatorSeparationDegree == 0
 
-	if err := updateWorkgroupPrompt(common.Workgroup, isOperator); err != nil {
+	if err := updateWorkgroupPrompt(ctx, ctx.Workgroup, isOperator); err != nil {
 		fmt.Printf("failed to update axiom workgroup: %s", err.Error())
-		os.Exit(1)
+		common.Exit(1)
 	}
 
 	var wgParams map[string]interface{}
-	raw, _ := json.Marshal(common.Workgroup)
+	raw, _ := json.Marshal(ctx.Workgroup)
 	json.Unmarshal(raw, &wgParams)
 
-	token, err := common.ResolveOrganizationToken()
+	token, err := common.ResolveOrganizationToken(ctx)
 	if err != nil {
 		fmt.Printf("failed to update axiom workgroup: %s", err.Error())
-		os.Exit(1)
+		common.Exit(1)
 	}
 
-	if err := axiom.UpdateWorkgroup(*token.AccessToken, common.WorkgroupID, wgParams); err != nil {
+	if err := axiom.UpdateWorkgroup(*token.AccessToken, ctx.WorkgroupID, wgParams); err != nil {
 		fmt.Printf("failed to update axiom workgroup: %s", err.Error())
-		os.Exit(1)
+		common.Exit(1)
 	}
 
 	result, _ := json.MarshalIndent(wgParams, "", "\t")
 	fmt.Printf("%s\n", string(result))
 }
 
-func updateWorkgroupPrompt(wg *common.WorkgroupType, isOperator bool) error {
+func updateWorkgroupPrompt(ctx *common.Context, wg *common.WorkgroupType, isOperator bool) error {
 	// name
 	if name == "" {
 		prompt := promptui.Prompt{
@@ -96,7 +96,7 @@ func updateWorkgroupPrompt(wg *common.WorkgroupType, isOperator bool) error {
 
 		result, err := prompt.Run()
 		if err != nil {
-			os.Exit(1)
+			common.Exit(1)
 		}
 
 		name = result
@@ -117,7 +117,7 @@ func updateWorkgroupPrompt(wg *common.WorkgroupType, isOperator bool) error {
 
 		result, err := prompt.Run()
 		if err != nil {
-			os.Exit(1)
+			common.Exit(1)
 		}
 
 		description = result
@@ -134,26 +134,26 @@ func updateWorkgroupPrompt(wg *common.WorkgroupType, isOperator bool) error {
 
 	// layers
 	if isOperator {
-		if common.NetworkID == "" {
-			common.RequireL1Network()
+		if ctx.NetworkID == "" {
+			common.RequireL1Network(ctx)
 		}
-		uuidNetworkID, err := uuid.FromString(common.NetworkID)
+		uuidNetworkID, err := uuid.FromString(ctx.NetworkID)
 		if err != nil {
 			fmt.Printf("failed to update axiom workgroup: %s", err.Error())
-			os.Exit(1)
+			common.Exit(1)
 		}
 		*wg.NetworkID = uuidNetworkID
 
-		if common.L2NetworkID == "" {
-			common.RequireL2Network()
+		if ctx.L2NetworkID == "" {
+			common.RequireL2Network(ctx)
 		}
-		uuidL2NetworkID, err := uuid.FromString(common.L2NetworkID)
+		uuidL2NetworkID, err := uuid.FromString(ctx.L2NetworkID)
 		if err != nil {
 			fmt.Printf("failed to update axiom workgroup: %s", err.Error())
-			os.Exit(1)
+			common.Exit(1)
 		}
 		*wg.Config.L2NetworkID = uuidL2NetworkID
-	} else if !isOperator && (common.NetworkID != "" || common.L2NetworkID != "") {
+	} else if !isOperator && (ctx.NetworkID != "" || ctx.L2NetworkID != "") {
 		return fmt.Errorf("workgroup participants cannot update layers\n")
 	}
 
@@ -161,10 +161,10 @@ func updateWorkgroupPrompt(wg *common.WorkgroupType, isOperator bool) error {
 }
 
 func init() {
-	updateBaselineWorkgroupCmd.Flags().StringVar(&common.OrganizationID, "organization", "", "organization identifier")
-	updateBaselineWorkgroupCmd.Flags().StringVar(&common.WorkgroupID, "workgroup", "", "workgroup identifier")
+	updateBaselineWorkgroupCmd.Flags().String("organization", "", "organization identifier")
+	updateBaselineWorkgroupCmd.Flags().String("workgroup", "", "workgroup identifier")
 	updateBaselineWorkgroupCmd.Flags().StringVar(&name, "name", "", "name of the axiom workgroup")
 	updateBaselineWorkgroupCmd.Flags().StringVar(&description, "description", "", "description of the axiom workgroup")
-	updateBaselineWorkgroupCmd.Flags().StringVar(&common.NetworkID, "network", "", "nchain network id of the axiom mainnet to use for this workgroup")
-	updateBaselineWorkgroupCmd.Flags().StringVar(&common.L2NetworkID, "l2", "", "nchain l2 network id of the axiom layer 2 to use for this workgroup")
+	updateBaselineWorkgroupCmd.Flags().String("network", "", "nchain network id of the axiom mainnet to use for this workgroup")
+	updateBaselineWorkgroupCmd.Flags().String("l2", "", "nchain l2 network id of the axiom layer 2 to use for this workgroup")
 }
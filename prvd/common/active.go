@@ -0,0 +1,90 @@
+/*
+ * Copyright 2017-2022 Provide Technologies Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package common
+
+import (
+	"os"
+
+	"github.com/spf13/viper"
+)
+
+const (
+	ActiveOrganizationIDConfigKey          = "active-organization-id"           // organization selected using `prvd use organization`
+	ActiveOrganizationNameConfigKey        = "active-organization-name"         // name of the selected organization, for display only
+	ActiveWorkgroupIDConfigKey             = "active-workgroup-id"              // workgroup selected using `prvd use workgroup`
+	ActiveWorkgroupNameConfigKey           = "active-workgroup-name"            // name of the selected workgroup, for display only
+	ActiveWorkgroupOrganizationIDConfigKey = "active-workgroup-organization-id" // organization in which the selected workgroup was resolved
+	organizationIDEnvVar                   = "PROVIDE_ORGANIZATION_ID"
+	workgroupIDEnvVar                      = "PROVIDE_WORKGROUP_ID"
+)
+
+// ActiveOrganization returns the id and name of the organization selected using `prvd use organization`
+func ActiveOrganization() (string, string) {
+	return viper.GetString(ActiveOrganizationIDConfigKey), viper.GetString(ActiveOrganizationNameConfigKey)
+}
+
+// ActiveWorkgroup returns the id and name of the workgroup selected using `prvd use workgroup`
+func ActiveWorkgroup() (string, string) {
+	return viper.GetString(ActiveWorkgroupIDConfigKey), viper.GetString(ActiveWorkgroupNameConfigKey)
+}
+
+// SetActiveOrganization persists the given organization as the active organization;
+// the active workgroup is cleared when it does not belong to the given organization
+func SetActiveOrganization(id, name string) error {
+	if viper.GetString(ActiveWorkgroupOrganizationIDConfigKey) != id {
+		viper.Set(ActiveWorkgroupIDConfigKey, "")
+		viper.Set(ActiveWorkgroupNameConfigKey, "")
+		viper.Set(ActiveWorkgroupOrganizationIDConfigKey, "")
+	}
+
+	viper.Set(ActiveOrganizationIDConfigKey, id)
+	viper.Set(ActiveOrganizationNameConfigKey, name)
+	return viper.WriteConfig()
+}
+
+// SetActiveWorkgroup persists the given workgroup, which was resolved within the given organization,
+// as the active workgroup
+func SetActiveWorkgroup(id, name, organizationID string) error {
+	viper.Set(ActiveWorkgroupIDConfigKey, id)
+	viper.Set(ActiveWorkgroupNameConfigKey, name)
+	viper.Set(ActiveWorkgroupOrganizationIDConfigKey, organizationID)
+	return viper.WriteConfig()
+}
+
+// resolveDefaultOrganizationID returns the PROVIDE_ORGANIZATION_ID environment variable, if set,
+// or the active organization; it is consulted by RequireOrganization prior to prompting
+func resolveDefaultOrganizationID() string {
+	if id := os.Getenv(organizationIDEnvVar); id != "" {
+		return id
+	}
+	return viper.GetString(ActiveOrganizationIDConfigKey)
+}
+
+// resolveDefaultWorkgroupID returns the PROVIDE_WORKGROUP_ID environment variable, if set, or the
+// active workgroup when it belongs to the given organization; it is consulted by RequireWorkgroup
+// prior to prompting
+func resolveDefaultWorkgroupID(organizationID string) string {
+	if id := os.Getenv(workgroupIDEnvVar); id != "" {
+		return id
+	}
+
+	id := viper.GetString(ActiveWorkgroupIDConfigKey)
+	if id != "" && organizationID != "" && viper.GetString(ActiveWorkgroupOrganizationIDConfigKey) != organizationID {
+		return ""
+	}
+	return id
+}
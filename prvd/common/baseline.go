@@ -91,54 +91,46 @@ var ExposeWebsocketMessagingTunnel bool
 var MessagingEndpoint string
 var tunnelClient *pgrok.Client
 
-var ApplicationAccessToken string
-var OrganizationAccessToken string
-var OrganizationRefreshToken string
+func AuthorizeApplicationContext(ctx *Context) {
+	RequireWorkgroup(ctx)
 
-var VaultID string
-
-var ResolvedBaselineOrgAddress string // HACK
-
-func AuthorizeApplicationContext() {
-	RequireWorkgroup()
-
-	token, err := ident.CreateToken(RequireUserAccessToken(), map[string]interface{}{
+	token, err := ident.CreateToken(RequireUserAccessToken(ctx), map[string]interface{}{
 		"scope":          "offline_access",
-		"application_id": ApplicationID,
+		"application_id": ctx.ApplicationID,
 	})
 	if err != nil {
-		log.Printf("failed to authorize API access token on behalf of application %s; %s", ApplicationID, err.Error())
-		os.Exit(1)
+		log.Printf("failed to authorize API access token on behalf of application %s; %s", ctx.ApplicationID, err.Error())
+		Exit(1)
 	}
 
 	if token.AccessToken != nil {
-		ApplicationAccessToken = *token.AccessToken
+		ctx.ApplicationAccessToken = *token.AccessToken
 	}
 }
 
-func AuthorizeOrganizationContext(persist bool) {
-	RequireOrganization()
+func AuthorizeOrganizationContext(ctx *Context, persist bool) {
+	RequireOrganization(ctx)
 
-	token, err := ident.CreateToken(RequireUserAccessToken(), map[string]interface{}{
+	token, err := ident.CreateToken(RequireUserAccessToken(ctx), map[string]interface{}{
 		"scope":           "offline_access",
-		"organization_id": OrganizationID,
+		"organization_id": ctx.OrganizationID,
 	})
 	if err != nil {
-		log.Printf("failed to authorize API access token on behalf of organization %s; %s", OrganizationID, err.Error())
-		os.Exit(1)
+		log.Printf("failed to authorize API access token on behalf of organization %s; %s", ctx.OrganizationID, err.Error())
+		Exit(1)
 	}
 
 	if token.AccessToken != nil {
-		OrganizationAccessToken = *token.AccessToken
+		ctx.OrganizationAccessToken = *token.AccessToken
 
 		if token.RefreshToken != nil {
-			OrganizationRefreshToken = *token.RefreshToken
+			ctx.OrganizationRefreshToken = *token.RefreshToken
 		}
 
 		if persist {
 			// FIXME-- DRY this up (also exists in api_tokens_init.go)
-			orgAPIAccessTokenKey := BuildConfigKeyWithID(AccessTokenConfigKey, OrganizationID)
-			orgAPIRefreshTokenKey := BuildConfigKeyWithID(RefreshTokenConfigKey, OrganizationID)
+			orgAPIAccessTokenKey := BuildConfigKeyWithID(AccessTokenConfigKey, ctx.OrganizationID)
+			orgAPIRefreshTokenKey := BuildConfigKeyWithID(RefreshTokenConfigKey, ctx.OrganizationID)
 
 			if token.AccessToken != nil {
 				// fmt.Printf("Access token authorized for organization: %s\t%s\n", OrganizationID, *token.AccessToken)
@@ -158,13 +150,13 @@ func AuthorizeOrganizationContext(persist bool) {
 	}
 }
 
-func InitWorkgroupContract(contractAddress string) *nchain.Contract {
-	wallet, err := nchain.CreateWallet(OrganizationAccessToken, map[string]interface{}{
+func InitWorkgroupContract(ctx *Context, contractAddress string) *nchain.Contract {
+	wallet, err := nchain.CreateWallet(ctx.OrganizationAccessToken, map[string]interface{}{
 		"purpose": 44,
 	})
 	if err != nil {
 		log.Printf("failed to initialize wallet for organization; %s", err.Error())
-		os.Exit(1)
+		Exit(1)
 	}
 
 	compiledArtifact := resolveBaselineOrgRegistryContractArtifact()
@@ -179,14 +171,14 @@ func InitWorkgroupContract(contractAddress string) *nchain.Contract {
 
 	if compiledArtifact == nil {
 		log.Printf("failed to resolve global axiom organization registry contract artifact")
-		os.Exit(1)
+		Exit(1)
 	}
 
 	log.Printf("deploying global axiom organization registry contract: %s", defaultBaselineRegistryContractName)
-	contract, err := nchain.CreateContract(OrganizationAccessToken, map[string]interface{}{
+	contract, err := nchain.CreateContract(ctx.OrganizationAccessToken, map[string]interface{}{
 		"address":    contractAddress,
 		"name":       contractName,
-		"network_id": NetworkID,
+		"network_id": ctx.NetworkID,
 		"params": map[string]interface{}{
 			"argv":              []interface{}{},
 			"compiled_artifact": compiledArtifact,
@@ -196,14 +188,14 @@ func InitWorkgroupContract(contractAddress string) *nchain.Contract {
 	})
 	if err != nil {
 		log.Printf("failed to initialize registry contract; %s", err.Error())
-		os.Exit(1)
+		Exit(1)
 	}
 
 	if contractAddress == "0x" {
-		contract, err := RequireContract(nil, common.StringOrNil(defaultBaselineOrgRegistryContractType), true)
+		contract, err := RequireContract(ctx, nil, common.StringOrNil(defaultBaselineOrgRegistryContractType), true)
 		if err != nil {
 			log.Printf("failed to initialize registry contract; %s", err.Error())
-			os.Exit(1)
+			Exit(1)
 		}
 
 		return contract
@@ -212,74 +204,74 @@ func InitWorkgroupContract(contractAddress string) *nchain.Contract {
 	return contract
 }
 
-func RegisterWorkgroupOrganization(workgroupID string) {
-	_, err := RequireContract(nil, util.StringOrNil(defaultBaselineOrgRegistryContractType), false)
+func RegisterWorkgroupOrganization(ctx *Context, workgroupID string) {
+	_, err := RequireContract(ctx, nil, util.StringOrNil(defaultBaselineOrgRegistryContractType), false)
 	if err != nil {
 		log.Printf("failed to initialize registry contract; %s", err.Error())
-		os.Exit(1)
+		Exit(1)
 	}
-	err = ident.CreateApplicationOrganization(OrganizationAccessToken, workgroupID, map[string]interface{}{
-		"organization_id": OrganizationID,
+	err = ident.CreateApplicationOrganization(ctx.OrganizationAccessToken, workgroupID, map[string]interface{}{
+		"organization_id": ctx.OrganizationID,
 	})
 	if err != nil {
-		orgs, err := ident.ListApplicationOrganizations(OrganizationAccessToken, workgroupID, map[string]interface{}{
-			"organization_id": OrganizationID,
+		orgs, err := ident.ListApplicationOrganizations(ctx.OrganizationAccessToken, workgroupID, map[string]interface{}{
+			"organization_id": ctx.OrganizationID,
 		})
 		if err == nil {
 			// FIXME--
 			for _, org := range orgs {
-				if org.ID != nil && *org.ID == OrganizationID {
+				if org.ID != nil && *org.ID == ctx.OrganizationID {
 					return
 				}
 			}
-			if len(orgs) > 0 && orgs[0].ID != nil && *orgs[0].ID == OrganizationID {
+			if len(orgs) > 0 && orgs[0].ID != nil && *orgs[0].ID == ctx.OrganizationID {
 				return
 			}
 		}
 		log.Printf("WARNING: organization not associated with workgroup")
-		os.Exit(1)
+		Exit(1)
 	}
 }
 
-func RequireOrganizationVault() error {
-	if OrganizationAccessToken == "" {
+func RequireOrganizationVault(ctx *Context) error {
+	if ctx.OrganizationAccessToken == "" {
 		return fmt.Errorf("organization access token not found")
 	}
 
 	// FIXME-- parameterize with --vault or similar?
-	vaults, err := vault.ListVaults(OrganizationAccessToken, map[string]interface{}{})
+	vaults, err := vault.ListVaults(ctx.OrganizationAccessToken, map[string]interface{}{})
 	if err != nil {
 		return err
 	}
 
 	if len(vaults) > 0 {
-		VaultID = vaults[0].ID.String()
+		ctx.VaultID = vaults[0].ID.String()
 		return nil
 	}
 
-	vault, err := vault.CreateVault(OrganizationAccessToken, map[string]interface{}{
-		"name":        fmt.Sprintf("vault for organization: %s", OrganizationID),
-		"description": fmt.Sprintf("identity/signing keystore for organization: %s", OrganizationID),
+	vault, err := vault.CreateVault(ctx.OrganizationAccessToken, map[string]interface{}{
+		"name":        fmt.Sprintf("vault for organization: %s", ctx.OrganizationID),
+		"description": fmt.Sprintf("identity/signing keystore for organization: %s", ctx.OrganizationID),
 	})
 	if err != nil {
 		return err
 	}
 
-	VaultID = vault.ID.String()
+	ctx.VaultID = vault.ID.String()
 	return nil
 }
 
-func RequireOrganizationKeypair(spec string) (*vault.Key, error) {
-	if VaultID == "" {
-		RequireOrganizationVault()
+func RequireOrganizationKeypair(ctx *Context, spec string) (*vault.Key, error) {
+	if ctx.VaultID == "" {
+		RequireOrganizationVault(ctx)
 	}
 
 	// FIXME-- parameterize each key i.e. --secp256k1-key or similar?
-	keys, err := vault.ListKeys(OrganizationAccessToken, VaultID, map[string]interface{}{
+	keys, err := vault.ListKeys(ctx.OrganizationAccessToken, ctx.VaultID, map[string]interface{}{
 		"spec": spec,
 	})
 	if err != nil {
-		log.Printf("failed to retrieve %s keys for organization: %s; %s", spec, OrganizationID, err.Error())
+		log.Printf("failed to retrieve %s keys for organization: %s; %s", spec, ctx.OrganizationID, err.Error())
 		return nil, err
 	}
 
@@ -287,9 +279,9 @@ func RequireOrganizationKeypair(spec string) (*vault.Key, error) {
 		return keys[0], nil
 	}
 
-	key, err := vault.CreateKey(OrganizationAccessToken, VaultID, map[string]interface{}{
-		"name":        fmt.Sprintf("%s key organization: %s", spec, OrganizationID),
-		"description": fmt.Sprintf("%s key organization: %s", spec, OrganizationID),
+	key, err := vault.CreateKey(ctx.OrganizationAccessToken, ctx.VaultID, map[string]interface{}{
+		"name":        fmt.Sprintf("%s key organization: %s", spec, ctx.OrganizationID),
+		"description": fmt.Sprintf("%s key organization: %s", spec, ctx.OrganizationID),
 		"spec":        spec,
 		"type":        "asymmetric",
 		"usage":       "sign/verify",
@@ -301,7 +293,7 @@ func RequireOrganizationKeypair(spec string) (*vault.Key, error) {
 	return key, nil
 }
 
-func RequireContract(contractID, contractType *string, printCreationTxLink bool) (*nchain.Contract, error) {
+func RequireContract(ctx *Context, contractID, contractType *string, printCreationTxLink bool) (*nchain.Contract, error) {
 	startTime := time.Now()
 	timer := time.NewTicker(requireContractTickerInterval)
 
@@ -313,9 +305,9 @@ func RequireContract(contractID, contractType *string, printCreationTxLink bool)
 			var contract *nchain.Contract
 			var err error
 			if contractID != nil {
-				contract, err = nchain.GetContractDetails(OrganizationAccessToken, *contractID, map[string]interface{}{})
+				contract, err = nchain.GetContractDetails(ctx.OrganizationAccessToken, *contractID, map[string]interface{}{})
 			} else if contractType != nil {
-				contracts, _ := nchain.ListContracts(OrganizationAccessToken, map[string]interface{}{
+				contracts, _ := nchain.ListContracts(ctx.OrganizationAccessToken, map[string]interface{}{
 					"type": contractType,
 				})
 				if len(contracts) > 0 {
@@ -326,11 +318,11 @@ func RequireContract(contractID, contractType *string, printCreationTxLink bool)
 			// FIXME-- KT-- review removal of contract.TransactionID != nil condition
 			if err == nil && contract != nil {
 				if !printed && printCreationTxLink && contract.TransactionID != nil {
-					tx, _ := nchain.GetTransactionDetails(OrganizationAccessToken, contract.TransactionID.String(), map[string]interface{}{})
+					tx, _ := nchain.GetTransactionDetails(ctx.OrganizationAccessToken, contract.TransactionID.String(), map[string]interface{}{})
 					if tx.Hash != nil {
-						etherscanBaseURL := EtherscanBaseURL(tx.NetworkID.String())
-						if etherscanBaseURL != nil {
-							log.Printf("View on Etherscan: %s/tx/%s", *etherscanBaseURL, *tx.Hash) // HACK
+						explorer := ResolveExplorer(ctx.OrganizationAccessToken, tx.NetworkID.String())
+						if explorer != nil {
+							log.Printf("View on block explorer: %s", explorer.TxURL(*tx.Hash))
 						} else {
 							log.Printf("Transaction hash: %s", *tx.Hash)
 						}
@@ -339,8 +331,8 @@ func RequireContract(contractID, contractType *string, printCreationTxLink bool)
 				}
 
 				if contract.Address != nil && *contract.Address != "0x" {
-					if Verbose {
-						tx, _ := nchain.GetTransactionDetails(OrganizationAccessToken, contract.TransactionID.String(), map[string]interface{}{})
+					if ctx.Verbose {
+						tx, _ := nchain.GetTransactionDetails(ctx.OrganizationAccessToken, contract.TransactionID.String(), map[string]interface{}{})
 						txraw, _ := json.MarshalIndent(tx, "", "  ")
 						log.Printf("%s", string(txraw))
 					}
@@ -413,71 +405,71 @@ func resolveBaselineOrgRegistryContractArtifact() *nchain.CompiledArtifact {
 
 // RequireOrganizationEndpoints fn is the function to call after the tunnel has been established,
 // prior to the runloop and signal handling is installed
-func RequireOrganizationEndpoints(fn func(), tunnelShutdownFn func(*string), apiPort, messagingPort, websocketMessagingPort int) {
+func RequireOrganizationEndpoints(ctx *Context, fn func(), tunnelShutdownFn func(*string), apiPort, messagingPort, websocketMessagingPort int) {
 	run := func() {
-		if OrganizationID == "" {
+		if ctx.OrganizationID == "" {
 			fmt.Println("WARNING: failed to set organization endpoints; organization id not set")
-			os.Exit(1)
+			Exit(1)
 		}
 
-		if WorkgroupID == "" {
+		if ctx.WorkgroupID == "" {
 			fmt.Println("WARNING: failed to set organization endpoints; workgroup id not set")
-			os.Exit(1)
+			Exit(1)
 		}
 
-		wgID, err := uuid.FromString(WorkgroupID)
+		wgID, err := uuid.FromString(ctx.WorkgroupID)
 		if err != nil {
 			log.Printf("WARNING: failed to update organization; %s", err.Error())
-			os.Exit(1)
+			Exit(1)
 		}
 
-		if Organization.Metadata == nil {
-			Organization.Metadata = &OrganizationMetadata{}
+		if ctx.Organization.Metadata == nil {
+			ctx.Organization.Metadata = &OrganizationMetadata{}
 		}
 
-		if Organization.Metadata.Workgroups == nil {
-			Organization.Metadata.Workgroups = map[uuid.UUID]*OrganizationWorkgroupMetadata{}
+		if ctx.Organization.Metadata.Workgroups == nil {
+			ctx.Organization.Metadata.Workgroups = map[uuid.UUID]*OrganizationWorkgroupMetadata{}
 		}
 
-		if Organization.Metadata.Workgroups[wgID] == nil {
-			Organization.Metadata.Workgroups[wgID] = &OrganizationWorkgroupMetadata{}
+		if ctx.Organization.Metadata.Workgroups[wgID] == nil {
+			ctx.Organization.Metadata.Workgroups[wgID] = &OrganizationWorkgroupMetadata{}
 		}
 
-		key, err := RequireOrganizationKeypair("secp256k1")
+		key, err := RequireOrganizationKeypair(ctx, "secp256k1")
 		if err != nil {
 			log.Printf("WARNING: failed to update organization; %s", err.Error())
-			os.Exit(1)
+			Exit(1)
 		}
 
-		Organization.Metadata.Address = *key.Address
-		ResolvedBaselineOrgAddress = *key.Address
+		ctx.Organization.Metadata.Address = *key.Address
+		ctx.ResolvedBaselineOrgAddress = *key.Address
 
 		if BPIEndpoint != "" {
-			Organization.Metadata.BPIEndpoint = BPIEndpoint
+			ctx.Organization.Metadata.BPIEndpoint = BPIEndpoint
 		} else {
-			Organization.Metadata.BPIEndpoint = "http://localhost:8080"
+			ctx.Organization.Metadata.BPIEndpoint = "http://localhost:8080"
 		}
 
 		if MessagingEndpoint != "" {
-			Organization.Metadata.MessagingEndpoint = MessagingEndpoint
+			ctx.Organization.Metadata.MessagingEndpoint = MessagingEndpoint
 		} else {
-			Organization.Metadata.MessagingEndpoint = "nats://localhost:4222"
+			ctx.Organization.Metadata.MessagingEndpoint = "nats://localhost:4222"
 		}
 
-		Organization.Metadata.Domain = "axiom.local" // FIXME-- read domain from args...
-		Organization.Metadata.Workgroups[wgID].BPIEndpoint = &Organization.Metadata.BPIEndpoint
-		Organization.Metadata.Workgroups[wgID].MessagingEndpoint = &Organization.Metadata.MessagingEndpoint
+		ctx.Organization.Metadata.Domain = "axiom.local" // FIXME-- read domain from args...
+		ctx.Organization.Metadata.Workgroups[wgID].BPIEndpoint = &ctx.Organization.Metadata.BPIEndpoint
+		ctx.Organization.Metadata.Workgroups[wgID].MessagingEndpoint = &ctx.Organization.Metadata.MessagingEndpoint
 
 		var org map[string]interface{}
-		raw, _ := json.Marshal(Organization)
+		raw, _ := json.Marshal(ctx.Organization)
 		json.Unmarshal(raw, &org)
 
-		if err := ident.UpdateOrganization(OrganizationAccessToken, OrganizationID, org); err != nil {
+		if err := ident.UpdateOrganization(ctx.OrganizationAccessToken, ctx.OrganizationID, org); err != nil {
 			log.Printf("WARNING: failed to update organization; %s", err.Error())
-			os.Exit(1)
+			Exit(1)
 		}
 		log.Printf("successfully set BPI endpoint: %s; messaging endpoint: %s on organization %s\n",
-			Organization.Metadata.BPIEndpoint, Organization.Metadata.MessagingEndpoint, OrganizationID)
+			ctx.Organization.Metadata.BPIEndpoint, ctx.Organization.Metadata.MessagingEndpoint, ctx.OrganizationID)
 
 		if fn != nil {
 			fn()
@@ -491,14 +483,14 @@ func RequireOrganizationEndpoints(fn func(), tunnelShutdownFn func(*string), api
 
 	if WithoutTunnels && (ExposeBPITunnel || ExposeMessagingTunnel || ExposeWebsocketMessagingTunnel) {
 		log.Printf("WARNING: conflicting tunnel arguments provided; --without-tunnels must not be contradicted")
-		os.Exit(1)
+		Exit(1)
 	}
 
 	if !ExposeBPITunnel && !ExposeMessagingTunnel {
 		publicIP, err := util.ResolvePublicIP()
 		if err != nil {
 			log.Printf("WARNING: failed to resolve public IP")
-			os.Exit(1)
+			Exit(1)
 		}
 
 		if BPIEndpoint == "" {
@@ -533,7 +525,6 @@ func RequireOrganizationEndpoints(fn func(), tunnelShutdownFn func(*string), api
 				log.Print("shutting down")
 				tunnelClient.Close()
 				cancelF()
-				os.Exit(0)
 			}
 		}
 
@@ -553,21 +544,24 @@ func RequireOrganizationEndpoints(fn func(), tunnelShutdownFn func(*string), api
 			})
 		}
 
+		// the tunnel goroutines report failures using tunnelErrs; only this goroutine may exit
+		tunnelErrs := make(chan error, 3)
+
 		go func() {
 			var err error
 			tunnelClient, err = pgrok.Factory()
 			if err != nil {
-				log.Printf("WARNING: failed to initialize tunnel; %s", err.Error())
-				os.Exit(1)
+				tunnelErrs <- fmt.Errorf("failed to initialize tunnel; %s", err.Error())
+				return
 			}
 
 			if ExposeBPITunnel {
 				tunnel, _ := tunnelClient.TunnelFactory(
-					fmt.Sprintf("%s-api", OrganizationID),
+					fmt.Sprintf("%s-api", ctx.OrganizationID),
 					fmt.Sprintf("127.0.0.1:%d", apiPort),
 					nil,
 					common.StringOrNil("https"),
-					common.StringOrNil(OrganizationAccessToken),
+					common.StringOrNil(ctx.OrganizationAccessToken),
 					_tunnelShutdownFn,
 				)
 				tunnelClient.AddTunnel(tunnel)
@@ -575,11 +569,11 @@ func RequireOrganizationEndpoints(fn func(), tunnelShutdownFn func(*string), api
 
 			if ExposeMessagingTunnel {
 				tunnel, _ := tunnelClient.TunnelFactory(
-					fmt.Sprintf("%s-msg", OrganizationID),
+					fmt.Sprintf("%s-msg", ctx.OrganizationID),
 					fmt.Sprintf("127.0.0.1:%d", messagingPort),
 					nil,
 					common.StringOrNil("tcp"),
-					common.StringOrNil(OrganizationAccessToken),
+					common.StringOrNil(ctx.OrganizationAccessToken),
 					_tunnelShutdownFn,
 				)
 				tunnelClient.AddTunnel(tunnel)
@@ -587,11 +581,11 @@ func RequireOrganizationEndpoints(fn func(), tunnelShutdownFn func(*string), api
 
 			if ExposeWebsocketMessagingTunnel {
 				tunnel, _ := tunnelClient.TunnelFactory(
-					fmt.Sprintf("%s-wss", OrganizationID),
+					fmt.Sprintf("%s-wss", ctx.OrganizationID),
 					fmt.Sprintf("127.0.0.1:%d", websocketMessagingPort),
 					nil,
 					common.StringOrNil("https"),
-					common.StringOrNil(OrganizationAccessToken),
+					common.StringOrNil(ctx.OrganizationAccessToken),
 					_tunnelShutdownFn,
 				)
 				tunnelClient.AddTunnel(tunnel)
@@ -599,8 +593,8 @@ func RequireOrganizationEndpoints(fn func(), tunnelShutdownFn func(*string), api
 
 			err = tunnelClient.ConnectAll()
 			if err != nil {
-				log.Printf("WARNING: failed to initialize tunnel(s); %s", err.Error())
-				os.Exit(1)
+				tunnelErrs <- fmt.Errorf("failed to initialize tunnel(s); %s", err.Error())
+				return
 			}
 
 			if ExposeBPITunnel {
@@ -632,8 +626,8 @@ func RequireOrganizationEndpoints(fn func(), tunnelShutdownFn func(*string), api
 				startTime := time.Now()
 				for BPIEndpoint == "" {
 					if startTime.Add(requireOrganizationAPIEndpointTimeout).Before(time.Now()) {
-						log.Printf("WARNING: organization API endpoint tunnel timed out")
-						os.Exit(1)
+						tunnelErrs <- errors.New("organization API endpoint tunnel timed out")
+						return
 					}
 					time.Sleep(time.Millisecond * 10)
 				}
@@ -645,8 +639,8 @@ func RequireOrganizationEndpoints(fn func(), tunnelShutdownFn func(*string), api
 				startTime := time.Now()
 				for MessagingEndpoint == "" {
 					if startTime.Add(requireOrganizationMessagingEndpointTimeout).Before(time.Now()) {
-						log.Printf("WARNING: organization messaging endpoint tunnel timed out")
-						os.Exit(1)
+						tunnelErrs <- errors.New("organization messaging endpoint tunnel timed out")
+						return
 					}
 					time.Sleep(time.Millisecond * 10)
 				}
@@ -654,7 +648,13 @@ func RequireOrganizationEndpoints(fn func(), tunnelShutdownFn func(*string), api
 		}
 
 		for (ExposeBPITunnel && BPIEndpoint == "") || (ExposeMessagingTunnel && MessagingEndpoint == "") {
-			time.Sleep(time.Millisecond * 10)
+			select {
+			case err := <-tunnelErrs:
+				log.Printf("WARNING: %s", err.Error())
+				Exit(1)
+			default:
+				time.Sleep(time.Millisecond * 10)
+			}
 		}
 
 		run()
@@ -679,5 +679,6 @@ func RequireOrganizationEndpoints(fn func(), tunnelShutdownFn func(*string), api
 
 		log.Printf("exiting tunnel runloop")
 		cancelF()
+		Exit(0)
 	}
 }
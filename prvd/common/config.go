@@ -59,7 +59,7 @@ func InitConfig() {
 		home, err := homedir.Dir()
 		if err != nil {
 			fmt.Println(err)
-			os.Exit(1)
+			Exit(1)
 		}
 
 		// Search config in home directory with name ".provide-cli" (without extension).
@@ -84,16 +84,12 @@ func InitConfig() {
 		fmt.Printf("WARNING: failed to read configuration; %s", err.Error())
 	} else {
 		os.Chmod(viper.ConfigFileUsed(), 0600)
-
-		if Verbose {
-			fmt.Println("Using configuration:", viper.ConfigFileUsed())
-		}
 	}
 }
 
-func RequireUserAccessToken() string {
-	if UserAccessToken != "" {
-		return UserAccessToken
+func RequireUserAccessToken(ctx *Context) string {
+	if ctx.UserAccessToken != "" {
+		return ctx.UserAccessToken
 	}
 
 	token := ""
@@ -103,7 +99,7 @@ func RequireUserAccessToken() string {
 
 	if token == "" || isTokenExpired(token) {
 		log.Printf("Authorized API access token required in prvd configuration; run 'authenticate'")
-		os.Exit(1)
+		Exit(1)
 	}
 
 	if isTokenExpired(token) {
@@ -132,7 +128,7 @@ func refreshToken(token string, id *string) {
 	})
 	if err != nil {
 		log.Println(err)
-		os.Exit(1)
+		Exit(1)
 	}
 
 	if resp != nil {
@@ -163,44 +159,44 @@ func CacheAccessRefreshToken(token *ident.Token, id *string) {
 	viper.WriteConfig()
 }
 
-func RequireApplicationToken() string {
+func RequireApplicationToken(ctx *Context) string {
 	var token string
-	tokenKey := BuildConfigKeyWithID(AccessTokenConfigKey, ApplicationID)
+	tokenKey := BuildConfigKeyWithID(AccessTokenConfigKey, ctx.ApplicationID)
 	if viper.IsSet(tokenKey) {
 		token = viper.GetString(tokenKey)
 	}
 
 	if token == "" || isTokenExpired(token) {
 		log.Printf("Authorized application API token required in prvd configuration; run 'prvd api_tokens init --application <id>'")
-		os.Exit(1)
+		Exit(1)
 	}
 
 	return token
 }
 
-func ResolveOrganizationToken() (*ident.Token, error) {
+func ResolveOrganizationToken(ctx *Context) (*ident.Token, error) {
 	var accessToken string
 	var refreshToken string
 
-	if OrganizationID == "" {
-		if err := RequireOrganization(); err != nil {
+	if ctx.OrganizationID == "" {
+		if err := RequireOrganization(ctx); err != nil {
 			return nil, err
 		}
 	}
 
-	accessTokenKey := BuildConfigKeyWithID(AccessTokenConfigKey, OrganizationID)
+	accessTokenKey := BuildConfigKeyWithID(AccessTokenConfigKey, ctx.OrganizationID)
 	if viper.IsSet(accessTokenKey) {
 		accessToken = viper.GetString(accessTokenKey)
 	}
 
-	refreshTokenKey := BuildConfigKeyWithID(RefreshTokenConfigKey, OrganizationID)
+	refreshTokenKey := BuildConfigKeyWithID(RefreshTokenConfigKey, ctx.OrganizationID)
 	if viper.IsSet(refreshTokenKey) {
 		refreshToken = viper.GetString(refreshTokenKey)
 	}
 
 	if accessToken == "" || refreshToken == "" || isTokenExpired(accessToken) || isTokenExpired(refreshToken) {
-		t, err := ident.CreateToken(RequireUserAccessToken(), map[string]interface{}{
-			"organization_id": OrganizationID,
+		t, err := ident.CreateToken(RequireUserAccessToken(ctx), map[string]interface{}{
+			"organization_id": ctx.OrganizationID,
 			"scope":           "offline_access",
 		})
 		if err != nil {
@@ -221,17 +217,17 @@ func ResolveOrganizationToken() (*ident.Token, error) {
 	}, nil
 }
 
-func RequireAPIToken() string {
+func RequireAPIToken(ctx *Context) string {
 	var token string
 	var tokenKey string
 	var id *string
 
-	if ApplicationID != "" {
-		tokenKey = BuildConfigKeyWithID(AccessTokenConfigKey, ApplicationID)
-		id = &ApplicationID
-	} else if OrganizationID != "" {
-		tokenKey = BuildConfigKeyWithID(AccessTokenConfigKey, OrganizationID)
-		id = &OrganizationID
+	if ctx.ApplicationID != "" {
+		tokenKey = BuildConfigKeyWithID(AccessTokenConfigKey, ctx.ApplicationID)
+		id = &ctx.ApplicationID
+	} else if ctx.OrganizationID != "" {
+		tokenKey = BuildConfigKeyWithID(AccessTokenConfigKey, ctx.OrganizationID)
+		id = &ctx.OrganizationID
 	} else {
 		tokenKey = AccessTokenConfigKey
 	}
@@ -244,13 +240,13 @@ func RequireAPIToken() string {
 				return viper.GetString(tokenKey)
 			}
 		}
-		return RequireUserAccessToken()
+		return RequireUserAccessToken(ctx)
 	}
 
 	token = requireToken()
 	if token == "" {
 		log.Printf("Authorized API access token required in prvd configuration; run 'authenticate'")
-		os.Exit(1)
+		Exit(1)
 	}
 
 	return token
@@ -267,6 +263,30 @@ func BuildConfigKeyWithID(keyPartial, id string) string {
 	return fmt.Sprintf("%s.%s", id, keyPartial)
 }
 
+// TokenExpiry returns the expiration of the given bearer token, which is not verified; nil is returned if it does not expire
+func TokenExpiry(bearerToken string) (*time.Time, error) {
+	if bearerToken == "" {
+		return nil, fmt.Errorf("no token")
+	}
+
+	token, _, err := new(jwt.Parser).ParseUnverified(bearerToken, jwt.MapClaims{})
+	if err != nil {
+		return nil, err
+	}
+
+	claims, claimsOk := token.Claims.(jwt.MapClaims)
+	if !claimsOk {
+		return nil, fmt.Errorf("invalid claims")
+	}
+
+	if exp, expOk := claims["exp"].(float64); expOk {
+		expTime := time.Unix(int64(exp), 0)
+		return &expTime, nil
+	}
+
+	return nil, nil
+}
+
 func isTokenExpired(bearerToken string) bool {
 	token, _ := jwt.Parse(bearerToken, func(_jwtToken *jwt.Token) (interface{}, error) {
 		// uncomment when enabling local verification
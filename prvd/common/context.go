@@ -0,0 +1,158 @@
+/*
+ * Copyright 2017-2022 Provide Technologies Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package common
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"github.com/provideplatform/provide-go/api/ident"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+	"github.com/spf13/viper"
+)
+
+// Context is the state resolved over the course of a single prvd invocation;
+// it is passed to the Require* helpers in lieu of package-level globals so that
+// many commands can be run safely within a single process (i.e., the shell)
+type Context struct {
+	ApplicationID string
+	Application   *ident.Application
+
+	WorkgroupID string
+	Workgroup   *WorkgroupType
+
+	OrganizationID string
+	Organization   *OrganizationType
+
+	UserID          string
+	User            *ident.User
+	UserAccessToken string
+
+	ApplicationAccessToken   string
+	OrganizationAccessToken  string
+	OrganizationRefreshToken string
+
+	SubjectAccountID string
+
+	AccountID     string
+	ConnectorID   string
+	ContractID    string
+	NetworkID     string
+	L2NetworkID   string
+	NodeID        string
+	TransactionID string
+	VaultID       string
+	WalletID      string
+
+	ResolvedBaselineOrgAddress string // HACK
+
+	Verbose bool
+}
+
+type contextKey struct{}
+
+// contextFlags maps the well-known identifier flags to the context field each populates
+var contextFlags = map[string]func(*Context) *string{
+	"access-token":    func(ctx *Context) *string { return &ctx.UserAccessToken },
+	"account":         func(ctx *Context) *string { return &ctx.AccountID },
+	"application":     func(ctx *Context) *string { return &ctx.ApplicationID },
+	"connector":       func(ctx *Context) *string { return &ctx.ConnectorID },
+	"contract":        func(ctx *Context) *string { return &ctx.ContractID },
+	"l2":              func(ctx *Context) *string { return &ctx.L2NetworkID },
+	"network":         func(ctx *Context) *string { return &ctx.NetworkID },
+	"node":            func(ctx *Context) *string { return &ctx.NodeID },
+	"organization":    func(ctx *Context) *string { return &ctx.OrganizationID },
+	"subject-account": func(ctx *Context) *string { return &ctx.SubjectAccountID },
+	"transaction":     func(ctx *Context) *string { return &ctx.TransactionID },
+	"vault":           func(ctx *Context) *string { return &ctx.VaultID },
+	"wallet":          func(ctx *Context) *string { return &ctx.WalletID },
+	"workgroup":       func(ctx *Context) *string { return &ctx.WorkgroupID },
+}
+
+// detachedContexts holds the context for command trees not executed using ExecuteContext
+var detachedContexts = map[*cobra.Command]*Context{}
+var detachedContextsMutex = &sync.Mutex{}
+
+// NewContext initializes an empty invocation context
+func NewContext() *Context {
+	return &Context{}
+}
+
+// WithContext returns a copy of parent which carries the given invocation context;
+// the result should be passed to ExecuteContext on the root command
+func WithContext(parent context.Context, ctx *Context) context.Context {
+	return context.WithValue(parent, contextKey{}, ctx)
+}
+
+// ContextFromCommand returns the invocation context of the given command
+func ContextFromCommand(cmd *cobra.Command) *Context {
+	root := cmd.Root()
+	if root.Context() != nil {
+		if ctx, ctxOk := root.Context().Value(contextKey{}).(*Context); ctxOk {
+			return ctx
+		}
+	}
+
+	detachedContextsMutex.Lock()
+	defer detachedContextsMutex.Unlock()
+
+	if detachedContexts[root] == nil {
+		detachedContexts[root] = NewContext()
+	}
+	return detachedContexts[root]
+}
+
+// ApplyFlags populates the context using the well-known flags defined on the given flagset
+func (ctx *Context) ApplyFlags(flags *pflag.FlagSet) {
+	for name, field := range contextFlags {
+		if flag := flags.Lookup(name); flag != nil {
+			*field(ctx) = flag.Value.String()
+		}
+	}
+
+	if verbose, err := flags.GetBool("verbose"); err == nil {
+		ctx.Verbose = verbose
+	}
+}
+
+// resetDetachedContext discards the context of the given command tree resolved by a previous
+// execution, unless the tree was executed using ExecuteContext
+func resetDetachedContext(root *cobra.Command) {
+	if root.Context() != nil {
+		if _, ctxOk := root.Context().Value(contextKey{}).(*Context); ctxOk {
+			return
+		}
+	}
+
+	detachedContextsMutex.Lock()
+	defer detachedContextsMutex.Unlock()
+	delete(detachedContexts, root)
+}
+
+// InitContext resolves the invocation context for the command being executed;
+// this is installed as the PersistentPreRun of the root command
+func InitContext(cmd *cobra.Command, args []string) {
+	resetDetachedContext(cmd.Root())
+	ctx := ContextFromCommand(cmd)
+	ctx.ApplyFlags(cmd.Flags())
+
+	if ctx.Verbose && viper.ConfigFileUsed() != "" {
+		fmt.Println("Using configuration:", viper.ConfigFileUsed())
+	}
+}
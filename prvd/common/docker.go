@@ -31,7 +31,11 @@ import (
 	"github.com/docker/docker/pkg/stdcopy"
 )
 
-func ListContainers(docker *client.Client, stack string) []types.Container {
+// DockerStackLabel is the label of docker resources which identifies the stack to which they belong
+const DockerStackLabel = "network.provide.stack"
+
+// ListContainers returns the containers of the given stack
+func ListContainers(docker *client.Client, stack string) ([]types.Container, error) {
 	containers, err := docker.ContainerList(context.Background(), types.ContainerListOptions{
 		All: true,
 		Filters: filters.NewArgs([]filters.KeyValuePair{
@@ -98,15 +102,19 @@ func ListContainers(docker *client.Client, stack string) []types.Container {
 		}...),
 	})
 	if err != nil {
-		log.Printf("failed to list containers; %s", err.Error())
-		os.Exit(1)
+		return nil, fmt.Errorf("failed to list containers; %s", err.Error())
 	}
 
-	return containers
+	return containers, nil
 }
 
 func LogContainers(docker *client.Client, wg *sync.WaitGroup, stack string) error {
-	for _, container := range ListContainers(docker, stack) {
+	containers, err := ListContainers(docker, stack)
+	if err != nil {
+		return err
+	}
+
+	for _, container := range containers {
 		if wg != nil {
 			wg.Add(1)
 		}
@@ -140,7 +148,13 @@ func LogContainer(docker *client.Client, containerID string) error {
 }
 
 func PurgeContainers(docker *client.Client, stack string, purgeVolumes bool) {
-	for _, container := range ListContainers(docker, stack) {
+	containers, err := ListContainers(docker, stack)
+	if err != nil {
+		log.Printf("WARNING: %s", err.Error())
+		return
+	}
+
+	for _, container := range containers {
 		if !purgeVolumes {
 			timeout := time.Millisecond * 5000
 			err := docker.ContainerStop(context.Background(), container.ID, &timeout)
@@ -170,8 +184,33 @@ func PurgeNetwork(docker *client.Client, stack string) {
 	}
 }
 
+// PurgeVolumes removes the named volumes which belong to the given stack
+func PurgeVolumes(docker *client.Client, stack string) {
+	volumes, err := docker.VolumeList(context.Background(), filters.NewArgs(filters.KeyValuePair{
+		Key:   "label",
+		Value: fmt.Sprintf("%s=%s", DockerStackLabel, strings.ReplaceAll(stack, " ", "")),
+	}))
+	if err != nil {
+		log.Printf("WARNING: failed to list volumes; %s", err.Error())
+		return
+	}
+
+	for _, volume := range volumes.Volumes {
+		err := docker.VolumeRemove(context.Background(), volume.Name, true)
+		if err != nil {
+			log.Printf("WARNING: failed to remove volume: %s; %s", volume.Name, err.Error())
+		}
+	}
+}
+
 func StopContainers(docker *client.Client, stack string) {
-	for _, container := range ListContainers(docker, stack) {
+	containers, err := ListContainers(docker, stack)
+	if err != nil {
+		log.Printf("WARNING: %s", err.Error())
+		return
+	}
+
+	for _, container := range containers {
 		timeout := time.Millisecond * 5000
 		err := docker.ContainerStop(context.Background(), container.ID, &timeout)
 
@@ -0,0 +1,170 @@
+/*
+ * Copyright 2017-2022 Provide Technologies Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package common
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"strings"
+)
+
+// JSONInputStdin is the JSON flag value which reads the JSON from stdin, i.e., --data @-
+const JSONInputStdin = "@-"
+
+// JSONInputFilePrefix prefixes a JSON flag value which reads the JSON from a file, i.e., --data @payload.json
+const JSONInputFilePrefix = "@"
+
+const defaultEditor = "vi"
+const editorCommentPrefix = "#"
+
+// ReadJSONInput resolves the value of a JSON flag; @- reads the JSON from stdin and @path reads
+// the JSON from the file at path, otherwise the value is returned as given
+func ReadJSONInput(value string) (string, error) {
+	switch {
+	case value == JSONInputStdin:
+		raw, err := ioutil.ReadAll(os.Stdin)
+		if err != nil {
+			return "", fmt.Errorf("failed to read JSON from stdin; %s", err.Error())
+		}
+		return strings.TrimSpace(string(raw)), nil
+	case strings.HasPrefix(value, JSONInputFilePrefix):
+		path := strings.TrimPrefix(value, JSONInputFilePrefix)
+		raw, err := ioutil.ReadFile(path)
+		if err != nil {
+			return "", fmt.Errorf("failed to read JSON from %s; %s", path, err.Error())
+		}
+		return strings.TrimSpace(string(raw)), nil
+	}
+	return value, nil
+}
+
+// EditJSON opens $VISUAL or $EDITOR on the given template, preceded by the given header as
+// comments, and returns the edited JSON once it passes the given validation; the editor is
+// reopened with the validation error until the JSON is valid, unchanged or removed
+func EditJSON(header, template string, validate func(string) error) (string, error) {
+	f, err := ioutil.TempFile("", "prvd-*.json")
+	if err != nil {
+		return "", fmt.Errorf("failed to create temporary file; %s", err.Error())
+	}
+	path := f.Name()
+	f.Close()
+	defer os.Remove(path)
+
+	input := prettyJSON(template)
+	comments := header
+	for {
+		err := ioutil.WriteFile(path, []byte(editorComments(comments)+input+"\n"), 0600)
+		if err != nil {
+			return "", fmt.Errorf("failed to write %s; %s", path, err.Error())
+		}
+
+		if err := runEditor(path); err != nil {
+			return "", err
+		}
+
+		raw, err := ioutil.ReadFile(path)
+		if err != nil {
+			return "", fmt.Errorf("failed to read %s; %s", path, err.Error())
+		}
+
+		edited := stripEditorComments(string(raw))
+		if edited == "" {
+			return "", errors.New("edit aborted; JSON is empty")
+		}
+
+		err = validate(edited)
+		if err == nil {
+			return edited, nil
+		}
+		if edited == input {
+			return "", err
+		}
+
+		input = edited
+		comments = fmt.Sprintf("error: %s\n%s", err.Error(), header)
+	}
+}
+
+// ValidateJSONArray validates that the given input is a JSON array
+func ValidateJSONArray(input string) error {
+	var js []interface{}
+	if json.Unmarshal([]byte(input), &js) != nil {
+		return errors.New("invalid JSON; expected an array")
+	}
+	return nil
+}
+
+func runEditor(path string) error {
+	editor := os.Getenv("VISUAL")
+	if editor == "" {
+		editor = os.Getenv("EDITOR")
+	}
+	if editor == "" {
+		editor = defaultEditor
+	}
+
+	argv := strings.Fields(editor)
+	cmd := exec.Command(argv[0], append(argv[1:], path)...)
+	cmd.Stdin = os.Stdin
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf("failed to run editor %s; %s", editor, err.Error())
+	}
+	return nil
+}
+
+func editorComments(comments string) string {
+	if strings.TrimSpace(comments) == "" {
+		return ""
+	}
+
+	var buf bytes.Buffer
+	for _, line := range strings.Split(strings.TrimSpace(comments), "\n") {
+		buf.WriteString(strings.TrimSpace(fmt.Sprintf("%s %s", editorCommentPrefix, line)))
+		buf.WriteString("\n")
+	}
+	return buf.String()
+}
+
+func stripEditorComments(input string) string {
+	var buf bytes.Buffer
+	scanner := bufio.NewScanner(strings.NewReader(input))
+	for scanner.Scan() {
+		line := scanner.Text()
+		if strings.HasPrefix(strings.TrimSpace(line), editorCommentPrefix) {
+			continue
+		}
+		buf.WriteString(line)
+		buf.WriteString("\n")
+	}
+	return strings.TrimSpace(buf.String())
+}
+
+func prettyJSON(input string) string {
+	var buf bytes.Buffer
+	if json.Indent(&buf, []byte(input), "", "  ") != nil {
+		return input
+	}
+	return buf.String()
+}
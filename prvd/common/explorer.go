@@ -0,0 +1,212 @@
+/*
+ * Copyright 2017-2022 Provide Technologies Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package common
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"os/exec"
+	"runtime"
+	"strings"
+	"sync"
+
+	"github.com/provideplatform/provide-go/api/nchain"
+	"github.com/spf13/viper"
+)
+
+// ExplorerConfigKeyPartial is the network-scoped block explorer config key;
+// i.e., `<network id>.explorer.url`, `<network id>.explorer.tx`, etc.
+const ExplorerConfigKeyPartial = "explorer"
+
+const explorerTemplateURL = "{url}"
+const explorerTemplateHash = "{hash}"
+const explorerTemplateAddress = "{address}"
+
+const defaultExplorerTxTemplate = "{url}/tx/{hash}"
+const defaultExplorerAddressTemplate = "{url}/address/{address}"
+const defaultExplorerContractTemplate = "{url}/address/{address}"
+
+// Explorer is a block explorer for a specific network; the tx, address and contract
+// templates are expanded using the {url}, {hash} and {address} placeholders
+type Explorer struct {
+	Name             string `json:"name,omitempty"`
+	URL              string `json:"url"`
+	TxTemplate       string `json:"tx,omitempty"`
+	AddressTemplate  string `json:"address,omitempty"`
+	ContractTemplate string `json:"contract,omitempty"`
+}
+
+// defaultExplorers are the block explorers for well-known public networks
+var defaultExplorers = map[string]*Explorer{
+	"deca2436-21ba-4ff5-b225-ad1b0b2f5c59": {Name: "Etherscan", URL: "https://etherscan.io"},
+	"1b16996e-3595-4985-816c-043345d22f8c": {Name: "Etherscan", URL: "https://goerli.etherscan.io"},
+}
+
+// explorers caches the block explorers resolved from config or nchain network metadata
+var explorers = map[string]*Explorer{}
+var explorersMutex = &sync.Mutex{}
+
+// TxURL returns the explorer URL for the given transaction hash
+func (e *Explorer) TxURL(hash string) string {
+	return e.expand(e.TxTemplate, defaultExplorerTxTemplate, explorerTemplateHash, hash)
+}
+
+// AddressURL returns the explorer URL for the given account address
+func (e *Explorer) AddressURL(address string) string {
+	return e.expand(e.AddressTemplate, defaultExplorerAddressTemplate, explorerTemplateAddress, address)
+}
+
+// ContractURL returns the explorer URL for the given contract address
+func (e *Explorer) ContractURL(address string) string {
+	return e.expand(e.ContractTemplate, defaultExplorerContractTemplate, explorerTemplateAddress, address)
+}
+
+func (e *Explorer) expand(tmpl, defaultTmpl, placeholder, val string) string {
+	if tmpl == "" {
+		tmpl = defaultTmpl
+	}
+	url := strings.ReplaceAll(tmpl, explorerTemplateURL, strings.TrimSuffix(e.URL, "/"))
+	return strings.ReplaceAll(url, placeholder, val)
+}
+
+// ResolveExplorer returns the block explorer for the given network, or nil if none is known;
+// the given token is only used to fetch the network details and is never prompted for;
+// explorers configured under `<network id>.explorer` take precedence over the `block_explorer_url`
+// advertised in the nchain network config, which takes precedence over the well-known defaults
+func ResolveExplorer(token, networkID string) *Explorer {
+	if networkID == "" {
+		return nil
+	}
+
+	explorersMutex.Lock()
+	defer explorersMutex.Unlock()
+
+	if explorer, explorerOk := explorers[networkID]; explorerOk {
+		return explorer
+	}
+
+	var err error
+	explorer := configuredExplorer(networkID)
+	if explorer == nil {
+		explorer, err = networkExplorer(token, networkID)
+	}
+	if explorer == nil {
+		explorer = defaultExplorers[networkID]
+	}
+
+	// the network details are fetched again on the next lookup if they could not be retrieved
+	if err == nil {
+		explorers[networkID] = explorer
+	}
+	return explorer
+}
+
+// ViewOnExplorer prints the block explorer url resolved for the given network using the given
+// func, and opens it using the default browser when open is true; exits if open is true and
+// no explorer is known for the network
+func ViewOnExplorer(token, networkID string, url func(explorer *Explorer) string, open bool) {
+	explorer := ResolveExplorer(token, networkID)
+	if explorer == nil {
+		if open {
+			log.Printf("No block explorer configured for network: %s", networkID)
+			Exit(1)
+		}
+		return
+	}
+
+	explorerURL := url(explorer)
+	fmt.Printf("View on block explorer: %s\n", explorerURL)
+	if open {
+		if err := OpenURL(explorerURL); err != nil {
+			log.Printf("Failed to open block explorer; %s", err.Error())
+			Exit(1)
+		}
+	}
+}
+
+// configuredExplorer returns the explorer configured for the given network, if any
+func configuredExplorer(networkID string) *Explorer {
+	key := BuildConfigKeyWithID(ExplorerConfigKeyPartial, networkID)
+	if !viper.IsSet(key) {
+		return nil
+	}
+
+	// a bare url may be configured in lieu of the explorer object
+	if url := viper.GetString(key); url != "" {
+		return &Explorer{URL: url}
+	}
+
+	explorer := &Explorer{
+		Name:             viper.GetString(fmt.Sprintf("%s.name", key)),
+		URL:              viper.GetString(fmt.Sprintf("%s.url", key)),
+		TxTemplate:       viper.GetString(fmt.Sprintf("%s.tx", key)),
+		AddressTemplate:  viper.GetString(fmt.Sprintf("%s.address", key)),
+		ContractTemplate: viper.GetString(fmt.Sprintf("%s.contract", key)),
+	}
+	if explorer.URL == "" && explorer.TxTemplate == "" {
+		return nil
+	}
+	return explorer
+}
+
+// networkExplorer returns the explorer advertised in the nchain network config, if any;
+// an error is returned if the network details could not be retrieved
+func networkExplorer(token, networkID string) (*Explorer, error) {
+	if token == "" {
+		return nil, errors.New("no API token to retrieve network details")
+	}
+
+	network, err := nchain.GetNetworkDetails(token, networkID, map[string]interface{}{})
+	if err != nil {
+		return nil, err
+	}
+	if network.Config == nil {
+		return nil, nil
+	}
+
+	var cfg map[string]interface{}
+	if err := json.Unmarshal(*network.Config, &cfg); err != nil {
+		return nil, nil
+	}
+
+	url, urlOk := cfg["block_explorer_url"].(string)
+	if !urlOk || url == "" {
+		return nil, nil
+	}
+
+	explorer := &Explorer{URL: url}
+	if network.Name != nil {
+		explorer.Name = *network.Name
+	}
+	return explorer, nil
+}
+
+// OpenURL opens the given url using the default browser
+func OpenURL(url string) error {
+	var cmd *exec.Cmd
+	switch runtime.GOOS {
+	case "darwin":
+		cmd = exec.Command("open", url)
+	case "windows":
+		cmd = exec.Command("rundll32", "url.dll,FileProtocolHandler", url)
+	default:
+		cmd = exec.Command("xdg-open", url)
+	}
+	return cmd.Start()
+}
@@ -0,0 +1,71 @@
+/*
+ * Copyright 2017-2022 Provide Technologies Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package common
+
+import (
+	"testing"
+)
+
+func TestExplorerURLs(t *testing.T) {
+	tests := []struct {
+		name     string
+		explorer *Explorer
+		tx       string
+		address  string
+		contract string
+	}{
+		{
+			name:     "default templates",
+			explorer: &Explorer{URL: "https://etherscan.io"},
+			tx:       "https://etherscan.io/tx/0xabc",
+			address:  "https://etherscan.io/address/0xdef",
+			contract: "https://etherscan.io/address/0xdef",
+		},
+		{
+			name:     "trailing slash",
+			explorer: &Explorer{URL: "https://goerli.etherscan.io/"},
+			tx:       "https://goerli.etherscan.io/tx/0xabc",
+			address:  "https://goerli.etherscan.io/address/0xdef",
+			contract: "https://goerli.etherscan.io/address/0xdef",
+		},
+		{
+			name: "custom templates",
+			explorer: &Explorer{
+				URL:              "https://explorer.example.com",
+				TxTemplate:       "{url}/transactions/{hash}?network=test",
+				AddressTemplate:  "{url}/accounts/{address}",
+				ContractTemplate: "https://contracts.example.com/{address}",
+			},
+			tx:       "https://explorer.example.com/transactions/0xabc?network=test",
+			address:  "https://explorer.example.com/accounts/0xdef",
+			contract: "https://contracts.example.com/0xdef",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.explorer.TxURL("0xabc"); got != tt.tx {
+				t.Errorf("TxURL() = %s, want %s", got, tt.tx)
+			}
+			if got := tt.explorer.AddressURL("0xdef"); got != tt.address {
+				t.Errorf("AddressURL() = %s, want %s", got, tt.address)
+			}
+			if got := tt.explorer.ContractURL("0xdef"); got != tt.contract {
+				t.Errorf("ContractURL() = %s, want %s", got, tt.contract)
+			}
+		})
+	}
+}
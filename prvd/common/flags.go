@@ -0,0 +1,46 @@
+/*
+ * Copyright 2017-2022 Provide Technologies Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package common
+
+import (
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+)
+
+// FlagValuesAnnotation is the flag annotation which enumerates the accepted values of a flag
+const FlagValuesAnnotation = "prvd_flag_values"
+
+// InteractiveAnnotation is the command annotation which marks a command as interacting with the
+// terminal, i.e., by attaching a TTY or always prompting; the shell never captures its output
+const InteractiveAnnotation = "prvd_interactive"
+
+// EditFlag is the name of the flag which opens $EDITOR, which the shell treats as interactive
+const EditFlag = "edit"
+
+// SetFlagValues enumerates the accepted values of the named flag; the values are offered
+// as suggestions by the interactive shell
+func SetFlagValues(cmd *cobra.Command, name string, values ...string) {
+	cmd.Flags().SetAnnotation(name, FlagValuesAnnotation, values)
+}
+
+// FlagValues returns the accepted values of the given flag, if enumerated
+func FlagValues(flag *pflag.Flag) []string {
+	if flag == nil || flag.Annotations == nil {
+		return nil
+	}
+	return flag.Annotations[FlagValuesAnnotation]
+}
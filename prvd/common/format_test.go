@@ -0,0 +1,43 @@
+/*
+ * Copyright 2017-2022 Provide Technologies Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package common
+
+import (
+	"testing"
+)
+
+func TestFormatBytes(t *testing.T) {
+	tests := []struct {
+		name string
+		n    uint64
+		want string
+	}{
+		{name: "zero", n: 0, want: "0B"},
+		{name: "bytes", n: 1023, want: "1023B"},
+		{name: "kibibyte", n: 1024, want: "1.0KiB"},
+		{name: "fractional mebibytes", n: 1536 * 1024, want: "1.5MiB"},
+		{name: "gibibytes", n: 4 * 1024 * 1024 * 1024, want: "4.0GiB"},
+		{name: "exbibytes", n: 1 << 62, want: "4.0EiB"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := FormatBytes(tt.n); got != tt.want {
+				t.Errorf("FormatBytes(%d) = %s, want %s", tt.n, got, tt.want)
+			}
+		})
+	}
+}
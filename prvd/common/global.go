@@ -23,10 +23,9 @@ import (
 	"fmt"
 	"os"
 	"strings"
+	"sync/atomic"
 
 	provide "github.com/provideplatform/provide-go/api"
-	"github.com/provideplatform/provide-go/api/ident"
-	"github.com/provideplatform/provide-go/common"
 )
 
 const releaseRepositoryPackageName = "Provide"
@@ -35,53 +34,36 @@ const releaseRepositoryHTTPSURL = "https://github.com/provideplatform/provide"
 const DefaultPage = 1
 const DefaultRpp = 25
 
-var (
-	ApplicationID string
-	Application   *ident.Application
+var Manifest *provide.Manifest
 
-	WorkgroupID string
-	Workgroup   *WorkgroupType
+// ExitStatus is the panic value raised by Exit when exits are recoverable
+type ExitStatus int
 
-	OrganizationID string
-	Organization   *OrganizationType
-
-	UserID          string
-	User            *ident.User
-	UserAccessToken string
-
-	SubjectAccountID string
-
-	AccountID   string
-	ConnectorID string
-	ContractID  string
-	NetworkID   string
-	L2NetworkID string
-	NodeID      string
-	WalletID    string
-
-	Manifest *provide.Manifest
-	Verbose  bool
-)
+// exitRecoverable is set by the shell so commands can be run in-process without exiting the shell
+var exitRecoverable int32
 
 func init() {
 	resolveReleaseContext()
 }
 
-func EtherscanBaseURL(networkID string) *string {
-	switch networkID {
-	case "deca2436-21ba-4ff5-b225-ad1b0b2f5c59":
-		return common.StringOrNil("https://etherscan.io")
-	case "07102258-5e49-480e-86af-6d0c3260827d":
-		return common.StringOrNil("https://rinkeby.etherscan.io")
-	case "66d44f30-9092-4182-a3c4-bc02736d6ae5":
-		return common.StringOrNil("https://ropsten.etherscan.io")
-	case "8d31bf48-df6b-4a71-9d7c-3cb291111e27":
-		return common.StringOrNil("https://kovan.etherscan.io")
-	case "1b16996e-3595-4985-816c-043345d22f8c":
-		return common.StringOrNil("https://goerli.etherscan.io")
-	default:
-		return nil
+// Exit terminates the current invocation with the given status code; when exits are recoverable,
+// Exit panics with an ExitStatus which is only recovered by the shell. Exit must be called from the
+// goroutine running the command; background goroutines should report errors using a channel
+func Exit(code int) {
+	if atomic.LoadInt32(&exitRecoverable) == 1 {
+		panic(ExitStatus(code))
+	}
+
+	os.Exit(code)
+}
+
+// SetExitRecoverable configures whether Exit panics with an ExitStatus rather than terminating the process
+func SetExitRecoverable(recoverable bool) {
+	var val int32
+	if recoverable {
+		val = 1
 	}
+	atomic.StoreInt32(&exitRecoverable, val)
 }
 
 // resolveReleaseContext attempts to parse a Provide release manifest.json
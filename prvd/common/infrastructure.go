@@ -73,6 +73,8 @@ func RequireInfrastructureFlags(cmd *cobra.Command, withImage bool) {
 	cmd.Flags().StringVar(&TargetID, "target", "aws", "target infrastructure platform (i.e., aws or azure)")
 	cmd.Flags().StringVar(&Region, "Region", "us-east-1", "target infrastructure Region")
 	cmd.Flags().StringVar(&ProviderID, "provider", "docker", "infrastructure virtualization provider (i.e., docker)")
+	SetFlagValues(cmd, "target", InfrastructureTargetAWS, InfrastructureTargetAzure)
+	SetFlagValues(cmd, "provider", "docker")
 	if withImage {
 		cmd.Flags().StringVar(&Image, "common.Image", "", "container common.Image name")
 	}
@@ -84,24 +86,24 @@ func requireAWSCredentials() (string, string) {
 	accessKeyID, err := reader.ReadString('\n')
 	if err != nil {
 		log.Println(err)
-		os.Exit(1)
+		Exit(1)
 	}
 	accessKeyID = strings.Trim(accessKeyID, "\n")
 	if accessKeyID == "" {
 		log.Println("Failed to read AWS access key ID from stdin")
-		os.Exit(1)
+		Exit(1)
 	}
 
 	fmt.Print("AWS Secret Access Key: ")
 	secretAccessKeyBytes, err := terminal.ReadPassword(0)
 	if err != nil {
 		log.Println(err)
-		os.Exit(1)
+		Exit(1)
 	}
 	secretAccessKey := strings.Trim(string(secretAccessKeyBytes[:]), "\n")
 	if secretAccessKey == "" {
 		log.Println("Failed to read AWS secret access key from stdin")
-		os.Exit(1)
+		Exit(1)
 	}
 
 	return accessKeyID, secretAccessKey
@@ -116,23 +118,23 @@ func requireAzureCredentials() (string, string, string, string) {
 	subscriptionID, err := reader.ReadString('\n')
 	if err != nil {
 		log.Println(err)
-		os.Exit(1)
+		Exit(1)
 	}
 	subscriptionID = strings.Trim(subscriptionID, "\n")
 	if subscriptionID == "" {
 		log.Println("Failed to read Azure subscription ID from stdin")
-		os.Exit(1)
+		Exit(1)
 	}
 
 	tenantID, err := reader.ReadString('\n')
 	if err != nil {
 		log.Println(err)
-		os.Exit(1)
+		Exit(1)
 	}
 	tenantID = strings.Trim(tenantID, "\n")
 	if tenantID == "" {
 		log.Println("Failed to read Azure tenant ID from stdin")
-		os.Exit(1)
+		Exit(1)
 	}
 
 	fmt.Print("Azure Client ID: ")
@@ -140,24 +142,24 @@ func requireAzureCredentials() (string, string, string, string) {
 	clientID, err := reader.ReadString('\n')
 	if err != nil {
 		log.Println(err)
-		os.Exit(1)
+		Exit(1)
 	}
 	clientID = strings.Trim(clientID, "\n")
 	if clientID == "" {
 		log.Println("Failed to read Azure client ID from stdin")
-		os.Exit(1)
+		Exit(1)
 	}
 
 	fmt.Print("Azure Client Secret: ")
 	clientSecretBytes, err := terminal.ReadPassword(0)
 	if err != nil {
 		log.Println(err)
-		os.Exit(1)
+		Exit(1)
 	}
 	clientSecret := strings.Trim(string(clientSecretBytes[:]), "\n")
 	if clientSecret == "" {
 		log.Println("Failed to read Azure client secret from stdin")
-		os.Exit(1)
+		Exit(1)
 	}
 
 	return tenantID, clientID, clientSecret, subscriptionID
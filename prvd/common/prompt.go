@@ -32,6 +32,7 @@ import (
 	"github.com/provideplatform/provide-go/api/nchain"
 	"github.com/provideplatform/provide-go/api/vault"
 	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
 )
 
 const requireAccountSelectLabel = "Select an account"
@@ -85,46 +86,191 @@ func CmdExistsOrExit(cmd *cobra.Command, args []string) {
 	exists, command := CmdExists(cmd, args)
 	if !exists {
 		fmt.Printf("%s is not a valid command", command)
-		os.Exit(1)
+		Exit(1)
 	}
 }
 
-// RequireApplication is equivalent to a required --application flag
-func RequireApplication() error {
-	if ApplicationID != "" {
-		return nil
+// SelectorItem is a single option presented by a Selector
+type SelectorItem struct {
+	ID    string
+	Name  string
+	Value interface{}
+}
+
+// SelectorPageFunc retrieves the given page of selector items
+type SelectorPageFunc func(page, rpp uint64) ([]*SelectorItem, error)
+
+// Selector is an interactive, searchable picker which lazily loads pages of items from the API
+type Selector struct {
+	Label string
+	Noun  string // plural noun used when nothing is found, i.e., "applications"
+	Hint  string // command which creates the missing resource, i.e., "prvd applications init"
+	Fetch SelectorPageFunc
+	Rpp   uint64
+}
+
+// selectorLoadMore is the sentinel item appended while additional pages may exist
+var selectorLoadMore = &SelectorItem{Name: "Load more..."}
+
+var selectorTemplates = &promptui.SelectTemplates{
+	Label:    "{{ . }}",
+	Active:   "▸ {{ .Name | cyan }}{{ if .ID }} ({{ .ID | faint }}){{ end }}",
+	Inactive: "  {{ .Name }}{{ if .ID }} ({{ .ID | faint }}){{ end }}",
+	Selected: "✔ {{ .Name | green }}{{ if .ID }} ({{ .ID | faint }}){{ end }}",
+}
+
+// Run presents the selector and returns the selected item; when there is nothing
+// to select, a message describing how to create the missing resource is printed
+// and the process exits
+func (s *Selector) Run() (*SelectorItem, error) {
+	rpp := s.Rpp
+	if rpp == 0 {
+		rpp = DefaultRpp
+	}
+
+	items := make([]*SelectorItem, 0)
+	page := uint64(DefaultPage)
+	more := true
+	cursor := 0
+
+	for {
+		if more {
+			next, err := s.Fetch(page, rpp)
+			if err != nil {
+				return nil, err
+			}
+			items = append(items, next...)
+			more = uint64(len(next)) == rpp
+			page++
+		}
+
+		if len(items) == 0 {
+			s.exitNothingToSelect()
+		}
+
+		opts := make([]*SelectorItem, len(items), len(items)+1)
+		copy(opts, items)
+		if more {
+			opts = append(opts, selectorLoadMore)
+		}
+
+		prompt := promptui.Select{
+			Label:     s.Label,
+			Items:     opts,
+			Templates: selectorTemplates,
+			Searcher: func(input string, i int) bool {
+				return opts[i] == selectorLoadMore || fuzzyMatch(input, fmt.Sprintf("%s %s", opts[i].Name, opts[i].ID))
+			},
+		}
+
+		i, _, err := prompt.RunCursorAt(cursor, 0)
+		if err != nil {
+			return nil, err
+		}
+
+		if opts[i] == selectorLoadMore {
+			cursor = len(items)
+			continue
+		}
+
+		return opts[i], nil
 	}
+}
 
-	opts := make([]string, 0)
-	apps, _ := ident.ListApplications(RequireUserAccessToken(), map[string]interface{}{})
-	for _, app := range apps {
-		opts = append(opts, *app.Name)
+func (s *Selector) exitNothingToSelect() {
+	if s.Hint != "" {
+		fmt.Printf("No %s found; create one with '%s'\n", s.Noun, s.Hint)
+	} else {
+		fmt.Printf("No %s found\n", s.Noun)
 	}
+	Exit(1)
+}
 
-	prompt := promptui.Select{
-		Label: requireApplicationSelectLabel,
-		Items: opts,
+// fuzzyMatch returns true if each character of the input appears in str in order, ignoring case
+func fuzzyMatch(input, str string) bool {
+	input = strings.ToLower(strings.ReplaceAll(input, " ", ""))
+	str = strings.ToLower(str)
+
+	for _, r := range input {
+		i := strings.IndexRune(str, r)
+		if i == -1 {
+			return false
+		}
+		str = str[i+len(string(r)):]
 	}
+	return true
+}
+
+// paginate returns a copy of the given params for the given page
+func paginate(params map[string]interface{}, page, rpp uint64) map[string]interface{} {
+	paginated := map[string]interface{}{}
+	for k, v := range params {
+		paginated[k] = v
+	}
+	paginated["page"] = fmt.Sprintf("%d", page)
+	paginated["rpp"] = fmt.Sprintf("%d", rpp)
+	return paginated
+}
 
-	i, _, err := prompt.Run()
+func stringOrEmpty(str *string) string {
+	if str == nil {
+		return ""
+	}
+	return *str
+}
+
+// RequireApplication is equivalent to a required --application flag
+func RequireApplication(ctx *Context) error {
+	if ctx.ApplicationID != "" {
+		return nil
+	}
+
+	token := RequireUserAccessToken(ctx)
+	selector := &Selector{
+		Label: requireApplicationSelectLabel,
+		Noun:  "applications",
+		Hint:  "prvd applications init",
+		Fetch: func(page, rpp uint64) ([]*SelectorItem, error) {
+			apps, err := ident.ListApplications(token, paginate(map[string]interface{}{}, page, rpp))
+			if err != nil {
+				return nil, err
+			}
+
+			items := make([]*SelectorItem, 0)
+			for _, app := range apps {
+				items = append(items, &SelectorItem{ID: app.ID.String(), Name: stringOrEmpty(app.Name), Value: app})
+			}
+			return items, nil
+		},
+	}
+
+	item, err := selector.Run()
 	if err != nil {
 		return err
 	}
 
-	Application = apps[i]
-	ApplicationID = apps[i].ID.String()
+	ctx.Application = item.Value.(*ident.Application)
+	ctx.ApplicationID = item.ID
 	return nil
 }
 
-// RequireWorkgroup is equivalent to a required --workgroup flag
-func RequireWorkgroup() error {
-	if WorkgroupID != "" {
-		token, err := ResolveOrganizationToken()
+// RequireWorkgroup is equivalent to a required --workgroup flag;
+// PROVIDE_WORKGROUP_ID or the active workgroup is used, if set, before prompting
+func RequireWorkgroup(ctx *Context) error {
+	if ctx.WorkgroupID == "" {
+		ctx.WorkgroupID = resolveDefaultWorkgroupID(ctx.OrganizationID)
+		if ctx.WorkgroupID != "" && ctx.OrganizationID == "" && os.Getenv(workgroupIDEnvVar) == "" {
+			ctx.OrganizationID = viper.GetString(ActiveWorkgroupOrganizationIDConfigKey)
+		}
+	}
+
+	if ctx.WorkgroupID != "" {
+		token, err := ResolveOrganizationToken(ctx)
 		if err != nil {
 			return err
 		}
 
-		wg, err := axiom.GetWorkgroupDetails(*token.AccessToken, WorkgroupID, map[string]interface{}{})
+		wg, err := axiom.GetWorkgroupDetails(*token.AccessToken, ctx.WorkgroupID, map[string]interface{}{})
 		if err != nil {
 			return err
 		}
@@ -134,279 +280,321 @@ func RequireWorkgroup() error {
 			return err
 		}
 
-		err = json.Unmarshal(raw, &Workgroup)
+		err = json.Unmarshal(raw, &ctx.Workgroup)
 		return err
 	}
 
 	var token string
 
 	// FIXME-- should check if token is in memory
-	tkn, err := ident.CreateToken(RequireUserAccessToken(), map[string]interface{}{
+	tkn, err := ident.CreateToken(RequireUserAccessToken(ctx), map[string]interface{}{
 		"scope":           "offline_access",
-		"organization_id": OrganizationID,
+		"organization_id": ctx.OrganizationID,
 	})
 	if err == nil && tkn.AccessToken != nil {
 		token = *tkn.AccessToken
 	} else if err != nil {
-		token = RequireUserAccessToken()
-	}
-
-	opts := make([]string, 0)
-	workgroups, _ := axiom.ListWorkgroups(token, map[string]interface{}{})
-	for _, app := range workgroups {
-		opts = append(opts, *app.Name)
+		token = RequireUserAccessToken(ctx)
 	}
 
-	prompt := promptui.Select{
+	selector := &Selector{
 		Label: requireWorkgroupSelectLabel,
-		Items: opts,
-	}
-
-	i, _, err := prompt.Run()
+		Noun:  "workgroups",
+		Hint:  "prvd axiom workgroups init",
+		Fetch: func(page, rpp uint64) ([]*SelectorItem, error) {
+			workgroups, err := axiom.ListWorkgroups(token, paginate(map[string]interface{}{}, page, rpp))
+			if err != nil {
+				return nil, err
+			}
+
+			items := make([]*SelectorItem, 0)
+			for _, wg := range workgroups {
+				items = append(items, &SelectorItem{ID: wg.ID.String(), Name: stringOrEmpty(wg.Name), Value: wg})
+			}
+			return items, nil
+		},
+	}
+
+	item, err := selector.Run()
 	if err != nil {
 		return err
 	}
 
-	WorkgroupID = workgroups[i].ID.String()
+	ctx.WorkgroupID = item.ID
 
-	wg := workgroups[i]
-	raw, err := json.Marshal(wg)
+	raw, err := json.Marshal(item.Value)
 	if err != nil {
 		return err
 	}
 
-	err = json.Unmarshal(raw, &Workgroup)
+	err = json.Unmarshal(raw, &ctx.Workgroup)
 	return err
 }
 
 // RequireConnector is equivalent to a required --connector flag
-func RequireConnector(params map[string]interface{}) error {
-	if ConnectorID != "" {
+func RequireConnector(ctx *Context, params map[string]interface{}) error {
+	if ctx.ConnectorID != "" {
 		return nil
 	}
 
-	opts := make([]string, 0)
-	connectors, _ := nchain.ListConnectors(RequireAPIToken(), params)
-	for _, connector := range connectors {
-		opts = append(opts, *connector.Name)
-	}
-
-	prompt := promptui.Select{
+	token := RequireAPIToken(ctx)
+	selector := &Selector{
 		Label: requireConnectorSelectLabel,
-		Items: opts,
-	}
-
-	i, _, err := prompt.Run()
+		Noun:  "connectors",
+		Hint:  "prvd connectors init",
+		Fetch: func(page, rpp uint64) ([]*SelectorItem, error) {
+			connectors, err := nchain.ListConnectors(token, paginate(params, page, rpp))
+			if err != nil {
+				return nil, err
+			}
+
+			items := make([]*SelectorItem, 0)
+			for _, connector := range connectors {
+				items = append(items, &SelectorItem{ID: connector.ID.String(), Name: stringOrEmpty(connector.Name)})
+			}
+			return items, nil
+		},
+	}
+
+	item, err := selector.Run()
 	if err != nil {
 		return err
 	}
 
-	ConnectorID = connectors[i].ID.String()
+	ctx.ConnectorID = item.ID
 	return nil
 }
 
-// RequireNetwork is equivalent to a required --network flag
-func RequireNetwork() error {
-	if NetworkID != "" {
-		return nil
-	}
-
-	opts := make([]string, 0)
-	networks, _ := nchain.ListNetworks(RequireUserAccessToken(), map[string]interface{}{})
-	for _, network := range networks {
-		opts = append(opts, *network.Name)
+// networkSelector returns a selector for the networks matching the given params
+func networkSelector(ctx *Context, label, noun, hint string, params map[string]interface{}) *Selector {
+	token := RequireUserAccessToken(ctx)
+	return &Selector{
+		Label: label,
+		Noun:  noun,
+		Hint:  hint,
+		Fetch: func(page, rpp uint64) ([]*SelectorItem, error) {
+			networks, err := nchain.ListNetworks(token, paginate(params, page, rpp))
+			if err != nil {
+				return nil, err
+			}
+
+			items := make([]*SelectorItem, 0)
+			for _, network := range networks {
+				items = append(items, &SelectorItem{ID: network.ID.String(), Name: stringOrEmpty(network.Name)})
+			}
+			return items, nil
+		},
 	}
+}
 
-	prompt := promptui.Select{
-		Label: requireNetworkSelectLabel,
-		Items: opts,
+// RequireNetwork is equivalent to a required --network flag
+func RequireNetwork(ctx *Context) error {
+	if ctx.NetworkID != "" {
+		return nil
 	}
 
-	i, _, err := prompt.Run()
+	selector := networkSelector(ctx, requireNetworkSelectLabel, "networks", "prvd networks init", map[string]interface{}{})
+	item, err := selector.Run()
 	if err != nil {
 		return err
 	}
 
-	NetworkID = networks[i].ID.String()
+	ctx.NetworkID = item.ID
 	return nil
 }
 
 // RequireL1Network is equivalent to a required --network flag; but list options filtered to show only public l1 networks
-func RequireL1Network() error {
-	if NetworkID != "" {
+func RequireL1Network(ctx *Context) error {
+	if ctx.NetworkID != "" {
 		return nil
 	}
 
-	opts := make([]string, 0)
-	networks, _ := nchain.ListNetworks(RequireUserAccessToken(), map[string]interface{}{
+	selector := networkSelector(ctx, requireNetworkSelectLabel, "public layer 1 networks", "", map[string]interface{}{
 		"public": "true",
 		"layer2": "false",
 	})
-	for _, network := range networks {
-		opts = append(opts, *network.Name)
-	}
-
-	prompt := promptui.Select{
-		Label: requireNetworkSelectLabel,
-		Items: opts,
-	}
-
-	i, _, err := prompt.Run()
+	item, err := selector.Run()
 	if err != nil {
 		return err
 	}
 
-	NetworkID = networks[i].ID.String()
+	ctx.NetworkID = item.ID
 	return nil
 }
 
 // RequireL2Network is equivalent to a required --l2 flag; but list options filtered to show only public l2 networks
-func RequireL2Network() error {
-	if L2NetworkID != "" {
+func RequireL2Network(ctx *Context) error {
+	if ctx.L2NetworkID != "" {
 		return nil
 	}
 
-	opts := make([]string, 0)
-	networks, _ := nchain.ListNetworks(RequireUserAccessToken(), map[string]interface{}{
+	selector := networkSelector(ctx, requireL2NetworkSelectLabel, "public layer 2 networks", "", map[string]interface{}{
 		"public": "true",
 		"layer2": "true",
 	})
-	for _, network := range networks {
-		opts = append(opts, *network.Name)
-	}
-
-	prompt := promptui.Select{
-		Label: requireNetworkSelectLabel,
-		Items: opts,
-	}
-
-	i, _, err := prompt.Run()
+	item, err := selector.Run()
 	if err != nil {
 		return err
 	}
 
-	L2NetworkID = networks[i].ID.String()
+	ctx.L2NetworkID = item.ID
 	return nil
 }
 
-// RequireOrganization is equivalent to a required --organization flag
-func RequireOrganization() error {
-	if OrganizationID != "" {
+// RequireOrganization is equivalent to a required --organization flag;
+// PROVIDE_ORGANIZATION_ID or the active organization is used, if set, before prompting
+func RequireOrganization(ctx *Context) error {
+	if ctx.OrganizationID == "" {
+		ctx.OrganizationID = resolveDefaultOrganizationID()
+	}
+
+	if ctx.OrganizationID != "" {
 		// TODO-- should also check if Organization is set; if so, check that IDs match, else refetch and re-set Organization -- same for other similar Require() methods
-		org, _ := ident.GetOrganizationDetails(RequireUserAccessToken(), OrganizationID, map[string]interface{}{})
+		org, _ := ident.GetOrganizationDetails(RequireUserAccessToken(ctx), ctx.OrganizationID, map[string]interface{}{})
 
 		raw, err := json.Marshal(org)
 		if err != nil {
 			return err
 		}
 
-		err = json.Unmarshal(raw, &Organization)
+		err = json.Unmarshal(raw, &ctx.Organization)
 		return err
 	}
 
-	opts := make([]string, 0)
-	orgs, _ := ident.ListOrganizations(RequireUserAccessToken(), map[string]interface{}{})
-	for _, org := range orgs {
-		opts = append(opts, *org.Name)
-	}
-
-	prompt := promptui.Select{
+	token := RequireUserAccessToken(ctx)
+	selector := &Selector{
 		Label: requireOrganizationSelectLabel,
-		Items: opts,
-	}
-
-	i, _, err := prompt.Run()
+		Noun:  "organizations",
+		Hint:  "prvd organizations init",
+		Fetch: func(page, rpp uint64) ([]*SelectorItem, error) {
+			orgs, err := ident.ListOrganizations(token, paginate(map[string]interface{}{}, page, rpp))
+			if err != nil {
+				return nil, err
+			}
+
+			items := make([]*SelectorItem, 0)
+			for _, org := range orgs {
+				items = append(items, &SelectorItem{ID: stringOrEmpty(org.ID), Name: stringOrEmpty(org.Name), Value: org})
+			}
+			return items, nil
+		},
+	}
+
+	item, err := selector.Run()
 	if err != nil {
 		return err
 	}
 
-	OrganizationID = *orgs[i].ID
+	ctx.OrganizationID = item.ID
 
-	org := orgs[i]
-	raw, err := json.Marshal(org)
+	raw, err := json.Marshal(item.Value)
 	if err != nil {
 		return err
 	}
 
-	err = json.Unmarshal(raw, &Organization)
+	err = json.Unmarshal(raw, &ctx.Organization)
 	return err
 }
 
 // RequireVault is equivalent to a required --vault flag
-func RequireVault() error {
-	if VaultID != "" {
+func RequireVault(ctx *Context) error {
+	if ctx.VaultID != "" {
 		return nil
 	}
 
-	opts := make([]string, 0)
-	vaults, _ := vault.ListVaults(RequireAPIToken(), map[string]interface{}{})
-	for _, vlt := range vaults {
-		opts = append(opts, *vlt.Name)
-	}
-
-	prompt := promptui.Select{
+	token := RequireAPIToken(ctx)
+	selector := &Selector{
 		Label: requireVaultSelectLabel,
-		Items: opts,
-	}
-
-	i, _, err := prompt.Run()
+		Noun:  "vaults",
+		Hint:  "prvd vaults init",
+		Fetch: func(page, rpp uint64) ([]*SelectorItem, error) {
+			vaults, err := vault.ListVaults(token, paginate(map[string]interface{}{}, page, rpp))
+			if err != nil {
+				return nil, err
+			}
+
+			items := make([]*SelectorItem, 0)
+			for _, vlt := range vaults {
+				items = append(items, &SelectorItem{ID: vlt.ID.String(), Name: stringOrEmpty(vlt.Name)})
+			}
+			return items, nil
+		},
+	}
+
+	item, err := selector.Run()
 	if err != nil {
 		return err
 	}
 
-	VaultID = vaults[i].ID.String()
+	ctx.VaultID = item.ID
 	return nil
 }
 
 // RequireAccount is equivalent to a required --account flag
-func RequireAccount(params map[string]interface{}) error {
-	if AccountID != "" {
+func RequireAccount(ctx *Context, params map[string]interface{}) error {
+	if ctx.AccountID != "" {
 		return nil
 	}
 
-	opts := make([]string, 0)
-	accounts, _ := nchain.ListAccounts(RequireAPIToken(), params)
-	for _, acct := range accounts {
-		opts = append(opts, *acct.PublicKey)
-	}
+	token := RequireAPIToken(ctx)
+	selector := &Selector{
+		Label: requireAccountSelectLabel,
+		Noun:  "accounts",
+		Hint:  "prvd accounts init",
+		Fetch: func(page, rpp uint64) ([]*SelectorItem, error) {
+			accounts, err := nchain.ListAccounts(token, paginate(params, page, rpp))
+			if err != nil {
+				return nil, err
+			}
 
-	prompt := promptui.Select{
-		Label: requireWalletSelectLabel,
-		Items: opts,
+			items := make([]*SelectorItem, 0)
+			for _, acct := range accounts {
+				items = append(items, &SelectorItem{ID: acct.ID.String(), Name: acct.Address})
+			}
+			return items, nil
+		},
 	}
 
-	i, _, err := prompt.Run()
+	item, err := selector.Run()
 	if err != nil {
 		return err
 	}
 
-	AccountID = accounts[i].ID.String()
+	ctx.AccountID = item.ID
 	return nil
 }
 
 // RequireWallet is equivalent to a required --wallet flag
-func RequireWallet() error {
-	if WalletID != "" {
+func RequireWallet(ctx *Context) error {
+	if ctx.WalletID != "" {
 		return nil
 	}
 
-	opts := make([]string, 0)
-	wallets, _ := nchain.ListWallets(RequireAPIToken(), map[string]interface{}{})
-	for _, wallet := range wallets {
-		opts = append(opts, *wallet.PublicKey)
-	}
-
-	prompt := promptui.Select{
+	token := RequireAPIToken(ctx)
+	selector := &Selector{
 		Label: requireWalletSelectLabel,
-		Items: opts,
-	}
-
-	i, _, err := prompt.Run()
+		Noun:  "wallets",
+		Hint:  "prvd wallets init",
+		Fetch: func(page, rpp uint64) ([]*SelectorItem, error) {
+			wallets, err := nchain.ListWallets(token, paginate(map[string]interface{}{}, page, rpp))
+			if err != nil {
+				return nil, err
+			}
+
+			items := make([]*SelectorItem, 0)
+			for _, wallet := range wallets {
+				items = append(items, &SelectorItem{ID: wallet.ID.String(), Name: stringOrEmpty(wallet.PublicKey)})
+			}
+			return items, nil
+		},
+	}
+
+	item, err := selector.Run()
 	if err != nil {
 		return err
 	}
 
-	WalletID = wallets[i].ID.String()
+	ctx.WalletID = item.ID
 	return nil
 }
 
@@ -528,7 +716,7 @@ func FreeInput(label string, defaultValue string, validate func(string) error) s
 	result, err := prompt.Run()
 
 	if err != nil {
-		os.Exit(1)
+		Exit(1)
 		return err.Error()
 	}
 
@@ -544,7 +732,7 @@ func SelectInput(args []string, label string) string {
 	_, result, err := prompt.Run()
 
 	if err != nil {
-		os.Exit(1)
+		Exit(1)
 		return err.Error()
 	}
 
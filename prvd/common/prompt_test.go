@@ -0,0 +1,47 @@
+/*
+ * Copyright 2017-2022 Provide Technologies Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package common
+
+import (
+	"testing"
+)
+
+func TestFuzzyMatch(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		str   string
+		want  bool
+	}{
+		{name: "empty input", input: "", str: "Acme Corp", want: true},
+		{name: "prefix", input: "acm", str: "Acme Corp", want: true},
+		{name: "ordered characters", input: "acp", str: "Acme Corp", want: true},
+		{name: "ignores case", input: "ACME", str: "acme corp", want: true},
+		{name: "ignores spaces in input", input: "acme corp", str: "AcmeCorp", want: true},
+		{name: "out of order", input: "pca", str: "Acme Corp", want: false},
+		{name: "repeated character", input: "aa", str: "Acme", want: false},
+		{name: "missing character", input: "acmez", str: "Acme Corp", want: false},
+		{name: "multibyte", input: "zü", str: "Zürich", want: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := fuzzyMatch(tt.input, tt.str); got != tt.want {
+				t.Errorf("fuzzyMatch(%q, %q) = %v, want %v", tt.input, tt.str, got, tt.want)
+			}
+		})
+	}
+}
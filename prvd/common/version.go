@@ -0,0 +1,191 @@
+/*
+ * Copyright 2017-2022 Provide Technologies Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package common
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/blang/semver/v4"
+	provide "github.com/provideplatform/provide-go/api"
+)
+
+// Build metadata; these are overridden at build time using -ldflags, i.e.:
+// -X github.com/provideplatform/provide-cli/prvd/common.Version=v1.2.3
+var (
+	Version   = "dev"
+	GitCommit = "unknown"
+	BuildDate = "unknown"
+)
+
+// APIService is a configured Provide API service
+type APIService struct {
+	Name   string
+	Host   string
+	Scheme string
+	Image  string
+}
+
+// ServiceStatus is the result of querying the status endpoint of an API service
+type ServiceStatus struct {
+	Service   *APIService
+	Reachable bool
+	Version   *string
+	Err       error
+}
+
+// ConfiguredAPIServices returns the API services configured via the environment;
+// the environment variables honored here are the same as those used by provide-go
+func ConfiguredAPIServices() []*APIService {
+	return []*APIService{
+		apiServiceFactory("ident", "provide/ident", "ident.provide.services"),
+		apiServiceFactory("nchain", "provide/nchain", "nchain.provide.services"),
+		apiServiceFactory("privacy", "provide/privacy", "privacy.provide.services"),
+		apiServiceFactory("vault", "provide/vault", "vault.provide.services"),
+		apiServiceFactory("axiom", "provide/axiom", "axiom.provide.services"),
+	}
+}
+
+func apiServiceFactory(name, image, defaultHost string) *APIService {
+	host := defaultHost
+	if os.Getenv(fmt.Sprintf("%s_API_HOST", strings.ToUpper(name))) != "" {
+		host = os.Getenv(fmt.Sprintf("%s_API_HOST", strings.ToUpper(name)))
+	}
+
+	scheme := "https"
+	if os.Getenv(fmt.Sprintf("%s_API_SCHEME", strings.ToUpper(name))) != "" {
+		scheme = os.Getenv(fmt.Sprintf("%s_API_SCHEME", strings.ToUpper(name)))
+	}
+
+	return &APIService{
+		Name:   name,
+		Host:   host,
+		Scheme: scheme,
+		Image:  image,
+	}
+}
+
+// StatusURL returns the url of the status endpoint for the API service
+func (s *APIService) StatusURL() string {
+	return fmt.Sprintf("%s://%s/status", s.Scheme, s.Host)
+}
+
+// FetchStatus queries the status endpoint of the API service; the reported
+// version is resolved when the service includes it in the response
+func (s *APIService) FetchStatus() *ServiceStatus {
+	client := &provide.Client{
+		Host:   s.Host,
+		Path:   "",
+		Scheme: s.Scheme,
+	}
+
+	status, resp, err := client.Get("status", map[string]interface{}{})
+	if err != nil {
+		return &ServiceStatus{Service: s, Err: err}
+	}
+
+	if status >= 300 {
+		return &ServiceStatus{Service: s, Err: fmt.Errorf("status endpoint returned %d status code", status)}
+	}
+
+	svcStatus := &ServiceStatus{Service: s, Reachable: true}
+	if respMap, respMapOk := resp.(map[string]interface{}); respMapOk {
+		if version, versionOk := respMap["version"].(string); versionOk && version != "" {
+			svcStatus.Version = &version
+		}
+	}
+
+	return svcStatus
+}
+
+// ExpectedVersion returns the version of the API service pinned by the
+// release manifest, or nil when not run within a release context
+func (s *APIService) ExpectedVersion() *string {
+	if !IsReleaseContext() {
+		return nil
+	}
+
+	version, err := Manifest.GetImageVersion(s.Image)
+	if err != nil {
+		return nil
+	}
+
+	return version
+}
+
+// CheckCompatibility returns an error if the version reported by the API service
+// targets a different major version than the expected version
+func (s *ServiceStatus) CheckCompatibility(expected string) error {
+	if s.Version == nil {
+		return nil
+	}
+
+	expectedVersion, err := semver.ParseTolerant(expected)
+	if err != nil {
+		return fmt.Errorf("failed to parse expected %s version: %s; %s", s.Service.Name, expected, err.Error())
+	}
+
+	actualVersion, err := semver.ParseTolerant(*s.Version)
+	if err != nil {
+		return fmt.Errorf("failed to parse %s version: %s; %s", s.Service.Name, *s.Version, err.Error())
+	}
+
+	if expectedVersion.Major != actualVersion.Major {
+		return fmt.Errorf("%s at %s reports version %s; incompatible with expected major version %d", s.Service.Name, s.Service.Host, *s.Version, expectedVersion.Major)
+	}
+
+	return nil
+}
+
+// CheckExpectedVersion returns an error if the version reported by the API service is
+// incompatible with the version pinned by the release manifest; API services are versioned
+// independently of the CLI, so nothing is enforced when no version is pinned
+func (s *ServiceStatus) CheckExpectedVersion() error {
+	expected := s.Service.ExpectedVersion()
+	if expected == nil {
+		return nil
+	}
+
+	return s.CheckCompatibility(*expected)
+}
+
+// RequireAPIServiceCompatibility enforces the target API major version of the named
+// service using its status endpoint; this is a no-op outside of a release context
+func RequireAPIServiceCompatibility(name, scheme, host string) error {
+	for _, svc := range ConfiguredAPIServices() {
+		if svc.Name != name {
+			continue
+		}
+
+		svc.Host = host
+		svc.Scheme = scheme
+
+		if svc.ExpectedVersion() == nil {
+			return nil
+		}
+
+		status := svc.FetchStatus()
+		if status.Err != nil {
+			return status.Err
+		}
+
+		return status.CheckExpectedVersion()
+	}
+
+	return fmt.Errorf("unknown API service: %s", name)
+}
@@ -0,0 +1,53 @@
+/*
+ * Copyright 2017-2022 Provide Technologies Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package common
+
+import (
+	"testing"
+)
+
+func TestCheckCompatibility(t *testing.T) {
+	tests := []struct {
+		name     string
+		version  *string
+		expected string
+		wantErr  bool
+	}{
+		{name: "same version", version: stringPtr("v1.2.3"), expected: "v1.2.3", wantErr: false},
+		{name: "same major version", version: stringPtr("1.9.0"), expected: "v1.2.3", wantErr: false},
+		{name: "tolerates missing patch", version: stringPtr("v2.1"), expected: "2.0.0", wantErr: false},
+		{name: "different major version", version: stringPtr("v2.0.0"), expected: "v1.2.3", wantErr: true},
+		{name: "unknown version", version: nil, expected: "v1.2.3", wantErr: false},
+		{name: "unparsable version", version: stringPtr("latest"), expected: "v1.2.3", wantErr: true},
+		{name: "unparsable expected version", version: stringPtr("v1.2.3"), expected: "dev", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			status := &ServiceStatus{
+				Service: &APIService{Name: "ident", Host: "ident.provide.services"},
+				Version: tt.version,
+			}
+			if err := status.CheckCompatibility(tt.expected); (err != nil) != tt.wantErr {
+				t.Errorf("CheckCompatibility(%s) error = %v, wantErr %v", tt.expected, err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func stringPtr(str string) *string {
+	return &str
+}
@@ -17,8 +17,6 @@
 package connectors
 
 import (
-	"os"
-
 	"github.com/provideplatform/provide-cli/prvd/common"
 	"github.com/spf13/cobra"
 )
@@ -41,7 +39,7 @@ This API allows you to provision load balanced, cloud-agnostic infrastructure fo
 
 		defer func() {
 			if r := recover(); r != nil {
-				os.Exit(1)
+				common.Exit(1)
 			}
 		}()
 	},
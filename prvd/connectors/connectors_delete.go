@@ -19,7 +19,6 @@ package connectors
 import (
 	"fmt"
 	"log"
-	"os"
 
 	"github.com/provideplatform/provide-cli/prvd/common"
 	provide "github.com/provideplatform/provide-go/api/nchain"
@@ -35,23 +34,24 @@ var connectorsDeleteCmd = &cobra.Command{
 }
 
 func deleteConnector(cmd *cobra.Command, args []string) {
-	token := common.RequireAPIToken()
-	err := provide.DeleteConnector(token, common.ConnectorID)
+	ctx := common.ContextFromCommand(cmd)
+	token := common.RequireAPIToken(ctx)
+	err := provide.DeleteConnector(token, ctx.ConnectorID)
 	if err != nil {
-		log.Printf("Failed to delete connector with id: %s; %s", common.ConnectorID, err.Error())
-		os.Exit(1)
+		log.Printf("Failed to delete connector with id: %s; %s", ctx.ConnectorID, err.Error())
+		common.Exit(1)
 	}
 	// if status != 204 {
 	// 	log.Printf("Failed to delete connector with id: %s; received status: %d", common.ConnectorID, status)
-	// 	os.Exit(1)
+	// 	common.Exit(1)
 	// }
-	fmt.Printf("Deleted connector with id: %s", common.ConnectorID)
+	fmt.Printf("Deleted connector with id: %s", ctx.ConnectorID)
 }
 
 func init() {
-	connectorsDeleteCmd.Flags().StringVar(&common.ConnectorID, "connector", "", "id of the connector")
+	connectorsDeleteCmd.Flags().String("connector", "", "id of the connector")
 	// connectorsDeleteCmd.MarkFlagRequired("connector")
 
-	connectorsDeleteCmd.Flags().StringVar(&common.ApplicationID, "application", "", "application id")
+	connectorsDeleteCmd.Flags().String("application", "", "application id")
 	// connectorsDeleteCmd.MarkFlagRequired("application")
 }
@@ -20,7 +20,6 @@ import (
 	"encoding/json"
 	"fmt"
 	"log"
-	"os"
 
 	"github.com/provideplatform/provide-cli/prvd/common"
 	provide "github.com/provideplatform/provide-go/api/nchain"
@@ -36,16 +35,17 @@ var connectorsDetailsCmd = &cobra.Command{
 }
 
 func fetchConnectorDetails(cmd *cobra.Command, args []string) {
-	token := common.RequireAPIToken()
+	ctx := common.ContextFromCommand(cmd)
+	token := common.RequireAPIToken(ctx)
 	params := map[string]interface{}{}
-	connector, err := provide.GetConnectorDetails(token, common.ConnectorID, params)
+	connector, err := provide.GetConnectorDetails(token, ctx.ConnectorID, params)
 	if err != nil {
-		log.Printf("Failed to retrieve details for connector with id: %s; %s", common.ConnectorID, err.Error())
-		os.Exit(1)
+		log.Printf("Failed to retrieve details for connector with id: %s; %s", ctx.ConnectorID, err.Error())
+		common.Exit(1)
 	}
 	// if status != 200 {
 	// 	log.Printf("Failed to retrieve details for connector with id: %s; received status: %d", common.ConnectorID, status)
-	// 	os.Exit(1)
+	// 	common.Exit(1)
 	// }
 	var config map[string]interface{}
 	json.Unmarshal(*connector.Config, &config)
@@ -57,6 +57,6 @@ func fetchConnectorDetails(cmd *cobra.Command, args []string) {
 }
 
 func init() {
-	connectorsDetailsCmd.Flags().StringVar(&common.ConnectorID, "connector", "", "id of the connector")
+	connectorsDetailsCmd.Flags().String("connector", "", "id of the connector")
 	// connectorsDetailsCmd.MarkFlagRequired("connector")
 }
@@ -19,7 +19,6 @@ package connectors
 import (
 	"fmt"
 	"log"
-	"os"
 
 	"github.com/provideplatform/provide-cli/prvd/common"
 	provide "github.com/provideplatform/provide-go/api/nchain"
@@ -93,17 +92,18 @@ func connectorConfigFactory() map[string]interface{} {
 }
 
 func createConnector(cmd *cobra.Command, args []string) {
-	token := common.RequireAPIToken()
+	ctx := common.ContextFromCommand(cmd)
+	token := common.RequireAPIToken(ctx)
 	params := map[string]interface{}{
 		"name":       connectorName,
-		"network_id": common.NetworkID,
+		"network_id": ctx.NetworkID,
 		"type":       connectorType,
 		"config":     connectorConfigFactory(),
 	}
 	connector, err := provide.CreateConnector(token, params)
 	if err != nil {
 		log.Printf("Failed to initialize connector; %s", err.Error())
-		os.Exit(1)
+		common.Exit(1)
 	}
 	result := fmt.Sprintf("%s\t%s\n", connector.ID.String(), *connector.Name)
 	fmt.Print(result)
@@ -116,10 +116,10 @@ func init() {
 	connectorsInitCmd.Flags().StringVar(&connectorType, "type", "", "type of the connector")
 	// connectorsInitCmd.MarkFlagRequired("type")
 
-	connectorsInitCmd.Flags().StringVar(&common.ApplicationID, "application", "", "application id")
+	connectorsInitCmd.Flags().String("application", "", "application id")
 	// connectorsInitCmd.MarkFlagRequired("application")
 
-	connectorsInitCmd.Flags().StringVar(&common.NetworkID, "network", "", "target network id")
+	connectorsInitCmd.Flags().String("network", "", "target network id")
 	// connectorsInitCmd.MarkFlagRequired("network")
 
 	common.RequireInfrastructureFlags(connectorsInitCmd, true)
@@ -20,7 +20,6 @@ import (
 	"encoding/json"
 	"fmt"
 	"log"
-	"os"
 
 	"github.com/provideplatform/provide-cli/prvd/common"
 	provide "github.com/provideplatform/provide-go/api/nchain"
@@ -39,22 +38,23 @@ var connectorsListCmd = &cobra.Command{
 }
 
 func listConnectors(cmd *cobra.Command, args []string) {
-	token := common.RequireAPIToken()
+	ctx := common.ContextFromCommand(cmd)
+	token := common.RequireAPIToken(ctx)
 	params := map[string]interface{}{
 		"page": fmt.Sprintf("%d", page),
 		"rpp":  fmt.Sprintf("%d", rpp),
 	}
-	if common.ApplicationID != "" {
-		params["application_id"] = common.ApplicationID
+	if ctx.ApplicationID != "" {
+		params["application_id"] = ctx.ApplicationID
 	}
 	connectors, err := provide.ListConnectors(token, params)
 	if err != nil {
 		log.Printf("Failed to retrieve connectors list; %s", err.Error())
-		os.Exit(1)
+		common.Exit(1)
 	}
 	// if status != 200 {
 	// 	log.Printf("Failed to retrieve connectors list; received status: %d", status)
-	// 	os.Exit(1)
+	// 	common.Exit(1)
 	// }
 	for i := range connectors {
 		connector := connectors[i]
@@ -69,7 +69,7 @@ func listConnectors(cmd *cobra.Command, args []string) {
 }
 
 func init() {
-	connectorsListCmd.Flags().StringVar(&common.ApplicationID, "application", "", "application identifier to filter connectors")
+	connectorsListCmd.Flags().String("application", "", "application identifier to filter connectors")
 	connectorsListCmd.Flags().BoolVarP(&optional, "optional", "", false, "List all the optional flags")
 	connectorsListCmd.Flags().BoolVarP(&paginate, "paginate", "", false, "List pagination flags")
 	connectorsListCmd.Flags().Uint64Var(&page, "page", common.DefaultPage, "page number to retrieve")
@@ -33,6 +33,7 @@ var emptyPromptLabel = "What would you like to do"
 
 // General Endpoints
 func generalPrompt(cmd *cobra.Command, args []string, currentStep string) {
+	ctx := common.ContextFromCommand(cmd)
 	switch step := currentStep; step {
 	case promptStepInit:
 		if connectorName == "" {
@@ -41,11 +42,11 @@ func generalPrompt(cmd *cobra.Command, args []string, currentStep string) {
 		if connectorType == "" {
 			connectorType = common.FreeInput("Connector Type", "", common.MandatoryValidation)
 		}
-		if common.ApplicationID == "" {
-			common.RequireApplication()
+		if ctx.ApplicationID == "" {
+			common.RequireApplication(ctx)
 		}
-		if common.NetworkID == "" {
-			common.RequireL1Network()
+		if ctx.NetworkID == "" {
+			common.RequireL1Network(ctx)
 		}
 		if optional {
 			if ipfsAPIPort == 5001 {
@@ -60,19 +61,19 @@ func generalPrompt(cmd *cobra.Command, args []string, currentStep string) {
 		createConnector(cmd, args)
 	case promptStepList:
 		if optional {
-			common.RequireApplication()
+			common.RequireApplication(ctx)
 		}
 		page, rpp = common.PromptPagination(paginate, page, rpp)
 		listConnectors(cmd, args)
 	case promptStepDetails:
-		common.RequireConnector(map[string]interface{}{})
+		common.RequireConnector(ctx, map[string]interface{}{})
 		fetchConnectorDetails(cmd, args)
 	case promptStepDelete:
-		if common.ConnectorID == "" {
-			common.RequireConnector(map[string]interface{}{})
+		if ctx.ConnectorID == "" {
+			common.RequireConnector(ctx, map[string]interface{}{})
 		}
-		if common.ApplicationID == "" {
-			common.RequireApplication()
+		if ctx.ApplicationID == "" {
+			common.RequireApplication(ctx)
 		}
 		deleteConnector(cmd, args)
 	case "":
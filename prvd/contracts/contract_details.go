@@ -19,7 +19,6 @@ package contracts
 import (
 	"fmt"
 	"log"
-	"os"
 
 	"github.com/provideplatform/provide-cli/prvd/common"
 	provide "github.com/provideplatform/provide-go/api/nchain"
@@ -35,22 +34,33 @@ var contractsDetailsCmd = &cobra.Command{
 }
 
 func fetchContractDetails(cmd *cobra.Command, args []string) {
-	token := common.RequireAPIToken()
+	ctx := common.ContextFromCommand(cmd)
+	token := common.RequireAPIToken(ctx)
 	params := map[string]interface{}{}
-	contract, err := provide.GetContractDetails(token, common.ContractID, params)
+	contract, err := provide.GetContractDetails(token, ctx.ContractID, params)
 	if err != nil {
-		log.Printf("Failed to retrieve details for contract with id: %s; %s", common.ContractID, err.Error())
-		os.Exit(1)
+		log.Printf("Failed to retrieve details for contract with id: %s; %s", ctx.ContractID, err.Error())
+		common.Exit(1)
 	}
 	// if status != 200 {
 	// 	log.Printf("Failed to retrieve details for contract with id: %s; %s", common.ContractID, resp)
-	// 	os.Exit(1)
+	// 	common.Exit(1)
 	// }
 	result := fmt.Sprintf("%s\t%s\n", contract.ID.String(), *contract.Name)
 	fmt.Print(result)
+
+	if contract.Address == nil || *contract.Address == "0x" {
+		return
+	}
+
+	common.ViewOnExplorer(token, contract.NetworkID.String(), func(explorer *common.Explorer) string {
+		return explorer.ContractURL(*contract.Address)
+	}, openExplorer)
 }
 
 func init() {
-	contractsDetailsCmd.Flags().StringVar(&common.ContractID, "contract", "", "id of the contract")
+	contractsDetailsCmd.Flags().String("contract", "", "id of the contract")
 	contractsDetailsCmd.MarkFlagRequired("contract")
+
+	contractsDetailsCmd.Flags().BoolVar(&openExplorer, "open", false, "open the contract in the block explorer for its network")
 }
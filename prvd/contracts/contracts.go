@@ -17,8 +17,6 @@
 package contracts
 
 import (
-	"os"
-
 	"github.com/provideplatform/provide-cli/prvd/common"
 	"github.com/spf13/cobra"
 )
@@ -28,6 +26,7 @@ const contractTypeRegistry = "registry"
 var contract map[string]interface{}
 var contracts []interface{}
 var contractType string
+var openExplorer bool
 
 var ContractsCmd = &cobra.Command{
 	Use:   "contracts",
@@ -40,14 +39,23 @@ var ContractsCmd = &cobra.Command{
 
 		defer func() {
 			if r := recover(); r != nil {
-				os.Exit(1)
+				common.Exit(1)
 			}
 		}()
 	},
 }
 
+// ResetState restores the package-level state assigned by prompts, which is not bound to a flag;
+// the shell calls this prior to each in-process invocation
+func ResetState() {
+	contract = nil
+	contractType = ""
+	compiledArtifact = nil
+}
+
 func init() {
 	ContractsCmd.AddCommand(contractsListCmd)
+	ContractsCmd.AddCommand(contractsDetailsCmd)
 	ContractsCmd.AddCommand(contractsExecuteCmd)
 	ContractsCmd.Flags().BoolVarP(&optional, "optional", "", false, "List all the optional flags")
 	ContractsCmd.Flags().BoolVarP(&paginate, "paginate", "", false, "List pagination flags")
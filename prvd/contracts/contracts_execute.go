@@ -19,7 +19,6 @@ package contracts
 import (
 	"fmt"
 	"log"
-	"os"
 	"strings"
 
 	"github.com/provideplatform/provide-cli/prvd/common"
@@ -42,30 +41,31 @@ var contractsExecuteCmd = &cobra.Command{
 }
 
 func executeContract(cmd *cobra.Command, args []string) {
-	if common.AccountID == "" && common.WalletID == "" {
+	ctx := common.ContextFromCommand(cmd)
+	if ctx.AccountID == "" && ctx.WalletID == "" {
 		fmt.Println("Cannot execute a contract without a specified signer.")
-		os.Exit(1)
+		common.Exit(1)
 	}
-	token := common.RequireAPIToken()
+	token := common.RequireAPIToken(ctx)
 	params := map[string]interface{}{
 		"method": contractExecMethod,
 		"params": contractExecParams,
 		"value":  contractExecValue,
 	}
-	if common.AccountID != "" {
-		if strings.HasPrefix(common.AccountID, "0x") {
-			params["account_address"] = common.AccountID
+	if ctx.AccountID != "" {
+		if strings.HasPrefix(ctx.AccountID, "0x") {
+			params["account_address"] = ctx.AccountID
 		} else {
-			params["account_id"] = common.AccountID
+			params["account_id"] = ctx.AccountID
 		}
 	}
-	if common.WalletID != "" {
-		params["wallet_id"] = common.WalletID
+	if ctx.WalletID != "" {
+		params["wallet_id"] = ctx.WalletID
 	}
-	resp, err := provide.ExecuteContract(token, common.ContractID, params)
+	resp, err := provide.ExecuteContract(token, ctx.ContractID, params)
 	if err != nil {
-		log.Printf("Failed to execute contract with id: %s; %s", common.ContractID, err.Error())
-		os.Exit(1)
+		log.Printf("Failed to execute contract with id: %s; %s", ctx.ContractID, err.Error())
+		common.Exit(1)
 	}
 
 	fmt.Printf("Successfully executed tx for asynchronous contract execution; tx ref: %s", *resp.Reference)
@@ -84,7 +84,7 @@ func executeContract(cmd *cobra.Command, args []string) {
 }
 
 func init() {
-	contractsExecuteCmd.Flags().StringVar(&common.ContractID, "contract", "", "target contract id")
+	contractsExecuteCmd.Flags().String("contract", "", "target contract id")
 	// contractsExecuteCmd.MarkFlagRequired("contract")
 
 	contractsExecuteCmd.Flags().StringVar(&contractExecMethod, "method", "", "ABI method to invoke on the contract")
@@ -92,7 +92,7 @@ func init() {
 
 	contractsExecuteCmd.Flags().Uint64Var(&contractExecValue, "value", 0, "value to send with transaction, specific in the smallest denonination of currency for the network (i.e., wei)")
 
-	contractsExecuteCmd.Flags().StringVar(&common.AccountID, "account", "", "signing account id with which to sign the tx")
-	contractsExecuteCmd.Flags().StringVar(&common.WalletID, "wallet", "", "HD wallet id with which to sign the tx")
+	contractsExecuteCmd.Flags().String("account", "", "signing account id with which to sign the tx")
+	contractsExecuteCmd.Flags().String("wallet", "", "HD wallet id with which to sign the tx")
 	contractsExecuteCmd.Flags().BoolVarP(&optional, "optional", "", false, "List all the optional flags")
 }
@@ -19,7 +19,6 @@ package contracts
 import (
 	"fmt"
 	"log"
-	"os"
 
 	"github.com/provideplatform/provide-cli/prvd/common"
 	provide "github.com/provideplatform/provide-go/api/nchain"
@@ -56,9 +55,9 @@ func compiledArtifactFactory() map[string]interface{} {
 	}
 }
 
-func contractParamsFactory() map[string]interface{} {
+func contractParamsFactory(ctx *common.Context) map[string]interface{} {
 	params := map[string]interface{}{
-		"wallet_id":         common.WalletID,
+		"wallet_id":         ctx.WalletID,
 		"compiled_artifact": compiledArtifactFactory(),
 	}
 	if contractType != "" {
@@ -68,24 +67,25 @@ func contractParamsFactory() map[string]interface{} {
 }
 
 func createContract(cmd *cobra.Command, args []string) {
-	if common.WalletID == "" {
+	ctx := common.ContextFromCommand(cmd)
+	if ctx.WalletID == "" {
 		fmt.Println("Cannot create a contract without a specified signer.")
-		os.Exit(1)
+		common.Exit(1)
 	}
-	token := common.RequireAPIToken()
+	token := common.RequireAPIToken(ctx)
 	params := map[string]interface{}{
 		"name":           contractName,
-		"network_id":     common.NetworkID,
-		"application_id": common.ApplicationID,
+		"network_id":     ctx.NetworkID,
+		"application_id": ctx.ApplicationID,
 		"address":        "0x",
-		"params":         contractParamsFactory(),
+		"params":         contractParamsFactory(ctx),
 	}
 	contract, err := provide.CreateContract(token, params)
 	if err != nil {
 		log.Printf("Failed to initialize application; %s", err.Error())
-		os.Exit(1)
+		common.Exit(1)
 	}
-	common.ContractID = contract.ID.String()
+	ctx.ContractID = contract.ID.String()
 	result := fmt.Sprintf("%s\t%s\n", contract.ID.String(), *contract.Name)
 	fmt.Print(result)
 }
@@ -94,12 +94,12 @@ func init() {
 	contractsInitCmd.Flags().StringVar(&contractName, "name", "", "name of the contract")
 	contractsInitCmd.MarkFlagRequired("name")
 
-	contractsInitCmd.Flags().StringVar(&common.NetworkID, "network", "", "target network id")
+	contractsInitCmd.Flags().String("network", "", "target network id")
 	contractsInitCmd.MarkFlagRequired("network")
 
-	contractsInitCmd.Flags().StringVar(&common.ApplicationID, "application", "", "target application id")
+	contractsInitCmd.Flags().String("application", "", "target application id")
 	contractsInitCmd.MarkFlagRequired("application")
 
-	contractsInitCmd.Flags().StringVar(&common.WalletID, "wallet", "", "wallet id with which to sign the tx")
+	contractsInitCmd.Flags().String("wallet", "", "wallet id with which to sign the tx")
 	contractsInitCmd.MarkFlagRequired("wallet")
 }
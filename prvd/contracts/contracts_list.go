@@ -19,7 +19,6 @@ package contracts
 import (
 	"fmt"
 	"log"
-	"os"
 
 	"github.com/provideplatform/provide-cli/prvd/common"
 	provide "github.com/provideplatform/provide-go/api/nchain"
@@ -38,28 +37,32 @@ var contractsListCmd = &cobra.Command{
 }
 
 func listContracts(cmd *cobra.Command, args []string) {
-	token := common.RequireAPIToken()
+	ctx := common.ContextFromCommand(cmd)
+	token := common.RequireAPIToken(ctx)
 	params := map[string]interface{}{
 		"page": fmt.Sprintf("%d", page),
 		"rpp":  fmt.Sprintf("%d", rpp),
 	}
-	if common.ApplicationID != "" {
-		params["application_id"] = common.ApplicationID
+	if ctx.ApplicationID != "" {
+		params["application_id"] = ctx.ApplicationID
 	}
 	contracts, err := provide.ListContracts(token, params)
 	if err != nil {
 		log.Printf("Failed to retrieve contracts list; %s", err.Error())
-		os.Exit(1)
+		common.Exit(1)
 	}
 	for i := range contracts {
 		contract := contracts[i]
-		result := fmt.Sprintf("%s\t%s\t%s\n", contract.ID.String(), *contract.Address, *contract.Name)
-		fmt.Print(result)
+		result := fmt.Sprintf("%s\t%s\t%s", contract.ID.String(), *contract.Address, *contract.Name)
+		if explorer := common.ResolveExplorer(token, contract.NetworkID.String()); explorer != nil && *contract.Address != "0x" {
+			result = fmt.Sprintf("%s\t%s", result, explorer.ContractURL(*contract.Address))
+		}
+		fmt.Println(result)
 	}
 }
 
 func init() {
-	contractsListCmd.Flags().StringVar(&common.ApplicationID, "application", "", "application identifier to filter contracts")
+	contractsListCmd.Flags().String("application", "", "application identifier to filter contracts")
 	contractsListCmd.Flags().BoolVarP(&optional, "optional", "", false, "List all the optional flags")
 	contractsListCmd.Flags().BoolVarP(&paginate, "paginate", "", false, "List pagination flags")
 	contractsListCmd.Flags().Uint64Var(&page, "page", common.DefaultPage, "page number to retrieve")
@@ -31,20 +31,21 @@ var emptyPromptLabel = "What would you like to do"
 
 // General Endpoints
 func generalPrompt(cmd *cobra.Command, args []string, currentStep string) {
+	ctx := common.ContextFromCommand(cmd)
 	switch step := currentStep; step {
 	case promptStepExecute:
 		if contractExecMethod == "" {
 			contractExecMethod = common.FreeInput("Method", "", common.MandatoryValidation)
 		}
-		if common.ContractID == "" {
-			common.ContractID = common.FreeInput("Contract ID", "", common.MandatoryValidation)
+		if ctx.ContractID == "" {
+			ctx.ContractID = common.FreeInput("Contract ID", "", common.MandatoryValidation)
 		}
 		if optional {
-			if common.AccountID == "" {
-				common.RequireAccount(map[string]interface{}{})
+			if ctx.AccountID == "" {
+				common.RequireAccount(ctx, map[string]interface{}{})
 			}
-			if common.WalletID == "" {
-				common.RequireWallet()
+			if ctx.WalletID == "" {
+				common.RequireWallet(ctx)
 			}
 			if contractExecValue == 0 {
 				result := common.FreeInput("Value", "0", common.NumberValidation)
@@ -55,7 +56,7 @@ func generalPrompt(cmd *cobra.Command, args []string, currentStep string) {
 		executeContract(cmd, args)
 	case promptStepList:
 		if optional {
-			common.RequireApplication()
+			common.RequireApplication(ctx)
 		}
 		page, rpp = common.PromptPagination(paginate, page, rpp)
 	case "":
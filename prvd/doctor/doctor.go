@@ -0,0 +1,96 @@
+/*
+ * Copyright 2017-2022 Provide Technologies Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package doctor
+
+import (
+	"fmt"
+	"log"
+	"strings"
+
+	"github.com/provideplatform/provide-cli/prvd/axiom/stack"
+	"github.com/provideplatform/provide-cli/prvd/common"
+	"github.com/spf13/cobra"
+)
+
+const checkStatusPass = "pass"
+const checkStatusWarn = "warn"
+const checkStatusFail = "fail"
+
+var bundlePath string
+var name string
+
+// checkResult is the outcome of a single diagnostic check
+type checkResult struct {
+	status string
+	check  string
+	detail string
+}
+
+func (r *checkResult) String() string {
+	return fmt.Sprintf("[%s]\t%s\t%s", r.status, r.check, r.detail)
+}
+
+var DoctorCmd = &cobra.Command{
+	Use:   "doctor",
+	Short: "Diagnose the local prvd environment",
+	Long: `Check the prvd configuration and its permissions, the validity of cached tokens, the reachability
+and status of each configured API host, the Docker daemon, the state of the local axiom stack containers
+and the availability of the ports used by the stack.
+
+Run with --bundle to also write a support bundle containing the redacted configuration, version
+information and recent stack logs.`,
+	Run: doctor,
+}
+
+func doctor(cmd *cobra.Command, args []string) {
+	diag := newDiagnostics(name)
+	defer diag.close()
+
+	results := diag.run()
+
+	failed := false
+	for _, result := range results {
+		fmt.Println(result.String())
+		failed = failed || result.status == checkStatusFail
+	}
+
+	if bundlePath != "" {
+		if err := diag.writeBundle(bundlePath, results); err != nil {
+			log.Printf("failed to write support bundle; %s", err.Error())
+			common.Exit(1)
+		}
+		fmt.Printf("\nWrote support bundle: %s\n", bundlePath)
+	}
+
+	if failed {
+		common.Exit(1)
+	}
+}
+
+// formatResults renders the given check results as they are printed by `prvd doctor`
+func formatResults(results []*checkResult) string {
+	lines := make([]string, 0)
+	for _, result := range results {
+		lines = append(lines, result.String())
+	}
+	return fmt.Sprintf("%s\n", strings.Join(lines, "\n"))
+}
+
+func init() {
+	DoctorCmd.Flags().StringVar(&bundlePath, "bundle", "", "path of a .tgz support bundle to write, i.e., out.tgz")
+	DoctorCmd.Flags().StringVar(&name, "name", stack.DefaultName, "name of the axiom stack instance")
+}
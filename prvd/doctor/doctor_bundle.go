@@ -0,0 +1,216 @@
+/*
+ * Copyright 2017-2022 Provide Technologies Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package doctor
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"regexp"
+	"runtime"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/pkg/stdcopy"
+	"github.com/provideplatform/provide-cli/prvd/common"
+	"github.com/spf13/viper"
+)
+
+const bundleLogTail = "500"
+const bundleLogTimeout = time.Second * 10
+const redacted = "[REDACTED]"
+
+// redactedKeyPartials are the substrings of config keys whose values are redacted from the bundle
+var redactedKeyPartials = []string{"credential", "key", "password", "secret", "seed", "token"}
+
+// redactedBearerTokenPattern matches the JWT bearer tokens redacted from container logs
+var redactedBearerTokenPattern = regexp.MustCompile(`eyJ[A-Za-z0-9_-]+\.[A-Za-z0-9_-]+\.[A-Za-z0-9_-]*`)
+
+// redactedAssignmentPattern matches the values assigned to sensitive keys in container logs;
+// i.e., `DATABASE_PASSWORD=...` or `"refresh_token": "..."`
+var redactedAssignmentPattern = regexp.MustCompile(`(?i)((?:credential|key|password|secret|seed|token)[A-Za-z0-9_]*["']?\s*[:=]\s*["']?)[^\s"',}&]+`)
+
+// writeBundle writes a gzipped tarball containing the check results, versions, redacted config and recent stack logs
+func (d *diagnostics) writeBundle(path string, results []*checkResult) error {
+	files := map[string][]byte{
+		"doctor.txt":   []byte(formatResults(results)),
+		"versions.txt": []byte(d.versions()),
+	}
+
+	config, err := json.MarshalIndent(redact(viper.AllSettings()), "", "  ")
+	if err != nil {
+		return err
+	}
+	files["config.json"] = config
+
+	for _, container := range d.containers {
+		logs, err := d.containerLogs(container.ID)
+		if err != nil {
+			logs = []byte(fmt.Sprintf("failed to retrieve logs; %s\n", err.Error()))
+		}
+		files[fmt.Sprintf("logs/%s.log", containerName(container))] = redactLogs(logs)
+	}
+
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_TRUNC|os.O_WRONLY, 0600)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	gz := gzip.NewWriter(f)
+	tw := tar.NewWriter(gz)
+
+	names := make([]string, 0)
+	for name := range files {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	now := time.Now()
+	for _, name := range names {
+		content := files[name]
+		err := tw.WriteHeader(&tar.Header{
+			Name:    fmt.Sprintf("prvd-doctor/%s", name),
+			Mode:    0600,
+			Size:    int64(len(content)),
+			ModTime: now,
+		})
+		if err != nil {
+			return err
+		}
+
+		if _, err := tw.Write(content); err != nil {
+			return err
+		}
+	}
+
+	if err := tw.Close(); err != nil {
+		return err
+	}
+	return gz.Close()
+}
+
+func (d *diagnostics) versions() string {
+	lines := []string{
+		fmt.Sprintf("prvd %s", common.Version),
+		fmt.Sprintf("git commit: %s", common.GitCommit),
+		fmt.Sprintf("build date: %s", common.BuildDate),
+		fmt.Sprintf("go: %s %s/%s", runtime.Version(), runtime.GOOS, runtime.GOARCH),
+	}
+
+	if common.IsReleaseContext() {
+		lines = append(lines, fmt.Sprintf("release: %s %s", common.Manifest.Name, common.Manifest.Version))
+	}
+
+	if d.dockerVersion != nil {
+		lines = append(lines, fmt.Sprintf("docker: %s (api %s) %s/%s", d.dockerVersion.Version, d.dockerVersion.APIVersion, d.dockerVersion.Os, d.dockerVersion.Arch))
+	}
+
+	for _, status := range d.serviceStatuses {
+		version := "unreachable"
+		if status.Err == nil {
+			version = "unknown"
+			if status.Version != nil {
+				version = *status.Version
+			}
+		}
+		lines = append(lines, fmt.Sprintf("%s: %s", status.Service.Name, version))
+	}
+
+	for _, container := range d.containers {
+		lines = append(lines, fmt.Sprintf("%s: %s", containerName(container), container.Image))
+	}
+
+	return fmt.Sprintf("%s\n", strings.Join(lines, "\n"))
+}
+
+func (d *diagnostics) containerLogs(containerID string) ([]byte, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), bundleLogTimeout)
+	defer cancel()
+
+	out, err := d.docker.ContainerLogs(ctx, containerID, types.ContainerLogsOptions{
+		ShowStderr: true,
+		ShowStdout: true,
+		Tail:       bundleLogTail,
+		Timestamps: true,
+	})
+	if err != nil {
+		return nil, err
+	}
+	defer out.Close()
+
+	buf := &bytes.Buffer{}
+	if _, err := stdcopy.StdCopy(buf, buf, out); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// redact returns a copy of the given settings with the values of sensitive keys redacted
+func redact(settings map[string]interface{}) map[string]interface{} {
+	redactedSettings := map[string]interface{}{}
+	for key, val := range settings {
+		if nested, nestedOk := val.(map[string]interface{}); nestedOk {
+			redactedSettings[key] = redact(nested)
+		} else if isSensitiveKey(key) {
+			redactedSettings[key] = redacted
+		} else {
+			redactedSettings[key] = val
+		}
+	}
+	return redactedSettings
+}
+
+// redactLogs returns a copy of the given logs with bearer tokens, the values of sensitive
+// keys and any secret values found in the config redacted
+func redactLogs(logs []byte) []byte {
+	for _, secret := range secretValues(viper.AllSettings()) {
+		logs = bytes.ReplaceAll(logs, []byte(secret), []byte(redacted))
+	}
+
+	logs = redactedBearerTokenPattern.ReplaceAll(logs, []byte(redacted))
+	return redactedAssignmentPattern.ReplaceAll(logs, []byte("${1}"+redacted))
+}
+
+// secretValues returns the string values of the sensitive keys in the given settings
+func secretValues(settings map[string]interface{}) []string {
+	secrets := make([]string, 0)
+	for key, val := range settings {
+		if nested, nestedOk := val.(map[string]interface{}); nestedOk {
+			secrets = append(secrets, secretValues(nested)...)
+		} else if secret, secretOk := val.(string); secretOk && secret != "" && isSensitiveKey(key) {
+			secrets = append(secrets, secret)
+		}
+	}
+	return secrets
+}
+
+func isSensitiveKey(key string) bool {
+	key = strings.ToLower(key)
+	for _, partial := range redactedKeyPartials {
+		if strings.Contains(key, partial) {
+			return true
+		}
+	}
+	return false
+}
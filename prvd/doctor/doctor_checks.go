@@ -0,0 +1,268 @@
+/*
+ * Copyright 2017-2022 Provide Technologies Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package doctor
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"os"
+	"runtime"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/client"
+	"github.com/provideplatform/provide-cli/prvd/axiom/stack"
+	"github.com/provideplatform/provide-cli/prvd/common"
+	"github.com/provideplatform/provide-go/api/ident"
+	"github.com/spf13/viper"
+)
+
+const dockerTimeout = time.Second * 5
+
+// diagnostics holds the state shared by the checks run by `prvd doctor`
+type diagnostics struct {
+	stack string
+
+	docker        *client.Client
+	dockerVersion *types.Version
+	containers    []types.Container
+
+	serviceStatuses []*common.ServiceStatus
+}
+
+func newDiagnostics(stack string) *diagnostics {
+	return &diagnostics{
+		stack: stack,
+	}
+}
+
+func (d *diagnostics) close() {
+	if d.docker != nil {
+		d.docker.Close()
+	}
+}
+
+// run executes each check in order; later checks depend on state resolved by earlier checks
+func (d *diagnostics) run() []*checkResult {
+	results := make([]*checkResult, 0)
+	results = append(results, d.checkConfig()...)
+	results = append(results, d.checkTokens()...)
+	results = append(results, d.checkAPIServices()...)
+	results = append(results, d.checkDocker()...)
+	results = append(results, d.checkStackContainers()...)
+	results = append(results, d.checkPorts()...)
+	return results
+}
+
+func (d *diagnostics) checkConfig() []*checkResult {
+	path := viper.ConfigFileUsed()
+	if path == "" {
+		return []*checkResult{{checkStatusWarn, "config", "no configuration file in use"}}
+	}
+
+	info, err := os.Stat(path)
+	if err != nil {
+		return []*checkResult{{checkStatusFail, "config", fmt.Sprintf("%s; %s", path, err.Error())}}
+	}
+
+	if _, err := os.ReadFile(path); err != nil {
+		return []*checkResult{{checkStatusFail, "config", fmt.Sprintf("%s is not readable; %s", path, err.Error())}}
+	}
+
+	if runtime.GOOS != "windows" && info.Mode().Perm()&0077 != 0 {
+		return []*checkResult{{checkStatusWarn, "config", fmt.Sprintf("%s has permissions %04o; it contains tokens and should only be accessible by you; run chmod 600 %s", path, info.Mode().Perm(), path)}}
+	}
+
+	return []*checkResult{{checkStatusPass, "config", path}}
+}
+
+// checkTokens checks the cached user-scoped token, and the tokens cached for each application and organization
+func (d *diagnostics) checkTokens() []*checkResult {
+	results := make([]*checkResult, 0)
+
+	if viper.GetString(common.AccessTokenConfigKey) == "" && viper.GetString(common.RefreshTokenConfigKey) == "" {
+		results = append(results, &checkResult{checkStatusWarn, "token:user", "not authenticated; run prvd authenticate"})
+	} else {
+		results = append(results, checkToken("token:user", viper.GetString(common.AccessTokenConfigKey), viper.GetString(common.RefreshTokenConfigKey)))
+	}
+
+	scopes := make([]string, 0)
+	for key, val := range viper.AllSettings() {
+		if settings, settingsOk := val.(map[string]interface{}); settingsOk {
+			if _, tokenOk := settings[common.AccessTokenConfigKey]; tokenOk {
+				scopes = append(scopes, key)
+			}
+		}
+	}
+	sort.Strings(scopes)
+
+	for _, id := range scopes {
+		accessToken := viper.GetString(common.BuildConfigKeyWithID(common.AccessTokenConfigKey, id))
+		refreshToken := viper.GetString(common.BuildConfigKeyWithID(common.RefreshTokenConfigKey, id))
+		results = append(results, checkToken(fmt.Sprintf("token:%s", id), accessToken, refreshToken))
+	}
+
+	return results
+}
+
+// checkToken verifies the given access token using an authenticated ident request; the refresh
+// token can only be verified by using it, so an expired access token is a warning when the
+// refresh token has not expired
+func checkToken(check, accessToken, refreshToken string) *checkResult {
+	accessExp, accessErr := common.TokenExpiry(accessToken)
+	if accessErr == nil && (accessExp == nil || accessExp.After(time.Now())) {
+		if _, err := ident.ListTokens(accessToken, map[string]interface{}{"rpp": "1"}); err != nil {
+			return &checkResult{checkStatusFail, check, fmt.Sprintf("access token rejected by ident; %s; run prvd authenticate", err.Error())}
+		}
+
+		if accessExp == nil {
+			return &checkResult{checkStatusPass, check, "access token does not expire"}
+		}
+		return &checkResult{checkStatusPass, check, fmt.Sprintf("access token expires %s", accessExp.Format(time.RFC3339))}
+	}
+
+	refreshExp, refreshErr := common.TokenExpiry(refreshToken)
+	if refreshToken != "" && refreshErr == nil && (refreshExp == nil || refreshExp.After(time.Now())) {
+		return &checkResult{checkStatusWarn, check, "access token expired or invalid; it will be refreshed on next use"}
+	}
+
+	return &checkResult{checkStatusFail, check, "access and refresh tokens expired or invalid; run prvd authenticate"}
+}
+
+func (d *diagnostics) checkAPIServices() []*checkResult {
+	results := make([]*checkResult, 0)
+
+	for _, svc := range common.ConfiguredAPIServices() {
+		check := fmt.Sprintf("api:%s", svc.Name)
+
+		status := svc.FetchStatus()
+		d.serviceStatuses = append(d.serviceStatuses, status)
+		if status.Err != nil {
+			results = append(results, &checkResult{checkStatusFail, check, fmt.Sprintf("%s unreachable; %s", svc.StatusURL(), status.Err.Error())})
+			continue
+		}
+
+		version := "unknown version"
+		if status.Version != nil {
+			version = *status.Version
+		}
+
+		if err := status.CheckExpectedVersion(); err != nil {
+			results = append(results, &checkResult{checkStatusWarn, check, fmt.Sprintf("%s; %s", svc.StatusURL(), err.Error())})
+			continue
+		}
+
+		results = append(results, &checkResult{checkStatusPass, check, fmt.Sprintf("%s %s", svc.StatusURL(), version)})
+	}
+
+	return results
+}
+
+func (d *diagnostics) checkDocker() []*checkResult {
+	docker, err := client.NewEnvClient()
+	if err != nil {
+		return []*checkResult{{checkStatusFail, "docker", fmt.Sprintf("failed to initialize docker client; %s", err.Error())}}
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), dockerTimeout)
+	defer cancel()
+
+	version, err := docker.ServerVersion(ctx)
+	if err != nil {
+		docker.Close()
+		return []*checkResult{{checkStatusFail, "docker", fmt.Sprintf("docker daemon unreachable; %s", err.Error())}}
+	}
+
+	d.docker = docker
+	d.dockerVersion = &version
+	return []*checkResult{{checkStatusPass, "docker", fmt.Sprintf("docker %s (api %s)", version.Version, version.APIVersion)}}
+}
+
+func (d *diagnostics) checkStackContainers() []*checkResult {
+	if d.docker == nil {
+		return []*checkResult{{checkStatusWarn, "stack", "skipped; docker daemon unreachable"}}
+	}
+
+	containers, err := common.ListContainers(d.docker, d.stack)
+	if err != nil {
+		return []*checkResult{{checkStatusFail, "stack", err.Error()}}
+	}
+
+	d.containers = containers
+	if len(d.containers) == 0 {
+		return []*checkResult{{checkStatusWarn, "stack", fmt.Sprintf("no containers found for stack %s; run prvd axiom stack start", d.stack)}}
+	}
+
+	results := make([]*checkResult, 0)
+	for _, container := range d.containers {
+		check := fmt.Sprintf("stack:%s", containerName(container))
+		status := checkStatusPass
+		if container.State != "running" {
+			status = checkStatusWarn
+		}
+		results = append(results, &checkResult{status, check, fmt.Sprintf("%s; %s", container.State, container.Status)})
+	}
+
+	return results
+}
+
+func (d *diagnostics) checkPorts() []*checkResult {
+	published := map[int]string{}
+	for _, container := range d.containers {
+		for _, port := range container.Ports {
+			if port.PublicPort != 0 {
+				published[int(port.PublicPort)] = containerName(container)
+			}
+		}
+	}
+
+	ports := stack.HostPorts(d.stack)
+	svcs := make([]string, 0)
+	for svc := range ports {
+		svcs = append(svcs, svc)
+	}
+	sort.Strings(svcs)
+
+	results := make([]*checkResult, 0)
+	for _, svc := range svcs {
+		port := ports[svc]
+		check := fmt.Sprintf("port:%d", port)
+
+		listener, err := net.Listen("tcp", net.JoinHostPort("", strconv.Itoa(port)))
+		if err == nil {
+			listener.Close()
+			results = append(results, &checkResult{checkStatusPass, check, fmt.Sprintf("available for %s", svc)})
+		} else if container, containerOk := published[port]; containerOk {
+			results = append(results, &checkResult{checkStatusPass, check, fmt.Sprintf("in use by %s", container)})
+		} else {
+			results = append(results, &checkResult{checkStatusWarn, check, fmt.Sprintf("in use by another process; %s will not be able to bind", svc)})
+		}
+	}
+
+	return results
+}
+
+func containerName(container types.Container) string {
+	if len(container.Names) > 0 {
+		return strings.TrimPrefix(container.Names[0], "/")
+	}
+	return container.ID
+}
@@ -19,7 +19,6 @@ package networks
 import (
 	"fmt"
 	"log"
-	"os"
 
 	"github.com/provideplatform/provide-cli/prvd/common"
 	provide "github.com/provideplatform/provide-go/api/nchain"
@@ -35,22 +34,23 @@ var networksDisableCmd = &cobra.Command{
 }
 
 func disableNetwork(cmd *cobra.Command, args []string) {
-	token := common.RequireAPIToken()
-	err := provide.UpdateNetwork(token, common.NetworkID, map[string]interface{}{
+	ctx := common.ContextFromCommand(cmd)
+	token := common.RequireAPIToken(ctx)
+	err := provide.UpdateNetwork(token, ctx.NetworkID, map[string]interface{}{
 		"enabled": false,
 	})
 	if err != nil {
-		log.Printf("Failed to disable network with id: %s; %s", common.NetworkID, err.Error())
-		os.Exit(1)
+		log.Printf("Failed to disable network with id: %s; %s", ctx.NetworkID, err.Error())
+		common.Exit(1)
 	}
 	// if status != 204 {
 	// 	log.Printf("Failed to disable network with id: %s; received status: %d", common.NetworkID, status)
-	// 	os.Exit(1)
+	// 	common.Exit(1)
 	// }
-	fmt.Printf("Disabled network with id: %s", common.NetworkID)
+	fmt.Printf("Disabled network with id: %s", ctx.NetworkID)
 }
 
 func init() {
-	networksDisableCmd.Flags().StringVar(&common.NetworkID, "network", "", "id of the network")
+	networksDisableCmd.Flags().String("network", "", "id of the network")
 	// networksDisableCmd.MarkFlagRequired("network")
 }
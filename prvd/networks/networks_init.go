@@ -20,7 +20,6 @@ import (
 	"encoding/json"
 	"fmt"
 	"log"
-	"os"
 
 	"github.com/provideplatform/provide-cli/prvd/common"
 	provide "github.com/provideplatform/provide-go/api/nchain"
@@ -45,7 +44,8 @@ var networksInitCmd = &cobra.Command{
 // CreateNetwork configures a new peer-to-peer network;
 // see https://docs.provide.services/microservices/goldmine/#create-a-network
 func CreateNetwork(cmd *cobra.Command, args []string) {
-	token := common.RequireAPIToken()
+	ctx := common.ContextFromCommand(cmd)
+	token := common.RequireAPIToken(ctx)
 	params := map[string]interface{}{
 		"name":   networkName,
 		"config": configFactory(),
@@ -53,9 +53,9 @@ func CreateNetwork(cmd *cobra.Command, args []string) {
 	network, err := provide.CreateNetwork(token, params)
 	if err != nil {
 		log.Printf("Failed to initialize network; %s", err.Error())
-		os.Exit(1)
+		common.Exit(1)
 	}
-	common.NetworkID = network.ID.String()
+	ctx.NetworkID = network.ID.String()
 	result := fmt.Sprintf("%s\t%s\n", network.ID.String(), *network.Name)
 	fmt.Print(result)
 }
@@ -86,7 +86,7 @@ func configFactory() map[string]interface{} {
 		chainspec = cliqueChainspecFactory()
 	} else {
 		log.Printf("Failed to initialize network; additional chainspec factories should be implemented")
-		os.Exit(1)
+		common.Exit(1)
 	}
 
 	return map[string]interface{}{
@@ -19,7 +19,6 @@ package networks
 import (
 	"fmt"
 	"log"
-	"os"
 
 	"github.com/provideplatform/provide-cli/prvd/common"
 	provide "github.com/provideplatform/provide-go/api/nchain"
@@ -40,7 +39,8 @@ var networksListCmd = &cobra.Command{
 }
 
 func listNetworks(cmd *cobra.Command, args []string) {
-	token := common.RequireAPIToken()
+	ctx := common.ContextFromCommand(cmd)
+	token := common.RequireAPIToken(ctx)
 	params := map[string]interface{}{
 		"page": fmt.Sprintf("%d", page),
 		"rpp":  fmt.Sprintf("%d", rpp),
@@ -51,7 +51,7 @@ func listNetworks(cmd *cobra.Command, args []string) {
 	networks, err := provide.ListNetworks(token, params)
 	if err != nil {
 		log.Printf("Failed to retrieve networks list; %s", err.Error())
-		os.Exit(1)
+		common.Exit(1)
 	}
 	for i := range networks {
 		network := networks[i]
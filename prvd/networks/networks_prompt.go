@@ -33,6 +33,7 @@ var publicPromptLabel = "Would you like the network to be public"
 
 // General Endpoints
 func generalPrompt(cmd *cobra.Command, args []string, currentStep string) {
+	ctx := common.ContextFromCommand(cmd)
 	switch step := currentStep; step {
 	case promptStepInit:
 		// Validation non-null
@@ -60,7 +61,7 @@ func generalPrompt(cmd *cobra.Command, args []string, currentStep string) {
 		page, rpp = common.PromptPagination(paginate, page, rpp)
 		listNetworks(cmd, args)
 	case promptStepDisable:
-		common.RequireNetwork()
+		common.RequireNetwork(ctx)
 		disableNetwork(cmd, args)
 	case "":
 		result := common.SelectInput(emptyPromptArgs, emptyPromptLabel)
@@ -17,7 +17,6 @@
 package nodes
 
 import (
-	"github.com/provideplatform/provide-cli/prvd/common"
 	// provide "github.com/provideplatform/provide-go/api/nchain"
 
 	"github.com/spf13/cobra"
@@ -51,9 +50,9 @@ func deleteNodeRun(cmd *cobra.Command, args []string) {
 }
 
 func init() {
-	nodesDeleteCmd.Flags().StringVar(&common.NetworkID, "network", "", "network id")
+	nodesDeleteCmd.Flags().String("network", "", "network id")
 	nodesDeleteCmd.MarkFlagRequired("network")
 
-	nodesDeleteCmd.Flags().StringVar(&common.NodeID, "node", "", "id of the node")
+	nodesDeleteCmd.Flags().String("node", "", "id of the node")
 	nodesDeleteCmd.MarkFlagRequired("node")
 }
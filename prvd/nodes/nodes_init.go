@@ -18,7 +18,6 @@ package nodes
 
 import (
 	"log"
-	"os"
 	"strconv"
 	"strings"
 
@@ -55,7 +54,7 @@ func nodeSecurityConfigFactory() map[string]interface{} {
 			portInt, err := strconv.Atoi(port)
 			if err != nil {
 				log.Printf("Invalid tcp ingress port: %s", port)
-				os.Exit(1)
+				common.Exit(1)
 			}
 			tcpIngress = append(tcpIngress, uint(portInt))
 		}
@@ -66,7 +65,7 @@ func nodeSecurityConfigFactory() map[string]interface{} {
 			portInt, err := strconv.Atoi(port)
 			if err != nil {
 				log.Printf("Invalid udp ingress port: %s", port)
-				os.Exit(1)
+				common.Exit(1)
 			}
 			udpIngress = append(udpIngress, uint(portInt))
 		}
@@ -134,7 +133,7 @@ func CreateNodeRun(cmd *cobra.Command, args []string) {
 	// node, err := provide.CreateNetworkNode(token, common.NetworkID, params)
 	// if err != nil {
 	// 	log.Printf("Failed to initialize node; %s", err.Error())
-	// 	os.Exit(1)
+	// 	common.Exit(1)
 	// }
 	// common.NodeID = node.ID.String().(string)
 	// result := fmt.Sprintf("%s\t%s\n", node.ID.String(), *node.Name)
@@ -142,7 +141,7 @@ func CreateNodeRun(cmd *cobra.Command, args []string) {
 }
 
 func init() {
-	nodesInitCmd.Flags().StringVar(&common.NetworkID, "network", "", "target network id")
+	nodesInitCmd.Flags().String("network", "", "target network id")
 	//nodesInitCmd.MarkFlagRequired("network")
 
 	nodesInitCmd.Flags().BoolVar(&isP2P, "p2p", true, "when true, genesis state and peer resolution are enforced during initialization")
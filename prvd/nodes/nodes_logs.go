@@ -46,11 +46,11 @@ func nodeLogsRun(cmd *cobra.Command, args []string) {
 	// })
 	// if err != nil {
 	// 	log.Printf("Failed to retrieve node logs for node with id: %s; %s", common.NodeID, err.Error())
-	// 	os.Exit(1)
+	// 	common.Exit(1)
 	// }
 	// if status != 200 {
 	// 	log.Printf("Failed to retrieve node logs for node with id: %s; received status: %d", common.NodeID, status)
-	// 	os.Exit(1)
+	// 	common.Exit(1)
 	// }
 	// logsResponse := resp.(map[string]interface{})
 	// if logs, logsOk := logsResponse["logs"].([]interface{}); logsOk {
@@ -64,10 +64,10 @@ func nodeLogsRun(cmd *cobra.Command, args []string) {
 }
 
 func init() {
-	nodesLogsCmd.Flags().StringVar(&common.NetworkID, "network", "", "network id")
+	nodesLogsCmd.Flags().String("network", "", "network id")
 	nodesLogsCmd.MarkFlagRequired("network")
 
-	nodesLogsCmd.Flags().StringVar(&common.NodeID, "node", "", "id of the node")
+	nodesLogsCmd.Flags().String("node", "", "id of the node")
 	nodesLogsCmd.MarkFlagRequired("node")
 
 	nodesLogsCmd.Flags().Uint64Var(&page, "page", common.DefaultPage, "page number to retrieve")
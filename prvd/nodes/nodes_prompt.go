@@ -33,10 +33,11 @@ var emptyPromptLabel = "What would you like to do"
 
 // General Endpoints
 func generalPrompt(cmd *cobra.Command, args []string, currentStep string) {
+	ctx := common.ContextFromCommand(cmd)
 	switch step := currentStep; step {
 	case promptStepInit:
-		if common.NetworkID == "" {
-			common.RequireL1Network()
+		if ctx.NetworkID == "" {
+			common.RequireL1Network(ctx)
 		}
 		if common.Image == "" {
 			common.Image = common.FreeInput("Image", "", common.MandatoryValidation)
@@ -62,19 +63,19 @@ func generalPrompt(cmd *cobra.Command, args []string, currentStep string) {
 		}
 		CreateNodeRun(cmd, args)
 	case promptStepDelete:
-		if common.NetworkID == "" {
-			common.RequireL1Network()
+		if ctx.NetworkID == "" {
+			common.RequireL1Network(ctx)
 		}
-		if common.NodeID == "" {
-			common.NodeID = common.FreeInput("Node ID", "", common.MandatoryValidation)
+		if ctx.NodeID == "" {
+			ctx.NodeID = common.FreeInput("Node ID", "", common.MandatoryValidation)
 		}
 		deleteNodeRun(cmd, args)
 	case promptStepLogs:
-		if common.NetworkID == "" {
-			common.RequireL1Network()
+		if ctx.NetworkID == "" {
+			common.RequireL1Network(ctx)
 		}
-		if common.NodeID == "" {
-			common.NodeID = common.FreeInput("Node ID", "", common.MandatoryValidation)
+		if ctx.NodeID == "" {
+			ctx.NodeID = common.FreeInput("Node ID", "", common.MandatoryValidation)
 		}
 		// Validation Number
 		if page == 1 {
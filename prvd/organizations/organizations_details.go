@@ -20,7 +20,6 @@ import (
 	"encoding/json"
 	"fmt"
 	"log"
-	"os"
 
 	"github.com/provideplatform/provide-cli/prvd/common"
 	provide "github.com/provideplatform/provide-go/api/ident"
@@ -40,19 +39,20 @@ func fetchOrganizationDetails(cmd *cobra.Command, args []string) {
 }
 
 func fetchOrganizationDetailsRun(cmd *cobra.Command, args []string) {
-	if common.OrganizationID == "" {
+	ctx := common.ContextFromCommand(cmd)
+	if ctx.OrganizationID == "" {
 		generalPrompt(cmd, args, "Details")
 	}
-	token := common.RequireUserAccessToken()
+	token := common.RequireUserAccessToken(ctx)
 	params := map[string]interface{}{}
-	organization, err := provide.GetOrganizationDetails(token, common.OrganizationID, params)
+	organization, err := provide.GetOrganizationDetails(token, ctx.OrganizationID, params)
 	if err != nil {
-		log.Printf("Failed to retrieve details for organization with id: %s; %s", common.OrganizationID, err.Error())
-		os.Exit(1)
+		log.Printf("Failed to retrieve details for organization with id: %s; %s", ctx.OrganizationID, err.Error())
+		common.Exit(1)
 	}
 	// if status != 200 {
 	// 	log.Printf("Failed to retrieve details for organization with id: %s; %s", common.OrganizationID, organization)
-	// 	os.Exit(1)
+	// 	common.Exit(1)
 	// }
 
 	result, _ := json.MarshalIndent(organization, "", "\t")
@@ -60,6 +60,6 @@ func fetchOrganizationDetailsRun(cmd *cobra.Command, args []string) {
 }
 
 func init() {
-	organizationsDetailsCmd.Flags().StringVar(&common.OrganizationID, "organization", "", "id of the organization")
+	organizationsDetailsCmd.Flags().String("organization", "", "id of the organization")
 	// organizationsDetailsCmd.MarkFlagRequired("organization")
 }
@@ -19,7 +19,6 @@ package organizations
 import (
 	"fmt"
 	"log"
-	"os"
 
 	"github.com/provideplatform/provide-cli/prvd/common"
 	"github.com/provideplatform/provide-go/api/ident"
@@ -38,9 +37,9 @@ var organizationsInitCmd = &cobra.Command{
 	Run:   createOrganization,
 }
 
-func organizationConfigFactory() map[string]interface{} {
+func organizationConfigFactory(ctx *common.Context) map[string]interface{} {
 	cfg := map[string]interface{}{
-		"network_id": common.NetworkID,
+		"network_id": ctx.NetworkID,
 	}
 
 	return cfg
@@ -50,33 +49,34 @@ func createOrganization(cmd *cobra.Command, args []string) {
 }
 
 func createOrganizationRun(cmd *cobra.Command, args []string) {
-	token := common.RequireAPIToken()
+	ctx := common.ContextFromCommand(cmd)
+	token := common.RequireAPIToken(ctx)
 	params := map[string]interface{}{
 		"name":   organizationName,
-		"config": organizationConfigFactory(),
+		"config": organizationConfigFactory(ctx),
 	}
 	organization, err := ident.CreateOrganization(token, params)
 	if err != nil {
 		log.Printf("Failed to initialize organization; %s", err.Error())
-		os.Exit(1)
+		common.Exit(1)
 	}
 
-	common.OrganizationID = *organization.ID
+	ctx.OrganizationID = *organization.ID
 
-	orgToken, err := common.ResolveOrganizationToken()
+	orgToken, err := common.ResolveOrganizationToken(ctx)
 	if err != nil {
 		log.Printf("failed to initialize organization; %s", err.Error())
-		os.Exit(1)
+		common.Exit(1)
 	}
 
 	if _, err := vault.CreateVault(*orgToken.AccessToken, map[string]interface{}{
 		"name": fmt.Sprintf("%s vault", organizationName),
 	}); err != nil {
 		log.Printf("failed to create organization vault; %s", err.Error())
-		os.Exit(1)
+		common.Exit(1)
 	}
 
-	log.Printf("initialized organization: %s\t%s\n", organizationName, common.OrganizationID)
+	log.Printf("initialized organization: %s\t%s\n", organizationName, ctx.OrganizationID)
 }
 
 func init() {
@@ -19,7 +19,6 @@ package organizations
 import (
 	"fmt"
 	"log"
-	"os"
 
 	"github.com/provideplatform/provide-cli/prvd/common"
 	provide "github.com/provideplatform/provide-go/api/ident"
@@ -42,7 +41,8 @@ func listOrganizations(cmd *cobra.Command, args []string) {
 }
 
 func listOrganizationsRun(cmd *cobra.Command, args []string) {
-	token := common.RequireAPIToken()
+	ctx := common.ContextFromCommand(cmd)
+	token := common.RequireAPIToken(ctx)
 	params := map[string]interface{}{
 		"page": fmt.Sprintf("%d", page),
 		"rpp":  fmt.Sprintf("%d", rpp),
@@ -50,7 +50,7 @@ func listOrganizationsRun(cmd *cobra.Command, args []string) {
 	organizations, err := provide.ListOrganizations(token, params)
 	if err != nil {
 		log.Printf("Failed to retrieve organizations list; %s", err.Error())
-		os.Exit(1)
+		common.Exit(1)
 	}
 	for i := range organizations {
 		organization := organizations[i]
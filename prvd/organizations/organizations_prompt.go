@@ -30,6 +30,7 @@ var emptyPromptLabel = "What would you like to do"
 
 // General Endpoints
 func generalPrompt(cmd *cobra.Command, args []string, step string) {
+	ctx := common.ContextFromCommand(cmd)
 	switch step {
 	case promptStepInit:
 		organizationName = common.FreeInput("Organization Name", "", common.MandatoryValidation)
@@ -38,7 +39,7 @@ func generalPrompt(cmd *cobra.Command, args []string, step string) {
 		page, rpp = common.PromptPagination(paginate, page, rpp)
 		listOrganizationsRun(cmd, args)
 	case promptStepDetails:
-		common.RequireOrganization()
+		common.RequireOrganization(ctx)
 		fetchOrganizationDetailsRun(cmd, args)
 	case "":
 		result := common.SelectInput(emptyPromptArgs, emptyPromptLabel)
@@ -17,6 +17,7 @@
 package prvd
 
 import (
+	"context"
 	"fmt"
 	"os"
 
@@ -26,15 +27,23 @@ import (
 	"github.com/provideplatform/provide-cli/prvd/api_tokens"
 	"github.com/provideplatform/provide-cli/prvd/applications"
 	axiom "github.com/provideplatform/provide-cli/prvd/axiom"
+	"github.com/provideplatform/provide-cli/prvd/axiom/stack"
+	"github.com/provideplatform/provide-cli/prvd/axiom/workflows/worksteps"
+	"github.com/provideplatform/provide-cli/prvd/axiom/workgroups"
 	"github.com/provideplatform/provide-cli/prvd/common"
 	"github.com/provideplatform/provide-cli/prvd/connectors"
 	"github.com/provideplatform/provide-cli/prvd/contracts"
+	"github.com/provideplatform/provide-cli/prvd/doctor"
 	"github.com/provideplatform/provide-cli/prvd/networks"
 	"github.com/provideplatform/provide-cli/prvd/nodes"
 	"github.com/provideplatform/provide-cli/prvd/organizations"
 	"github.com/provideplatform/provide-cli/prvd/shell"
+	"github.com/provideplatform/provide-cli/prvd/transactions"
+	"github.com/provideplatform/provide-cli/prvd/use"
 	"github.com/provideplatform/provide-cli/prvd/users"
 	"github.com/provideplatform/provide-cli/prvd/vaults"
+	"github.com/provideplatform/provide-cli/prvd/vaults/keys"
+	"github.com/provideplatform/provide-cli/prvd/version"
 	"github.com/provideplatform/provide-cli/prvd/wallets"
 )
 
@@ -46,20 +55,34 @@ var rootCmd = &cobra.Command{
 The Provide CLI exposes low-code tools to manage network, application and organization resources.
 
 Run with the --help flag to see available options`, common.ASCIIBanner),
+	PersistentPreRun: common.InitContext,
 }
 
 // Execute the default command path
 func Execute() {
-	if err := rootCmd.Execute(); err != nil {
+	ctx := common.WithContext(context.Background(), common.NewContext())
+	if err := rootCmd.ExecuteContext(ctx); err != nil {
 		fmt.Println(err)
 		os.Exit(1)
 	}
 }
 
+// resetState restores the package-level state of each command tree which is not bound to a flag;
+// flag values are reset by the shell itself, so this only covers values assigned by prompts
+func resetState() {
+	api_tokens.ResetState()
+	contracts.ResetState()
+	keys.ResetState()
+	stack.ResetState()
+	users.ResetState()
+	workgroups.ResetState()
+	worksteps.ResetState()
+}
+
 func init() {
 	cobra.OnInitialize(common.InitConfig)
 
-	rootCmd.PersistentFlags().BoolVarP(&common.Verbose, "verbose", "v", false, "enable verbose output")
+	rootCmd.PersistentFlags().BoolP("verbose", "v", false, "enable verbose output")
 	rootCmd.PersistentFlags().StringVarP(&common.CfgFile, "config", "c", "", "config file (default is $HOME/.provide-cli.yaml)")
 
 	rootCmd.AddCommand(accounts.AccountsCmd)
@@ -69,13 +92,18 @@ func init() {
 	rootCmd.AddCommand(axiom.BaselineCmd)
 	rootCmd.AddCommand(connectors.ConnectorsCmd)
 	rootCmd.AddCommand(contracts.ContractsCmd)
+	rootCmd.AddCommand(doctor.DoctorCmd)
 	rootCmd.AddCommand(networks.NetworksCmd)
 	rootCmd.AddCommand(nodes.NodesCmd)
 	rootCmd.AddCommand(organizations.OrganizationsCmd)
 	rootCmd.AddCommand(shell.ShellCmd)
+	rootCmd.AddCommand(transactions.TransactionsCmd)
+	rootCmd.AddCommand(use.UseCmd)
 	rootCmd.AddCommand(users.UsersCmd)
 	rootCmd.AddCommand(vaults.VaultsCmd)
+	rootCmd.AddCommand(version.VersionCmd)
 	rootCmd.AddCommand(wallets.WalletsCmd)
 
 	common.CacheCommands(rootCmd)
+	shell.ResetState = resetState
 }
@@ -0,0 +1,493 @@
+/*
+ * Copyright 2017-2022 Provide Technologies Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package shell
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/c-bata/go-prompt"
+	"github.com/provideplatform/provide-cli/prvd/common"
+	"github.com/provideplatform/provide-go/api/axiom"
+	"github.com/provideplatform/provide-go/api/ident"
+	"github.com/provideplatform/provide-go/api/nchain"
+	"github.com/provideplatform/provide-go/api/vault"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+	"github.com/spf13/viper"
+)
+
+// completionCacheTTL is the duration for which resource suggestions fetched from the API are reused
+const completionCacheTTL = time.Second * 30
+
+// completionFetchTimeout bounds the time completion waits for resource suggestions to be fetched;
+// slower fetches complete in the background and their suggestions are offered once cached
+const completionFetchTimeout = time.Millisecond * 250
+
+// completionRpp is the maximum number of resources fetched for suggestion
+const completionRpp = 25
+
+// completionScope holds the flag values given so far for the command being completed
+type completionScope map[string]string
+
+// completionRequest holds a copy of the scope and the cached tokens used to fetch resource
+// suggestions; it is resolved on the prompt goroutine since viper is not safe for concurrent use
+type completionRequest struct {
+	scope             completionScope
+	userToken         string
+	organizationToken string
+	apiToken          string
+}
+
+// resourceCompletionFunc fetches suggestions for an id flag from the API
+type resourceCompletionFunc func(req *completionRequest) ([]prompt.Suggest, error)
+
+type cachedSuggestions struct {
+	expiresAt   time.Time
+	suggestions []prompt.Suggest
+}
+
+var completionCache = map[string]*cachedSuggestions{}
+var completionCacheMutex = &sync.Mutex{}
+
+// completionFetches are the resource suggestions being fetched, by cache key; each channel is
+// closed once its fetch completes
+var completionFetches = map[string]chan struct{}{}
+
+// nativeCommandSuggestions are the commands handled by the shell itself
+var nativeCommandSuggestions = []prompt.Suggest{
+	{Text: nativeCommandCd, Description: "Change the current path, i.e., cd /organizations/acme/workgroups/supply-chain"},
+	{Text: sanitizedPromptInputMatchClear, Description: "Clear the screen"},
+	{Text: nativeCommandDashboard, Description: "Display a live dashboard for the local axiom stack"},
+	{Text: nativeCommandEnv, Description: "Display the active organization and workgroup, API services and token expiry"},
+	{Text: sanitizedPromptInputMatchExit, Description: "Exit the shell"},
+	{Text: nativeCommandHistory, Description: "List the shell history"},
+	{Text: nativeCommandLogs, Description: "Print the stack logs, optionally for a single service; use -f to follow"},
+	{Text: nativeCommandLs, Description: "List the resources at the current path or the given path"},
+	{Text: nativeCommandPs, Description: "List the stack containers with their state, health and ports"},
+	{Text: nativeCommandPwd, Description: "Print the current path"},
+	{Text: nativeCommandSet, Description: "Assign a variable, i.e., set NAME=value, or stop scripts on error using set -e"},
+	{Text: nativeCommandSource, Description: "Execute the commands in the given script"},
+	{Text: nativeCommandTop, Description: "Display a live stream of container resource usage"},
+}
+
+// resourceCompletions fetch suggestions for the id flags shared across the command tree
+var resourceCompletions = map[string]resourceCompletionFunc{
+	"account": func(req *completionRequest) ([]prompt.Suggest, error) {
+		accounts, err := nchain.ListAccounts(req.apiToken, completionParams(req.scope, "application_id", "application"))
+		if err != nil {
+			return nil, err
+		}
+
+		suggestions := make([]prompt.Suggest, 0)
+		for _, acct := range accounts {
+			suggestions = append(suggestions, prompt.Suggest{Text: acct.ID.String(), Description: acct.Address})
+		}
+		return suggestions, nil
+	},
+	"application": func(req *completionRequest) ([]prompt.Suggest, error) {
+		apps, err := ident.ListApplications(req.userToken, completionParams(req.scope))
+		if err != nil {
+			return nil, err
+		}
+
+		suggestions := make([]prompt.Suggest, 0)
+		for _, app := range apps {
+			suggestions = append(suggestions, prompt.Suggest{Text: app.ID.String(), Description: stringOrEmpty(app.Name)})
+		}
+		return suggestions, nil
+	},
+	"connector": func(req *completionRequest) ([]prompt.Suggest, error) {
+		connectors, err := nchain.ListConnectors(req.apiToken, completionParams(req.scope, "application_id", "application"))
+		if err != nil {
+			return nil, err
+		}
+
+		suggestions := make([]prompt.Suggest, 0)
+		for _, connector := range connectors {
+			suggestions = append(suggestions, prompt.Suggest{Text: connector.ID.String(), Description: stringOrEmpty(connector.Name)})
+		}
+		return suggestions, nil
+	},
+	"contract": func(req *completionRequest) ([]prompt.Suggest, error) {
+		contracts, err := nchain.ListContracts(req.apiToken, completionParams(req.scope, "application_id", "application"))
+		if err != nil {
+			return nil, err
+		}
+
+		suggestions := make([]prompt.Suggest, 0)
+		for _, contract := range contracts {
+			suggestions = append(suggestions, prompt.Suggest{Text: contract.ID.String(), Description: stringOrEmpty(contract.Name)})
+		}
+		return suggestions, nil
+	},
+	"network": func(req *completionRequest) ([]prompt.Suggest, error) {
+		networks, err := nchain.ListNetworks(req.userToken, completionParams(req.scope))
+		if err != nil {
+			return nil, err
+		}
+
+		suggestions := make([]prompt.Suggest, 0)
+		for _, network := range networks {
+			suggestions = append(suggestions, prompt.Suggest{Text: network.ID.String(), Description: stringOrEmpty(network.Name)})
+		}
+		return suggestions, nil
+	},
+	"organization": func(req *completionRequest) ([]prompt.Suggest, error) {
+		orgs, err := ident.ListOrganizations(req.userToken, completionParams(req.scope))
+		if err != nil {
+			return nil, err
+		}
+
+		suggestions := make([]prompt.Suggest, 0)
+		for _, org := range orgs {
+			suggestions = append(suggestions, prompt.Suggest{Text: stringOrEmpty(org.ID), Description: stringOrEmpty(org.Name)})
+		}
+		return suggestions, nil
+	},
+	"vault": func(req *completionRequest) ([]prompt.Suggest, error) {
+		vaults, err := vault.ListVaults(req.apiToken, completionParams(req.scope))
+		if err != nil {
+			return nil, err
+		}
+
+		suggestions := make([]prompt.Suggest, 0)
+		for _, vlt := range vaults {
+			suggestions = append(suggestions, prompt.Suggest{Text: vlt.ID.String(), Description: stringOrEmpty(vlt.Name)})
+		}
+		return suggestions, nil
+	},
+	"wallet": func(req *completionRequest) ([]prompt.Suggest, error) {
+		wallets, err := nchain.ListWallets(req.apiToken, completionParams(req.scope, "application_id", "application"))
+		if err != nil {
+			return nil, err
+		}
+
+		suggestions := make([]prompt.Suggest, 0)
+		for _, wallet := range wallets {
+			suggestions = append(suggestions, prompt.Suggest{Text: wallet.ID.String(), Description: stringOrEmpty(wallet.PublicKey)})
+		}
+		return suggestions, nil
+	},
+	"workflow": func(req *completionRequest) ([]prompt.Suggest, error) {
+		workflows, err := axiom.ListWorkflows(req.organizationToken, completionParams(req.scope, "workgroup_id", "workgroup"))
+		if err != nil {
+			return nil, err
+		}
+
+		suggestions := make([]prompt.Suggest, 0)
+		for _, workflow := range workflows {
+			suggestions = append(suggestions, prompt.Suggest{Text: workflow.ID.String(), Description: stringOrEmpty(workflow.Name)})
+		}
+		return suggestions, nil
+	},
+	"workgroup": func(req *completionRequest) ([]prompt.Suggest, error) {
+		workgroups, err := axiom.ListWorkgroups(req.organizationToken, completionParams(req.scope))
+		if err != nil {
+			return nil, err
+		}
+
+		suggestions := make([]prompt.Suggest, 0)
+		for _, wg := range workgroups {
+			suggestions = append(suggestions, prompt.Suggest{Text: wg.ID.String(), Description: stringOrEmpty(wg.Name)})
+		}
+		return suggestions, nil
+	},
+}
+
+// completionSuggestions walks the command tree of the given root command for the text before
+// the cursor and suggests the subcommands, flags or flag values which may follow it
+func completionSuggestions(root, shellCmd *cobra.Command, d prompt.Document) []prompt.Suggest {
+	text := d.TextBeforeCursor()
+	if strings.TrimSpace(text) == "" {
+		return nil
+	}
+
+	args := strings.Fields(text)
+	word := ""
+	if !strings.HasSuffix(text, " ") {
+		word = args[len(args)-1]
+		args = args[:len(args)-1]
+	}
+
+	cmd, scope, pending := walkCommandTree(root, args)
+	if pending != nil {
+		return prompt.FilterHasPrefix(flagValueSuggestions(pending, scope, ""), word, true)
+	}
+
+	if strings.HasPrefix(word, "-") {
+		if i := strings.Index(word, "="); i != -1 {
+			flag := lookupFlag(cmd, word[:i])
+			if flag == nil {
+				return nil
+			}
+			return prompt.FilterHasPrefix(flagValueSuggestions(flag, scope, word[:i+1]), word, true)
+		}
+		return prompt.FilterHasPrefix(flagSuggestions(cmd, scope), word, true)
+	}
+
+	suggestions := make([]prompt.Suggest, 0)
+	for _, child := range cmd.Commands() {
+		if child == shellCmd || !child.IsAvailableCommand() {
+			continue
+		}
+		suggestions = append(suggestions, prompt.Suggest{Text: child.Name(), Description: child.Short})
+	}
+	if cmd == root {
+		suggestions = append(suggestions, nativeCommandSuggestions...)
+	}
+
+	return prompt.FilterHasPrefix(suggestions, word, true)
+}
+
+// walkCommandTree resolves the command addressed by the given args; the values of the flags
+// given so far are returned, along with the flag awaiting a value, if any
+func walkCommandTree(root *cobra.Command, args []string) (*cobra.Command, completionScope, *pflag.Flag) {
+	cmd := root
+	scope := completionScope{}
+
+	for i := 0; i < len(args); i++ {
+		arg := args[i]
+		if strings.HasPrefix(arg, "-") {
+			name := arg
+			value := ""
+			hasValue := false
+			if j := strings.Index(arg, "="); j != -1 {
+				name = arg[:j]
+				value = arg[j+1:]
+				hasValue = true
+			}
+
+			flag := lookupFlag(cmd, name)
+			if flag == nil {
+				continue
+			}
+
+			if !hasValue && flag.NoOptDefVal == "" {
+				if i == len(args)-1 {
+					return cmd, scope, flag
+				}
+				i++
+				value = args[i]
+			}
+
+			scope[flag.Name] = value
+			continue
+		}
+
+		for _, child := range cmd.Commands() {
+			if child.Name() == arg || child.HasAlias(arg) {
+				cmd = child
+				break
+			}
+		}
+	}
+
+	return cmd, scope, nil
+}
+
+// lookupFlag resolves the flag with the given name or shorthand, i.e., --network or -n
+func lookupFlag(cmd *cobra.Command, name string) *pflag.Flag {
+	flags := []*pflag.FlagSet{cmd.Flags(), cmd.InheritedFlags()}
+	for _, flagset := range flags {
+		if strings.HasPrefix(name, "--") {
+			if flag := flagset.Lookup(strings.TrimPrefix(name, "--")); flag != nil {
+				return flag
+			}
+		} else if len(name) == 2 {
+			if flag := flagset.ShorthandLookup(name[1:]); flag != nil {
+				return flag
+			}
+		}
+	}
+	return nil
+}
+
+// flagSuggestions suggests the flags of the given command which have not yet been given
+func flagSuggestions(cmd *cobra.Command, scope completionScope) []prompt.Suggest {
+	suggestions := make([]prompt.Suggest, 0)
+	visit := func(flag *pflag.Flag) {
+		if _, given := scope[flag.Name]; given || flag.Hidden {
+			return
+		}
+		suggestions = append(suggestions, prompt.Suggest{Text: fmt.Sprintf("--%s", flag.Name), Description: flag.Usage})
+	}
+
+	cmd.Flags().VisitAll(visit)
+	cmd.InheritedFlags().VisitAll(func(flag *pflag.Flag) {
+		if cmd.Flags().Lookup(flag.Name) == nil {
+			visit(flag)
+		}
+	})
+	return suggestions
+}
+
+// flagValueSuggestions suggests the enumerated values of the given flag or, for id flags,
+// the matching resources fetched from the API; each suggestion is prefixed with the given prefix
+func flagValueSuggestions(flag *pflag.Flag, scope completionScope, prefix string) []prompt.Suggest {
+	var suggestions []prompt.Suggest
+
+	if values := common.FlagValues(flag); len(values) > 0 {
+		suggestions = make([]prompt.Suggest, 0)
+		for _, value := range values {
+			suggestions = append(suggestions, prompt.Suggest{Text: value})
+		}
+	} else if fn, fnOk := resourceCompletions[flag.Name]; fnOk {
+		suggestions = cachedResourceSuggestions(flag.Name, scope, fn)
+	}
+
+	if prefix == "" {
+		return suggestions
+	}
+
+	prefixed := make([]prompt.Suggest, 0)
+	for _, suggestion := range suggestions {
+		prefixed = append(prefixed, prompt.Suggest{Text: prefix + suggestion.Text, Description: suggestion.Description})
+	}
+	return prefixed
+}
+
+// cachedResourceSuggestions returns the resource suggestions for the given flag and scope,
+// fetching them using the given func when they are not cached; since suggestions are fetched
+// as the user types, no suggestions are returned when the fetch fails or outlasts the timeout
+func cachedResourceSuggestions(name string, scope completionScope, fn resourceCompletionFunc) []prompt.Suggest {
+	key := fmt.Sprintf("%s:%s:%s:%s", name, scope.organizationID(), scope.workgroupID(), scope["application"])
+
+	completionCacheMutex.Lock()
+	if cached, cachedOk := completionCache[key]; cachedOk && time.Now().Before(cached.expiresAt) {
+		completionCacheMutex.Unlock()
+		return cached.suggestions
+	}
+
+	done, fetching := completionFetches[key]
+	if !fetching {
+		done = make(chan struct{})
+		completionFetches[key] = done
+		go fetchResourceSuggestions(key, scope.request(), fn, done)
+	}
+	completionCacheMutex.Unlock()
+
+	select {
+	case <-done:
+	case <-time.After(completionFetchTimeout):
+		return nil
+	}
+
+	completionCacheMutex.Lock()
+	defer completionCacheMutex.Unlock()
+	if cached, cachedOk := completionCache[key]; cachedOk {
+		return cached.suggestions
+	}
+	return nil
+}
+
+// fetchResourceSuggestions fetches the resource suggestions for the given cache key; failures
+// are not cached, so the fetch is retried as the user continues typing
+func fetchResourceSuggestions(key string, req *completionRequest, fn resourceCompletionFunc, done chan struct{}) {
+	suggestions, err := fn(req)
+
+	completionCacheMutex.Lock()
+	defer completionCacheMutex.Unlock()
+
+	if err == nil {
+		completionCache[key] = &cachedSuggestions{
+			expiresAt:   time.Now().Add(completionCacheTTL),
+			suggestions: suggestions,
+		}
+	}
+	delete(completionFetches, key)
+	close(done)
+}
+
+// completionParams returns the list params for a completion request, including the
+// given param/flag pairs for which a flag value was given
+func completionParams(scope completionScope, pairs ...string) map[string]interface{} {
+	params := map[string]interface{}{
+		"page": 1,
+		"rpp":  completionRpp,
+	}
+	for i := 0; i+1 < len(pairs); i += 2 {
+		if value := scope[pairs[i+1]]; value != "" {
+			params[pairs[i]] = value
+		}
+	}
+	return params
+}
+
+// request returns the request used to fetch resource suggestions in the background
+func (s completionScope) request() *completionRequest {
+	scope := completionScope{}
+	for name, value := range s {
+		scope[name] = value
+	}
+
+	return &completionRequest{
+		scope:             scope,
+		userToken:         s.userToken(),
+		organizationToken: s.organizationToken(),
+		apiToken:          s.apiToken(),
+	}
+}
+
+// organizationID returns the --organization flag value or the active organization
+func (s completionScope) organizationID() string {
+	if id := s["organization"]; id != "" {
+		return id
+	}
+	id, _ := common.ActiveOrganization()
+	return id
+}
+
+// workgroupID returns the --workgroup flag value or the active workgroup
+func (s completionScope) workgroupID() string {
+	if id := s["workgroup"]; id != "" {
+		return id
+	}
+	id, _ := common.ActiveWorkgroup()
+	return id
+}
+
+// userToken returns the cached user access token; no token is ever requested or refreshed
+// on behalf of the user while completing input
+func (s completionScope) userToken() string {
+	return viper.GetString(common.AccessTokenConfigKey)
+}
+
+// organizationToken returns the cached access token for the organization in scope
+func (s completionScope) organizationToken() string {
+	return viper.GetString(common.BuildConfigKeyWithID(common.AccessTokenConfigKey, s.organizationID()))
+}
+
+// apiToken returns the cached access token for the application or organization in scope,
+// falling back to the user access token
+func (s completionScope) apiToken() string {
+	for _, id := range []string{s["application"], s["organization"]} {
+		if token := viper.GetString(common.BuildConfigKeyWithID(common.AccessTokenConfigKey, id)); token != "" {
+			return token
+		}
+	}
+	return s.userToken()
+}
+
+func stringOrEmpty(str *string) string {
+	if str == nil {
+		return ""
+	}
+	return *str
+}
@@ -0,0 +1,119 @@
+/*
+ * Copyright 2017-2022 Provide Technologies Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package shell
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+
+	"github.com/provideplatform/provide-cli/prvd/common"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+)
+
+// ResetState restores the package-level state of the command tree which is not bound to a flag,
+// i.e., values assigned by prompts, prior to each in-process invocation; the shell does not import
+// the command packages, so this is installed by the root command
+var ResetState = func() {}
+
+var installExitRecovery sync.Once
+
+// stdout is the standard output of the shell, which remains in place while command output is captured
+var stdout = os.Stdout
+
+// promptWriter writes the output of in-process commands using the prompt writer
+type promptWriter struct{}
+
+func (w *promptWriter) Write(buf []byte) (int, error) {
+	if writer == nil {
+		return stdout.Write(buf)
+	}
+
+	mutex.Lock()
+	defer mutex.Unlock()
+
+	writer.WriteRaw(buf)
+	writer.Flush()
+	return len(buf), nil
+}
+
+// execute runs the given argv against the command tree of the given root command in-process;
+// each invocation receives a fresh context and flags are reset to their defaults beforehand,
+// so the only state shared between invocations is the configuration (i.e., cached tokens and
+// the active organization and workgroup)
+func execute(root *cobra.Command, argv []string) error {
+	return guard(func() error {
+		resetFlags(root)
+		ResetState()
+
+		out := &promptWriter{}
+		root.SetArgs(argv)
+		root.SetIn(os.Stdin)
+		root.SetOut(out)
+		root.SetErr(out)
+		return root.ExecuteContext(common.WithContext(context.Background(), common.NewContext()))
+	})
+}
+
+// guard runs the given func such that exiting returns an error rather than terminating the shell;
+// common.Exit panics with a common.ExitStatus while the shell is running, which is recovered here
+func guard(fn func() error) (err error) {
+	installExitRecovery.Do(func() {
+		common.SetExitRecoverable(true)
+	})
+
+	defer func() {
+		if r := recover(); r != nil {
+			if code, codeOk := r.(common.ExitStatus); codeOk {
+				if code != 0 {
+					err = fmt.Errorf("exit status %d", code)
+				}
+				return
+			}
+			err = fmt.Errorf("recovered from panic; %v", r)
+		}
+	}()
+
+	return fn()
+}
+
+// resetFlags restores the default value of each flag; values are restored whether or not the
+// flag was given, as prompts assign the variables bound to flags of a previous invocation
+func resetFlags(cmd *cobra.Command) {
+	reset := func(flag *pflag.Flag) {
+		if slice, sliceOk := flag.Value.(pflag.SliceValue); sliceOk {
+			defaults := make([]string, 0)
+			if val := strings.Trim(flag.DefValue, "[]"); val != "" {
+				defaults = strings.Split(val, ",")
+			}
+			slice.Replace(defaults)
+		} else {
+			flag.Value.Set(flag.DefValue)
+		}
+		flag.Changed = false
+	}
+
+	cmd.Flags().VisitAll(reset)
+	cmd.PersistentFlags().VisitAll(reset)
+
+	for _, child := range cmd.Commands() {
+		resetFlags(child)
+	}
+}
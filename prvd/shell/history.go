@@ -0,0 +1,289 @@
+/*
+ * Copyright 2017-2022 Provide Technologies Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package shell
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/c-bata/go-prompt"
+	homedir "github.com/mitchellh/go-homedir"
+	"github.com/spf13/viper"
+)
+
+const historyFilename = ".prvd_history"
+const historyMaxEntries = 1000
+const historyRedacted = "[REDACTED]"
+
+const nativeCommandHistory = "history"
+
+// historyRedactedFlags are the flags whose values are never persisted to the history file
+var historyRedactedFlags = []string{"--access-token", "--client-secret", "--password"}
+
+// History is the persistent shell history
+type History struct {
+	entries []string
+	path    string
+
+	searching    bool
+	searchQuery  string
+	searchOffset int
+}
+
+// LoadHistory reads the shell history from the config directory
+func LoadHistory() (*History, error) {
+	path, err := historyPath()
+	if err != nil {
+		return nil, err
+	}
+
+	h := &History{
+		entries: make([]string, 0),
+		path:    path,
+	}
+
+	f, err := os.Open(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return h, nil
+		}
+		return nil, err
+	}
+	defer f.Close()
+
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		if entry := strings.TrimSpace(scanner.Text()); entry != "" {
+			h.entries = append(h.entries, entry)
+		}
+	}
+
+	// the file is rewritten once the trimmed history is next added to
+	if len(h.entries) > historyMaxEntries {
+		h.entries = h.entries[len(h.entries)-historyMaxEntries:]
+	}
+
+	return h, scanner.Err()
+}
+
+// historyPath returns the path of the history file, which lives alongside the prvd configuration
+func historyPath() (string, error) {
+	if cfg := viper.ConfigFileUsed(); cfg != "" {
+		return filepath.Join(filepath.Dir(cfg), historyFilename), nil
+	}
+
+	home, err := homedir.Dir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(home, historyFilename), nil
+}
+
+// Entries returns a copy of the history entries, oldest first
+func (h *History) Entries() []string {
+	entries := make([]string, len(h.entries))
+	copy(entries, h.entries)
+	return entries
+}
+
+// Add appends the given input to the history and its redacted form to the history file; the
+// unredacted input is only kept in memory so it can be recalled during this session. The file
+// is rewritten when the history is trimmed so entry numbers remain stable between sessions
+func (h *History) Add(input string) error {
+	entry := strings.TrimSpace(input)
+	if entry == "" {
+		return nil
+	}
+
+	h.entries = append(h.entries, entry)
+	if len(h.entries) > historyMaxEntries {
+		h.entries = h.entries[len(h.entries)-historyMaxEntries:]
+		return h.save()
+	}
+
+	if h.path == "" {
+		return nil
+	}
+
+	f, err := os.OpenFile(h.path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0600)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	_, err = f.WriteString(fmt.Sprintf("%s\n", redactHistoryEntry(entry)))
+	return err
+}
+
+// save rewrites the history file with the redacted entries
+func (h *History) save() error {
+	if h.path == "" {
+		return nil
+	}
+
+	var buf strings.Builder
+	for _, entry := range h.entries {
+		buf.WriteString(fmt.Sprintf("%s\n", redactHistoryEntry(entry)))
+	}
+
+	tmp := fmt.Sprintf("%s.tmp", h.path)
+	if err := ioutil.WriteFile(tmp, []byte(buf.String()), 0600); err != nil {
+		return err
+	}
+	return os.Rename(tmp, h.path)
+}
+
+// Expand resolves `!n` (the nth entry) and `!!` (the previous entry) references;
+// other input is returned unmodified
+func (h *History) Expand(input string) (string, error) {
+	if !strings.HasPrefix(input, "!") || len(input) == 1 {
+		return input, nil
+	}
+
+	if input == "!!" {
+		if len(h.entries) == 0 {
+			return "", errors.New("event not found: !!")
+		}
+		return h.entries[len(h.entries)-1], nil
+	}
+
+	n, err := strconv.Atoi(input[1:])
+	if err != nil || n < 1 || n > len(h.entries) {
+		return "", fmt.Errorf("event not found: %s", input)
+	}
+	return h.entries[n-1], nil
+}
+
+// Print writes the numbered history entries
+func (h *History) Print() {
+	for i, entry := range h.entries {
+		writer.WriteRaw([]byte(fmt.Sprintf("%5d  %s\n", i+1, entry)))
+	}
+	writer.Flush()
+}
+
+// StartSearch enters reverse incremental search mode or, when already searching,
+// advances to the next older match
+func (h *History) StartSearch() {
+	if h.searching {
+		if h.searchOffset < len(h.matches(h.searchQuery))-1 {
+			h.searchOffset++
+		}
+		return
+	}
+
+	h.searching = true
+	h.searchOffset = 0
+}
+
+// StopSearch leaves reverse incremental search mode
+func (h *History) StopSearch() {
+	h.searching = false
+	h.searchQuery = ""
+	h.searchOffset = 0
+}
+
+// Searching returns true when in reverse incremental search mode
+func (h *History) Searching() bool {
+	return h.searching
+}
+
+// SetSearchQuery updates the query as it is typed; the search restarts from the newest entry
+func (h *History) SetSearchQuery(query string) {
+	if query != h.searchQuery {
+		h.searchQuery = query
+		h.searchOffset = 0
+	}
+}
+
+// SearchMatch returns the current match for the search query, if any
+func (h *History) SearchMatch() string {
+	matches := h.matches(h.searchQuery)
+	if h.searchOffset < len(matches) {
+		return matches[h.searchOffset]
+	}
+	return ""
+}
+
+// SearchPrefix renders the prompt prefix while searching
+func (h *History) SearchPrefix() string {
+	return fmt.Sprintf("(reverse-i-search: %s)`", h.SearchMatch())
+}
+
+// SearchSuggestions returns the entries matching the search query as prompt suggestions
+func (h *History) SearchSuggestions() []prompt.Suggest {
+	suggestions := make([]prompt.Suggest, 0)
+	for _, match := range h.matches(h.searchQuery) {
+		suggestions = append(suggestions, prompt.Suggest{Text: match})
+	}
+	return suggestions
+}
+
+// matches returns the unique entries containing the given query, newest first
+func (h *History) matches(query string) []string {
+	matches := make([]string, 0)
+	seen := map[string]bool{}
+	for i := len(h.entries) - 1; i >= 0; i-- {
+		entry := h.entries[i]
+		if !seen[entry] && strings.Contains(entry, query) {
+			matches = append(matches, entry)
+			seen[entry] = true
+		}
+	}
+	return matches
+}
+
+// redactHistoryEntry replaces the values of sensitive flags in the given input; arguments are
+// split as the shell splits them, so quoted values containing whitespace are redacted entirely
+func redactHistoryEntry(input string) string {
+	type redaction struct {
+		span        argSpan
+		replacement string
+	}
+
+	argv, spans, _ := scanArgs(input, false)
+	redactions := make([]redaction, 0)
+	for i := 0; i < len(argv); i++ {
+		for _, flag := range historyRedactedFlags {
+			if argv[i] == flag && i+1 < len(argv) {
+				redactions = append(redactions, redaction{spans[i+1], historyRedacted})
+				i++
+				break
+			} else if strings.HasPrefix(argv[i], fmt.Sprintf("%s=", flag)) {
+				redactions = append(redactions, redaction{spans[i], fmt.Sprintf("%s=%s", flag, historyRedacted)})
+				break
+			}
+		}
+	}
+
+	runes := []rune(input)
+	var entry strings.Builder
+	offset := 0
+	for _, r := range redactions {
+		entry.WriteString(string(runes[offset:r.span.start]))
+		entry.WriteString(r.replacement)
+		offset = r.span.end
+	}
+	entry.WriteString(string(runes[offset:]))
+	return entry.String()
+}
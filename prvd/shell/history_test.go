@@ -0,0 +1,129 @@
+/*
+ * Copyright 2017-2022 Provide Technologies Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package shell
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestRedactHistoryEntry(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		want  string
+	}{
+		{name: "no secrets", input: "organizations list --rpp 10", want: "organizations list --rpp 10"},
+		{name: "flag value", input: "authenticate --password secret", want: "authenticate --password [REDACTED]"},
+		{name: "flag assignment", input: "api_tokens init --access-token=tok", want: "api_tokens init --access-token=[REDACTED]"},
+		{name: "double quoted value", input: `authenticate --password "my secret" --email a@b.c`, want: "authenticate --password [REDACTED] --email a@b.c"},
+		{name: "single quoted assignment", input: `x --client-secret='a b' y`, want: "x --client-secret=[REDACTED] y"},
+		{name: "quoted flag assignment", input: `x "--password=a b"`, want: "x --password=[REDACTED]"},
+		{name: "escaped space", input: `x --password a\ b c`, want: "x --password [REDACTED] c"},
+		{name: "unterminated quote", input: `x --password "a b`, want: "x --password [REDACTED]"},
+		{name: "multiple secrets", input: "x --password a --client-secret b", want: "x --password [REDACTED] --client-secret [REDACTED]"},
+		{name: "flag without value", input: "x --password", want: "x --password"},
+		{name: "whitespace is preserved", input: "x  --rpp\t10 --password a", want: "x  --rpp\t10 --password [REDACTED]"},
+		{name: "variables are not expanded", input: "x --password $SECRET", want: "x --password [REDACTED]"},
+		{name: "similar flag", input: "x --password-file /tmp/pw", want: "x --password-file /tmp/pw"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := redactHistoryEntry(tt.input); got != tt.want {
+				t.Errorf("redactHistoryEntry(%q) = %q, want %q", tt.input, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestHistoryExpand(t *testing.T) {
+	h := &History{entries: []string{"organizations list", "authenticate --password secret", "wallets list"}}
+
+	tests := []struct {
+		name    string
+		input   string
+		want    string
+		wantErr bool
+	}{
+		{name: "not a reference", input: "accounts list", want: "accounts list"},
+		{name: "bang", input: "!", want: "!"},
+		{name: "previous entry", input: "!!", want: "wallets list"},
+		{name: "first entry", input: "!1", want: "organizations list"},
+		{name: "unredacted entry", input: "!2", want: "authenticate --password secret"},
+		{name: "zero", input: "!0", wantErr: true},
+		{name: "out of range", input: "!4", wantErr: true},
+		{name: "not a number", input: "!abc", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := h.Expand(tt.input)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Expand(%q) error = %v, wantErr %v", tt.input, err, tt.wantErr)
+				return
+			}
+			if got != tt.want {
+				t.Errorf("Expand(%q) = %q, want %q", tt.input, got, tt.want)
+			}
+		})
+	}
+
+	if _, err := (&History{}).Expand("!!"); err == nil {
+		t.Errorf("Expand(\"!!\") of empty history should fail")
+	}
+}
+
+func TestHistoryAdd(t *testing.T) {
+	dir, err := ioutil.TempDir("", "prvd-history")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	h := &History{entries: make([]string, 0), path: filepath.Join(dir, historyFilename)}
+	for i := 1; i <= historyMaxEntries+2; i++ {
+		if err := h.Add(fmt.Sprintf(" authenticate --password secret%d ", i)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	h.Add("   ")
+
+	entries := h.Entries()
+	if len(entries) != historyMaxEntries {
+		t.Fatalf("history has %d entries, want %d", len(entries), historyMaxEntries)
+	}
+	if want := fmt.Sprintf("authenticate --password secret%d", historyMaxEntries+2); entries[len(entries)-1] != want {
+		t.Errorf("last entry = %q, want %q", entries[len(entries)-1], want)
+	}
+
+	raw, err := ioutil.ReadFile(h.path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	lines := strings.Split(strings.TrimSpace(string(raw)), "\n")
+	if len(lines) != historyMaxEntries {
+		t.Errorf("history file has %d lines, want %d", len(lines), historyMaxEntries)
+	}
+	for _, line := range lines {
+		if line != "authenticate --password [REDACTED]" {
+			t.Fatalf("history file line = %q, want redacted entry", line)
+		}
+	}
+}
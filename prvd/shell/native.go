@@ -24,8 +24,6 @@ import (
 	"github.com/manifoldco/promptui"
 )
 
-const nativeCommandTop = "top"
-
 type NoopCloser struct {
 	buf *bytes.Buffer
 }
@@ -46,44 +44,15 @@ func (nc *NoopCloser) Close() error {
 }
 
 var nativeCommands = map[string]interface{}{
-	nativeCommandTop: func(argv []string) (*REPL, error) {
-
-		buf := &bytes.Buffer{}
-		i := 0
-		var pending bool
-
-		saveCursor()
-		hideCursor()
-		eraseCursorToEnd()
-
-		repl, _ = NewREPL(func(wg *sync.WaitGroup) error {
-			if !pending {
-				pending = true
-				toggleAlternateBuffer()
-				go shellOut("docker", []string{"stats"}, buf)
-			}
-
-			if buf.Len() > 0 {
-				mutex.Lock()
-				defer mutex.Unlock()
-
-				writer.SaveCursor()
-				writer.CursorGoTo(shellHeaderRows+1, 0)
-				writer.WriteRaw([]byte("\033[0J"))
-				raw := stripEscapeSequences(string(buf.Bytes()[i:]))
-				writer.WriteRaw([]byte(raw))
-				writer.UnSaveCursor()
-				writer.Flush()
-
-				i += buf.Len() - i
-			}
-
-			return nil
-		})
-
-		go repl.run()
-		return repl, nil
-	},
+	nativeCommandCd:        cd,
+	nativeCommandDashboard: dashboard,
+	nativeCommandEnv:       env,
+	nativeCommandLogs:      logs,
+	nativeCommandLs:        ls,
+	nativeCommandPs:        ps,
+	nativeCommandPwd:       pwd,
+	nativeCommandUse:       use,
+	nativeCommandTop:       top,
 }
 
 // MarshalPromptIO marshals IO from promptui text or select prompt
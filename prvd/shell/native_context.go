@@ -0,0 +1,116 @@
+/*
+ * Copyright 2017-2022 Provide Technologies Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package shell
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/provideplatform/provide-cli/prvd/common"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+const nativeCommandEnv = "env"
+const nativeCommandUse = "use"
+
+// rootCmd is the root of the command tree against which the shell executes commands
+var rootCmd *cobra.Command
+
+// env prints the active organization and workgroup, the configured API services and the expiry
+// of the cached access tokens
+func env(argv []string) (*REPL, error) {
+	printf("config\t%s\n", viper.ConfigFileUsed())
+
+	orgID, orgName := common.ActiveOrganization()
+	wgID, wgName := common.ActiveWorkgroup()
+	printf("organization\t%s\n", describeSelection(orgID, orgName))
+	printf("workgroup\t%s\n", describeSelection(wgID, wgName))
+
+	for _, svc := range common.ConfiguredAPIServices() {
+		printf("api:%s\t%s://%s\n", svc.Name, svc.Scheme, svc.Host)
+	}
+
+	printf("token:user\t%s\n", describeTokenExpiry(viper.GetString(common.AccessTokenConfigKey)))
+	if orgID != "" {
+		token := viper.GetString(common.BuildConfigKeyWithID(common.AccessTokenConfigKey, orgID))
+		printf("token:organization\t%s\n", describeTokenExpiry(token))
+	}
+
+	return nil, nil
+}
+
+// use switches the active organization and workgroup without leaving the shell; in addition to
+// the forms accepted by `prvd use`, the organization and workgroup may be given together,
+// i.e., use acme/supply-chain
+func use(argv []string) (*REPL, error) {
+	args := argv[1:]
+	if len(args) != 1 || strings.HasPrefix(args[0], "-") || args[0] == "organization" || args[0] == "workgroup" {
+		return nil, execute(rootCmd, argv)
+	}
+
+	selection := strings.SplitN(args[0], "/", 2)
+	if err := execute(rootCmd, []string{nativeCommandUse, "organization", selection[0]}); err != nil {
+		return nil, err
+	}
+	if len(selection) == 2 && selection[1] != "" {
+		if err := execute(rootCmd, []string{nativeCommandUse, "workgroup", selection[1]}); err != nil {
+			return nil, err
+		}
+	}
+	return nil, nil
+}
+
+func describeSelection(id, name string) string {
+	if id == "" {
+		return "none"
+	}
+	if name == "" {
+		return id
+	}
+	return fmt.Sprintf("%s (%s)", name, id)
+}
+
+func describeTokenExpiry(token string) string {
+	if token == "" {
+		return "not authenticated"
+	}
+
+	exp, err := common.TokenExpiry(token)
+	if err != nil {
+		return fmt.Sprintf("invalid; %s", err.Error())
+	}
+	if exp == nil {
+		return "does not expire"
+	}
+
+	remaining := time.Until(*exp)
+	if remaining <= 0 {
+		return fmt.Sprintf("expired %s", exp.Format(time.RFC3339))
+	}
+	return fmt.Sprintf("expires %s (in %s)", exp.Format(time.RFC3339), formatDuration(remaining))
+}
+
+// formatDuration renders the given duration rounded to the minute, i.e., 1h5m
+func formatDuration(d time.Duration) string {
+	d = d.Round(time.Minute)
+	if d < time.Minute {
+		return "less than a minute"
+	}
+	return strings.TrimSuffix(d.String(), "0s")
+}
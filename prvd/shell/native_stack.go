@@ -0,0 +1,129 @@
+/*
+ * Copyright 2017-2022 Provide Technologies Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package shell
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"sync"
+
+	"github.com/docker/docker/client"
+	"github.com/provideplatform/provide-cli/prvd/axiom/stack"
+	"github.com/spf13/pflag"
+)
+
+const nativeCommandDashboard = "dashboard"
+const nativeCommandLogs = "logs"
+const nativeCommandPs = "ps"
+
+const defaultLogsTail = "100"
+
+// ps lists the containers of the stack along with their state, health and published ports
+func ps(argv []string) (*REPL, error) {
+	flags := nativeFlagSet(nativeCommandPs)
+	name := flags.String("name", stack.DefaultName, "name of the axiom stack instance")
+	if err := flags.Parse(argv[1:]); err != nil {
+		return nil, err
+	}
+
+	docker, err := client.NewEnvClient()
+	if err != nil {
+		return nil, err
+	}
+	defer docker.Close()
+
+	statuses, err := stack.ServiceStatuses(docker, *name)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list containers for stack: %s; %s", *name, err.Error())
+	}
+
+	for _, status := range statuses {
+		printf("%s\t%s\t%s\t%s\t%s\n", status.Service, status.State, status.Health, status.Uptime, status.Ports)
+	}
+
+	return nil, nil
+}
+
+// dashboard presents the stack dashboard; it is equivalent to prvd axiom stack dashboard
+func dashboard(argv []string) (*REPL, error) {
+	if writer == nil {
+		return nil, fmt.Errorf("%s: not supported when running a script", nativeCommandDashboard)
+	}
+	return nil, execute(rootCmd, append([]string{"axiom", "stack", nativeCommandDashboard}, argv[1:]...))
+}
+
+// logs prints the multiplexed logs of the stack, or of the given services, prefixed with the name
+// of the service; when following, logs are streamed into the shell until interrupted
+func logs(argv []string) (*REPL, error) {
+	flags := nativeFlagSet(nativeCommandLogs)
+	name := flags.String("name", stack.DefaultName, "name of the axiom stack instance")
+	follow := flags.BoolP("follow", "f", false, "when true, logs are streamed until interrupted")
+	tail := flags.String("tail", defaultLogsTail, "number of lines to show from the end of each log, or all")
+	if err := flags.Parse(argv[1:]); err != nil {
+		return nil, err
+	}
+	if *follow && writer == nil {
+		return nil, fmt.Errorf("%s: --follow is not supported when running a script", nativeCommandLogs)
+	}
+
+	docker, err := client.NewEnvClient()
+	if err != nil {
+		return nil, err
+	}
+
+	opts := &stack.LogsOptions{
+		Services: flags.Args(),
+		Follow:   *follow,
+		Tail:     *tail,
+		Stdout:   &promptWriter{},
+		Stderr:   &promptWriter{},
+	}
+
+	if !*follow {
+		defer docker.Close()
+		return nil, stack.PrintLogs(context.Background(), docker, *name, opts)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		if err := stack.PrintLogs(ctx, docker, *name, opts); err != nil {
+			printf("%s: %s\n", shellTitle, err.Error())
+		}
+	}()
+
+	repl, _ := NewREPL(func(wg *sync.WaitGroup) error {
+		return nil
+	})
+	go func() {
+		repl.run()
+		cancel()
+		<-done
+		docker.Close()
+	}()
+
+	return repl, nil
+}
+
+// nativeFlagSet returns the flagset used to parse the args of the given native command
+func nativeFlagSet(name string) *pflag.FlagSet {
+	flags := pflag.NewFlagSet(name, pflag.ContinueOnError)
+	flags.SetOutput(ioutil.Discard)
+	return flags
+}
@@ -0,0 +1,287 @@
+/*
+ * Copyright 2017-2022 Provide Technologies Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package shell
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/client"
+	"github.com/provideplatform/provide-cli/prvd/axiom/stack"
+	"github.com/provideplatform/provide-cli/prvd/common"
+)
+
+const nativeCommandTop = "top"
+
+const topDefaultInterval = time.Second * 2
+const topMinInterval = time.Millisecond * 500
+
+const topSortCPU = "cpu"
+const topSortMemory = "mem"
+const topSortNetwork = "net"
+const topSortBlockIO = "io"
+const topSortService = "name"
+
+// topSortKeys are the accepted values of the top --sort flag
+var topSortKeys = []string{topSortCPU, topSortMemory, topSortNetwork, topSortBlockIO, topSortService}
+
+// containerStats is the most recent resource usage sample of a stack container
+type containerStats struct {
+	service     string
+	cpuPercent  float64
+	memUsage    uint64
+	memLimit    uint64
+	memPercent  float64
+	netRx       uint64
+	netTx       uint64
+	blockRead   uint64
+	blockWrite  uint64
+	pids        uint64
+	unavailable error
+}
+
+// top renders a live view of the resource usage of the stack containers using the docker stats
+// API; the view is refreshed at the given interval and sorted by the given column until interrupted
+func top(argv []string) (*REPL, error) {
+	if writer == nil {
+		return nil, fmt.Errorf("%s: not supported when running a script", nativeCommandTop)
+	}
+
+	flags := nativeFlagSet(nativeCommandTop)
+	name := flags.String("name", stack.DefaultName, "name of the axiom stack instance")
+	sortKey := flags.StringP("sort", "s", topSortCPU, fmt.Sprintf("column by which containers are sorted; one of %s", strings.Join(topSortKeys, ", ")))
+	interval := flags.DurationP("interval", "n", topDefaultInterval, "refresh interval, i.e., 5s")
+	if err := flags.Parse(argv[1:]); err != nil {
+		return nil, err
+	}
+
+	validSortKey := false
+	for _, key := range topSortKeys {
+		validSortKey = validSortKey || key == *sortKey
+	}
+	if !validSortKey {
+		return nil, fmt.Errorf("invalid sort: %s; must be one of %s", *sortKey, strings.Join(topSortKeys, ", "))
+	}
+	if *interval < topMinInterval {
+		*interval = topMinInterval
+	}
+
+	docker, err := client.NewEnvClient()
+	if err != nil {
+		return nil, err
+	}
+
+	containers, err := stack.Containers(docker, *name)
+	if err != nil {
+		docker.Close()
+		return nil, err
+	}
+	if len(containers) == 0 {
+		docker.Close()
+		return nil, fmt.Errorf("no containers found for stack: %s", *name)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	samples := map[string]*containerStats{}
+	samplesMutex := &sync.Mutex{}
+
+	for _, container := range containers {
+		container := container
+		samples[container.ID] = &containerStats{service: container.Service}
+
+		go streamContainerStats(ctx, docker, container, func(stats *containerStats) {
+			samplesMutex.Lock()
+			defer samplesMutex.Unlock()
+			samples[container.ID] = stats
+		})
+	}
+
+	saveCursor()
+	hideCursor()
+	eraseCursorToEnd()
+	toggleAlternateBuffer()
+
+	var renderedAt time.Time
+	repl, _ := NewREPL(func(wg *sync.WaitGroup) error {
+		if time.Since(renderedAt) < *interval {
+			return nil
+		}
+		renderedAt = time.Now()
+
+		samplesMutex.Lock()
+		rows := make([]*containerStats, 0)
+		for _, stats := range samples {
+			rows = append(rows, stats)
+		}
+		samplesMutex.Unlock()
+
+		sortContainerStats(rows, *sortKey)
+		renderContainerStats(*name, *sortKey, *interval, rows)
+		return nil
+	})
+
+	go func() {
+		repl.run()
+		cancel()
+		docker.Close()
+	}()
+
+	return repl, nil
+}
+
+// streamContainerStats decodes the stats streamed for the given container, invoking the given
+// callback with each sample until the context is canceled
+func streamContainerStats(ctx context.Context, docker *client.Client, container *stack.Container, callback func(*containerStats)) {
+	resp, err := docker.ContainerStats(ctx, container.ID, true)
+	if err != nil {
+		callback(&containerStats{service: container.Service, unavailable: err})
+		return
+	}
+	defer resp.Body.Close()
+
+	decoder := json.NewDecoder(resp.Body)
+	for {
+		var stats types.StatsJSON
+		if err := decoder.Decode(&stats); err != nil {
+			if ctx.Err() == nil {
+				callback(&containerStats{service: container.Service, unavailable: err})
+			}
+			return
+		}
+		callback(parseContainerStats(container.Service, &stats))
+	}
+}
+
+// parseContainerStats calculates the resource usage of the given sample as the docker CLI does
+func parseContainerStats(service string, stats *types.StatsJSON) *containerStats {
+	result := &containerStats{
+		service:  service,
+		memLimit: stats.MemoryStats.Limit,
+		pids:     stats.PidsStats.Current,
+	}
+
+	cpuDelta := float64(stats.CPUStats.CPUUsage.TotalUsage) - float64(stats.PreCPUStats.CPUUsage.TotalUsage)
+	systemDelta := float64(stats.CPUStats.SystemUsage) - float64(stats.PreCPUStats.SystemUsage)
+	cpus := float64(stats.CPUStats.OnlineCPUs)
+	if cpus == 0 {
+		cpus = float64(len(stats.CPUStats.CPUUsage.PercpuUsage))
+	}
+	if cpuDelta > 0 && systemDelta > 0 {
+		result.cpuPercent = cpuDelta / systemDelta * cpus * 100
+	}
+
+	// page cache is excluded from memory usage; cgroup v1 reports it as total_inactive_file and v2 as inactive_file
+	result.memUsage = stats.MemoryStats.Usage
+	for _, key := range []string{"total_inactive_file", "inactive_file"} {
+		if cache, cacheOk := stats.MemoryStats.Stats[key]; cacheOk && cache < result.memUsage {
+			result.memUsage -= cache
+			break
+		}
+	}
+	if result.memLimit > 0 {
+		result.memPercent = float64(result.memUsage) / float64(result.memLimit) * 100
+	}
+
+	for _, network := range stats.Networks {
+		result.netRx += network.RxBytes
+		result.netTx += network.TxBytes
+	}
+
+	for _, entry := range stats.BlkioStats.IoServiceBytesRecursive {
+		switch strings.ToLower(entry.Op) {
+		case "read":
+			result.blockRead += entry.Value
+		case "write":
+			result.blockWrite += entry.Value
+		}
+	}
+
+	return result
+}
+
+// sortContainerStats sorts the given rows by the given column, descending by usage or ascending by service
+func sortContainerStats(rows []*containerStats, key string) {
+	sort.SliceStable(rows, func(i, j int) bool {
+		a, b := rows[i], rows[j]
+		switch key {
+		case topSortCPU:
+			if a.cpuPercent != b.cpuPercent {
+				return a.cpuPercent > b.cpuPercent
+			}
+		case topSortMemory:
+			if a.memUsage != b.memUsage {
+				return a.memUsage > b.memUsage
+			}
+		case topSortNetwork:
+			if a.netRx+a.netTx != b.netRx+b.netTx {
+				return a.netRx+a.netTx > b.netRx+b.netTx
+			}
+		case topSortBlockIO:
+			if a.blockRead+a.blockWrite != b.blockRead+b.blockWrite {
+				return a.blockRead+a.blockWrite > b.blockRead+b.blockWrite
+			}
+		}
+		return a.service < b.service
+	})
+}
+
+// renderContainerStats renders the given rows below the shell header
+func renderContainerStats(stack, sortKey string, interval time.Duration, rows []*containerStats) {
+	width := len("SERVICE")
+	for _, row := range rows {
+		if len(row.service) > width {
+			width = len(row.service)
+		}
+	}
+
+	var buf strings.Builder
+	buf.WriteString(fmt.Sprintf("%s: %d containers; sorted by %s; refreshing every %s; press ctrl-c to exit\n\n", stack, len(rows), sortKey, interval))
+	buf.WriteString(fmt.Sprintf("%-*s  %8s  %-21s  %7s  %-21s  %-21s  %5s\n", width, "SERVICE", "CPU %", "MEM USAGE / LIMIT", "MEM %", "NET I/O", "BLOCK I/O", "PIDS"))
+	for _, row := range rows {
+		if row.unavailable != nil {
+			buf.WriteString(fmt.Sprintf("%-*s  stats unavailable; %s\n", width, row.service, row.unavailable.Error()))
+			continue
+		}
+
+		buf.WriteString(fmt.Sprintf("%-*s  %7.2f%%  %-21s  %6.2f%%  %-21s  %-21s  %5d\n",
+			width,
+			row.service,
+			row.cpuPercent,
+			fmt.Sprintf("%s / %s", common.FormatBytes(row.memUsage), common.FormatBytes(row.memLimit)),
+			row.memPercent,
+			fmt.Sprintf("%s / %s", common.FormatBytes(row.netRx), common.FormatBytes(row.netTx)),
+			fmt.Sprintf("%s / %s", common.FormatBytes(row.blockRead), common.FormatBytes(row.blockWrite)),
+			row.pids,
+		))
+	}
+
+	mutex.Lock()
+	defer mutex.Unlock()
+
+	writer.SaveCursor()
+	writer.CursorGoTo(shellHeaderRows+1, 0)
+	writer.WriteRaw([]byte("\033[0J"))
+	writer.WriteRaw([]byte(buf.String()))
+	writer.UnSaveCursor()
+	writer.Flush()
+}
@@ -0,0 +1,177 @@
+/*
+ * Copyright 2017-2022 Provide Technologies Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package shell
+
+import (
+	"math"
+	"testing"
+
+	"github.com/docker/docker/api/types"
+)
+
+func TestParseContainerStats(t *testing.T) {
+	tests := []struct {
+		name       string
+		stats      *types.StatsJSON
+		cpuPercent float64
+		memUsage   uint64
+		memPercent float64
+		netRx      uint64
+		netTx      uint64
+		blockRead  uint64
+		blockWrite uint64
+	}{
+		{
+			name: "cgroup v1",
+			stats: &types.StatsJSON{
+				Stats: types.Stats{
+					CPUStats: types.CPUStats{
+						CPUUsage:    types.CPUUsage{TotalUsage: 300, PercpuUsage: []uint64{150, 150}},
+						SystemUsage: 2000,
+					},
+					PreCPUStats: types.CPUStats{
+						CPUUsage:    types.CPUUsage{TotalUsage: 100},
+						SystemUsage: 1000,
+					},
+					MemoryStats: types.MemoryStats{
+						Usage: 300,
+						Limit: 1000,
+						Stats: map[string]uint64{"total_inactive_file": 100},
+					},
+					BlkioStats: types.BlkioStats{
+						IoServiceBytesRecursive: []types.BlkioStatEntry{
+							{Op: "Read", Value: 10},
+							{Op: "Write", Value: 20},
+							{Op: "Read", Value: 5},
+							{Op: "Total", Value: 35},
+						},
+					},
+				},
+				Networks: map[string]types.NetworkStats{
+					"eth0": {RxBytes: 100, TxBytes: 200},
+					"eth1": {RxBytes: 1, TxBytes: 2},
+				},
+			},
+			cpuPercent: 40,
+			memUsage:   200,
+			memPercent: 20,
+			netRx:      101,
+			netTx:      202,
+			blockRead:  15,
+			blockWrite: 20,
+		},
+		{
+			name: "cgroup v2",
+			stats: &types.StatsJSON{
+				Stats: types.Stats{
+					CPUStats: types.CPUStats{
+						CPUUsage:    types.CPUUsage{TotalUsage: 500},
+						SystemUsage: 2000,
+						OnlineCPUs:  4,
+					},
+					PreCPUStats: types.CPUStats{
+						CPUUsage:    types.CPUUsage{TotalUsage: 400},
+						SystemUsage: 1000,
+					},
+					MemoryStats: types.MemoryStats{
+						Usage: 600,
+						Limit: 2000,
+						Stats: map[string]uint64{"inactive_file": 200},
+					},
+				},
+			},
+			cpuPercent: 40,
+			memUsage:   400,
+			memPercent: 20,
+		},
+		{
+			name: "first sample",
+			stats: &types.StatsJSON{
+				Stats: types.Stats{
+					CPUStats: types.CPUStats{
+						CPUUsage:    types.CPUUsage{TotalUsage: 500},
+						SystemUsage: 2000,
+						OnlineCPUs:  1,
+					},
+					MemoryStats: types.MemoryStats{
+						Usage: 100,
+						Stats: map[string]uint64{"inactive_file": 200},
+					},
+				},
+			},
+			cpuPercent: 25,
+			memUsage:   100,
+		},
+		{
+			name:  "no usage",
+			stats: &types.StatsJSON{},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := parseContainerStats("api", tt.stats)
+			if got.service != "api" {
+				t.Errorf("service = %s, want api", got.service)
+			}
+			if math.Abs(got.cpuPercent-tt.cpuPercent) > 0.001 {
+				t.Errorf("cpuPercent = %f, want %f", got.cpuPercent, tt.cpuPercent)
+			}
+			if got.memUsage != tt.memUsage || math.Abs(got.memPercent-tt.memPercent) > 0.001 {
+				t.Errorf("memory = %d (%f%%), want %d (%f%%)", got.memUsage, got.memPercent, tt.memUsage, tt.memPercent)
+			}
+			if got.netRx != tt.netRx || got.netTx != tt.netTx {
+				t.Errorf("network = %d / %d, want %d / %d", got.netRx, got.netTx, tt.netRx, tt.netTx)
+			}
+			if got.blockRead != tt.blockRead || got.blockWrite != tt.blockWrite {
+				t.Errorf("block io = %d / %d, want %d / %d", got.blockRead, got.blockWrite, tt.blockRead, tt.blockWrite)
+			}
+		})
+	}
+}
+
+func TestSortContainerStats(t *testing.T) {
+	rows := func() []*containerStats {
+		return []*containerStats{
+			{service: "redis", cpuPercent: 1, memUsage: 30, netRx: 1, blockWrite: 50},
+			{service: "api", cpuPercent: 5, memUsage: 10, netTx: 9, blockRead: 5},
+			{service: "nats", cpuPercent: 5, memUsage: 20, netRx: 3, netTx: 3},
+		}
+	}
+
+	tests := []struct {
+		name string
+		key  string
+		want []string
+	}{
+		{name: "cpu", key: topSortCPU, want: []string{"api", "nats", "redis"}},
+		{name: "memory", key: topSortMemory, want: []string{"redis", "nats", "api"}},
+		{name: "network", key: topSortNetwork, want: []string{"api", "nats", "redis"}},
+		{name: "block io", key: topSortBlockIO, want: []string{"redis", "api", "nats"}},
+		{name: "service", key: topSortService, want: []string{"api", "nats", "redis"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sorted := rows()
+			sortContainerStats(sorted, tt.key)
+			for i, row := range sorted {
+				if row.service != tt.want[i] {
+					t.Errorf("sortContainerStats(%s)[%d] = %s, want %s", tt.key, i, row.service, tt.want[i])
+				}
+			}
+		})
+	}
+}
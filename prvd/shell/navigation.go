@@ -0,0 +1,350 @@
+/*
+ * Copyright 2017-2022 Provide Technologies Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package shell
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/provideplatform/provide-cli/prvd/common"
+	"github.com/provideplatform/provide-go/api/axiom"
+	"github.com/provideplatform/provide-go/api/ident"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+const nativeCommandCd = "cd"
+const nativeCommandLs = "ls"
+const nativeCommandPwd = "pwd"
+
+const pathSeparator = "/"
+const pathParent = ".."
+const pathCurrent = "."
+
+const pathOrganizations = "organizations"
+const pathWorkgroups = "workgroups"
+const pathWorkflows = "workflows"
+
+// navigationRpp is the maximum number of resources listed at each level of the resource tree
+const navigationRpp = 100
+
+// pathCollections are the collections of the resource tree in order of nesting; the resource
+// selected within each collection supplies the corresponding flag of pathFlags
+var pathCollections = []string{pathOrganizations, pathWorkgroups, pathWorkflows}
+var pathFlags = []string{"organization", "workgroup", "workflow"}
+
+// pathResource is a resource selected in the current path
+type pathResource struct {
+	id   string
+	name string
+}
+
+// location is a path within the resource tree, i.e., /organizations/acme/workgroups; resources
+// holds the resource selected within each collection and collection is true when the collection
+// following the last resource is entered
+type location struct {
+	resources  []*pathResource
+	collection bool
+}
+
+// cwd is the current location within the resource tree
+var cwd = &location{}
+
+// String renders the location using resource names
+func (l *location) String() string {
+	segments := make([]string, 0)
+	for i, resource := range l.resources {
+		segments = append(segments, pathCollections[i], resource.name)
+	}
+	if l.collection {
+		segments = append(segments, pathCollections[len(l.resources)])
+	}
+	return pathSeparator + strings.Join(segments, pathSeparator)
+}
+
+func (l *location) copy() *location {
+	resources := make([]*pathResource, len(l.resources))
+	copy(resources, l.resources)
+	return &location{resources: resources, collection: l.collection}
+}
+
+// cd changes the current location; without args, the root of the resource tree is restored
+func cd(argv []string) (*REPL, error) {
+	if len(argv) > 2 {
+		return nil, fmt.Errorf("usage: %s [path]", nativeCommandCd)
+	}
+
+	loc := &location{}
+	if len(argv) == 2 {
+		var err error
+		loc, err = resolveLocation(argv[1])
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	cwd = loc
+	path = ""
+	if len(cwd.resources) > 0 || cwd.collection {
+		path = fmt.Sprintf(" %s", cwd.String())
+	}
+	return nil, nil
+}
+
+// ls lists the current location or the given path; resources are listed as name and id
+func ls(argv []string) (*REPL, error) {
+	if len(argv) > 2 {
+		return nil, fmt.Errorf("usage: %s [path]", nativeCommandLs)
+	}
+
+	loc := cwd
+	if len(argv) == 2 {
+		var err error
+		loc, err = resolveLocation(argv[1])
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if !loc.collection {
+		if len(loc.resources) < len(pathCollections) {
+			printf("%s%s\n", pathCollections[len(loc.resources)], pathSeparator)
+			return nil, nil
+		}
+
+		worksteps, err := listWorksteps(loc)
+		if err != nil {
+			return nil, err
+		}
+		for _, workstep := range worksteps {
+			printf("%s\t%s\t%s\n", stringOrEmpty(workstep.Name), workstep.ID.String(), stringOrEmpty(workstep.Status))
+		}
+		return nil, nil
+	}
+
+	resources, err := listResources(loc)
+	if err != nil {
+		return nil, err
+	}
+	for _, resource := range resources {
+		printf("%s\t%s\n", resource.name, resource.id)
+	}
+	return nil, nil
+}
+
+// pwd prints the current location
+func pwd(argv []string) (*REPL, error) {
+	printf("%s\n", cwd.String())
+	return nil, nil
+}
+
+// resolveLocation resolves the given absolute or relative path; resources are matched by id or
+// case-insensitive name. At the root, workgroups and workflows are resolved within the active
+// organization and workgroup, i.e., cd workgroups/supply-chain
+func resolveLocation(p string) (*location, error) {
+	loc := cwd.copy()
+	if strings.HasPrefix(p, pathSeparator) {
+		loc = &location{}
+	}
+
+	for _, segment := range strings.Split(p, pathSeparator) {
+		switch {
+		case segment == "" || segment == pathCurrent:
+			continue
+		case segment == pathParent:
+			if loc.collection {
+				loc.collection = false
+			} else if len(loc.resources) > 0 {
+				loc.resources = loc.resources[:len(loc.resources)-1]
+				loc.collection = true
+			}
+		case loc.collection:
+			resource, err := resolveResource(loc, segment)
+			if err != nil {
+				return nil, err
+			}
+			loc.resources = append(loc.resources, resource)
+			loc.collection = false
+		case len(loc.resources) == 0 && (segment == pathWorkgroups || segment == pathWorkflows):
+			active, err := activeLocation(segment)
+			if err != nil {
+				return nil, err
+			}
+			loc = active
+		case len(loc.resources) < len(pathCollections) && segment == pathCollections[len(loc.resources)]:
+			loc.collection = true
+		default:
+			return nil, fmt.Errorf("no such path: %s", p)
+		}
+	}
+
+	return loc, nil
+}
+
+// activeLocation returns the location of the given collection within the active organization and workgroup
+func activeLocation(collection string) (*location, error) {
+	orgID, orgName := common.ActiveOrganization()
+	if orgID == "" {
+		return nil, fmt.Errorf("no active organization; run use organization or cd %s", pathSeparator+pathOrganizations)
+	}
+
+	loc := &location{resources: []*pathResource{{id: orgID, name: orDefault(orgName, orgID)}}, collection: true}
+	if collection == pathWorkgroups {
+		return loc, nil
+	}
+
+	wgID, wgName := common.ActiveWorkgroup()
+	if wgID == "" || viper.GetString(common.ActiveWorkgroupOrganizationIDConfigKey) != orgID {
+		return nil, fmt.Errorf("no active workgroup; run use workgroup or cd %s", pathWorkgroups)
+	}
+	loc.resources = append(loc.resources, &pathResource{id: wgID, name: orDefault(wgName, wgID)})
+	return loc, nil
+}
+
+// resolveResource resolves the resource with the given id or name within the collection entered at the given location
+func resolveResource(loc *location, segment string) (*pathResource, error) {
+	resources, err := listResources(loc)
+	if err != nil {
+		return nil, err
+	}
+
+	matches := make([]*pathResource, 0)
+	for _, resource := range resources {
+		if resource.id == segment {
+			return resource, nil
+		}
+		if strings.EqualFold(resource.name, segment) {
+			matches = append(matches, resource)
+		}
+	}
+
+	switch len(matches) {
+	case 0:
+		return nil, fmt.Errorf("no such %s: %s", strings.TrimSuffix(pathCollections[len(loc.resources)], "s"), segment)
+	case 1:
+		return matches[0], nil
+	}
+	return nil, fmt.Errorf("%s is ambiguous; cd using the id instead", segment)
+}
+
+// listResources lists the resources of the collection entered at the given location
+func listResources(loc *location) ([]*pathResource, error) {
+	resources := make([]*pathResource, 0)
+	params := map[string]interface{}{"rpp": navigationRpp}
+
+	err := guard(func() error {
+		switch pathCollections[len(loc.resources)] {
+		case pathOrganizations:
+			orgs, err := ident.ListOrganizations(common.RequireUserAccessToken(common.NewContext()), params)
+			if err != nil {
+				return err
+			}
+			for _, org := range orgs {
+				resources = append(resources, &pathResource{id: stringOrEmpty(org.ID), name: orDefault(stringOrEmpty(org.Name), stringOrEmpty(org.ID))})
+			}
+		case pathWorkgroups:
+			token, err := organizationToken(loc)
+			if err != nil {
+				return err
+			}
+			workgroups, err := axiom.ListWorkgroups(token, params)
+			if err != nil {
+				return err
+			}
+			for _, wg := range workgroups {
+				resources = append(resources, &pathResource{id: wg.ID.String(), name: orDefault(stringOrEmpty(wg.Name), wg.ID.String())})
+			}
+		case pathWorkflows:
+			token, err := organizationToken(loc)
+			if err != nil {
+				return err
+			}
+			params["workgroup_id"] = loc.resources[1].id
+			workflows, err := axiom.ListWorkflows(token, params)
+			if err != nil {
+				return err
+			}
+			for _, workflow := range workflows {
+				resources = append(resources, &pathResource{id: workflow.ID.String(), name: orDefault(stringOrEmpty(workflow.Name), workflow.ID.String())})
+			}
+		}
+		return nil
+	})
+
+	return resources, err
+}
+
+// listWorksteps lists the worksteps of the workflow at the given location
+func listWorksteps(loc *location) ([]*axiom.Workstep, error) {
+	var worksteps []*axiom.Workstep
+	err := guard(func() error {
+		token, err := organizationToken(loc)
+		if err != nil {
+			return err
+		}
+		worksteps, err = axiom.ListWorksteps(token, loc.resources[2].id, map[string]interface{}{"rpp": navigationRpp})
+		return err
+	})
+	return worksteps, err
+}
+
+// organizationToken resolves an access token for the organization at the given location
+func organizationToken(loc *location) (string, error) {
+	token, err := common.ResolveOrganizationToken(&common.Context{OrganizationID: loc.resources[0].id})
+	if err != nil {
+		return "", err
+	}
+	return *token.AccessToken, nil
+}
+
+// applyPathFlags supplies the --organization, --workgroup and --workflow flags of the command
+// addressed by the given argv using the resources selected in the current path, unless given
+func applyPathFlags(root *cobra.Command, argv []string) []string {
+	if len(cwd.resources) == 0 {
+		return argv
+	}
+
+	target, _, err := root.Find(argv)
+	if err != nil {
+		return argv
+	}
+
+	for i, resource := range cwd.resources {
+		flag := fmt.Sprintf("--%s", pathFlags[i])
+		if lookupFlag(target, flag) == nil || flagGiven(argv, flag) {
+			continue
+		}
+		argv = append(argv, flag, resource.id)
+	}
+	return argv
+}
+
+func flagGiven(argv []string, flag string) bool {
+	for _, arg := range argv {
+		if arg == flag || strings.HasPrefix(arg, flag+"=") {
+			return true
+		}
+	}
+	return false
+}
+
+func orDefault(str, defaultStr string) string {
+	if str == "" {
+		return defaultStr
+	}
+	return str
+}
@@ -0,0 +1,485 @@
+/*
+ * Copyright 2017-2022 Provide Technologies Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package shell
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/provideplatform/provide-cli/prvd/common"
+	"github.com/spf13/cobra"
+)
+
+const nativeCommandSet = "set"
+const nativeCommandSource = "source"
+
+const scriptComment = "#"
+const scriptContinuation = "\\"
+const scriptResultVariable = "_"
+
+// scriptMaxSourceDepth bounds the nesting of sourced scripts, i.e., a script which sources itself
+const scriptMaxSourceDepth = 32
+
+// errScriptExit is returned when a script executes exit or quit
+var errScriptExit = errors.New("exit")
+
+// variables holds the shell variables assigned using `set NAME=value`
+var variables = map[string]string{}
+
+// result is the output of the previous command, compacted when it is JSON; it is expanded using $_
+var result string
+
+// scriptRun is the state of a running script; a sourced script inherits the state of the script
+// which sources it, but changes made by the sourced script do not affect its caller
+type scriptRun struct {
+	// errexit stops the script at the first failing command when set using `set -e`
+	errexit bool
+
+	// depth is the number of scripts sourcing this script
+	depth int
+
+	// capture copies the output of each command into the result; output is only captured by
+	// scripts, as the commands run by the interactive shell may use the terminal
+	capture bool
+}
+
+// interactiveRun is the state of the interactive shell, which is inherited by the scripts it sources
+var interactiveRun = &scriptRun{}
+
+// captureMutex serializes the capture of command output, during which os.Stdout is replaced
+var captureMutex sync.Mutex
+
+// runScript executes each line of the script at the given path sequentially; comments and blank
+// lines are ignored and lines ending with a backslash are continued on the following line
+func runScript(cmd *cobra.Command, path string, run *scriptRun) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	scanner := bufio.NewScanner(f)
+	line := ""
+	lineno := 0
+	start := 0
+
+	for scanner.Scan() {
+		lineno++
+		text := strings.TrimSpace(scanner.Text())
+		if line == "" {
+			start = lineno
+			if text == "" || strings.HasPrefix(text, scriptComment) {
+				continue
+			}
+		}
+
+		if strings.HasSuffix(text, scriptContinuation) {
+			line += strings.TrimSuffix(text, scriptContinuation) + " "
+			continue
+		}
+
+		line += text
+		err := evaluate(cmd, run, line)
+		line = ""
+
+		if err == errScriptExit {
+			return nil
+		} else if err != nil {
+			if run.errexit {
+				return fmt.Errorf("%s:%d: %s", path, start, err.Error())
+			}
+			printf("%s: %s:%d: %s\n", shellTitle, path, start, err.Error())
+		}
+	}
+
+	if err := scanner.Err(); err != nil {
+		return err
+	}
+	if line != "" {
+		return evaluate(cmd, run, line)
+	}
+	return nil
+}
+
+// evaluate expands and executes a single line of shell input within the given script run
+func evaluate(cmd *cobra.Command, run *scriptRun, input string) error {
+	argv, err := splitArgs(input)
+	if err != nil {
+		return err
+	}
+	if len(argv) == 0 {
+		return nil
+	}
+
+	switch argv[0] {
+	case nativeCommandSet:
+		return set(run, argv[1:])
+	case nativeCommandSource:
+		if len(argv) != 2 {
+			return fmt.Errorf("usage: %s <path>", nativeCommandSource)
+		}
+		if run.depth >= scriptMaxSourceDepth {
+			return fmt.Errorf("%s: maximum source depth of %d exceeded", argv[1], scriptMaxSourceDepth)
+		}
+		return runScript(cmd, argv[1], &scriptRun{errexit: run.errexit, depth: run.depth + 1, capture: true})
+	case sanitizedPromptInputMatchExit, sanitizedPromptInputMatchQuit:
+		return errScriptExit
+	}
+
+	if supportedNativeCommand(argv) {
+		repl, err := resolveNativeCommand(argv)(argv)
+		if repl != nil {
+			repls = append(repls, repl)
+		}
+		return err
+	}
+
+	_cmd, i := resolveChildCmd(cmd, argv)
+	if _cmd != nil {
+		if debug {
+			printf("resolved child command for input: %s; argv[%d]: %v; use: %s", input, i, argv, _cmd.Use)
+		}
+
+		argv = applyPathFlags(cmd.Root(), argv[i:])
+
+		var err error
+		if run.capture && !interactiveCommand(cmd.Root(), argv) {
+			var out string
+			out, err = captureOutput(func() error {
+				return execute(cmd.Root(), argv)
+			})
+			result = parseResult(out)
+		} else {
+			err = execute(cmd.Root(), argv)
+			result = ""
+		}
+		if err != nil {
+			return fmt.Errorf("%s: %s", strings.Join(argv, " "), err.Error())
+		}
+		return nil
+	}
+
+	return fmt.Errorf("command not found: %s", strings.Join(argv, " "))
+}
+
+// set handles `set -e`, `set +e` and `set NAME=value`; the variables are printed when no args are given
+func set(run *scriptRun, args []string) error {
+	if len(args) == 0 {
+		names := make([]string, 0)
+		for name := range variables {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+
+		for _, name := range names {
+			printf("%s=%s\n", name, variables[name])
+		}
+		return nil
+	}
+
+	switch args[0] {
+	case "-e":
+		run.errexit = true
+		return nil
+	case "+e":
+		run.errexit = false
+		return nil
+	}
+
+	assignment := strings.Join(args, " ")
+	i := strings.Index(assignment, "=")
+	if i < 1 {
+		return fmt.Errorf("usage: %s NAME=value", nativeCommandSet)
+	}
+
+	name := assignment[:i]
+	if !validVariableName(name) {
+		return fmt.Errorf("invalid variable name: %s", name)
+	}
+	variables[name] = assignment[i+1:]
+	return nil
+}
+
+// splitArgs splits the given input into arguments; single and double quotes group arguments
+// containing whitespace and variables are expanded everywhere except within single quotes
+func splitArgs(input string) ([]string, error) {
+	args, _, err := scanArgs(input, true)
+	return args, err
+}
+
+// argSpan is the position of an argument within the input, in runes
+type argSpan struct {
+	start int
+	end   int
+}
+
+// scanArgs splits the given input into arguments as splitArgs does, additionally returning the
+// span of each argument within the input; variables are only expanded when expand is true. The
+// arguments scanned are returned alongside the error when a quote is unterminated
+func scanArgs(input string, expand bool) ([]string, []argSpan, error) {
+	args := make([]string, 0)
+	spans := make([]argSpan, 0)
+	var arg strings.Builder
+	inArg := false
+	start := 0
+	var quote rune
+
+	runes := []rune(input)
+	for i := 0; i < len(runes); i++ {
+		r := runes[i]
+		if !inArg && r != ' ' && r != '\t' {
+			start = i
+		}
+
+		switch {
+		case quote == '\'':
+			if r == '\'' {
+				quote = 0
+			} else {
+				arg.WriteRune(r)
+			}
+		case r == '\\' && i+1 < len(runes):
+			i++
+			arg.WriteRune(runes[i])
+			inArg = true
+		case r == '$' && expand:
+			val, n, err := expandVariable(runes[i+1:])
+			if err != nil {
+				return nil, nil, err
+			}
+			arg.WriteString(val)
+			i += n
+			inArg = true
+		case quote == '"':
+			if r == '"' {
+				quote = 0
+			} else {
+				arg.WriteRune(r)
+			}
+		case r == '\'' || r == '"':
+			quote = r
+			inArg = true
+		case r == ' ' || r == '\t':
+			if inArg {
+				args = append(args, arg.String())
+				spans = append(spans, argSpan{start: start, end: i})
+				arg.Reset()
+				inArg = false
+			}
+		default:
+			arg.WriteRune(r)
+			inArg = true
+		}
+	}
+
+	if inArg {
+		args = append(args, arg.String())
+		spans = append(spans, argSpan{start: start, end: len(runes)})
+	}
+	if quote != 0 {
+		return args, spans, fmt.Errorf("unterminated quote: %c", quote)
+	}
+	return args, spans, nil
+}
+
+// expandVariable expands the variable reference following a $; $NAME, ${NAME}, $_ and
+// $_.path.to.field (which selects a field of the previous JSON result) are supported;
+// returns the value and the number of runes consumed
+func expandVariable(runes []rune) (string, int, error) {
+	braced := len(runes) > 0 && runes[0] == '{'
+	start := 0
+	if braced {
+		start = 1
+	}
+
+	end := start
+	for end < len(runes) && isVariableRune(runes[end], end == start) {
+		end++
+	}
+	name := string(runes[start:end])
+
+	var path []string
+	if name == scriptResultVariable {
+		for end < len(runes) && runes[end] == '.' {
+			field := end + 1
+			for field < len(runes) && (isVariableRune(runes[field], false) || runes[field] == '-') {
+				field++
+			}
+			if field == end+1 {
+				break
+			}
+			path = append(path, string(runes[end+1:field]))
+			end = field
+		}
+	}
+
+	consumed := end
+	if braced {
+		if end >= len(runes) || runes[end] != '}' {
+			return "", 0, errors.New("bad substitution")
+		}
+		consumed++
+	}
+
+	if name == "" {
+		return "$", consumed, nil
+	}
+
+	if name == scriptResultVariable {
+		val, err := resultField(path)
+		return val, consumed, err
+	}
+
+	if val, valOk := variables[name]; valOk {
+		return val, consumed, nil
+	}
+	if val, valOk := os.LookupEnv(name); valOk {
+		return val, consumed, nil
+	}
+	return "", 0, fmt.Errorf("unbound variable: %s", name)
+}
+
+// resultField selects the field at the given path of the previous JSON result
+func resultField(path []string) (string, error) {
+	if len(path) == 0 {
+		return result, nil
+	}
+
+	var val interface{}
+	if err := json.Unmarshal([]byte(result), &val); err != nil {
+		return "", errors.New("previous result is not JSON")
+	}
+
+	for _, field := range path {
+		switch v := val.(type) {
+		case map[string]interface{}:
+			val = v[field]
+		case []interface{}:
+			i, err := strconv.Atoi(field)
+			if err != nil || i < 0 || i >= len(v) {
+				return "", fmt.Errorf("invalid index in previous result: %s", field)
+			}
+			val = v[i]
+		default:
+			val = nil
+		}
+
+		if val == nil {
+			return "", fmt.Errorf("field not found in previous result: %s", strings.Join(path, "."))
+		}
+	}
+
+	if str, strOk := val.(string); strOk {
+		return str, nil
+	}
+
+	raw, err := json.Marshal(val)
+	if err != nil {
+		return "", err
+	}
+	return string(raw), nil
+}
+
+// parseResult returns the given command output as the result; JSON output is compacted
+func parseResult(out string) string {
+	out = strings.TrimSpace(out)
+
+	buf := &bytes.Buffer{}
+	if err := json.Compact(buf, []byte(out)); err == nil {
+		return buf.String()
+	}
+	return out
+}
+
+// interactiveCommand returns true if the command resolved for the given argv uses the terminal,
+// i.e., it is annotated as interactive or is given --edit; its output is never captured
+func interactiveCommand(root *cobra.Command, argv []string) bool {
+	for _, arg := range argv {
+		if arg == "--"+common.EditFlag || arg == "--"+common.EditFlag+"=true" {
+			return true
+		}
+	}
+
+	cmd, _, err := root.Find(argv)
+	if err != nil {
+		return false
+	}
+	for ; cmd != nil; cmd = cmd.Parent() {
+		if cmd.Annotations[common.InteractiveAnnotation] != "" {
+			return true
+		}
+	}
+	return false
+}
+
+// captureOutput runs the given func while copying anything it writes to stdout into the returned
+// output; captures are serialized and the shell itself writes to its own stdout, which is never
+// replaced, so output written by background goroutines is neither captured nor lost
+func captureOutput(fn func() error) (string, error) {
+	captureMutex.Lock()
+	defer captureMutex.Unlock()
+
+	r, w, err := os.Pipe()
+	if err != nil {
+		return "", fn()
+	}
+
+	buf := &bytes.Buffer{}
+	done := make(chan struct{})
+	go func() {
+		io.Copy(io.MultiWriter(stdout, buf), r)
+		close(done)
+	}()
+
+	previous := os.Stdout
+	os.Stdout = w
+	err = fn()
+	os.Stdout = previous
+
+	w.Close()
+	<-done
+	r.Close()
+
+	return buf.String(), err
+}
+
+// printf writes to the prompt writer when interactive; otherwise to stdout
+func printf(format string, a ...interface{}) {
+	(&promptWriter{}).Write([]byte(fmt.Sprintf(format, a...)))
+}
+
+func validVariableName(name string) bool {
+	for i, r := range name {
+		if !isVariableRune(r, i == 0) {
+			return false
+		}
+	}
+	return name != "" && name != scriptResultVariable
+}
+
+func isVariableRune(r rune, first bool) bool {
+	return r == '_' || r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || (!first && r >= '0' && r <= '9')
+}
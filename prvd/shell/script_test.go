@@ -0,0 +1,166 @@
+/*
+ * Copyright 2017-2022 Provide Technologies Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package shell
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/provideplatform/provide-cli/prvd/common"
+	"github.com/spf13/cobra"
+)
+
+func TestSplitArgs(t *testing.T) {
+	variables = map[string]string{"NAME": "acme", "EMPTY": "", "SPACED": "a b"}
+	result = `{"id":"abc","tags":["x","y"],"org":{"name":"Acme Corp"}}`
+	defer func() {
+		variables = map[string]string{}
+		result = ""
+	}()
+
+	tests := []struct {
+		name    string
+		input   string
+		want    []string
+		wantErr bool
+	}{
+		{name: "empty", input: "", want: []string{}},
+		{name: "whitespace", input: " \t ", want: []string{}},
+		{name: "words", input: "organizations list  --rpp 10", want: []string{"organizations", "list", "--rpp", "10"}},
+		{name: "double quotes", input: `set NAME="acme corp"`, want: []string{"set", "NAME=acme corp"}},
+		{name: "single quotes", input: `echo 'a  b'`, want: []string{"echo", "a  b"}},
+		{name: "empty quotes", input: `x "" ''`, want: []string{"x", "", ""}},
+		{name: "escaped space", input: `a\ b c`, want: []string{"a b", "c"}},
+		{name: "escaped quote", input: `a\"b`, want: []string{`a"b`}},
+		{name: "variable", input: "--name $NAME", want: []string{"--name", "acme"}},
+		{name: "braced variable", input: "${NAME}-corp", want: []string{"acme-corp"}},
+		{name: "variable in double quotes", input: `"$NAME corp"`, want: []string{"acme corp"}},
+		{name: "no expansion in single quotes", input: `'$NAME'`, want: []string{"$NAME"}},
+		{name: "escaped dollar", input: `\$NAME`, want: []string{"$NAME"}},
+		{name: "unquoted variable with spaces", input: "$SPACED", want: []string{"a b"}},
+		{name: "empty variable", input: "x $EMPTY", want: []string{"x", ""}},
+		{name: "lone dollar", input: "$ 5", want: []string{"$", "5"}},
+		{name: "previous result", input: "$_.id", want: []string{"abc"}},
+		{name: "previous result index", input: "$_.tags.1", want: []string{"y"}},
+		{name: "previous result nested field", input: `"$_.org.name"`, want: []string{"Acme Corp"}},
+		{name: "previous result object", input: "${_.org}", want: []string{`{"name":"Acme Corp"}`}},
+		{name: "previous result missing field", input: "$_.missing", wantErr: true},
+		{name: "previous result index out of range", input: "$_.tags.2", wantErr: true},
+		{name: "unbound variable", input: "$PRVD_TEST_UNBOUND_VARIABLE", wantErr: true},
+		{name: "bad substitution", input: "${NAME", wantErr: true},
+		{name: "unterminated quote", input: `"acme`, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := splitArgs(tt.input)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("splitArgs(%q) error = %v, wantErr %v", tt.input, err, tt.wantErr)
+				return
+			}
+			if !tt.wantErr && !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("splitArgs(%q) = %q, want %q", tt.input, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestScanArgsSpans(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		want  []argSpan
+	}{
+		{name: "words", input: "a bc", want: []argSpan{{0, 1}, {2, 4}}},
+		{name: "quoted", input: ` "a b"  c`, want: []argSpan{{1, 6}, {8, 9}}},
+		{name: "variable is not expanded", input: "$UNBOUND x", want: []argSpan{{0, 8}, {9, 10}}},
+		{name: "unterminated quote", input: `a "b c`, want: []argSpan{{0, 1}, {2, 6}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, spans, _ := scanArgs(tt.input, false)
+			if !reflect.DeepEqual(spans, tt.want) {
+				t.Errorf("scanArgs(%q) spans = %v, want %v", tt.input, spans, tt.want)
+			}
+		})
+	}
+}
+
+func TestSet(t *testing.T) {
+	defer func() { variables = map[string]string{} }()
+
+	tests := []struct {
+		name        string
+		args        []string
+		wantErrexit bool
+		wantVar     string
+		wantVal     string
+		wantErr     bool
+	}{
+		{name: "errexit", args: []string{"-e"}, wantErrexit: true},
+		{name: "assignment", args: []string{"ORG=acme"}, wantVar: "ORG", wantVal: "acme"},
+		{name: "assignment with spaces", args: []string{"ORG=acme", "corp"}, wantVar: "ORG", wantVal: "acme corp"},
+		{name: "empty value", args: []string{"ORG="}, wantVar: "ORG", wantVal: ""},
+		{name: "missing name", args: []string{"=acme"}, wantErr: true},
+		{name: "invalid name", args: []string{"1ORG=acme"}, wantErr: true},
+		{name: "result variable", args: []string{"_=acme"}, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			variables = map[string]string{}
+			run := &scriptRun{}
+			if err := set(run, tt.args); (err != nil) != tt.wantErr {
+				t.Errorf("set(%q) error = %v, wantErr %v", tt.args, err, tt.wantErr)
+				return
+			}
+			if run.errexit != tt.wantErrexit {
+				t.Errorf("set(%q) errexit = %v, want %v", tt.args, run.errexit, tt.wantErrexit)
+			}
+			if tt.wantVar != "" && variables[tt.wantVar] != tt.wantVal {
+				t.Errorf("set(%q) %s = %q, want %q", tt.args, tt.wantVar, variables[tt.wantVar], tt.wantVal)
+			}
+		})
+	}
+}
+
+func TestInteractiveCommand(t *testing.T) {
+	root := &cobra.Command{Use: "prvd"}
+	stack := &cobra.Command{Use: "stack"}
+	exec := &cobra.Command{Use: "exec", Run: func(*cobra.Command, []string) {}, Annotations: map[string]string{common.InteractiveAnnotation: "true"}}
+	send := &cobra.Command{Use: "send", Run: func(*cobra.Command, []string) {}}
+	send.Flags().Bool(common.EditFlag, false, "")
+	stack.AddCommand(exec)
+	root.AddCommand(stack, send)
+
+	tests := []struct {
+		name string
+		argv []string
+		want bool
+	}{
+		{name: "annotated", argv: []string{"stack", "exec", "api"}, want: true},
+		{name: "edit", argv: []string{"send", "--edit"}, want: true},
+		{name: "edit disabled", argv: []string{"send", "--edit=false"}, want: false},
+		{name: "not interactive", argv: []string{"send"}, want: false},
+		{name: "unknown command", argv: []string{"unknown"}, want: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := interactiveCommand(root, tt.argv); got != tt.want {
+				t.Errorf("interactiveCommand(%q) = %v, want %v", tt.argv, got, tt.want)
+			}
+		})
+	}
+}
@@ -19,6 +19,7 @@ package shell
 import (
 	"bytes"
 	"fmt"
+	"log"
 	"os"
 	"os/exec"
 	"strings"
@@ -35,7 +36,7 @@ const shellHeaderRows = 7
 
 const shellExitMessage = "Exiting... have a nice day!"
 const shellTitle = "prvd"
-const shellPrefix = "➜  prvd:($VERSION)$PATH"
+const shellPrefix = "➜  prvd:($VERSION)$SCOPE$PATH"
 const shellPrefixPrompt = " > "
 
 const shellOptionDefaultFGColor = prompt.DefaultColor
@@ -60,18 +61,18 @@ const sanitizedPromptInputMatchQuit = "quit" // FIXME-- combine exit and quit in
 const sanitizedPromptInputMatchRoot = ""
 
 var debug bool
+var scriptFile string
 
 var path string
 var version string
 
 var childCommands []*cobra.Command
-var childCommandSuggestions []prompt.Suggest
 
 var cursorHidden bool
 var viewingAlternateBuffer bool
 
 var prmpt *prompt.Prompt
-var history *prompt.History
+var history *History
 var parser prompt.ConsoleParser
 var writer prompt.ConsoleWriter
 
@@ -87,6 +88,17 @@ var ShellCmd = &cobra.Command{
 
 The Provide shell allows you to attach to a specific version of the Provide stack.
 
+Run with --file to execute a script non-interactively; scripts may also be run from within
+the shell using source. Each line is executed in order and may assign variables using
+set NAME=value, which are expanded using $NAME. Within a script, the output of the previous
+command is expanded using $_ and, when it is JSON, its fields using $_.path.to.field; the
+output of commands which use the terminal, i.e., those given --edit, is not captured. Lines
+beginning with # are ignored. After set -e, a script stops at the first failing command.
+
+Use cd, ls and pwd to navigate organizations, workgroups and workflows by path, i.e.,
+cd /organizations/acme/workgroups/supply-chain; commands run within a path are given the
+--organization, --workgroup and --workflow flags of the path unless provided explicitly.
+
 Run with the --help flag to see available options`, common.ASCIIBanner),
 	Run: shell,
 }
@@ -97,18 +109,28 @@ func shell(cmd *cobra.Command, args []string) {
 
 	repls = make([]*REPL, 0)
 
+	rootCmd = cmd.Root()
 	childCommands = make([]*cobra.Command, 0)
-	childCommandSuggestions = make([]prompt.Suggest, 0)
 	for _, child := range cmd.Root().Commands() {
 		if child != cmd {
 			childCommands = append(childCommands, child)
-			childCommandSuggestions = append(childCommandSuggestions, prompt.Suggest{
-				Text:        child.Use,
-				Description: child.Short,
-			})
 		}
 	}
 
+	var err error
+	history, err = LoadHistory()
+	if err != nil {
+		history = &History{entries: make([]string, 0)}
+	}
+
+	if scriptFile != "" {
+		if err := runScript(cmd, scriptFile, &scriptRun{capture: true}); err != nil {
+			log.Printf("Failed to run script; %s", err.Error())
+			os.Exit(1) // common.Exit only unwinds guarded commands once the shell has run one
+		}
+		return
+	}
+
 	if version == "" {
 		if common.IsReleaseContext() {
 			version = common.Manifest.Version
@@ -130,13 +152,8 @@ func refresh(cmd *cobra.Command, msg []byte) {
 		}
 	}()
 
-	prefix := strings.ReplaceAll(
-		strings.ReplaceAll(shellPrefix, "$VERSION", version),
-		"$PATH",
-		fmt.Sprintf("%s%s", path, shellPrefixPrompt),
-	)
+	prefix := renderPrefix()
 
-	history = prompt.NewHistory() // TODO -- use this?
 	parser = prompt.NewStandardInputParser()
 	parser.Setup()
 
@@ -164,7 +181,9 @@ func refresh(cmd *cobra.Command, msg []byte) {
 		prompt.OptionAddKeyBind(prompt.KeyBind{
 			Key: prompt.ControlC,
 			Fn: func(buf *prompt.Buffer) {
-				if viewingAlternateBuffer {
+				if history.Searching() {
+					history.StopSearch()
+				} else if viewingAlternateBuffer {
 					for _, repl := range repls { // FIXME-- should this just be a single, top-level repl?
 						repl.shutdown()
 					}
@@ -172,11 +191,29 @@ func refresh(cmd *cobra.Command, msg []byte) {
 					repls = make([]*REPL, 0)
 					toggleAlternateBuffer()
 					showCursor()
+				} else if len(repls) > 0 {
+					for _, repl := range repls {
+						repl.shutdown()
+					}
+
+					repls = make([]*REPL, 0)
 				} else {
 					writer.WriteRaw([]byte("Interrupt\n"))
 				}
 			},
 		}),
+		prompt.OptionAddKeyBind(prompt.KeyBind{
+			Key: prompt.ControlR,
+			Fn: func(buf *prompt.Buffer) {
+				history.StartSearch()
+			},
+		}),
+		prompt.OptionAddKeyBind(prompt.KeyBind{
+			Key: prompt.ControlG,
+			Fn: func(buf *prompt.Buffer) {
+				history.StopSearch()
+			},
+		}),
 		prompt.OptionAddKeyBind(prompt.KeyBind{
 			Key: prompt.ControlD,
 			Fn: func(buf *prompt.Buffer) {
@@ -188,12 +225,16 @@ func refresh(cmd *cobra.Command, msg []byte) {
 		}),
 		prompt.OptionDescriptionBGColor(shellOptionDescriptionBGColor),
 		prompt.OptionDescriptionTextColor(shellOptionDescriptionTextColor),
+		prompt.OptionHistory(history.Entries()),
 		prompt.OptionInputTextColor(shellOptionDefaultInputTextColor),
 		prompt.OptionLivePrefix(func() (string, bool) {
 			if cursorHidden {
 				return "", true
 			}
-			return prefix, true
+			if history.Searching() {
+				return history.SearchPrefix(), true
+			}
+			return renderPrefix(), true
 		}),
 		prompt.OptionMaxSuggestion(shellOptionDefaultMaxSuggestions),
 		prompt.OptionParser(parser),
@@ -219,6 +260,35 @@ func refresh(cmd *cobra.Command, msg []byte) {
 	prmpt.Run()
 }
 
+// renderPrefix renders the prompt prefix, including the active organization and workgroup, if any
+func renderPrefix() string {
+	return strings.NewReplacer(
+		"$VERSION", version,
+		"$SCOPE", activeScope(),
+		"$PATH", fmt.Sprintf("%s%s", path, shellPrefixPrompt),
+	).Replace(shellPrefix)
+}
+
+// activeScope renders the organization and workgroup selected using `prvd use`
+func activeScope() string {
+	orgID, orgName := common.ActiveOrganization()
+	if orgID == "" {
+		return ""
+	}
+	if orgName == "" {
+		orgName = orgID
+	}
+
+	wgID, wgName := common.ActiveWorkgroup()
+	if wgID == "" {
+		return fmt.Sprintf("[%s]", orgName)
+	}
+	if wgName == "" {
+		wgName = wgID
+	}
+	return fmt.Sprintf("[%s/%s]", orgName, wgName)
+}
+
 func installREPL() {
 	repl, _ = NewREPL(func(_wg *sync.WaitGroup) error {
 		renderRootBanner()
@@ -339,11 +409,32 @@ func shellOut(bin string, argv []string, buf *bytes.Buffer) error {
 }
 
 func interpret(cmd *cobra.Command, input string) {
-	if strings.TrimSpace(input) == "" {
+	if history.Searching() {
+		history.SetSearchQuery(input)
+		input = history.SearchMatch()
+		history.StopSearch()
+	}
+
+	input = strings.TrimSpace(input)
+	if input == "" {
 		return
 	}
 
+	expanded, err := history.Expand(input)
+	if err != nil {
+		writer.WriteRaw([]byte(fmt.Sprintf("%s: %s\n", shellTitle, err.Error())))
+		return
+	}
+	if expanded != input {
+		writer.WriteRaw([]byte(fmt.Sprintf("%s\n", expanded)))
+		input = expanded
+	}
+	history.Add(input)
+
 	switch input {
+	case nativeCommandHistory:
+		history.Print()
+		return
 	case sanitizedPromptInputMatchClear:
 		defaultCursorPosition()
 		eraseCursorToEnd()
@@ -358,47 +449,8 @@ func interpret(cmd *cobra.Command, input string) {
 		return
 	}
 
-	argv := strings.Split(strings.TrimSpace(input), " ")
-
-	_cmd, i := resolveChildCmd(cmd, argv)
-	if _cmd != nil {
-		if debug {
-			writer.WriteRaw([]byte(fmt.Sprintf("resolved child command for input: %s; argv[%d]: %v; use: %s", input, i, argv, _cmd.Use)))
-		}
-
-		// // TODO? -- use the following instead of shellOut()
-		// _cmd.SetArgs(argv[i:])
-		// _cmd.SetErr(out)
-		// _cmd.SetOut(out)
-		// _cmd.SetOutput(out)
-		// _cmd.SetIn(os.Stdin)
-		// err := _cmd.RunE(_cmd, argv[i:])
-		// if err != nil {
-		// 	writer.WriteStr(fmt.Sprintf("WARNING: failed to execute; %s", err.Error()))
-		// }
-		// writer.WriteStr(out.String())
-
-		out := &bytes.Buffer{}
-		repl, _ := NewREPLWithCmd(*exec.Command("prvd", argv...), out)
-		repl.run()
-		repls = append(repls, repl)
-		writer.WriteRaw(out.Bytes())
-	} else if supportedNativeCommand(argv) {
-		mutex.Lock()
-		writer.SaveCursor()
-		writer.HideCursor()
-		writer.Flush()
-		mutex.Unlock()
-
-		repl, err := resolveNativeCommand(argv)(argv)
-		repls = append(repls, repl)
-		if err != nil {
-			writer.WriteRaw([]byte(fmt.Sprintf("%s: native command returned err: %s;%s\n", shellTitle, strings.Join(argv, " "), err.Error())))
-		}
-	} else {
-		if len(argv) > 0 {
-			writer.WriteRaw([]byte(fmt.Sprintf("%s: command not found: %s\n", shellTitle, strings.Join(argv, " "))))
-		}
+	if err := evaluate(cmd, interactiveRun, input); err != nil {
+		printf("%s: %s\n", shellTitle, err.Error())
 	}
 }
 
@@ -420,31 +472,12 @@ func promptSuggestionFactory(cmd *cobra.Command, d prompt.Document) []prompt.Sug
 		return nil
 	}
 
-	// var results []prompt.Suggest = nil
-	input := strings.TrimSpace(d.CurrentLine())
-	// argv := strings.Split(input, " ") // this is hardly sanitized -- but it's a start
-
-	// i := len(argv) - 1 // TODO: handle caret position for previous suggest fields...
-	// i now contains offset for arg at cursor position
-
-	// switch argv[i] {
-	// case sanitizedPromptInputMatchRoot:
-	// 	results = childCommandSuggestions
-	// }
-
-	results := make([]prompt.Suggest, 0)
-	for _, result := range childCommandSuggestions {
-		if len(input) > 0 {
-			if strings.HasPrefix(result.Text, input) {
-				results = append(results, result)
-			}
-		} else {
-			// Uncomment the following line to enable suggestions by default
-			// results = append(results, result)
-		}
+	if history.Searching() {
+		history.SetSearchQuery(d.TextBeforeCursor())
+		return history.SearchSuggestions()
 	}
 
-	return results
+	return completionSuggestions(cmd.Root(), cmd, d)
 }
 
 func write(buf []byte, flush bool) error {
@@ -474,3 +507,7 @@ func writeRaw(buf []byte, flush bool) error {
 
 	return nil
 }
+
+func init() {
+	ShellCmd.Flags().StringVarP(&scriptFile, "file", "f", "", "path of a script to execute non-interactively, i.e., onboard.prvd")
+}
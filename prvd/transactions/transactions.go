@@ -0,0 +1,50 @@
+/*
+ * Copyright 2017-2022 Provide Technologies Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package transactions
+
+import (
+	"github.com/provideplatform/provide-cli/prvd/common"
+	"github.com/spf13/cobra"
+)
+
+var optional bool
+var paginate bool
+var openExplorer bool
+
+var TransactionsCmd = &cobra.Command{
+	Use:   "transactions",
+	Short: "Manage transactions",
+	Long:  `Retrieve transactions signed and broadcast on behalf of your applications and organizations`,
+	Run: func(cmd *cobra.Command, args []string) {
+		common.CmdExistsOrExit(cmd, args)
+
+		generalPrompt(cmd, args, "")
+
+		defer func() {
+			if r := recover(); r != nil {
+				common.Exit(1)
+			}
+		}()
+	},
+}
+
+func init() {
+	TransactionsCmd.AddCommand(transactionsListCmd)
+	TransactionsCmd.AddCommand(transactionsDetailsCmd)
+	TransactionsCmd.Flags().BoolVarP(&optional, "optional", "", false, "List all the optional flags")
+	TransactionsCmd.Flags().BoolVarP(&paginate, "paginate", "", false, "List pagination flags")
+}
@@ -0,0 +1,65 @@
+/*
+ * Copyright 2017-2022 Provide Technologies Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package transactions
+
+import (
+	"fmt"
+	"log"
+
+	"github.com/provideplatform/provide-cli/prvd/common"
+	provide "github.com/provideplatform/provide-go/api/nchain"
+
+	"github.com/spf13/cobra"
+)
+
+var transactionsDetailsCmd = &cobra.Command{
+	Use:   "details",
+	Short: "Retrieve a specific transaction",
+	Long:  `Retrieve details for a specific transaction by identifier, scoped to the authorized API token`,
+	Run:   fetchTransactionDetails,
+}
+
+func fetchTransactionDetails(cmd *cobra.Command, args []string) {
+	ctx := common.ContextFromCommand(cmd)
+	token := common.RequireAPIToken(ctx)
+	tx, err := provide.GetTransactionDetails(token, ctx.TransactionID, map[string]interface{}{})
+	if err != nil {
+		log.Printf("Failed to retrieve details for transaction with id: %s; %s", ctx.TransactionID, err.Error())
+		common.Exit(1)
+	}
+	result := fmt.Sprintf("%s\t%s\t%s\n", tx.ID.String(), stringOrEmpty(tx.Hash), stringOrEmpty(tx.Status))
+	fmt.Print(result)
+
+	if tx.Hash == nil {
+		if openExplorer {
+			log.Printf("Transaction with id: %s has not been broadcast", ctx.TransactionID)
+			common.Exit(1)
+		}
+		return
+	}
+
+	common.ViewOnExplorer(token, tx.NetworkID.String(), func(explorer *common.Explorer) string {
+		return explorer.TxURL(*tx.Hash)
+	}, openExplorer)
+}
+
+func init() {
+	transactionsDetailsCmd.Flags().String("transaction", "", "id of the transaction")
+	transactionsDetailsCmd.MarkFlagRequired("transaction")
+
+	transactionsDetailsCmd.Flags().BoolVar(&openExplorer, "open", false, "open the transaction in the block explorer for its network")
+}
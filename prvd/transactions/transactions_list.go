@@ -0,0 +1,88 @@
+/*
+ * Copyright 2017-2022 Provide Technologies Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package transactions
+
+import (
+	"fmt"
+	"log"
+
+	"github.com/provideplatform/provide-cli/prvd/common"
+	provide "github.com/provideplatform/provide-go/api/nchain"
+
+	"github.com/spf13/cobra"
+)
+
+var page uint64
+var rpp uint64
+
+var transactionsListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "Retrieve a list of transactions",
+	Long:  `Retrieve a list of transactions scoped to the authorized API token`,
+	Run:   listTransactions,
+}
+
+func listTransactions(cmd *cobra.Command, args []string) {
+	ctx := common.ContextFromCommand(cmd)
+	token := common.RequireAPIToken(ctx)
+	params := map[string]interface{}{
+		"page": fmt.Sprintf("%d", page),
+		"rpp":  fmt.Sprintf("%d", rpp),
+	}
+	if ctx.ApplicationID != "" {
+		params["application_id"] = ctx.ApplicationID
+	}
+
+	var txs []*provide.Transaction
+	var err error
+	if ctx.NetworkID != "" {
+		txs, err = provide.ListNetworkTransactions(token, ctx.NetworkID, params)
+	} else {
+		txs, err = provide.ListTransactions(token, params)
+	}
+	if err != nil {
+		log.Printf("Failed to retrieve transactions list; %s", err.Error())
+		common.Exit(1)
+	}
+
+	for i := range txs {
+		tx := txs[i]
+		result := fmt.Sprintf("%s\t%s\t%s", tx.ID.String(), stringOrEmpty(tx.Hash), stringOrEmpty(tx.Status))
+		if tx.Hash != nil {
+			if explorer := common.ResolveExplorer(token, tx.NetworkID.String()); explorer != nil {
+				result = fmt.Sprintf("%s\t%s", result, explorer.TxURL(*tx.Hash))
+			}
+		}
+		fmt.Println(result)
+	}
+}
+
+func stringOrEmpty(str *string) string {
+	if str == nil {
+		return ""
+	}
+	return *str
+}
+
+func init() {
+	transactionsListCmd.Flags().String("application", "", "application identifier to filter transactions")
+	transactionsListCmd.Flags().String("network", "", "network identifier to filter transactions")
+	transactionsListCmd.Flags().BoolVarP(&optional, "optional", "", false, "List all the optional flags")
+	transactionsListCmd.Flags().BoolVarP(&paginate, "paginate", "", false, "List pagination flags")
+	transactionsListCmd.Flags().Uint64Var(&page, "page", common.DefaultPage, "page number to retrieve")
+	transactionsListCmd.Flags().Uint64Var(&rpp, "rpp", common.DefaultRpp, "number of transactions to retrieve per page")
+}
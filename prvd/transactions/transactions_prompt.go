@@ -0,0 +1,52 @@
+/*
+ * Copyright 2017-2022 Provide Technologies Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package transactions
+
+import (
+	"fmt"
+
+	"github.com/provideplatform/provide-cli/prvd/common"
+	"github.com/spf13/cobra"
+)
+
+const promptStepDetails = "Details"
+const promptStepList = "List"
+
+var emptyPromptArgs = []string{promptStepList, promptStepDetails}
+var emptyPromptLabel = "What would you like to do"
+
+// General Endpoints
+func generalPrompt(cmd *cobra.Command, args []string, currentStep string) {
+	ctx := common.ContextFromCommand(cmd)
+	switch step := currentStep; step {
+	case promptStepDetails:
+		if ctx.TransactionID == "" {
+			ctx.TransactionID = common.FreeInput("Transaction ID", "", common.MandatoryValidation)
+		}
+		fetchTransactionDetails(cmd, args)
+	case promptStepList:
+		if optional {
+			fmt.Println("Optional Flags:")
+			common.RequireApplication(ctx)
+		}
+		page, rpp = common.PromptPagination(paginate, page, rpp)
+		listTransactions(cmd, args)
+	case "":
+		result := common.SelectInput(emptyPromptArgs, emptyPromptLabel)
+		generalPrompt(cmd, args, result)
+	}
+}
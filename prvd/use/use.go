@@ -0,0 +1,69 @@
+/*
+ * Copyright 2017-2022 Provide Technologies Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package use
+
+import (
+	"fmt"
+
+	"github.com/provideplatform/provide-cli/prvd/common"
+	"github.com/spf13/cobra"
+)
+
+// selectionRpp is the number of organizations or workgroups retrieved per page when resolving a selection
+const selectionRpp = 100
+
+var clearSelection bool
+
+var UseCmd = &cobra.Command{
+	Use:   "use",
+	Short: "Select the default organization and workgroup",
+	Long: `Select the organization and workgroup used by default when the --organization and --workgroup flags are not provided.
+
+The selection is persisted in the active configuration and is consulted before prompting.
+Run without a subcommand to print the current selection.`,
+	Run: printActiveSelection,
+}
+
+func printActiveSelection(cmd *cobra.Command, args []string) {
+	orgID, orgName := common.ActiveOrganization()
+	wgID, wgName := common.ActiveWorkgroup()
+
+	fmt.Printf("organization:\t%s\n", selectionOrNone(orgID, orgName))
+	fmt.Printf("workgroup:\t%s\n", selectionOrNone(wgID, wgName))
+}
+
+func selectionOrNone(id, name string) string {
+	if id == "" {
+		return "(none)"
+	}
+	if name == "" {
+		return id
+	}
+	return fmt.Sprintf("%s\t%s", id, name)
+}
+
+func stringOrEmpty(val *string) string {
+	if val == nil {
+		return ""
+	}
+	return *val
+}
+
+func init() {
+	UseCmd.AddCommand(useOrganizationCmd)
+	UseCmd.AddCommand(useWorkgroupCmd)
+}
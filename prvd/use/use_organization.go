@@ -0,0 +1,104 @@
+/*
+ * Copyright 2017-2022 Provide Technologies Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package use
+
+import (
+	"fmt"
+	"log"
+	"strings"
+
+	"github.com/provideplatform/provide-cli/prvd/common"
+	"github.com/provideplatform/provide-go/api/ident"
+	"github.com/spf13/cobra"
+)
+
+var useOrganizationCmd = &cobra.Command{
+	Use:   "organization <id|name>",
+	Short: "Select the default organization",
+	Long:  `Select the organization, by identifier or name, used by default when the --organization flag is not provided`,
+	Args:  cobra.MaximumNArgs(1),
+	Run:   useOrganization,
+}
+
+func useOrganization(cmd *cobra.Command, args []string) {
+	if clearSelection {
+		if err := common.SetActiveOrganization("", ""); err != nil {
+			log.Printf("failed to clear active organization; %s", err.Error())
+			common.Exit(1)
+		}
+		fmt.Println("Cleared active organization")
+		return
+	}
+
+	if len(args) == 0 {
+		printActiveSelection(cmd, args)
+		return
+	}
+
+	ctx := common.ContextFromCommand(cmd)
+	orgs, err := listOrganizations(common.RequireUserAccessToken(ctx))
+	if err != nil {
+		log.Printf("failed to retrieve organizations; %s", err.Error())
+		common.Exit(1)
+	}
+
+	var match *ident.Organization
+	for _, org := range orgs {
+		if (org.ID != nil && *org.ID == args[0]) || (org.Name != nil && strings.EqualFold(*org.Name, args[0])) {
+			if match != nil {
+				log.Printf("organization name is ambiguous: %s; use the organization id instead", args[0])
+				common.Exit(1)
+			}
+			match = org
+		}
+	}
+
+	if match == nil {
+		log.Printf("organization not found: %s", args[0])
+		common.Exit(1)
+	}
+
+	if err := common.SetActiveOrganization(*match.ID, stringOrEmpty(match.Name)); err != nil {
+		log.Printf("failed to persist active organization; %s", err.Error())
+		common.Exit(1)
+	}
+
+	fmt.Printf("Using organization %s\n", selectionOrNone(*match.ID, stringOrEmpty(match.Name)))
+}
+
+// listOrganizations retrieves every page of the organizations of the authorized user
+func listOrganizations(token string) ([]*ident.Organization, error) {
+	orgs := make([]*ident.Organization, 0)
+	for page := common.DefaultPage; ; page++ {
+		results, err := ident.ListOrganizations(token, map[string]interface{}{
+			"page": fmt.Sprintf("%d", page),
+			"rpp":  fmt.Sprintf("%d", selectionRpp),
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		orgs = append(orgs, results...)
+		if len(results) < selectionRpp {
+			return orgs, nil
+		}
+	}
+}
+
+func init() {
+	useOrganizationCmd.Flags().BoolVar(&clearSelection, "clear", false, "clear the active organization and workgroup")
+}
@@ -0,0 +1,113 @@
+/*
+ * Copyright 2017-2022 Provide Technologies Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package use
+
+import (
+	"fmt"
+	"log"
+	"strings"
+
+	"github.com/provideplatform/provide-cli/prvd/common"
+	"github.com/provideplatform/provide-go/api/axiom"
+	"github.com/spf13/cobra"
+)
+
+var useWorkgroupCmd = &cobra.Command{
+	Use:   "workgroup <id|name>",
+	Short: "Select the default workgroup",
+	Long: `Select the workgroup, by identifier or name, used by default when the --workgroup flag is not provided.
+
+The workgroup is resolved within the active organization unless --organization is provided.`,
+	Args: cobra.MaximumNArgs(1),
+	Run:  useWorkgroup,
+}
+
+func useWorkgroup(cmd *cobra.Command, args []string) {
+	if clearSelection {
+		if err := common.SetActiveWorkgroup("", "", ""); err != nil {
+			log.Printf("failed to clear active workgroup; %s", err.Error())
+			common.Exit(1)
+		}
+		fmt.Println("Cleared active workgroup")
+		return
+	}
+
+	if len(args) == 0 {
+		printActiveSelection(cmd, args)
+		return
+	}
+
+	ctx := common.ContextFromCommand(cmd)
+	token, err := common.ResolveOrganizationToken(ctx)
+	if err != nil {
+		log.Printf("failed to resolve organization token; %s", err.Error())
+		common.Exit(1)
+	}
+
+	workgroups, err := listWorkgroups(*token.AccessToken)
+	if err != nil {
+		log.Printf("failed to retrieve workgroups; %s", err.Error())
+		common.Exit(1)
+	}
+
+	var match *axiom.Workgroup
+	for _, wg := range workgroups {
+		if wg.ID.String() == args[0] || (wg.Name != nil && strings.EqualFold(*wg.Name, args[0])) {
+			if match != nil {
+				log.Printf("workgroup name is ambiguous: %s; use the workgroup id instead", args[0])
+				common.Exit(1)
+			}
+			match = wg
+		}
+	}
+
+	if match == nil {
+		log.Printf("workgroup not found in organization %s: %s", ctx.OrganizationID, args[0])
+		common.Exit(1)
+	}
+
+	if err := common.SetActiveWorkgroup(match.ID.String(), stringOrEmpty(match.Name), ctx.OrganizationID); err != nil {
+		log.Printf("failed to persist active workgroup; %s", err.Error())
+		common.Exit(1)
+	}
+
+	fmt.Printf("Using workgroup %s\n", selectionOrNone(match.ID.String(), stringOrEmpty(match.Name)))
+}
+
+// listWorkgroups retrieves every page of the workgroups of the authorized organization
+func listWorkgroups(token string) ([]*axiom.Workgroup, error) {
+	workgroups := make([]*axiom.Workgroup, 0)
+	for page := common.DefaultPage; ; page++ {
+		results, err := axiom.ListWorkgroups(token, map[string]interface{}{
+			"page": fmt.Sprintf("%d", page),
+			"rpp":  fmt.Sprintf("%d", selectionRpp),
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		workgroups = append(workgroups, results...)
+		if len(results) < selectionRpp {
+			return workgroups, nil
+		}
+	}
+}
+
+func init() {
+	useWorkgroupCmd.Flags().String("organization", "", "organization identifier")
+	useWorkgroupCmd.Flags().BoolVar(&clearSelection, "clear", false, "clear the active workgroup")
+}
@@ -18,7 +18,6 @@ package users
 
 import (
 	"log"
-	"os"
 
 	"github.com/provideplatform/provide-cli/prvd/common"
 	provide "github.com/provideplatform/provide-go/api/ident"
@@ -33,7 +32,8 @@ var AuthenticateCmd = &cobra.Command{
 	Short: "Authenticate using your credentials",
 	Long: `Authenticate using user credentials and receive a
 valid access/refresh token pair which can be used to make API calls.`,
-	Run: authenticate,
+	Run:         authenticate,
+	Annotations: map[string]string{common.InteractiveAnnotation: "true"},
 }
 
 func authenticate(cmd *cobra.Command, args []string) {
@@ -43,7 +43,7 @@ func authenticate(cmd *cobra.Command, args []string) {
 	resp, err := provide.Authenticate(email, passwd)
 	if err != nil {
 		log.Println(err)
-		os.Exit(1)
+		common.Exit(1)
 	}
 
 	if resp.Token.AccessToken != nil && resp.Token.RefreshToken != nil {
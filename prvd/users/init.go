@@ -19,7 +19,6 @@ package users
 import (
 	"fmt"
 	"log"
-	"os"
 
 	"github.com/provideplatform/provide-cli/prvd/common"
 	provide "github.com/provideplatform/provide-go/api/ident"
@@ -29,10 +28,11 @@ import (
 
 // initCmd creates a new user
 var initCmd = &cobra.Command{
-	Use:   "init",
-	Short: "Create a new user",
-	Long:  `Create a new user in the configured ident instance; defaults to ident.provide.services.`,
-	Run:   create,
+	Use:         "init",
+	Short:       "Create a new user",
+	Long:        `Create a new user in the configured ident instance; defaults to ident.provide.services.`,
+	Run:         create,
+	Annotations: map[string]string{common.InteractiveAnnotation: "true"},
 }
 
 var firstName string
@@ -54,13 +54,13 @@ func create(cmd *cobra.Command, args []string) {
 	})
 	if err != nil {
 		log.Println(err)
-		os.Exit(1)
+		common.Exit(1)
 	}
 
 	_, err = provide.Authenticate(email, passwd)
 	if err != nil {
 		log.Println(err)
-		os.Exit(1)
+		common.Exit(1)
 	}
 
 	fmt.Printf("created user: %s", *resp.ID)
@@ -17,8 +17,6 @@
 package users
 
 import (
-	"os"
-
 	"github.com/provideplatform/provide-cli/prvd/common"
 	"github.com/spf13/cobra"
 )
@@ -33,12 +31,21 @@ var UsersCmd = &cobra.Command{
 		generalPrompt(cmd, args, "")
 		defer func() {
 			if r := recover(); r != nil {
-				os.Exit(1)
+				common.Exit(1)
 			}
 		}()
 	},
 }
 
+// ResetState restores the package-level state assigned by prompts, which is not bound to a flag;
+// the shell calls this prior to each in-process invocation
+func ResetState() {
+	firstName = ""
+	lastName = ""
+	email = ""
+	passwd = ""
+}
+
 func init() {
 	UsersCmd.AddCommand(initCmd)
 }
@@ -17,11 +17,28 @@
 package keys
 
 import (
-	"os"
-
+	"github.com/provideplatform/provide-cli/prvd/common"
+	vault "github.com/provideplatform/provide-go/api/vault"
 	"github.com/spf13/cobra"
 )
 
+// keySpecs are the key specs supported by vault
+var keySpecs = []string{
+	vault.KeySpecAES256GCM,
+	vault.KeySpecChaCha20,
+	vault.KeySpecECCBabyJubJub,
+	vault.KeySpecECCBIP39,
+	vault.KeySpecECCC25519,
+	vault.KeySpecECCEd25519,
+	vault.KeySpecECCSecp256k1,
+	vault.KeySpecRSA2048,
+	vault.KeySpecRSA3072,
+	vault.KeySpecRSA4096,
+}
+
+var keyTypes = []string{vault.KeyTypeSymmetric, vault.KeyTypeAsymmetric}
+var keyUsages = []string{vault.KeyUsageEncryptDecrypt, vault.KeyUsageSignVerify}
+
 var KeysCmd = &cobra.Command{
 	Use:   "keys",
 	Short: "Manage keys",
@@ -35,12 +52,18 @@ Docs: https://docs.provide.services/vault/api-reference/keys`,
 
 		defer func() {
 			if r := recover(); r != nil {
-				os.Exit(1)
+				common.Exit(1)
 			}
 		}()
 	},
 }
 
+// ResetState restores the package-level state assigned by prompts, which is not bound to a flag;
+// the shell calls this prior to each in-process invocation
+func ResetState() {
+	promptArgs = nil
+}
+
 func init() {
 	KeysCmd.AddCommand(keysListCmd)
 	KeysCmd.AddCommand(keysInitCmd)
@@ -19,7 +19,6 @@ package keys
 import (
 	"fmt"
 	"log"
-	"os"
 
 	"github.com/provideplatform/provide-cli/prvd/common"
 	vault "github.com/provideplatform/provide-go/api/vault"
@@ -46,7 +45,8 @@ func createKey(cmd *cobra.Command, args []string) {
 }
 
 func createKeyRun(cmd *cobra.Command, args []string) {
-	token := common.RequireAPIToken()
+	ctx := common.ContextFromCommand(cmd)
+	token := common.RequireAPIToken(ctx)
 	params := map[string]interface{}{
 		"name":        name,
 		"description": description,
@@ -54,10 +54,10 @@ func createKeyRun(cmd *cobra.Command, args []string) {
 		"type":        keytype,
 		"usage":       keyusage,
 	}
-	vlt, err := vault.CreateKey(token, common.VaultID, params)
+	vlt, err := vault.CreateKey(token, ctx.VaultID, params)
 	if err != nil {
-		log.Printf("failed to create key in vault: %s; %s", common.VaultID, err.Error())
-		os.Exit(1)
+		log.Printf("failed to create key in vault: %s; %s", ctx.VaultID, err.Error())
+		common.Exit(1)
 	}
 	result := fmt.Sprintf("%s\t%s\t%s\n", vlt.ID.String(), *vlt.Name, *vlt.Description)
 	fmt.Print(result)
@@ -69,7 +69,10 @@ func init() {
 	keysInitCmd.Flags().StringVar(&keyspec, "spec", "", "key spec to use for the key")
 	keysInitCmd.Flags().StringVar(&keytype, "type", "", "key type; must be symmetric or asymmetric")
 	keysInitCmd.Flags().StringVar(&keyusage, "usage", "", "intended usage for the key; must be encrypt/decrypt or sign/verify")
+	common.SetFlagValues(keysInitCmd, "spec", keySpecs...)
+	common.SetFlagValues(keysInitCmd, "type", keyTypes...)
+	common.SetFlagValues(keysInitCmd, "usage", keyUsages...)
 
-	keysInitCmd.Flags().StringVar(&common.ApplicationID, "application", "", "application identifier for which the key will be created")
-	keysInitCmd.Flags().StringVar(&common.OrganizationID, "organization", "", "organization identifier for which the key will be created")
+	keysInitCmd.Flags().String("application", "", "application identifier for which the key will be created")
+	keysInitCmd.Flags().String("organization", "", "organization identifier for which the key will be created")
 }
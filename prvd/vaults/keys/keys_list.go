@@ -19,7 +19,6 @@ package keys
 import (
 	"fmt"
 	"log"
-	"os"
 
 	"github.com/provideplatform/provide-cli/prvd/common"
 	vault "github.com/provideplatform/provide-go/api/vault"
@@ -42,21 +41,22 @@ func listKeys(cmd *cobra.Command, args []string) {
 }
 
 func listKeysRun(cmd *cobra.Command, args []string) {
-	token := common.RequireAPIToken()
+	ctx := common.ContextFromCommand(cmd)
+	token := common.RequireAPIToken(ctx)
 	params := map[string]interface{}{
 		"page": fmt.Sprintf("%d", page),
 		"rpp":  fmt.Sprintf("%d", rpp),
 	}
-	if common.ApplicationID != "" {
-		params["application_id"] = common.ApplicationID
+	if ctx.ApplicationID != "" {
+		params["application_id"] = ctx.ApplicationID
 	}
-	if common.OrganizationID != "" {
-		params["organization_id"] = common.OrganizationID
+	if ctx.OrganizationID != "" {
+		params["organization_id"] = ctx.OrganizationID
 	}
-	resp, err := vault.ListKeys(token, common.VaultID, params)
+	resp, err := vault.ListKeys(token, ctx.VaultID, params)
 	if err != nil {
 		log.Printf("failed to retrieve keys list; %s", err.Error())
-		os.Exit(1)
+		common.Exit(1)
 	}
 	for i := range resp {
 		vlt := resp[i]
@@ -66,13 +66,16 @@ func listKeysRun(cmd *cobra.Command, args []string) {
 }
 
 func init() {
-	keysListCmd.Flags().StringVar(&common.ApplicationID, "application", "", "application identifier to filter keys")
-	keysListCmd.Flags().StringVar(&common.OrganizationID, "organization", "", "organization identifier to filter keys")
-	keysListCmd.Flags().StringVar(&common.VaultID, "vault", "", "identifier of the vault")
+	keysListCmd.Flags().String("application", "", "application identifier to filter keys")
+	keysListCmd.Flags().String("organization", "", "organization identifier to filter keys")
+	keysListCmd.Flags().String("vault", "", "identifier of the vault")
 
 	keysListCmd.Flags().StringVar(&keyspec, "spec", "", "key spec query; non-matching keys are filtered")
 	keysListCmd.Flags().StringVar(&keytype, "type", "", "key type query; non-matching keys are filtered")
 	keysListCmd.Flags().StringVar(&keyusage, "usage", "", "key usage query; non-matching keys are filtered")
+	common.SetFlagValues(keysListCmd, "spec", keySpecs...)
+	common.SetFlagValues(keysListCmd, "type", keyTypes...)
+	common.SetFlagValues(keysListCmd, "usage", keyUsages...)
 
 	keysListCmd.Flags().BoolVarP(&paginate, "paginate", "", false, "List pagination flags")
 	keysListCmd.Flags().Uint64Var(&page, "page", common.DefaultPage, "page number to retrieve")
@@ -18,7 +18,6 @@ package keys
 
 import (
 	"fmt"
-	"os"
 
 	"github.com/manifoldco/promptui"
 	"github.com/provideplatform/provide-cli/prvd/common"
@@ -35,8 +34,9 @@ const promptStepList = "List"
 
 // General Endpoints
 func generalPrompt(cmd *cobra.Command, args []string, currentStep string) {
-	if common.VaultID == "" {
-		common.RequireVault()
+	ctx := common.ContextFromCommand(cmd)
+	if ctx.VaultID == "" {
+		common.RequireVault(ctx)
 	}
 
 	switch step := currentStep; step {
@@ -61,7 +61,7 @@ func emptyPrompt(cmd *cobra.Command, args []string) {
 	_, result, err := prompt.Run()
 
 	if err != nil {
-		os.Exit(1)
+		common.Exit(1)
 		return
 	}
 
@@ -79,7 +79,7 @@ func flagPrompt(cmd *cobra.Command, args []string) bool {
 	_, flagResult, err := flagPrompt.Run()
 
 	if err != nil {
-		os.Exit(1)
+		common.Exit(1)
 		return false
 	}
 
@@ -87,11 +87,12 @@ func flagPrompt(cmd *cobra.Command, args []string) bool {
 }
 
 func promptInit(cmd *cobra.Command, args []string) {
-	if common.ApplicationID == "" {
-		applicationIDFlagPrompt()
+	ctx := common.ContextFromCommand(cmd)
+	if ctx.ApplicationID == "" {
+		applicationIDFlagPrompt(ctx)
 	}
-	if common.OrganizationID == "" {
-		organizationidFlagPrompt()
+	if ctx.OrganizationID == "" {
+		organizationidFlagPrompt(ctx)
 	}
 	if keytype == "" {
 		keyTypePrompt()
@@ -111,22 +112,24 @@ func promptInit(cmd *cobra.Command, args []string) {
 }
 
 func promptList(cmd *cobra.Command, args []string) {
-	if common.ApplicationID == "" {
-		applicationIDFlagPrompt()
+	ctx := common.ContextFromCommand(cmd)
+	if ctx.ApplicationID == "" {
+		applicationIDFlagPrompt(ctx)
 	}
-	if common.OrganizationID == "" {
-		organizationidFlagPrompt()
+	if ctx.OrganizationID == "" {
+		organizationidFlagPrompt(ctx)
 	}
 	page, rpp = common.PromptPagination(paginate, page, rpp)
 }
 
 func optionalFlagsList(cmd *cobra.Command, args []string) {
+	ctx := common.ContextFromCommand(cmd)
 	fmt.Println("Optional Flags:")
-	if common.ApplicationID == "" {
-		applicationIDFlagPrompt()
+	if ctx.ApplicationID == "" {
+		applicationIDFlagPrompt(ctx)
 	}
-	if common.OrganizationID == "" {
-		applicationIDFlagPrompt()
+	if ctx.OrganizationID == "" {
+		applicationIDFlagPrompt(ctx)
 	}
 }
 
@@ -144,7 +147,7 @@ func nameFlagPrompt() {
 	result, err := prompt.Run()
 
 	if err != nil {
-		os.Exit(1)
+		common.Exit(1)
 		return
 	}
 
@@ -164,7 +167,7 @@ func descriptionFlagPrompt() {
 	result, err := prompt.Run()
 
 	if err != nil {
-		os.Exit(1)
+		common.Exit(1)
 		return
 	}
 
@@ -205,7 +208,7 @@ func keyTypePrompt() {
 
 	_, result, err := prompt.Run()
 	if err != nil {
-		os.Exit(1)
+		common.Exit(1)
 		return
 	}
 
@@ -220,7 +223,7 @@ func keyUsagePrompt() {
 
 	_, result, err := prompt.Run()
 	if err != nil {
-		os.Exit(1)
+		common.Exit(1)
 		return
 	}
 
@@ -228,10 +231,10 @@ func keyUsagePrompt() {
 }
 
 // Optional Flag For List Keys
-func applicationIDFlagPrompt() {
-	common.RequireApplication()
+func applicationIDFlagPrompt(ctx *common.Context) {
+	common.RequireApplication(ctx)
 }
 
-func organizationidFlagPrompt() {
-	common.RequireOrganization()
+func organizationidFlagPrompt(ctx *common.Context) {
+	common.RequireOrganization(ctx)
 }
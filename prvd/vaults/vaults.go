@@ -17,8 +17,6 @@
 package vaults
 
 import (
-	"os"
-
 	"github.com/spf13/cobra"
 
 	"github.com/provideplatform/provide-cli/prvd/common"
@@ -40,7 +38,7 @@ Docs: https://docs.provide.services/vault`,
 
 		defer func() {
 			if r := recover(); r != nil {
-				os.Exit(1)
+				common.Exit(1)
 			}
 		}()
 	},
@@ -19,7 +19,6 @@ package vaults
 import (
 	"fmt"
 	"log"
-	"os"
 
 	"github.com/provideplatform/provide-cli/prvd/common"
 	provide "github.com/provideplatform/provide-go/api/vault"
@@ -44,8 +43,9 @@ func createVault(cmd *cobra.Command, args []string) {
 }
 
 func createVaultRun(cmd *cobra.Command, args []string) {
+	ctx := common.ContextFromCommand(cmd)
 
-	token := common.RequireAPIToken()
+	token := common.RequireAPIToken(ctx)
 	params := map[string]interface{}{
 		"name":        name,
 		"description": description,
@@ -53,7 +53,7 @@ func createVaultRun(cmd *cobra.Command, args []string) {
 	vlt, err := provide.CreateVault(token, params)
 	if err != nil {
 		log.Printf("Failed to genereate HD wallet; %s", err.Error())
-		os.Exit(1)
+		common.Exit(1)
 	}
 	result := fmt.Sprintf("%s\t%s\t%s\n", vlt.ID.String(), *vlt.Name, *vlt.Description)
 	fmt.Print(result)
@@ -63,7 +63,7 @@ func init() {
 	vaultsInitCmd.Flags().StringVar(&name, "name", "", "name of the vault")
 	vaultsInitCmd.Flags().StringVar(&description, "description", "", "description of the vault")
 
-	vaultsInitCmd.Flags().StringVar(&common.ApplicationID, "application", "", "application identifier for which the vault will be created")
-	vaultsInitCmd.Flags().StringVar(&common.OrganizationID, "organization", "", "organization identifier for which the vault will be created")
+	vaultsInitCmd.Flags().String("application", "", "application identifier for which the vault will be created")
+	vaultsInitCmd.Flags().String("organization", "", "organization identifier for which the vault will be created")
 	vaultsInitCmd.Flags().BoolVarP(&optional, "optional", "", false, "List all the optional flags")
 }
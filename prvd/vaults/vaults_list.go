@@ -19,7 +19,6 @@ package vaults
 import (
 	"fmt"
 	"log"
-	"os"
 
 	"github.com/provideplatform/provide-cli/prvd/common"
 	provide "github.com/provideplatform/provide-go/api/vault"
@@ -42,21 +41,22 @@ func listVaults(cmd *cobra.Command, args []string) {
 }
 
 func listVaultsRun(cmd *cobra.Command, args []string) {
-	token := common.RequireAPIToken()
+	ctx := common.ContextFromCommand(cmd)
+	token := common.RequireAPIToken(ctx)
 	params := map[string]interface{}{
 		"page": fmt.Sprintf("%d", page),
 		"rpp":  fmt.Sprintf("%d", rpp),
 	}
-	if common.ApplicationID != "" {
-		params["application_id"] = common.ApplicationID
+	if ctx.ApplicationID != "" {
+		params["application_id"] = ctx.ApplicationID
 	}
-	if common.OrganizationID != "" {
-		params["organization_id"] = common.OrganizationID
+	if ctx.OrganizationID != "" {
+		params["organization_id"] = ctx.OrganizationID
 	}
 	resp, err := provide.ListVaults(token, params)
 	if err != nil {
 		log.Printf("failed to retrieve vaults list; %s", err.Error())
-		os.Exit(1)
+		common.Exit(1)
 	}
 	for i := range resp {
 		vlt := resp[i]
@@ -66,8 +66,8 @@ func listVaultsRun(cmd *cobra.Command, args []string) {
 }
 
 func init() {
-	vaultsListCmd.Flags().StringVar(&common.ApplicationID, "application", "", "application identifier to filter vaults")
-	vaultsListCmd.Flags().StringVar(&common.OrganizationID, "organization", "", "organization identifier to filter vaults")
+	vaultsListCmd.Flags().String("application", "", "application identifier to filter vaults")
+	vaultsListCmd.Flags().String("organization", "", "organization identifier to filter vaults")
 	vaultsListCmd.Flags().BoolVarP(&optional, "optional", "", false, "List all the optional flags")
 	vaultsListCmd.Flags().BoolVarP(&paginate, "paginate", "", false, "List pagination flags")
 	vaultsListCmd.Flags().Uint64Var(&page, "page", common.DefaultPage, "page number to retrieve")
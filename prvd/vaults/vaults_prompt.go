@@ -31,6 +31,7 @@ var emptyPromptLabel = "What would you like to do"
 
 // General Endpoints
 func generalPrompt(cmd *cobra.Command, args []string, currentStep string) {
+	ctx := common.ContextFromCommand(cmd)
 	switch step := currentStep; step {
 	case promptStepInit:
 		if name == "" {
@@ -41,22 +42,22 @@ func generalPrompt(cmd *cobra.Command, args []string, currentStep string) {
 			if description == "" {
 				description = common.FreeInput("Vault Description", "", common.NoValidation)
 			}
-			if common.ApplicationID == "" {
-				common.RequireApplication()
+			if ctx.ApplicationID == "" {
+				common.RequireApplication(ctx)
 			}
-			if common.OrganizationID == "" {
-				common.RequireOrganization()
+			if ctx.OrganizationID == "" {
+				common.RequireOrganization(ctx)
 			}
 		}
 		createVaultRun(cmd, args)
 	case promptStepList:
 		if optional {
 			fmt.Println("Optional Flags:")
-			if common.ApplicationID == "" {
-				common.RequireApplication()
+			if ctx.ApplicationID == "" {
+				common.RequireApplication(ctx)
 			}
-			if common.OrganizationID == "" {
-				common.RequireOrganization()
+			if ctx.OrganizationID == "" {
+				common.RequireOrganization(ctx)
 			}
 		}
 		page, rpp = common.PromptPagination(paginate, page, rpp)
@@ -0,0 +1,89 @@
+/*
+ * Copyright 2017-2022 Provide Technologies Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package version
+
+import (
+	"fmt"
+
+	"github.com/provideplatform/provide-cli/prvd/common"
+	"github.com/spf13/cobra"
+)
+
+var check bool
+
+var VersionCmd = &cobra.Command{
+	Use:   "version",
+	Short: "Print version information",
+	Long: `Print the version, git commit and build date of the Provide CLI.
+
+When run from the root of a Provide release, the release manifest version is also printed.
+Run with --check to query the status endpoint of each configured API service and warn
+about incompatible major versions; API services are versioned independently of the CLI,
+so only the versions pinned by the release manifest are enforced.`,
+	Run: printVersion,
+}
+
+func printVersion(cmd *cobra.Command, args []string) {
+	fmt.Printf("prvd %s\n", common.Version)
+	fmt.Printf("git commit:\t%s\n", common.GitCommit)
+	fmt.Printf("build date:\t%s\n", common.BuildDate)
+
+	if common.IsReleaseContext() {
+		fmt.Printf("release:\t%s %s\n", common.Manifest.Name, common.Manifest.Version)
+	}
+
+	if check {
+		if !checkServiceCompatibility() {
+			common.Exit(1)
+		}
+	}
+}
+
+// checkServiceCompatibility prints the status of each configured API service;
+// returns false if any service is unreachable or incompatible
+func checkServiceCompatibility() bool {
+	compatible := true
+
+	fmt.Println()
+	for _, svc := range common.ConfiguredAPIServices() {
+		status := svc.FetchStatus()
+		if status.Err != nil {
+			fmt.Printf("WARNING: %s\t%s\tunreachable; %s\n", svc.Name, svc.StatusURL(), status.Err.Error())
+			compatible = false
+			continue
+		}
+
+		version := "unknown"
+		if status.Version != nil {
+			version = *status.Version
+		}
+
+		if err := status.CheckExpectedVersion(); err != nil {
+			fmt.Printf("WARNING: %s\t%s\t%s\n", svc.Name, svc.StatusURL(), err.Error())
+			compatible = false
+			continue
+		}
+
+		fmt.Printf("%s\t%s\t%s\n", svc.Name, svc.StatusURL(), version)
+	}
+
+	return compatible
+}
+
+func init() {
+	VersionCmd.Flags().BoolVar(&check, "check", false, "when true, the status endpoint of each configured API service is queried for compatibility")
+}
@@ -20,7 +20,6 @@ import (
 	"encoding/hex"
 	"fmt"
 	"log"
-	"os"
 
 	"github.com/provideplatform/provide-cli/prvd/common"
 	provide "github.com/provideplatform/provide-go/api/nchain"
@@ -59,20 +58,21 @@ func createNonCustodialWallet() {
 	publicKey, privateKey, err := providecrypto.EVMGenerateKeyPair()
 	if err != nil {
 		log.Printf("Failed to genereate non-custodial HD wallet; %s", err.Error())
-		os.Exit(1)
+		common.Exit(1)
 	}
 	secret := hex.EncodeToString(providecrypto.FromECDSA(privateKey))
 	walletJSON, err := providecrypto.EVMMarshalEncryptedKey(providecrypto.HexToAddress(*publicKey), privateKey, secret)
 	if err != nil {
 		log.Printf("Failed to genereate non-custodial HD wallet; %s", err.Error())
-		os.Exit(1)
+		common.Exit(1)
 	}
 	result := fmt.Sprintf("%s\t%s\n", *publicKey, string(walletJSON))
 	fmt.Print(result)
 }
 
 func createCustodialWallet(cmd *cobra.Command, args []string) {
-	token := common.RequireAPIToken()
+	ctx := common.ContextFromCommand(cmd)
+	token := common.RequireAPIToken(ctx)
 	params := map[string]interface{}{
 		"purpose": purpose,
 	}
@@ -80,19 +80,19 @@ func createCustodialWallet(cmd *cobra.Command, args []string) {
 	wallet, err := provide.CreateWallet(token, params)
 	if err != nil {
 		log.Printf("Failed to genereate custodial HD wallet; %s", err.Error())
-		os.Exit(1)
+		common.Exit(1)
 	}
-	common.WalletID = wallet.ID.String()
+	ctx.WalletID = wallet.ID.String()
 	result := fmt.Sprintf("Wallet %s\t%s\n", wallet.ID.String(), *wallet.PublicKey)
 
-	if common.ApplicationID != "" {
-		appWalletKey := common.BuildConfigKeyWithID(common.WalletConfigKeyPartial, common.ApplicationID)
+	if ctx.ApplicationID != "" {
+		appWalletKey := common.BuildConfigKeyWithID(common.WalletConfigKeyPartial, ctx.ApplicationID)
 		if !viper.IsSet(appWalletKey) {
 			viper.Set(appWalletKey, wallet.ID.String())
 			viper.WriteConfig()
 		}
-	} else if common.OrganizationID != "" {
-		orgWalletKey := common.BuildConfigKeyWithID(common.WalletConfigKeyPartial, common.OrganizationID)
+	} else if ctx.OrganizationID != "" {
+		orgWalletKey := common.BuildConfigKeyWithID(common.WalletConfigKeyPartial, ctx.OrganizationID)
 		if !viper.IsSet(orgWalletKey) {
 			viper.Set(orgWalletKey, wallet.ID.String())
 			viper.WriteConfig()
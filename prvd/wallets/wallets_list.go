@@ -19,7 +19,6 @@ package wallets
 import (
 	"fmt"
 	"log"
-	"os"
 
 	"github.com/provideplatform/provide-cli/prvd/common"
 	provide "github.com/provideplatform/provide-go/api/nchain"
@@ -42,18 +41,19 @@ func listWallets(cmd *cobra.Command, args []string) {
 }
 
 func listWalletsRun(cmd *cobra.Command, args []string) {
-	token := common.RequireAPIToken()
+	ctx := common.ContextFromCommand(cmd)
+	token := common.RequireAPIToken(ctx)
 	params := map[string]interface{}{
 		"page": fmt.Sprintf("%d", page),
 		"rpp":  fmt.Sprintf("%d", rpp),
 	}
-	if common.ApplicationID != "" {
-		params["application_id"] = common.ApplicationID
+	if ctx.ApplicationID != "" {
+		params["application_id"] = ctx.ApplicationID
 	}
 	resp, err := provide.ListWallets(token, params)
 	if err != nil {
 		log.Printf("Failed to retrieve wallets list; %s", err.Error())
-		os.Exit(1)
+		common.Exit(1)
 	}
 	for i := range resp {
 		wallet := resp[i]
@@ -64,7 +64,7 @@ func listWalletsRun(cmd *cobra.Command, args []string) {
 }
 
 func init() {
-	walletsListCmd.Flags().StringVar(&common.ApplicationID, "application", "", "application identifier to filter HD wallets")
+	walletsListCmd.Flags().String("application", "", "application identifier to filter HD wallets")
 	walletsListCmd.Flags().BoolVarP(&optional, "optional", "", false, "List all the optional flags")
 	walletsListCmd.Flags().BoolVarP(&paginate, "paginate", "", false, "List pagination flags")
 	walletsListCmd.Flags().Uint64Var(&page, "page", common.DefaultPage, "page number to retrieve")
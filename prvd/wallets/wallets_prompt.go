@@ -40,6 +40,7 @@ var emptyPromptLabel = "What would you like to do"
 
 // General Endpoints
 func generalPrompt(cmd *cobra.Command, args []string, step string) {
+	ctx := common.ContextFromCommand(cmd)
 	switch step {
 	case promptStepInit:
 		common.SelectInput(walletTypePromptArgs, walletTypeLabel)
@@ -61,8 +62,8 @@ func generalPrompt(cmd *cobra.Command, args []string, step string) {
 	case promptStepList:
 		if optional {
 			fmt.Println("Optional Flags:")
-			if common.ApplicationID == "" {
-				common.RequireApplication()
+			if ctx.ApplicationID == "" {
+				common.RequireApplication(ctx)
 			}
 		}
 		page, rpp = common.PromptPagination(paginate, page, rpp)
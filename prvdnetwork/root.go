@@ -17,6 +17,7 @@
 package prvdnetwork
 
 import (
+	"context"
 	"fmt"
 	"os"
 
@@ -35,11 +36,13 @@ var rootCmd = &cobra.Command{
 The provide.network CLI exposes commands to run and manage full and validator nodes on permissionless provide.network.
 
 Run with the --help flag to see available options`, common.ASCIIBanner),
+	PersistentPreRun: common.InitContext,
 }
 
 // Execute the default command path
 func Execute() {
-	if err := rootCmd.Execute(); err != nil {
+	ctx := common.WithContext(context.Background(), common.NewContext())
+	if err := rootCmd.ExecuteContext(ctx); err != nil {
 		fmt.Println(err)
 		os.Exit(1)
 	}
@@ -48,7 +51,7 @@ func Execute() {
 func init() {
 	cobra.OnInitialize(common.InitConfig)
 
-	rootCmd.PersistentFlags().BoolVarP(&common.Verbose, "verbose", "v", false, "enable verbose output")
+	rootCmd.PersistentFlags().BoolP("verbose", "v", false, "enable verbose output")
 	rootCmd.PersistentFlags().StringVarP(&common.CfgFile, "config", "c", "", "config file (default is $HOME/.provide-cli.yaml)")
 
 	rootCmd.AddCommand(accounts.AccountsCmd)